@@ -1,6 +1,7 @@
 package alias
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"path/filepath"
@@ -26,6 +27,7 @@ func prepare(t *testing.T, root string) {
 }
 
 func TestNewFS(t *testing.T) {
+	ctx := context.Background()
 	type testEntry struct {
 		remote string
 		size   int64
@@ -69,7 +71,7 @@ func TestNewFS(t *testing.T) {
 		prepare(t, remoteRoot)
 		f, err := fs.NewFs(fmt.Sprintf("%s:%s", remoteName, test.fsRoot))
 		require.NoError(t, err, what)
-		gotEntries, err := f.List(test.fsList)
+		gotEntries, err := f.List(ctx, test.fsList)
 		require.NoError(t, err, what)
 
 		sort.Sort(gotEntries)
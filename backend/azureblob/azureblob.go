@@ -50,12 +50,58 @@ const (
 	maxUploadCutoff     = 256 * 1024 * 1024
 )
 
+// system metadata keys which this backend translates
+var systemMetadataInfo = map[string]fs.MetadataHelp{
+	"cache-control": {
+		Help:    "Cache-Control header",
+		Type:    "string",
+		Example: "no-cache",
+	},
+	"content-disposition": {
+		Help:    "Content-Disposition header",
+		Type:    "string",
+		Example: "inline",
+	},
+	"content-encoding": {
+		Help:    "Content-Encoding header",
+		Type:    "string",
+		Example: "gzip",
+	},
+	"content-language": {
+		Help:    "Content-Language header",
+		Type:    "string",
+		Example: "en-US",
+	},
+	"content-type": {
+		Help:    "Content-Type header",
+		Type:    "string",
+		Example: "text/plain",
+	},
+	modTimeKey: {
+		Help:    "Time of last modification, read from rclone metadata",
+		Type:    "RFC 3339",
+		Example: "2006-01-02T15:04:05.999999999Z07:00",
+	},
+	"tier": {
+		Help:     "Access tier of the blob",
+		Type:     "string",
+		Example:  "Hot",
+		ReadOnly: true,
+	},
+}
+
 // Register with Fs
 func init() {
 	fs.Register(&fs.RegInfo{
 		Name:        "azureblob",
 		Description: "Microsoft Azure Blob Storage",
 		NewFs:       NewFs,
+		MetadataInfo: &fs.MetadataInfo{
+			System: systemMetadataInfo,
+			Help: `User metadata is stored as blob metadata. Azure metadata keys must be
+valid C# identifiers and are case insensitive - they are always
+returned in lower case.`,
+		},
 		Options: []fs.Option{{
 			Name: "account",
 			Help: "Storage Account Name (leave blank to use connection string or SAS URL)",
@@ -119,6 +165,11 @@ type Object struct {
 	mimeType   string                // Content-Type of the object
 	accessTier azblob.AccessTierType // Blob Access Tier
 	meta       map[string]string     // blob metadata
+
+	cacheControl       string // Cache-Control: header
+	contentDisposition string // Content-Disposition: header
+	contentEncoding    string // Content-Encoding: header
+	contentLanguage    string // Content-Language: header
 }
 
 // ------------------------------------------------------------
@@ -191,6 +242,7 @@ func (f *Fs) shouldRetry(err error) (bool, error) {
 
 // NewFs contstructs an Fs from the path, container:path
 func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
+	ctx := context.Background()
 	// Parse config into Options struct
 	opt := new(Options)
 	err := configstruct.Set(m, opt)
@@ -265,6 +317,8 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 		ReadMimeType:  true,
 		WriteMimeType: true,
 		BucketBased:   true,
+		ReadMetadata:  true,
+		WriteMetadata: true,
 	}).Fill(f)
 	if f.root != "" {
 		f.root += "/"
@@ -277,7 +331,7 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 		} else {
 			f.root += "/"
 		}
-		_, err := f.NewObject(remote)
+		_, err := f.NewObject(ctx, remote)
 		if err != nil {
 			if err == fs.ErrorObjectNotFound {
 				// File doesn't exist so return old f
@@ -295,7 +349,7 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 // Return an Object from a path
 //
 // If it can't be found it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(remote string, info *azblob.BlobItem) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *azblob.BlobItem) (fs.Object, error) {
 	o := &Object{
 		fs:     f,
 		remote: remote,
@@ -306,7 +360,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *azblob.BlobItem) (fs.Object,
 			return nil, err
 		}
 	} else {
-		err := o.readMetaData() // reads info and headers, returning an error
+		err := o.readMetaData(ctx) // reads info and headers, returning an error
 		if err != nil {
 			return nil, err
 		}
@@ -316,8 +370,8 @@ func (f *Fs) newObjectWithInfo(remote string, info *azblob.BlobItem) (fs.Object,
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(remote, nil)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	return f.newObjectWithInfo(ctx, remote, nil)
 }
 
 // getBlobReference creates an empty blob reference with no metadata
@@ -343,7 +397,7 @@ type listFn func(remote string, object *azblob.BlobItem, isDirectory bool) error
 // the container and root supplied
 //
 // dir is the starting directory, "" for root
-func (f *Fs) list(dir string, recurse bool, maxResults uint, fn listFn) error {
+func (f *Fs) list(ctx context.Context, dir string, recurse bool, maxResults uint, fn listFn) error {
 	f.containerOKMu.Lock()
 	deleted := f.containerDeleted
 	f.containerOKMu.Unlock()
@@ -370,7 +424,6 @@ func (f *Fs) list(dir string, recurse bool, maxResults uint, fn listFn) error {
 		Prefix:     root,
 		MaxResults: int32(maxResults),
 	}
-	ctx := context.Background()
 	for marker := (azblob.Marker{}); marker.NotDone(); {
 		var response *azblob.ListBlobsHierarchySegmentResponse
 		err := f.pacer.Call(func() (bool, error) {
@@ -430,12 +483,12 @@ func (f *Fs) list(dir string, recurse bool, maxResults uint, fn listFn) error {
 }
 
 // Convert a list item into a DirEntry
-func (f *Fs) itemToDirEntry(remote string, object *azblob.BlobItem, isDirectory bool) (fs.DirEntry, error) {
+func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *azblob.BlobItem, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
 		d := fs.NewDir(remote, time.Time{})
 		return d, nil
 	}
-	o, err := f.newObjectWithInfo(remote, object)
+	o, err := f.newObjectWithInfo(ctx, remote, object)
 	if err != nil {
 		return nil, err
 	}
@@ -453,9 +506,9 @@ func (f *Fs) markContainerOK() {
 }
 
 // listDir lists a single directory
-func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
-	err = f.list(dir, false, listChunkSize, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+func (f *Fs) listDir(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	err = f.list(ctx, dir, false, listChunkSize, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -473,11 +526,11 @@ func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
 }
 
 // listContainers returns all the containers to out
-func (f *Fs) listContainers(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listContainers(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if dir != "" {
 		return nil, fs.ErrorListBucketRequired
 	}
-	err = f.listContainersToFn(func(container *azblob.ContainerItem) error {
+	err = f.listContainersToFn(ctx, func(container *azblob.ContainerItem) error {
 		d := fs.NewDir(container.Name, container.Properties.LastModified)
 		entries = append(entries, d)
 		return nil
@@ -497,11 +550,11 @@ func (f *Fs) listContainers(dir string) (entries fs.DirEntries, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if f.container == "" {
-		return f.listContainers(dir)
+		return f.listContainers(ctx, dir)
 	}
-	return f.listDir(dir)
+	return f.listDir(ctx, dir)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -520,13 +573,13 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	if f.container == "" {
 		return fs.ErrorListBucketRequired
 	}
 	list := walk.NewListRHelper(callback)
-	err = f.list(dir, true, listChunkSize, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, true, listChunkSize, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -544,11 +597,10 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 type listContainerFn func(*azblob.ContainerItem) error
 
 // listContainersToFn lists the containers to the function supplied
-func (f *Fs) listContainersToFn(fn listContainerFn) error {
+func (f *Fs) listContainersToFn(ctx context.Context, fn listContainerFn) error {
 	params := azblob.ListContainersSegmentOptions{
 		MaxResults: int32(listChunkSize),
 	}
-	ctx := context.Background()
 	for marker := (azblob.Marker{}); marker.NotDone(); {
 		var response *azblob.ListContainersResponse
 		err := f.pacer.Call(func() (bool, error) {
@@ -577,17 +629,17 @@ func (f *Fs) listContainersToFn(fn listContainerFn) error {
 // Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// Temporary Object under construction
 	fs := &Object{
 		fs:     f,
 		remote: src.Remote(),
 	}
-	return fs, fs.Update(in, src, options...)
+	return fs, fs.Update(ctx, in, src, options...)
 }
 
 // Mkdir creates the container if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	f.containerOKMu.Lock()
 	defer f.containerOKMu.Unlock()
 	if f.containerOK {
@@ -596,7 +648,6 @@ func (f *Fs) Mkdir(dir string) error {
 
 	// now try to create the container
 	err := f.pacer.Call(func() (bool, error) {
-		ctx := context.Background()
 		_, err := f.cntURL.Create(ctx, azblob.Metadata{}, azblob.PublicAccessNone)
 		if err != nil {
 			if storageErr, ok := err.(azblob.StorageError); ok {
@@ -620,9 +671,9 @@ func (f *Fs) Mkdir(dir string) error {
 }
 
 // isEmpty checks to see if a given directory is empty and returns an error if not
-func (f *Fs) isEmpty(dir string) (err error) {
+func (f *Fs) isEmpty(ctx context.Context, dir string) (err error) {
 	empty := true
-	err = f.list("", true, 1, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
+	err = f.list(ctx, "", true, 1, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
 		empty = false
 		return nil
 	})
@@ -637,11 +688,10 @@ func (f *Fs) isEmpty(dir string) (err error) {
 
 // deleteContainer deletes the container.  It can delete a full
 // container so use isEmpty if you don't want that.
-func (f *Fs) deleteContainer() error {
+func (f *Fs) deleteContainer(ctx context.Context) error {
 	f.containerOKMu.Lock()
 	defer f.containerOKMu.Unlock()
 	options := azblob.ContainerAccessConditions{}
-	ctx := context.Background()
 	err := f.pacer.Call(func() (bool, error) {
 		_, err := f.cntURL.GetProperties(ctx, azblob.LeaseAccessConditions{})
 		if err == nil {
@@ -669,15 +719,15 @@ func (f *Fs) deleteContainer() error {
 // Rmdir deletes the container if the fs is at the root
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
-	err := f.isEmpty(dir)
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	err := f.isEmpty(ctx, dir)
 	if err != nil {
 		return err
 	}
 	if f.root != "" || dir != "" {
 		return nil
 	}
-	return f.deleteContainer()
+	return f.deleteContainer(ctx)
 }
 
 // Precision of the remote
@@ -691,13 +741,13 @@ func (f *Fs) Hashes() hash.Set {
 }
 
 // Purge deletes all the files and directories including the old versions.
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	dir := "" // forward compat!
 	if f.root != "" || dir != "" {
 		// Delegate to caller if not root container
 		return fs.ErrorCantPurge
 	}
-	return f.deleteContainer()
+	return f.deleteContainer(ctx)
 }
 
 // Copy src to this remote using server side copy operations.
@@ -709,8 +759,8 @@ func (f *Fs) Purge() error {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
-	err := f.Mkdir("")
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		return nil, err
 	}
@@ -728,7 +778,6 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 	}
 
 	options := azblob.BlobAccessConditions{}
-	ctx := context.Background()
 	var startCopy *azblob.BlobStartCopyFromURLResponse
 
 	err = f.pacer.Call(func() (bool, error) {
@@ -749,7 +798,7 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 		copyStatus = getMetadata.CopyStatus()
 	}
 
-	return f.NewObject(remote)
+	return f.NewObject(ctx, remote)
 }
 
 // ------------------------------------------------------------
@@ -825,6 +874,10 @@ func (o *Object) decodeMetaDataFromPropertiesResponse(info *azblob.BlobGetProper
 	o.size = info.ContentLength()
 	o.modTime = time.Time(info.LastModified())
 	o.accessTier = azblob.AccessTierType(info.AccessTier())
+	o.cacheControl = info.CacheControl()
+	o.contentDisposition = info.ContentDisposition()
+	o.contentEncoding = info.ContentEncoding()
+	o.contentLanguage = info.ContentLanguage()
 	o.setMetadata(info.NewMetadata())
 
 	return nil
@@ -836,10 +889,22 @@ func (o *Object) decodeMetaDataFromBlob(info *azblob.BlobItem) (err error) {
 	o.size = *info.Properties.ContentLength
 	o.modTime = info.Properties.LastModified
 	o.accessTier = info.Properties.AccessTier
+	o.cacheControl = stringValue(info.Properties.CacheControl)
+	o.contentDisposition = stringValue(info.Properties.ContentDisposition)
+	o.contentEncoding = stringValue(info.Properties.ContentEncoding)
+	o.contentLanguage = stringValue(info.Properties.ContentLanguage)
 	o.setMetadata(info.Metadata)
 	return nil
 }
 
+// stringValue returns the string pointed to by p or "" if p is nil
+func stringValue(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
 // getBlobReference creates an empty blob reference with no metadata
 func (o *Object) getBlobReference() azblob.BlobURL {
 	return o.fs.getBlobReference(o.remote)
@@ -857,7 +922,7 @@ func (o *Object) clearMetaData() {
 //  o.modTime
 //  o.size
 //  o.md5
-func (o *Object) readMetaData() (err error) {
+func (o *Object) readMetaData(ctx context.Context) (err error) {
 	if !o.modTime.IsZero() {
 		return nil
 	}
@@ -865,7 +930,6 @@ func (o *Object) readMetaData() (err error) {
 
 	// Read metadata (this includes metadata)
 	options := azblob.BlobAccessConditions{}
-	ctx := context.Background()
 	var blobProperties *azblob.BlobGetPropertiesResponse
 	err = o.fs.pacer.Call(func() (bool, error) {
 		blobProperties, err = blob.GetProperties(ctx, options)
@@ -909,8 +973,9 @@ func (o *Object) parseTimeString(timeString string) (err error) {
 // It attempts to read the objects mtime and if that isn't present the
 // LastModified returned in the http headers
 func (o *Object) ModTime() (result time.Time) {
+	ctx := context.TODO()
 	// The error is logged in readMetaData
-	_ = o.readMetaData()
+	_ = o.readMetaData(ctx)
 	return o.modTime
 }
 
@@ -942,7 +1007,7 @@ func (o *Object) Storable() bool {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	// Offset and Count for range download
 	var offset int64
 	var count int64
@@ -962,7 +1027,6 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 		}
 	}
 	blob := o.getBlobReference()
-	ctx := context.Background()
 	ac := azblob.BlobAccessConditions{}
 	var dowloadResponse *azblob.DownloadResponse
 	err = o.fs.pacer.Call(func() (bool, error) {
@@ -1006,7 +1070,7 @@ type readSeeker struct {
 // uploadMultipart uploads a file using multipart upload
 //
 // Write a larger blob, using CreateBlockBlob, PutBlock, and PutBlockList.
-func (o *Object) uploadMultipart(in io.Reader, size int64, blob *azblob.BlobURL, httpHeaders *azblob.BlobHTTPHeaders) (err error) {
+func (o *Object) uploadMultipart(ctx context.Context, in io.Reader, size int64, blob *azblob.BlobURL, httpHeaders *azblob.BlobHTTPHeaders) (err error) {
 	// Calculate correct chunkSize
 	chunkSize := int64(o.fs.opt.ChunkSize)
 	var totalParts int64
@@ -1056,7 +1120,6 @@ func (o *Object) uploadMultipart(in io.Reader, size int64, blob *azblob.BlobURL,
 
 	// Get BlockBlobURL, we will use default pipeline here
 	blockBlobURL := blob.ToBlockBlobURL()
-	ctx := context.Background()
 	ac := azblob.LeaseAccessConditions{} // Use default lease access conditions
 
 	// unwrap the accounting from the input, we use wrap to put it
@@ -1148,8 +1211,8 @@ outer:
 // Update the object with the contents of the io.Reader, modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
-	err = o.fs.Mkdir("")
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
+	err = o.fs.Mkdir(ctx, "")
 	if err != nil {
 		return err
 	}
@@ -1163,6 +1226,38 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	blob := o.getBlobReference()
 	httpHeaders := azblob.BlobHTTPHeaders{}
 	httpHeaders.ContentType = fs.MimeType(o)
+
+	// Set the metadata if --metadata is in use
+	meta, err := fs.GetMetadataOptions(src, options)
+	if err != nil {
+		return errors.Wrap(err, "failed to read metadata from source object")
+	}
+	for k, v := range meta {
+		switch k {
+		case "cache-control":
+			httpHeaders.CacheControl = v
+		case "content-disposition":
+			httpHeaders.ContentDisposition = v
+		case "content-encoding":
+			httpHeaders.ContentEncoding = v
+		case "content-language":
+			httpHeaders.ContentLanguage = v
+		case "content-type":
+			httpHeaders.ContentType = v
+		case modTimeKey:
+			// mtime in meta overrides source ModTime
+			metaModTime, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				fs.Debugf(o, "failed to parse metadata %s: %q: %v", k, v, err)
+			} else {
+				o.updateMetadataWithModTime(metaModTime)
+			}
+		case "tier":
+			// read only so ignore
+		default:
+			o.meta[k] = v
+		}
+	}
 	// Multipart upload doesn't support MD5 checksums at put block calls, hence calculate
 	// MD5 only for PutBlob requests
 	if size < int64(o.fs.opt.UploadCutoff) {
@@ -1183,12 +1278,11 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		BlobHTTPHeaders: httpHeaders,
 	}
 
-	ctx := context.Background()
 	// Don't retry, return a retry error instead
 	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
 		if size >= int64(o.fs.opt.UploadCutoff) {
 			// If a large file upload in chunks
-			err = o.uploadMultipart(in, size, &blob, &httpHeaders)
+			err = o.uploadMultipart(ctx, in, size, &blob, &httpHeaders)
 		} else {
 			// Write a small blob in one transaction
 			blockBlobURL := blob.ToBlockBlobURL()
@@ -1200,15 +1294,14 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		return err
 	}
 	o.clearMetaData()
-	return o.readMetaData()
+	return o.readMetaData(ctx)
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	blob := o.getBlobReference()
 	snapShotOptions := azblob.DeleteSnapshotsOptionNone
 	ac := azblob.BlobAccessConditions{}
-	ctx := context.Background()
 	return o.fs.pacer.Call(func() (bool, error) {
 		_, err := blob.Delete(ctx, snapShotOptions, ac)
 		return o.fs.shouldRetry(err)
@@ -1220,12 +1313,43 @@ func (o *Object) MimeType() string {
 	return o.mimeType
 }
 
+// Metadata returns metadata for an object
+//
+// It should return nil if there is no Metadata
+func (o *Object) Metadata() (metadata fs.Metadata, err error) {
+	ctx := context.TODO()
+	err = o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata = make(fs.Metadata, len(o.meta)+7)
+	for k, v := range o.meta {
+		metadata[strings.ToLower(k)] = v
+	}
+	if _, ok := metadata[modTimeKey]; ok {
+		metadata[modTimeKey] = o.modTime.Format(time.RFC3339Nano)
+	}
+	setMetadata := func(k, v string) {
+		if v != "" {
+			metadata[k] = v
+		}
+	}
+	setMetadata("content-type", o.mimeType)
+	setMetadata("cache-control", o.cacheControl)
+	setMetadata("content-disposition", o.contentDisposition)
+	setMetadata("content-encoding", o.contentEncoding)
+	setMetadata("content-language", o.contentLanguage)
+	setMetadata("tier", string(o.accessTier))
+	return metadata, nil
+}
+
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs        = &Fs{}
-	_ fs.Copier    = &Fs{}
-	_ fs.Purger    = &Fs{}
-	_ fs.ListRer   = &Fs{}
-	_ fs.Object    = &Object{}
-	_ fs.MimeTyper = &Object{}
+	_ fs.Fs         = &Fs{}
+	_ fs.Copier     = &Fs{}
+	_ fs.Purger     = &Fs{}
+	_ fs.ListRer    = &Fs{}
+	_ fs.Object     = &Object{}
+	_ fs.MimeTyper  = &Object{}
+	_ fs.Metadataer = &Object{}
 )
@@ -4,6 +4,7 @@ package cache_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"log"
@@ -110,22 +111,23 @@ func TestMain(m *testing.M) {
 }
 
 func TestInternalListRootAndInnerRemotes(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tilrair%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	// Instantiate inner fs
 	innerFolder := "inner"
-	runInstance.mkdir(t, rootFs, innerFolder)
-	rootFs2, boltDb2 := runInstance.newCacheFs(t, remoteName, id+"/"+innerFolder, true, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs2, boltDb2)
+	runInstance.mkdir(ctx, t, rootFs, innerFolder)
+	rootFs2, boltDb2 := runInstance.newCacheFs(ctx, t, remoteName, id+"/"+innerFolder, true, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs2, boltDb2)
 
-	runInstance.writeObjectString(t, rootFs2, "one", "content")
-	listRoot, err := runInstance.list(t, rootFs, "")
+	runInstance.writeObjectString(ctx, t, rootFs2, "one", "content")
+	listRoot, err := runInstance.list(ctx, t, rootFs, "")
 	require.NoError(t, err)
-	listRootInner, err := runInstance.list(t, rootFs, innerFolder)
+	listRootInner, err := runInstance.list(ctx, t, rootFs, innerFolder)
 	require.NoError(t, err)
-	listInner, err := rootFs2.List("")
+	listInner, err := rootFs2.List(ctx, "")
 	require.NoError(t, err)
 
 	require.Len(t, listRoot, 1)
@@ -234,9 +236,10 @@ func TestInternalVfsCache(t *testing.T) {
 */
 
 func TestInternalObjWrapFsFound(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiowff%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
@@ -250,39 +253,41 @@ func TestInternalObjWrapFsFound(t *testing.T) {
 		testData = []byte("test content")
 	}
 
-	runInstance.writeObjectBytes(t, wrappedFs, runInstance.encryptRemoteIfNeeded(t, "test"), testData)
-	listRoot, err := runInstance.list(t, rootFs, "")
+	runInstance.writeObjectBytes(ctx, t, wrappedFs, runInstance.encryptRemoteIfNeeded(t, "test"), testData)
+	listRoot, err := runInstance.list(ctx, t, rootFs, "")
 	require.NoError(t, err)
 	require.Len(t, listRoot, 1)
 
-	cachedData, err := runInstance.readDataFromRemote(t, rootFs, "test", 0, int64(len([]byte("test content"))), false)
+	cachedData, err := runInstance.readDataFromRemote(ctx, t, rootFs, "test", 0, int64(len([]byte("test content"))), false)
 	require.NoError(t, err)
 	require.Equal(t, "test content", string(cachedData))
 
-	err = runInstance.rm(t, rootFs, "test")
+	err = runInstance.rm(ctx, t, rootFs, "test")
 	require.NoError(t, err)
-	listRoot, err = runInstance.list(t, rootFs, "")
+	listRoot, err = runInstance.list(ctx, t, rootFs, "")
 	require.NoError(t, err)
 	require.Len(t, listRoot, 0)
 }
 
 func TestInternalObjNotFound(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tionf%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
-	obj, err := rootFs.NewObject("404")
+	obj, err := rootFs.NewObject(ctx, "404")
 	require.Error(t, err)
 	require.Nil(t, obj)
 }
 
 func TestInternalRemoteWrittenFileFoundInMount(t *testing.T) {
+	ctx := context.Background()
 	if !runInstance.useMount {
 		t.Skip("test needs mount mode")
 	}
 	id := fmt.Sprintf("tirwffim%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
@@ -295,16 +300,17 @@ func TestInternalRemoteWrittenFileFoundInMount(t *testing.T) {
 		testData = []byte("test content")
 	}
 
-	runInstance.writeObjectBytes(t, cfs.UnWrap(), runInstance.encryptRemoteIfNeeded(t, "test"), testData)
-	data, err := runInstance.readDataFromRemote(t, rootFs, "test", 0, int64(len([]byte("test content"))), false)
+	runInstance.writeObjectBytes(ctx, t, cfs.UnWrap(), runInstance.encryptRemoteIfNeeded(t, "test"), testData)
+	data, err := runInstance.readDataFromRemote(ctx, t, rootFs, "test", 0, int64(len([]byte("test content"))), false)
 	require.NoError(t, err)
 	require.Equal(t, "test content", string(data))
 }
 
 func TestInternalCachedWrittenContentMatches(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("ticwcm%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
@@ -314,40 +320,42 @@ func TestInternalCachedWrittenContentMatches(t *testing.T) {
 	testData := randStringBytes(int(chunkSize*4 + chunkSize/2))
 
 	// write the object
-	runInstance.writeRemoteBytes(t, rootFs, "data.bin", testData)
+	runInstance.writeRemoteBytes(ctx, t, rootFs, "data.bin", testData)
 
 	// check sample of data from in-file
 	sampleStart := chunkSize / 2
 	sampleEnd := chunkSize
 	testSample := testData[sampleStart:sampleEnd]
-	checkSample, err := runInstance.readDataFromRemote(t, rootFs, "data.bin", sampleStart, sampleEnd, false)
+	checkSample, err := runInstance.readDataFromRemote(ctx, t, rootFs, "data.bin", sampleStart, sampleEnd, false)
 	require.NoError(t, err)
 	require.Equal(t, int64(len(checkSample)), sampleEnd-sampleStart)
 	require.Equal(t, checkSample, testSample)
 }
 
 func TestInternalDoubleWrittenContentMatches(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tidwcm%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	// write the object
-	runInstance.writeRemoteString(t, rootFs, "one", "one content")
-	err := runInstance.updateData(t, rootFs, "one", "one content", " updated")
+	runInstance.writeRemoteString(ctx, t, rootFs, "one", "one content")
+	err := runInstance.updateData(ctx, t, rootFs, "one", "one content", " updated")
 	require.NoError(t, err)
-	err = runInstance.updateData(t, rootFs, "one", "one content updated", " double")
+	err = runInstance.updateData(ctx, t, rootFs, "one", "one content updated", " double")
 	require.NoError(t, err)
 
 	// check sample of data from in-file
-	data, err := runInstance.readDataFromRemote(t, rootFs, "one", int64(0), int64(len("one content updated double")), true)
+	data, err := runInstance.readDataFromRemote(ctx, t, rootFs, "one", int64(0), int64(len("one content updated double")), true)
 	require.NoError(t, err)
 	require.Equal(t, "one content updated double", string(data))
 }
 
 func TestInternalCachedUpdatedContentMatches(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("ticucm%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 	var err error
 
 	// create some rand test data
@@ -364,20 +372,21 @@ func TestInternalCachedUpdatedContentMatches(t *testing.T) {
 	}
 
 	// write the object
-	o := runInstance.updateObjectRemote(t, rootFs, "data.bin", testData1, testData2)
+	o := runInstance.updateObjectRemote(ctx, t, rootFs, "data.bin", testData1, testData2)
 	require.Equal(t, o.Size(), int64(len(testData2)))
 
 	// check data from in-file
-	checkSample, err := runInstance.readDataFromRemote(t, rootFs, "data.bin", 0, int64(len(testData2)), false)
+	checkSample, err := runInstance.readDataFromRemote(ctx, t, rootFs, "data.bin", 0, int64(len(testData2)), false)
 	require.NoError(t, err)
 	require.Equal(t, checkSample, testData2)
 }
 
 func TestInternalWrappedWrittenContentMatches(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiwwcm%v", time.Now().Unix())
 	vfsflags.Opt.DirCacheTime = time.Second
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 	if runInstance.rootIsCrypt {
 		t.Skip("test skipped with crypt remote")
 	}
@@ -391,11 +400,11 @@ func TestInternalWrappedWrittenContentMatches(t *testing.T) {
 	testData := randStringBytes(int(testSize))
 
 	// write the object
-	o := runInstance.writeObjectBytes(t, cfs.UnWrap(), "data.bin", testData)
+	o := runInstance.writeObjectBytes(ctx, t, cfs.UnWrap(), "data.bin", testData)
 	require.Equal(t, o.Size(), int64(testSize))
 	time.Sleep(time.Second * 3)
 
-	checkSample, err := runInstance.readDataFromRemote(t, rootFs, "data.bin", 0, int64(testSize), false)
+	checkSample, err := runInstance.readDataFromRemote(ctx, t, rootFs, "data.bin", 0, int64(testSize), false)
 	require.NoError(t, err)
 	require.Equal(t, int64(len(checkSample)), o.Size())
 
@@ -405,10 +414,11 @@ func TestInternalWrappedWrittenContentMatches(t *testing.T) {
 }
 
 func TestInternalLargeWrittenContentMatches(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tilwcm%v", time.Now().Unix())
 	vfsflags.Opt.DirCacheTime = time.Second
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 	if runInstance.rootIsCrypt {
 		t.Skip("test skipped with crypt remote")
 	}
@@ -422,10 +432,10 @@ func TestInternalLargeWrittenContentMatches(t *testing.T) {
 	testData := randStringBytes(int(testSize))
 
 	// write the object
-	runInstance.writeObjectBytes(t, cfs.UnWrap(), "data.bin", testData)
+	runInstance.writeObjectBytes(ctx, t, cfs.UnWrap(), "data.bin", testData)
 	time.Sleep(time.Second * 3)
 
-	readData, err := runInstance.readDataFromRemote(t, rootFs, "data.bin", 0, testSize, false)
+	readData, err := runInstance.readDataFromRemote(ctx, t, rootFs, "data.bin", 0, testSize, false)
 	require.NoError(t, err)
 	for i := 0; i < len(readData); i++ {
 		require.Equalf(t, testData[i], readData[i], "at byte %v", i)
@@ -433,9 +443,10 @@ func TestInternalLargeWrittenContentMatches(t *testing.T) {
 }
 
 func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiwfcns%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
@@ -443,14 +454,14 @@ func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
 
 	// create some rand test data
 	testData := randStringBytes(int(chunkSize*4 + chunkSize/2))
-	runInstance.writeRemoteBytes(t, rootFs, "data.bin", testData)
+	runInstance.writeRemoteBytes(ctx, t, rootFs, "data.bin", testData)
 
 	// update in the wrapped fs
-	originalSize, err := runInstance.size(t, rootFs, "data.bin")
+	originalSize, err := runInstance.size(ctx, t, rootFs, "data.bin")
 	require.NoError(t, err)
 	log.Printf("original size: %v", originalSize)
 
-	o, err := cfs.UnWrap().NewObject(runInstance.encryptRemoteIfNeeded(t, "data.bin"))
+	o, err := cfs.UnWrap().NewObject(ctx, runInstance.encryptRemoteIfNeeded(t, "data.bin"))
 	require.NoError(t, err)
 	expectedSize := int64(len([]byte("test content")))
 	var data2 []byte
@@ -462,7 +473,7 @@ func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
 		data2 = []byte("test content")
 	}
 	objInfo := object.NewStaticObjectInfo(runInstance.encryptRemoteIfNeeded(t, "data.bin"), time.Now(), int64(len(data2)), true, nil, cfs.UnWrap())
-	err = o.Update(bytes.NewReader(data2), objInfo)
+	err = o.Update(ctx, bytes.NewReader(data2), objInfo)
 	require.NoError(t, err)
 	require.Equal(t, int64(len(data2)), o.Size())
 	log.Printf("updated size: %v", len(data2))
@@ -470,7 +481,7 @@ func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
 	// get a new instance from the cache
 	if runInstance.wrappedIsExternal {
 		err = runInstance.retryBlock(func() error {
-			coSize, err := runInstance.size(t, rootFs, "data.bin")
+			coSize, err := runInstance.size(ctx, t, rootFs, "data.bin")
 			if err != nil {
 				return err
 			}
@@ -481,16 +492,17 @@ func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
 		}, 12, time.Second*10)
 		require.NoError(t, err)
 	} else {
-		coSize, err := runInstance.size(t, rootFs, "data.bin")
+		coSize, err := runInstance.size(ctx, t, rootFs, "data.bin")
 		require.NoError(t, err)
 		require.NotEqual(t, coSize, expectedSize)
 	}
 }
 
 func TestInternalMoveWithNotify(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("timwn%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 	if !runInstance.wrappedIsExternal {
 		t.Skipf("Not external")
 	}
@@ -508,23 +520,23 @@ func TestInternalMoveWithNotify(t *testing.T) {
 	} else {
 		testData = []byte("test content")
 	}
-	_ = cfs.UnWrap().Mkdir(runInstance.encryptRemoteIfNeeded(t, "test"))
-	_ = cfs.UnWrap().Mkdir(runInstance.encryptRemoteIfNeeded(t, "test/one"))
-	_ = cfs.UnWrap().Mkdir(runInstance.encryptRemoteIfNeeded(t, "test/second"))
-	srcObj := runInstance.writeObjectBytes(t, cfs.UnWrap(), srcName, testData)
+	_ = cfs.UnWrap().Mkdir(ctx, runInstance.encryptRemoteIfNeeded(t, "test"))
+	_ = cfs.UnWrap().Mkdir(ctx, runInstance.encryptRemoteIfNeeded(t, "test/one"))
+	_ = cfs.UnWrap().Mkdir(ctx, runInstance.encryptRemoteIfNeeded(t, "test/second"))
+	srcObj := runInstance.writeObjectBytes(ctx, t, cfs.UnWrap(), srcName, testData)
 
 	// list in mount
-	_, err = runInstance.list(t, rootFs, "test")
+	_, err = runInstance.list(ctx, t, rootFs, "test")
 	require.NoError(t, err)
-	_, err = runInstance.list(t, rootFs, "test/one")
+	_, err = runInstance.list(ctx, t, rootFs, "test/one")
 	require.NoError(t, err)
 
 	// move file
-	_, err = cfs.UnWrap().Features().Move(srcObj, dstName)
+	_, err = cfs.UnWrap().Features().Move(ctx, srcObj, dstName)
 	require.NoError(t, err)
 
 	err = runInstance.retryBlock(func() error {
-		li, err := runInstance.list(t, rootFs, "test")
+		li, err := runInstance.list(ctx, t, rootFs, "test")
 		if err != nil {
 			log.Printf("err: %v", err)
 			return err
@@ -534,7 +546,7 @@ func TestInternalMoveWithNotify(t *testing.T) {
 			return errors.Errorf("not expected listing /test: %v", li)
 		}
 
-		li, err = runInstance.list(t, rootFs, "test/one")
+		li, err = runInstance.list(ctx, t, rootFs, "test/one")
 		if err != nil {
 			log.Printf("err: %v", err)
 			return err
@@ -544,7 +556,7 @@ func TestInternalMoveWithNotify(t *testing.T) {
 			return errors.Errorf("not expected listing /test/one: %v", li)
 		}
 
-		li, err = runInstance.list(t, rootFs, "test/second")
+		li, err = runInstance.list(ctx, t, rootFs, "test/second")
 		if err != nil {
 			log.Printf("err: %v", err)
 			return err
@@ -575,9 +587,10 @@ func TestInternalMoveWithNotify(t *testing.T) {
 }
 
 func TestInternalNotifyCreatesEmptyParts(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tincep%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 	if !runInstance.wrappedIsExternal {
 		t.Skipf("Not external")
 	}
@@ -594,16 +607,16 @@ func TestInternalNotifyCreatesEmptyParts(t *testing.T) {
 	} else {
 		testData = []byte("test content")
 	}
-	err = rootFs.Mkdir("test")
+	err = rootFs.Mkdir(ctx, "test")
 	require.NoError(t, err)
-	err = rootFs.Mkdir("test/one")
+	err = rootFs.Mkdir(ctx, "test/one")
 	require.NoError(t, err)
-	srcObj := runInstance.writeObjectBytes(t, cfs.UnWrap(), srcName, testData)
+	srcObj := runInstance.writeObjectBytes(ctx, t, cfs.UnWrap(), srcName, testData)
 
 	// list in mount
-	_, err = runInstance.list(t, rootFs, "test")
+	_, err = runInstance.list(ctx, t, rootFs, "test")
 	require.NoError(t, err)
-	_, err = runInstance.list(t, rootFs, "test/one")
+	_, err = runInstance.list(ctx, t, rootFs, "test/one")
 	require.NoError(t, err)
 
 	found := boltDb.HasEntry(path.Join(cfs.Root(), runInstance.encryptRemoteIfNeeded(t, "test")))
@@ -613,7 +626,7 @@ func TestInternalNotifyCreatesEmptyParts(t *testing.T) {
 	require.False(t, found)
 
 	// move file
-	_, err = cfs.UnWrap().Features().Move(srcObj, dstName)
+	_, err = cfs.UnWrap().Features().Move(ctx, srcObj, dstName)
 	require.NoError(t, err)
 
 	err = runInstance.retryBlock(func() error {
@@ -632,7 +645,7 @@ func TestInternalNotifyCreatesEmptyParts(t *testing.T) {
 			log.Printf("not found /test/one/test2")
 			return errors.Errorf("not found /test/one/test2")
 		}
-		li, err := runInstance.list(t, rootFs, "test/one")
+		li, err := runInstance.list(ctx, t, rootFs, "test/one")
 		if err != nil {
 			log.Printf("err: %v", err)
 			return err
@@ -662,9 +675,10 @@ func TestInternalNotifyCreatesEmptyParts(t *testing.T) {
 }
 
 func TestInternalChangeSeenAfterDirCacheFlush(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("ticsadcf%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
@@ -672,35 +686,36 @@ func TestInternalChangeSeenAfterDirCacheFlush(t *testing.T) {
 
 	// create some rand test data
 	testData := randStringBytes(int(chunkSize*4 + chunkSize/2))
-	runInstance.writeRemoteBytes(t, rootFs, "data.bin", testData)
+	runInstance.writeRemoteBytes(ctx, t, rootFs, "data.bin", testData)
 
 	// update in the wrapped fs
-	o, err := cfs.UnWrap().NewObject(runInstance.encryptRemoteIfNeeded(t, "data.bin"))
+	o, err := cfs.UnWrap().NewObject(ctx, runInstance.encryptRemoteIfNeeded(t, "data.bin"))
 	require.NoError(t, err)
 	wrappedTime := time.Now().Add(-1 * time.Hour)
 	err = o.SetModTime(wrappedTime)
 	require.NoError(t, err)
 
 	// get a new instance from the cache
-	co, err := rootFs.NewObject("data.bin")
+	co, err := rootFs.NewObject(ctx, "data.bin")
 	require.NoError(t, err)
 	require.NotEqual(t, o.ModTime().String(), co.ModTime().String())
 
 	cfs.DirCacheFlush() // flush the cache
 
 	// get a new instance from the cache
-	co, err = rootFs.NewObject("data.bin")
+	co, err = rootFs.NewObject(ctx, "data.bin")
 	require.NoError(t, err)
 	require.Equal(t, wrappedTime.Unix(), co.ModTime().Unix())
 }
 
 func TestInternalChangeSeenAfterRc(t *testing.T) {
+	ctx := context.Background()
 	rcflags.Opt.Enabled = true
 	rc.Start(&rcflags.Opt)
 
 	id := fmt.Sprintf("ticsarc%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	if !runInstance.useMount {
 		t.Skipf("needs mount")
@@ -715,17 +730,17 @@ func TestInternalChangeSeenAfterRc(t *testing.T) {
 
 	// create some rand test data
 	testData := randStringBytes(int(chunkSize*4 + chunkSize/2))
-	runInstance.writeRemoteBytes(t, rootFs, "data.bin", testData)
+	runInstance.writeRemoteBytes(ctx, t, rootFs, "data.bin", testData)
 
 	// update in the wrapped fs
-	o, err := cfs.UnWrap().NewObject(runInstance.encryptRemoteIfNeeded(t, "data.bin"))
+	o, err := cfs.UnWrap().NewObject(ctx, runInstance.encryptRemoteIfNeeded(t, "data.bin"))
 	require.NoError(t, err)
 	wrappedTime := time.Now().Add(-1 * time.Hour)
 	err = o.SetModTime(wrappedTime)
 	require.NoError(t, err)
 
 	// get a new instance from the cache
-	co, err := rootFs.NewObject("data.bin")
+	co, err := rootFs.NewObject(ctx, "data.bin")
 	require.NoError(t, err)
 	require.NotEqual(t, o.ModTime().String(), co.ModTime().String())
 
@@ -742,17 +757,17 @@ func TestInternalChangeSeenAfterRc(t *testing.T) {
 	require.Contains(t, m["message"], "cached file cleared")
 
 	// get a new instance from the cache
-	co, err = rootFs.NewObject("data.bin")
+	co, err = rootFs.NewObject(ctx, "data.bin")
 	require.NoError(t, err)
 	require.Equal(t, wrappedTime.Unix(), co.ModTime().Unix())
-	li1, err := runInstance.list(t, rootFs, "")
+	li1, err := runInstance.list(ctx, t, rootFs, "")
 
 	// create some rand test data
 	testData2 := randStringBytes(int(chunkSize))
-	runInstance.writeObjectBytes(t, cfs.UnWrap(), runInstance.encryptRemoteIfNeeded(t, "test2"), testData2)
+	runInstance.writeObjectBytes(ctx, t, cfs.UnWrap(), runInstance.encryptRemoteIfNeeded(t, "test2"), testData2)
 
 	// list should have 1 item only
-	li1, err = runInstance.list(t, rootFs, "")
+	li1, err = runInstance.list(ctx, t, rootFs, "")
 	require.Len(t, li1, 1)
 
 	m = make(map[string]string)
@@ -768,14 +783,15 @@ func TestInternalChangeSeenAfterRc(t *testing.T) {
 	require.Contains(t, m["message"], "cached directory cleared")
 
 	// list should have 2 items now
-	li2, err := runInstance.list(t, rootFs, "")
+	li2, err := runInstance.list(ctx, t, rootFs, "")
 	require.Len(t, li2, 2)
 }
 
 func TestInternalCacheWrites(t *testing.T) {
+	ctx := context.Background()
 	id := "ticw"
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, map[string]string{"writes": "true"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, map[string]string{"writes": "true"})
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
@@ -784,7 +800,7 @@ func TestInternalCacheWrites(t *testing.T) {
 	// create some rand test data
 	earliestTime := time.Now()
 	testData := randStringBytes(int(chunkSize*4 + chunkSize/2))
-	runInstance.writeRemoteBytes(t, rootFs, "data.bin", testData)
+	runInstance.writeRemoteBytes(ctx, t, rootFs, "data.bin", testData)
 	expectedTs := time.Now()
 	ts, err := boltDb.GetChunkTs(runInstance.encryptRemoteIfNeeded(t, path.Join(rootFs.Root(), "data.bin")), 0)
 	require.NoError(t, err)
@@ -792,9 +808,10 @@ func TestInternalCacheWrites(t *testing.T) {
 }
 
 func TestInternalMaxChunkSizeRespected(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("timcsr%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, map[string]string{"workers": "1"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil, map[string]string{"workers": "1"})
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
@@ -803,14 +820,14 @@ func TestInternalMaxChunkSizeRespected(t *testing.T) {
 
 	// create some rand test data
 	testData := randStringBytes(int(int64(totalChunks-1)*chunkSize + chunkSize/2))
-	runInstance.writeRemoteBytes(t, rootFs, "data.bin", testData)
-	o, err := cfs.NewObject(runInstance.encryptRemoteIfNeeded(t, "data.bin"))
+	runInstance.writeRemoteBytes(ctx, t, rootFs, "data.bin", testData)
+	o, err := cfs.NewObject(ctx, runInstance.encryptRemoteIfNeeded(t, "data.bin"))
 	require.NoError(t, err)
 	co, ok := o.(*cache.Object)
 	require.True(t, ok)
 
 	for i := 0; i < 4; i++ { // read first 4
-		_ = runInstance.readDataFromObj(t, co, chunkSize*int64(i), chunkSize*int64(i+1), false)
+		_ = runInstance.readDataFromObj(ctx, t, co, chunkSize*int64(i), chunkSize*int64(i+1), false)
 	}
 	cfs.CleanUpCache(true)
 	// the last 2 **must** be in the cache
@@ -818,7 +835,7 @@ func TestInternalMaxChunkSizeRespected(t *testing.T) {
 	require.True(t, boltDb.HasChunk(co, chunkSize*3))
 
 	for i := 4; i < 6; i++ { // read next 2
-		_ = runInstance.readDataFromObj(t, co, chunkSize*int64(i), chunkSize*int64(i+1), false)
+		_ = runInstance.readDataFromObj(ctx, t, co, chunkSize*int64(i), chunkSize*int64(i+1), false)
 	}
 	cfs.CleanUpCache(true)
 	// the last 2 **must** be in the cache
@@ -827,31 +844,32 @@ func TestInternalMaxChunkSizeRespected(t *testing.T) {
 }
 
 func TestInternalExpiredEntriesRemoved(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tieer%v", time.Now().Unix())
 	vfsflags.Opt.DirCacheTime = time.Second * 4 // needs to be lower than the defined
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true, map[string]string{"info_age": "5s"}, nil)
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true, map[string]string{"info_age": "5s"}, nil)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
 
 	// create some rand test data
-	runInstance.writeRemoteString(t, rootFs, "one", "one content")
-	runInstance.mkdir(t, rootFs, "test")
-	runInstance.writeRemoteString(t, rootFs, "test/second", "second content")
+	runInstance.writeRemoteString(ctx, t, rootFs, "one", "one content")
+	runInstance.mkdir(ctx, t, rootFs, "test")
+	runInstance.writeRemoteString(ctx, t, rootFs, "test/second", "second content")
 
-	l, err := runInstance.list(t, rootFs, "test")
+	l, err := runInstance.list(ctx, t, rootFs, "test")
 	require.NoError(t, err)
 	require.Len(t, l, 1)
 
-	err = cfs.UnWrap().Mkdir(runInstance.encryptRemoteIfNeeded(t, "test/third"))
+	err = cfs.UnWrap().Mkdir(ctx, runInstance.encryptRemoteIfNeeded(t, "test/third"))
 	require.NoError(t, err)
 
-	l, err = runInstance.list(t, rootFs, "test")
+	l, err = runInstance.list(ctx, t, rootFs, "test")
 	require.NoError(t, err)
 	require.Len(t, l, 1)
 
 	err = runInstance.retryBlock(func() error {
-		l, err = runInstance.list(t, rootFs, "test")
+		l, err = runInstance.list(ctx, t, rootFs, "test")
 		if err != nil {
 			return err
 		}
@@ -864,12 +882,13 @@ func TestInternalExpiredEntriesRemoved(t *testing.T) {
 }
 
 func TestInternalBug2117(t *testing.T) {
+	ctx := context.Background()
 	vfsflags.Opt.DirCacheTime = time.Second * 10
 
 	id := fmt.Sprintf("tib2117%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil,
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true, nil,
 		map[string]string{"info_age": "72h", "chunk_clean_interval": "15m"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	if runInstance.rootIsCrypt {
 		t.Skipf("skipping crypt")
@@ -878,38 +897,38 @@ func TestInternalBug2117(t *testing.T) {
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
 
-	err = cfs.UnWrap().Mkdir("test")
+	err = cfs.UnWrap().Mkdir(ctx, "test")
 	require.NoError(t, err)
 	for i := 1; i <= 4; i++ {
-		err = cfs.UnWrap().Mkdir(fmt.Sprintf("test/dir%d", i))
+		err = cfs.UnWrap().Mkdir(ctx, fmt.Sprintf("test/dir%d", i))
 		require.NoError(t, err)
 
 		for j := 1; j <= 4; j++ {
-			err = cfs.UnWrap().Mkdir(fmt.Sprintf("test/dir%d/dir%d", i, j))
+			err = cfs.UnWrap().Mkdir(ctx, fmt.Sprintf("test/dir%d/dir%d", i, j))
 			require.NoError(t, err)
 
-			runInstance.writeObjectString(t, cfs.UnWrap(), fmt.Sprintf("test/dir%d/dir%d/test.txt", i, j), "test")
+			runInstance.writeObjectString(ctx, t, cfs.UnWrap(), fmt.Sprintf("test/dir%d/dir%d/test.txt", i, j), "test")
 		}
 	}
 
-	di, err := runInstance.list(t, rootFs, "test/dir1/dir2")
+	di, err := runInstance.list(ctx, t, rootFs, "test/dir1/dir2")
 	require.NoError(t, err)
 	log.Printf("len: %v", len(di))
 	require.Len(t, di, 1)
 
 	time.Sleep(time.Second * 30)
 
-	di, err = runInstance.list(t, rootFs, "test/dir1/dir2")
+	di, err = runInstance.list(ctx, t, rootFs, "test/dir1/dir2")
 	require.NoError(t, err)
 	log.Printf("len: %v", len(di))
 	require.Len(t, di, 1)
 
-	di, err = runInstance.list(t, rootFs, "test/dir1")
+	di, err = runInstance.list(ctx, t, rootFs, "test/dir1")
 	require.NoError(t, err)
 	log.Printf("len: %v", len(di))
 	require.Len(t, di, 4)
 
-	di, err = runInstance.list(t, rootFs, "test")
+	di, err = runInstance.list(ctx, t, rootFs, "test")
 	require.NoError(t, err)
 	log.Printf("len: %v", len(di))
 	require.Len(t, di, 4)
@@ -1004,7 +1023,7 @@ func (r *run) encryptRemoteIfNeeded(t *testing.T, remote string) string {
 	return enc
 }
 
-func (r *run) newCacheFs(t *testing.T, remote, id string, needRemote, purge bool, cfg map[string]string, flags map[string]string) (fs.Fs, *cache.Persistent) {
+func (r *run) newCacheFs(ctx context.Context, t *testing.T, remote, id string, needRemote, purge bool, cfg map[string]string, flags map[string]string) (fs.Fs, *cache.Persistent) {
 	fstest.Initialise()
 	remoteExists := false
 	for _, s := range config.FileSections() {
@@ -1090,10 +1109,10 @@ func (r *run) newCacheFs(t *testing.T, remote, id string, needRemote, purge bool
 	}
 
 	if purge {
-		_ = f.Features().Purge()
+		_ = f.Features().Purge(ctx)
 		require.NoError(t, err)
 	}
-	err = f.Mkdir("")
+	err = f.Mkdir(ctx, "")
 	require.NoError(t, err)
 	if r.useMount && !r.isMounted {
 		r.mountFs(t, f)
@@ -1102,12 +1121,12 @@ func (r *run) newCacheFs(t *testing.T, remote, id string, needRemote, purge bool
 	return f, boltDb
 }
 
-func (r *run) cleanupFs(t *testing.T, f fs.Fs, b *cache.Persistent) {
+func (r *run) cleanupFs(ctx context.Context, t *testing.T, f fs.Fs, b *cache.Persistent) {
 	if r.useMount && r.isMounted {
 		r.unmountFs(t, f)
 	}
 
-	err := f.Features().Purge()
+	err := f.Features().Purge(ctx)
 	require.NoError(t, err)
 	cfs, err := r.getCacheFs(f)
 	require.NoError(t, err)
@@ -1147,32 +1166,32 @@ func (r *run) randomReader(t *testing.T, size int64) io.ReadCloser {
 	return f
 }
 
-func (r *run) writeRemoteRandomBytes(t *testing.T, f fs.Fs, p string, size int64) string {
+func (r *run) writeRemoteRandomBytes(ctx context.Context, t *testing.T, f fs.Fs, p string, size int64) string {
 	remote := path.Join(p, strconv.Itoa(rand.Int())+".bin")
 	// create some rand test data
 	testData := randStringBytes(int(size))
 
-	r.writeRemoteBytes(t, f, remote, testData)
+	r.writeRemoteBytes(ctx, t, f, remote, testData)
 	return remote
 }
 
-func (r *run) writeObjectRandomBytes(t *testing.T, f fs.Fs, p string, size int64) fs.Object {
+func (r *run) writeObjectRandomBytes(ctx context.Context, t *testing.T, f fs.Fs, p string, size int64) fs.Object {
 	remote := path.Join(p, strconv.Itoa(rand.Int())+".bin")
 	// create some rand test data
 	testData := randStringBytes(int(size))
 
-	return r.writeObjectBytes(t, f, remote, testData)
+	return r.writeObjectBytes(ctx, t, f, remote, testData)
 }
 
-func (r *run) writeRemoteString(t *testing.T, f fs.Fs, remote, content string) {
-	r.writeRemoteBytes(t, f, remote, []byte(content))
+func (r *run) writeRemoteString(ctx context.Context, t *testing.T, f fs.Fs, remote, content string) {
+	r.writeRemoteBytes(ctx, t, f, remote, []byte(content))
 }
 
-func (r *run) writeObjectString(t *testing.T, f fs.Fs, remote, content string) fs.Object {
-	return r.writeObjectBytes(t, f, remote, []byte(content))
+func (r *run) writeObjectString(ctx context.Context, t *testing.T, f fs.Fs, remote, content string) fs.Object {
+	return r.writeObjectBytes(ctx, t, f, remote, []byte(content))
 }
 
-func (r *run) writeRemoteBytes(t *testing.T, f fs.Fs, remote string, data []byte) {
+func (r *run) writeRemoteBytes(ctx context.Context, t *testing.T, f fs.Fs, remote string, data []byte) {
 	var err error
 
 	if r.useMount {
@@ -1182,11 +1201,11 @@ func (r *run) writeRemoteBytes(t *testing.T, f fs.Fs, remote string, data []byte
 		require.NoError(t, err)
 		r.vfs.WaitForWriters(10 * time.Second)
 	} else {
-		r.writeObjectBytes(t, f, remote, data)
+		r.writeObjectBytes(ctx, t, f, remote, data)
 	}
 }
 
-func (r *run) writeRemoteReader(t *testing.T, f fs.Fs, remote string, in io.ReadCloser) {
+func (r *run) writeRemoteReader(ctx context.Context, t *testing.T, f fs.Fs, remote string, in io.ReadCloser) {
 	defer func() {
 		_ = in.Close()
 	}()
@@ -1202,23 +1221,23 @@ func (r *run) writeRemoteReader(t *testing.T, f fs.Fs, remote string, in io.Read
 		require.NoError(t, err)
 		r.vfs.WaitForWriters(10 * time.Second)
 	} else {
-		r.writeObjectReader(t, f, remote, in)
+		r.writeObjectReader(ctx, t, f, remote, in)
 	}
 }
 
-func (r *run) writeObjectBytes(t *testing.T, f fs.Fs, remote string, data []byte) fs.Object {
+func (r *run) writeObjectBytes(ctx context.Context, t *testing.T, f fs.Fs, remote string, data []byte) fs.Object {
 	in := bytes.NewReader(data)
-	_ = r.writeObjectReader(t, f, remote, in)
-	o, err := f.NewObject(remote)
+	_ = r.writeObjectReader(ctx, t, f, remote, in)
+	o, err := f.NewObject(ctx, remote)
 	require.NoError(t, err)
 	require.Equal(t, int64(len(data)), o.Size())
 	return o
 }
 
-func (r *run) writeObjectReader(t *testing.T, f fs.Fs, remote string, in io.Reader) fs.Object {
+func (r *run) writeObjectReader(ctx context.Context, t *testing.T, f fs.Fs, remote string, in io.Reader) fs.Object {
 	modTime := time.Now()
 	objInfo := object.NewStaticObjectInfo(remote, modTime, -1, true, nil, f)
-	obj, err := f.Put(in, objInfo)
+	obj, err := f.Put(ctx, in, objInfo)
 	require.NoError(t, err)
 	if r.useMount {
 		r.vfs.WaitForWriters(10 * time.Second)
@@ -1227,7 +1246,7 @@ func (r *run) writeObjectReader(t *testing.T, f fs.Fs, remote string, in io.Read
 	return obj
 }
 
-func (r *run) updateObjectRemote(t *testing.T, f fs.Fs, remote string, data1 []byte, data2 []byte) fs.Object {
+func (r *run) updateObjectRemote(ctx context.Context, t *testing.T, f fs.Fs, remote string, data1 []byte, data2 []byte) fs.Object {
 	var err error
 	var obj fs.Object
 
@@ -1238,25 +1257,25 @@ func (r *run) updateObjectRemote(t *testing.T, f fs.Fs, remote string, data1 []b
 		err = ioutil.WriteFile(path.Join(r.mntDir, remote), data2, 0600)
 		require.NoError(t, err)
 		r.vfs.WaitForWriters(10 * time.Second)
-		obj, err = f.NewObject(remote)
+		obj, err = f.NewObject(ctx, remote)
 	} else {
 		in1 := bytes.NewReader(data1)
 		in2 := bytes.NewReader(data2)
 		objInfo1 := object.NewStaticObjectInfo(remote, time.Now(), int64(len(data1)), true, nil, f)
 		objInfo2 := object.NewStaticObjectInfo(remote, time.Now(), int64(len(data2)), true, nil, f)
 
-		obj, err = f.Put(in1, objInfo1)
+		obj, err = f.Put(ctx, in1, objInfo1)
 		require.NoError(t, err)
-		obj, err = f.NewObject(remote)
+		obj, err = f.NewObject(ctx, remote)
 		require.NoError(t, err)
-		err = obj.Update(in2, objInfo2)
+		err = obj.Update(ctx, in2, objInfo2)
 	}
 	require.NoError(t, err)
 
 	return obj
 }
 
-func (r *run) readDataFromRemote(t *testing.T, f fs.Fs, remote string, offset, end int64, noLengthCheck bool) ([]byte, error) {
+func (r *run) readDataFromRemote(ctx context.Context, t *testing.T, f fs.Fs, remote string, offset, end int64, noLengthCheck bool) ([]byte, error) {
 	size := end - offset
 	checkSample := make([]byte, size)
 
@@ -1278,11 +1297,11 @@ func (r *run) readDataFromRemote(t *testing.T, f fs.Fs, remote string, offset, e
 			return checkSample, err
 		}
 	} else {
-		co, err := f.NewObject(remote)
+		co, err := f.NewObject(ctx, remote)
 		if err != nil {
 			return checkSample, err
 		}
-		checkSample = r.readDataFromObj(t, co, offset, end, noLengthCheck)
+		checkSample = r.readDataFromObj(ctx, t, co, offset, end, noLengthCheck)
 	}
 	if !noLengthCheck && size != int64(len(checkSample)) {
 		return checkSample, errors.Errorf("read size doesn't match expected: %v <> %v", len(checkSample), size)
@@ -1290,10 +1309,10 @@ func (r *run) readDataFromRemote(t *testing.T, f fs.Fs, remote string, offset, e
 	return checkSample, nil
 }
 
-func (r *run) readDataFromObj(t *testing.T, o fs.Object, offset, end int64, noLengthCheck bool) []byte {
+func (r *run) readDataFromObj(ctx context.Context, t *testing.T, o fs.Object, offset, end int64, noLengthCheck bool) []byte {
 	size := end - offset
 	checkSample := make([]byte, size)
-	reader, err := o.Open(&fs.SeekOption{Offset: offset})
+	reader, err := o.Open(ctx, &fs.SeekOption{Offset: offset})
 	require.NoError(t, err)
 	totalRead, err := io.ReadFull(reader, checkSample)
 	if (err == io.EOF || err == io.ErrUnexpectedEOF) && noLengthCheck {
@@ -1305,35 +1324,35 @@ func (r *run) readDataFromObj(t *testing.T, o fs.Object, offset, end int64, noLe
 	return checkSample
 }
 
-func (r *run) mkdir(t *testing.T, f fs.Fs, remote string) {
+func (r *run) mkdir(ctx context.Context, t *testing.T, f fs.Fs, remote string) {
 	var err error
 	if r.useMount {
 		err = os.Mkdir(path.Join(r.mntDir, remote), 0700)
 	} else {
-		err = f.Mkdir(remote)
+		err = f.Mkdir(ctx, remote)
 	}
 	require.NoError(t, err)
 }
 
-func (r *run) rm(t *testing.T, f fs.Fs, remote string) error {
+func (r *run) rm(ctx context.Context, t *testing.T, f fs.Fs, remote string) error {
 	var err error
 
 	if r.useMount {
 		err = os.Remove(path.Join(r.mntDir, remote))
 	} else {
 		var obj fs.Object
-		obj, err = f.NewObject(remote)
+		obj, err = f.NewObject(ctx, remote)
 		if err != nil {
-			err = f.Rmdir(remote)
+			err = f.Rmdir(ctx, remote)
 		} else {
-			err = obj.Remove()
+			err = obj.Remove(ctx)
 		}
 	}
 
 	return err
 }
 
-func (r *run) list(t *testing.T, f fs.Fs, remote string) ([]interface{}, error) {
+func (r *run) list(ctx context.Context, t *testing.T, f fs.Fs, remote string) ([]interface{}, error) {
 	var err error
 	var l []interface{}
 	if r.useMount {
@@ -1344,7 +1363,7 @@ func (r *run) list(t *testing.T, f fs.Fs, remote string) ([]interface{}, error)
 		}
 	} else {
 		var list fs.DirEntries
-		list, err = f.List(remote)
+		list, err = f.List(ctx, remote)
 		for _, ll := range list {
 			l = append(l, ll)
 		}
@@ -1352,7 +1371,7 @@ func (r *run) list(t *testing.T, f fs.Fs, remote string) ([]interface{}, error)
 	return l, err
 }
 
-func (r *run) listPath(t *testing.T, f fs.Fs, remote string) []string {
+func (r *run) listPath(ctx context.Context, t *testing.T, f fs.Fs, remote string) []string {
 	var err error
 	var l []string
 	if r.useMount {
@@ -1363,7 +1382,7 @@ func (r *run) listPath(t *testing.T, f fs.Fs, remote string) []string {
 		}
 	} else {
 		var list fs.DirEntries
-		list, err = f.List(remote)
+		list, err = f.List(ctx, remote)
 		for _, ll := range list {
 			l = append(l, ll.Remote())
 		}
@@ -1393,7 +1412,7 @@ func (r *run) copyFile(t *testing.T, f fs.Fs, src, dst string) error {
 	return err
 }
 
-func (r *run) dirMove(t *testing.T, rootFs fs.Fs, src, dst string) error {
+func (r *run) dirMove(ctx context.Context, t *testing.T, rootFs fs.Fs, src, dst string) error {
 	var err error
 
 	if runInstance.useMount {
@@ -1403,7 +1422,7 @@ func (r *run) dirMove(t *testing.T, rootFs fs.Fs, src, dst string) error {
 		}
 		r.vfs.WaitForWriters(10 * time.Second)
 	} else if rootFs.Features().DirMove != nil {
-		err = rootFs.Features().DirMove(rootFs, src, dst)
+		err = rootFs.Features().DirMove(ctx, rootFs, src, dst)
 		if err != nil {
 			return err
 		}
@@ -1415,7 +1434,7 @@ func (r *run) dirMove(t *testing.T, rootFs fs.Fs, src, dst string) error {
 	return err
 }
 
-func (r *run) move(t *testing.T, rootFs fs.Fs, src, dst string) error {
+func (r *run) move(ctx context.Context, t *testing.T, rootFs fs.Fs, src, dst string) error {
 	var err error
 
 	if runInstance.useMount {
@@ -1425,11 +1444,11 @@ func (r *run) move(t *testing.T, rootFs fs.Fs, src, dst string) error {
 		}
 		r.vfs.WaitForWriters(10 * time.Second)
 	} else if rootFs.Features().Move != nil {
-		obj1, err := rootFs.NewObject(src)
+		obj1, err := rootFs.NewObject(ctx, src)
 		if err != nil {
 			return err
 		}
-		_, err = rootFs.Features().Move(obj1, dst)
+		_, err = rootFs.Features().Move(ctx, obj1, dst)
 		if err != nil {
 			return err
 		}
@@ -1441,7 +1460,7 @@ func (r *run) move(t *testing.T, rootFs fs.Fs, src, dst string) error {
 	return err
 }
 
-func (r *run) copy(t *testing.T, rootFs fs.Fs, src, dst string) error {
+func (r *run) copy(ctx context.Context, t *testing.T, rootFs fs.Fs, src, dst string) error {
 	var err error
 
 	if r.useMount {
@@ -1451,11 +1470,11 @@ func (r *run) copy(t *testing.T, rootFs fs.Fs, src, dst string) error {
 		}
 		r.vfs.WaitForWriters(10 * time.Second)
 	} else if rootFs.Features().Copy != nil {
-		obj, err := rootFs.NewObject(src)
+		obj, err := rootFs.NewObject(ctx, src)
 		if err != nil {
 			return err
 		}
-		_, err = rootFs.Features().Copy(obj, dst)
+		_, err = rootFs.Features().Copy(ctx, obj, dst)
 		if err != nil {
 			return err
 		}
@@ -1467,7 +1486,7 @@ func (r *run) copy(t *testing.T, rootFs fs.Fs, src, dst string) error {
 	return err
 }
 
-func (r *run) modTime(t *testing.T, rootFs fs.Fs, src string) (time.Time, error) {
+func (r *run) modTime(ctx context.Context, t *testing.T, rootFs fs.Fs, src string) (time.Time, error) {
 	var err error
 
 	if r.useMount {
@@ -1477,14 +1496,14 @@ func (r *run) modTime(t *testing.T, rootFs fs.Fs, src string) (time.Time, error)
 		}
 		return fi.ModTime(), nil
 	}
-	obj1, err := rootFs.NewObject(src)
+	obj1, err := rootFs.NewObject(ctx, src)
 	if err != nil {
 		return time.Time{}, err
 	}
 	return obj1.ModTime(), nil
 }
 
-func (r *run) size(t *testing.T, rootFs fs.Fs, src string) (int64, error) {
+func (r *run) size(ctx context.Context, t *testing.T, rootFs fs.Fs, src string) (int64, error) {
 	var err error
 
 	if r.useMount {
@@ -1494,14 +1513,14 @@ func (r *run) size(t *testing.T, rootFs fs.Fs, src string) (int64, error) {
 		}
 		return fi.Size(), nil
 	}
-	obj1, err := rootFs.NewObject(src)
+	obj1, err := rootFs.NewObject(ctx, src)
 	if err != nil {
 		return int64(0), err
 	}
 	return obj1.Size(), nil
 }
 
-func (r *run) updateData(t *testing.T, rootFs fs.Fs, src, data, append string) error {
+func (r *run) updateData(ctx context.Context, t *testing.T, rootFs fs.Fs, src, data, append string) error {
 	var err error
 
 	if r.useMount {
@@ -1515,14 +1534,14 @@ func (r *run) updateData(t *testing.T, rootFs fs.Fs, src, data, append string) e
 		}()
 		_, err = f.WriteString(data + append)
 	} else {
-		obj1, err := rootFs.NewObject(src)
+		obj1, err := rootFs.NewObject(ctx, src)
 		if err != nil {
 			return err
 		}
 		data1 := []byte(data + append)
 		r := bytes.NewReader(data1)
 		objInfo1 := object.NewStaticObjectInfo(src, time.Now(), int64(len(data1)), true, nil, rootFs)
-		err = obj1.Update(r, objInfo1)
+		err = obj1.Update(ctx, r, objInfo1)
 	}
 
 	return err
@@ -3,6 +3,7 @@
 package cache_test
 
 import (
+	"context"
 	"math/rand"
 	"os"
 	"path"
@@ -19,22 +20,23 @@ import (
 )
 
 func TestInternalUploadTempDirCreated(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiutdc%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true,
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, false, true,
 		nil,
 		map[string]string{"tmp_upload_path": path.Join(runInstance.tmpUploadDir, id)})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	_, err := os.Stat(path.Join(runInstance.tmpUploadDir, id))
 	require.NoError(t, err)
 }
 
-func testInternalUploadQueueOneFile(t *testing.T, id string, rootFs fs.Fs, boltDb *cache.Persistent) {
+func testInternalUploadQueueOneFile(ctx context.Context, t *testing.T, id string, rootFs fs.Fs, boltDb *cache.Persistent) {
 	// create some rand test data
 	testSize := int64(524288000)
 	testReader := runInstance.randomReader(t, testSize)
 	bu := runInstance.listenForBackgroundUpload(t, rootFs, "one")
-	runInstance.writeRemoteReader(t, rootFs, "one", testReader)
+	runInstance.writeRemoteReader(ctx, t, rootFs, "one", testReader)
 	// validate that it exists in temp fs
 	ti, err := os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "one")))
 	require.NoError(t, err)
@@ -44,7 +46,7 @@ func testInternalUploadQueueOneFile(t *testing.T, id string, rootFs fs.Fs, boltD
 	} else {
 		require.Equal(t, testSize, ti.Size())
 	}
-	de1, err := runInstance.list(t, rootFs, "")
+	de1, err := runInstance.list(ctx, t, rootFs, "")
 	require.NoError(t, err)
 	require.Len(t, de1, 1)
 
@@ -54,52 +56,55 @@ func testInternalUploadQueueOneFile(t *testing.T, id string, rootFs fs.Fs, boltD
 	require.True(t, os.IsNotExist(err))
 
 	// check if it can be read
-	data2, err := runInstance.readDataFromRemote(t, rootFs, "one", 0, int64(1024), false)
+	data2, err := runInstance.readDataFromRemote(ctx, t, rootFs, "one", 0, int64(1024), false)
 	require.NoError(t, err)
 	require.Len(t, data2, 1024)
 }
 
 func TestInternalUploadQueueOneFileNoRest(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiuqofnr%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true,
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true,
 		nil,
 		map[string]string{"tmp_upload_path": path.Join(runInstance.tmpUploadDir, id), "tmp_wait_time": "0s"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
-	testInternalUploadQueueOneFile(t, id, rootFs, boltDb)
+	testInternalUploadQueueOneFile(ctx, t, id, rootFs, boltDb)
 }
 
 func TestInternalUploadQueueOneFileWithRest(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiuqofwr%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true,
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true,
 		nil,
 		map[string]string{"tmp_upload_path": path.Join(runInstance.tmpUploadDir, id), "tmp_wait_time": "1m"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
-	testInternalUploadQueueOneFile(t, id, rootFs, boltDb)
+	testInternalUploadQueueOneFile(ctx, t, id, rootFs, boltDb)
 }
 
 func TestInternalUploadMoveExistingFile(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiumef%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true,
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true,
 		nil,
 		map[string]string{"tmp_upload_path": path.Join(runInstance.tmpUploadDir, id), "tmp_wait_time": "3s"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
-	err := rootFs.Mkdir("one")
+	err := rootFs.Mkdir(ctx, "one")
 	require.NoError(t, err)
-	err = rootFs.Mkdir("one/test")
+	err = rootFs.Mkdir(ctx, "one/test")
 	require.NoError(t, err)
-	err = rootFs.Mkdir("second")
+	err = rootFs.Mkdir(ctx, "second")
 	require.NoError(t, err)
 
 	// create some rand test data
 	testSize := int64(10485760)
 	testReader := runInstance.randomReader(t, testSize)
-	runInstance.writeObjectReader(t, rootFs, "one/test/data.bin", testReader)
+	runInstance.writeObjectReader(ctx, t, rootFs, "one/test/data.bin", testReader)
 	runInstance.completeAllBackgroundUploads(t, rootFs, "one/test/data.bin")
 
-	de1, err := runInstance.list(t, rootFs, "one/test")
+	de1, err := runInstance.list(ctx, t, rootFs, "one/test")
 	require.NoError(t, err)
 	require.Len(t, de1, 1)
 
@@ -107,35 +112,36 @@ func TestInternalUploadMoveExistingFile(t *testing.T) {
 	//_ = os.Remove(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "one/test")))
 	//require.NoError(t, err)
 
-	err = runInstance.dirMove(t, rootFs, "one/test", "second/test")
+	err = runInstance.dirMove(ctx, t, rootFs, "one/test", "second/test")
 	require.NoError(t, err)
 
 	// check if it can be read
-	de1, err = runInstance.list(t, rootFs, "second/test")
+	de1, err = runInstance.list(ctx, t, rootFs, "second/test")
 	require.NoError(t, err)
 	require.Len(t, de1, 1)
 }
 
 func TestInternalUploadTempPathCleaned(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiutpc%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true,
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true,
 		nil,
 		map[string]string{"cache-tmp-upload-path": path.Join(runInstance.tmpUploadDir, id), "cache-tmp-wait-time": "5s"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
-	err := rootFs.Mkdir("one")
+	err := rootFs.Mkdir(ctx, "one")
 	require.NoError(t, err)
-	err = rootFs.Mkdir("one/test")
+	err = rootFs.Mkdir(ctx, "one/test")
 	require.NoError(t, err)
-	err = rootFs.Mkdir("second")
+	err = rootFs.Mkdir(ctx, "second")
 	require.NoError(t, err)
 
 	// create some rand test data
 	testSize := int64(1048576)
 	testReader := runInstance.randomReader(t, testSize)
 	testReader2 := runInstance.randomReader(t, testSize)
-	runInstance.writeObjectReader(t, rootFs, "one/test/data.bin", testReader)
-	runInstance.writeObjectReader(t, rootFs, "second/data.bin", testReader2)
+	runInstance.writeObjectReader(ctx, t, rootFs, "one/test/data.bin", testReader)
+	runInstance.writeObjectReader(ctx, t, rootFs, "second/data.bin", testReader2)
 
 	runInstance.completeAllBackgroundUploads(t, rootFs, "one/test/data.bin")
 	_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "one/test")))
@@ -149,24 +155,25 @@ func TestInternalUploadTempPathCleaned(t *testing.T) {
 	_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "second/data.bin")))
 	require.True(t, os.IsNotExist(err))
 
-	de1, err := runInstance.list(t, rootFs, "one/test")
+	de1, err := runInstance.list(ctx, t, rootFs, "one/test")
 	require.NoError(t, err)
 	require.Len(t, de1, 1)
 
 	// check if it can be read
-	de1, err = runInstance.list(t, rootFs, "second")
+	de1, err = runInstance.list(ctx, t, rootFs, "second")
 	require.NoError(t, err)
 	require.Len(t, de1, 1)
 }
 
 func TestInternalUploadQueueMoreFiles(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiuqmf%v", time.Now().Unix())
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true,
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true,
 		nil,
 		map[string]string{"tmp_upload_path": path.Join(runInstance.tmpUploadDir, id), "tmp_wait_time": "1s"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
-	err := rootFs.Mkdir("test")
+	err := rootFs.Mkdir(ctx, "test")
 	require.NoError(t, err)
 	minSize := 5242880
 	maxSize := 10485760
@@ -178,7 +185,7 @@ func TestInternalUploadQueueMoreFiles(t *testing.T) {
 		size := int64(rand.Intn(maxSize-minSize) + minSize)
 		testReader := runInstance.randomReader(t, size)
 		remote := "test/" + strconv.Itoa(i) + ".bin"
-		runInstance.writeRemoteReader(t, rootFs, remote, testReader)
+		runInstance.writeRemoteReader(ctx, t, rootFs, remote, testReader)
 
 		// validate that it exists in temp fs
 		ti, err := os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, remote)))
@@ -192,7 +199,7 @@ func TestInternalUploadQueueMoreFiles(t *testing.T) {
 	}
 
 	// check if cache lists all files, likely temp upload didn't finish yet
-	de1, err := runInstance.list(t, rootFs, "test")
+	de1, err := runInstance.list(ctx, t, rootFs, "test")
 	require.NoError(t, err)
 	require.Len(t, de1, totalFiles)
 
@@ -204,26 +211,27 @@ func TestInternalUploadQueueMoreFiles(t *testing.T) {
 	require.True(t, os.IsNotExist(err))
 
 	// check if cache lists all files
-	de1, err = runInstance.list(t, rootFs, "test")
+	de1, err = runInstance.list(ctx, t, rootFs, "test")
 	require.NoError(t, err)
 	require.Len(t, de1, totalFiles)
 }
 
 func TestInternalUploadTempFileOperations(t *testing.T) {
+	ctx := context.Background()
 	id := "tiutfo"
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true,
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true,
 		nil,
 		map[string]string{"tmp_upload_path": path.Join(runInstance.tmpUploadDir, id), "tmp_wait_time": "1h"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	boltDb.PurgeTempUploads()
 
 	// create some rand test data
-	runInstance.mkdir(t, rootFs, "test")
-	runInstance.writeRemoteString(t, rootFs, "test/one", "one content")
+	runInstance.mkdir(ctx, t, rootFs, "test")
+	runInstance.writeRemoteString(ctx, t, rootFs, "test/one", "one content")
 
 	// check if it can be read
-	data1, err := runInstance.readDataFromRemote(t, rootFs, "test/one", 0, int64(len([]byte("one content"))), false)
+	data1, err := runInstance.readDataFromRemote(ctx, t, rootFs, "test/one", 0, int64(len([]byte("one content"))), false)
 	require.NoError(t, err)
 	require.Equal(t, []byte("one content"), data1)
 	// validate that it exists in temp fs
@@ -231,12 +239,12 @@ func TestInternalUploadTempFileOperations(t *testing.T) {
 	require.NoError(t, err)
 
 	// test DirMove - allowed
-	err = runInstance.dirMove(t, rootFs, "test", "second")
+	err = runInstance.dirMove(ctx, t, rootFs, "test", "second")
 	if err != errNotSupported {
 		require.NoError(t, err)
-		_, err = rootFs.NewObject("test/one")
+		_, err = rootFs.NewObject(ctx, "test/one")
 		require.Error(t, err)
-		_, err = rootFs.NewObject("second/one")
+		_, err = rootFs.NewObject(ctx, "second/one")
 		require.NoError(t, err)
 		// validate that it exists in temp fs
 		_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "test/one")))
@@ -249,15 +257,15 @@ func TestInternalUploadTempFileOperations(t *testing.T) {
 		started, err = boltDb.SearchPendingUpload(runInstance.encryptRemoteIfNeeded(t, path.Join(id, "second/one")))
 		require.NoError(t, err)
 		require.False(t, started)
-		runInstance.mkdir(t, rootFs, "test")
-		runInstance.writeRemoteString(t, rootFs, "test/one", "one content")
+		runInstance.mkdir(ctx, t, rootFs, "test")
+		runInstance.writeRemoteString(ctx, t, rootFs, "test/one", "one content")
 	}
 
 	// test Rmdir - allowed
-	err = runInstance.rm(t, rootFs, "test")
+	err = runInstance.rm(ctx, t, rootFs, "test")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "directory not empty")
-	_, err = rootFs.NewObject("test/one")
+	_, err = rootFs.NewObject(ctx, "test/one")
 	require.NoError(t, err)
 	// validate that it exists in temp fs
 	_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "test/one")))
@@ -267,15 +275,15 @@ func TestInternalUploadTempFileOperations(t *testing.T) {
 	require.NoError(t, err)
 
 	// test Move/Rename -- allowed
-	err = runInstance.move(t, rootFs, path.Join("test", "one"), path.Join("test", "second"))
+	err = runInstance.move(ctx, t, rootFs, path.Join("test", "one"), path.Join("test", "second"))
 	if err != errNotSupported {
 		require.NoError(t, err)
 		// try to read from it
-		_, err = rootFs.NewObject("test/one")
+		_, err = rootFs.NewObject(ctx, "test/one")
 		require.Error(t, err)
-		_, err = rootFs.NewObject("test/second")
+		_, err = rootFs.NewObject(ctx, "test/second")
 		require.NoError(t, err)
-		data2, err := runInstance.readDataFromRemote(t, rootFs, "test/second", 0, int64(len([]byte("one content"))), false)
+		data2, err := runInstance.readDataFromRemote(ctx, t, rootFs, "test/second", 0, int64(len([]byte("one content"))), false)
 		require.NoError(t, err)
 		require.Equal(t, []byte("one content"), data2)
 		// validate that it exists in temp fs
@@ -283,18 +291,18 @@ func TestInternalUploadTempFileOperations(t *testing.T) {
 		require.Error(t, err)
 		_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "test/second")))
 		require.NoError(t, err)
-		runInstance.writeRemoteString(t, rootFs, "test/one", "one content")
+		runInstance.writeRemoteString(ctx, t, rootFs, "test/one", "one content")
 	}
 
 	// test Copy -- allowed
-	err = runInstance.copy(t, rootFs, path.Join("test", "one"), path.Join("test", "third"))
+	err = runInstance.copy(ctx, t, rootFs, path.Join("test", "one"), path.Join("test", "third"))
 	if err != errNotSupported {
 		require.NoError(t, err)
-		_, err = rootFs.NewObject("test/one")
+		_, err = rootFs.NewObject(ctx, "test/one")
 		require.NoError(t, err)
-		_, err = rootFs.NewObject("test/third")
+		_, err = rootFs.NewObject(ctx, "test/third")
 		require.NoError(t, err)
-		data2, err := runInstance.readDataFromRemote(t, rootFs, "test/third", 0, int64(len([]byte("one content"))), false)
+		data2, err := runInstance.readDataFromRemote(ctx, t, rootFs, "test/third", 0, int64(len([]byte("one content"))), false)
 		require.NoError(t, err)
 		require.Equal(t, []byte("one content"), data2)
 		// validate that it exists in temp fs
@@ -305,23 +313,23 @@ func TestInternalUploadTempFileOperations(t *testing.T) {
 	}
 
 	// test Remove -- allowed
-	err = runInstance.rm(t, rootFs, "test/one")
+	err = runInstance.rm(ctx, t, rootFs, "test/one")
 	require.NoError(t, err)
-	_, err = rootFs.NewObject("test/one")
+	_, err = rootFs.NewObject(ctx, "test/one")
 	require.Error(t, err)
 	// validate that it doesn't exist in temp fs
 	_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "test/one")))
 	require.Error(t, err)
-	runInstance.writeRemoteString(t, rootFs, "test/one", "one content")
+	runInstance.writeRemoteString(ctx, t, rootFs, "test/one", "one content")
 
 	// test Update -- allowed
-	firstModTime, err := runInstance.modTime(t, rootFs, "test/one")
+	firstModTime, err := runInstance.modTime(ctx, t, rootFs, "test/one")
 	require.NoError(t, err)
-	err = runInstance.updateData(t, rootFs, "test/one", "one content", " updated")
+	err = runInstance.updateData(ctx, t, rootFs, "test/one", "one content", " updated")
 	require.NoError(t, err)
-	obj2, err := rootFs.NewObject("test/one")
+	obj2, err := rootFs.NewObject(ctx, "test/one")
 	require.NoError(t, err)
-	data2 := runInstance.readDataFromObj(t, obj2, 0, int64(len("one content updated")), false)
+	data2 := runInstance.readDataFromObj(ctx, t, obj2, 0, int64(len("one content updated")), false)
 	require.Equal(t, "one content updated", string(data2))
 	tmpInfo, err := os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "test/one")))
 	require.NoError(t, err)
@@ -332,7 +340,7 @@ func TestInternalUploadTempFileOperations(t *testing.T) {
 	}
 
 	// test SetModTime -- allowed
-	secondModTime, err := runInstance.modTime(t, rootFs, "test/one")
+	secondModTime, err := runInstance.modTime(ctx, t, rootFs, "test/one")
 	require.NoError(t, err)
 	require.NotEqual(t, secondModTime, firstModTime)
 	require.NotEqual(t, time.Time{}, firstModTime)
@@ -340,20 +348,21 @@ func TestInternalUploadTempFileOperations(t *testing.T) {
 }
 
 func TestInternalUploadUploadingFileOperations(t *testing.T) {
+	ctx := context.Background()
 	id := "tiuufo"
-	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true,
+	rootFs, boltDb := runInstance.newCacheFs(ctx, t, remoteName, id, true, true,
 		nil,
 		map[string]string{"tmp_upload_path": path.Join(runInstance.tmpUploadDir, id), "tmp_wait_time": "1h"})
-	defer runInstance.cleanupFs(t, rootFs, boltDb)
+	defer runInstance.cleanupFs(ctx, t, rootFs, boltDb)
 
 	boltDb.PurgeTempUploads()
 
 	// create some rand test data
-	runInstance.mkdir(t, rootFs, "test")
-	runInstance.writeRemoteString(t, rootFs, "test/one", "one content")
+	runInstance.mkdir(ctx, t, rootFs, "test")
+	runInstance.writeRemoteString(ctx, t, rootFs, "test/one", "one content")
 
 	// check if it can be read
-	data1, err := runInstance.readDataFromRemote(t, rootFs, "test/one", 0, int64(len([]byte("one content"))), false)
+	data1, err := runInstance.readDataFromRemote(ctx, t, rootFs, "test/one", 0, int64(len([]byte("one content"))), false)
 	require.NoError(t, err)
 	require.Equal(t, []byte("one content"), data1)
 	// validate that it exists in temp fs
@@ -364,10 +373,10 @@ func TestInternalUploadUploadingFileOperations(t *testing.T) {
 	require.NoError(t, err)
 
 	// test DirMove
-	err = runInstance.dirMove(t, rootFs, "test", "second")
+	err = runInstance.dirMove(ctx, t, rootFs, "test", "second")
 	if err != errNotSupported {
 		require.Error(t, err)
-		_, err = rootFs.NewObject("test/one")
+		_, err = rootFs.NewObject(ctx, "test/one")
 		require.NoError(t, err)
 		// validate that it exists in temp fs
 		_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "test/one")))
@@ -377,22 +386,22 @@ func TestInternalUploadUploadingFileOperations(t *testing.T) {
 	}
 
 	// test Rmdir
-	err = runInstance.rm(t, rootFs, "test")
+	err = runInstance.rm(ctx, t, rootFs, "test")
 	require.Error(t, err)
-	_, err = rootFs.NewObject("test/one")
+	_, err = rootFs.NewObject(ctx, "test/one")
 	require.NoError(t, err)
 	// validate that it doesn't exist in temp fs
 	_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "test/one")))
 	require.NoError(t, err)
 
 	// test Move/Rename
-	err = runInstance.move(t, rootFs, path.Join("test", "one"), path.Join("test", "second"))
+	err = runInstance.move(ctx, t, rootFs, path.Join("test", "one"), path.Join("test", "second"))
 	if err != errNotSupported {
 		require.Error(t, err)
 		// try to read from it
-		_, err = rootFs.NewObject("test/one")
+		_, err = rootFs.NewObject(ctx, "test/one")
 		require.NoError(t, err)
-		_, err = rootFs.NewObject("test/second")
+		_, err = rootFs.NewObject(ctx, "test/second")
 		require.Error(t, err)
 		// validate that it exists in temp fs
 		_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "test/one")))
@@ -402,14 +411,14 @@ func TestInternalUploadUploadingFileOperations(t *testing.T) {
 	}
 
 	// test Copy -- allowed
-	err = runInstance.copy(t, rootFs, path.Join("test", "one"), path.Join("test", "third"))
+	err = runInstance.copy(ctx, t, rootFs, path.Join("test", "one"), path.Join("test", "third"))
 	if err != errNotSupported {
 		require.NoError(t, err)
-		_, err = rootFs.NewObject("test/one")
+		_, err = rootFs.NewObject(ctx, "test/one")
 		require.NoError(t, err)
-		_, err = rootFs.NewObject("test/third")
+		_, err = rootFs.NewObject(ctx, "test/third")
 		require.NoError(t, err)
-		data2, err := runInstance.readDataFromRemote(t, rootFs, "test/third", 0, int64(len([]byte("one content"))), false)
+		data2, err := runInstance.readDataFromRemote(ctx, t, rootFs, "test/third", 0, int64(len([]byte("one content"))), false)
 		require.NoError(t, err)
 		require.Equal(t, []byte("one content"), data2)
 		// validate that it exists in temp fs
@@ -420,14 +429,14 @@ func TestInternalUploadUploadingFileOperations(t *testing.T) {
 	}
 
 	// test Remove
-	err = runInstance.rm(t, rootFs, "test/one")
+	err = runInstance.rm(ctx, t, rootFs, "test/one")
 	require.Error(t, err)
-	_, err = rootFs.NewObject("test/one")
+	_, err = rootFs.NewObject(ctx, "test/one")
 	require.NoError(t, err)
 	// validate that it doesn't exist in temp fs
 	_, err = os.Stat(path.Join(runInstance.tmpUploadDir, id, runInstance.encryptRemoteIfNeeded(t, "test/one")))
 	require.NoError(t, err)
-	runInstance.writeRemoteString(t, rootFs, "test/one", "one content")
+	runInstance.writeRemoteString(ctx, t, rootFs, "test/one", "one content")
 
 	// test Update - this seems to work. Why? FIXME
 	//firstModTime, err := runInstance.modTime(t, rootFs, "test/one")
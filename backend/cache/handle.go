@@ -3,6 +3,7 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"sync"
@@ -41,6 +42,7 @@ func initBackgroundUploader(fs *Fs) (*backgroundWriter, error) {
 
 // Handle is managing the read/write/seek operations on an open handle
 type Handle struct {
+	ctx            context.Context
 	cachedObject   *Object
 	cfs            *Fs
 	memory         *Memory
@@ -58,8 +60,9 @@ type Handle struct {
 }
 
 // NewObjectHandle returns a new Handle for an existing Object
-func NewObjectHandle(o *Object, cfs *Fs) *Handle {
+func NewObjectHandle(ctx context.Context, o *Object, cfs *Fs) *Handle {
 	r := &Handle{
+		ctx:           ctx,
 		cachedObject:  o,
 		cfs:           cfs,
 		offset:        0,
@@ -134,7 +137,7 @@ func (r *Handle) scaleWorkers(desired int) {
 				ch: r.preloadQueue,
 				id: current + i,
 			}
-			go w.run()
+			go w.run(r.ctx)
 
 			r.workers = append(r.workers, w)
 		}
@@ -365,12 +368,12 @@ func (w *worker) String() string {
 //   - if it supports seeking it will seek to the desired offset and return the same reader
 //   - if it doesn't support seeking it will close a possible existing one and open at the desired offset
 //   - if there's no reader associated with this worker, it will create one
-func (w *worker) reader(offset, end int64, closeOpen bool) (io.ReadCloser, error) {
+func (w *worker) reader(ctx context.Context, offset, end int64, closeOpen bool) (io.ReadCloser, error) {
 	var err error
 	r := w.rc
 	if w.rc == nil {
 		r, err = w.r.cacheFs().openRateLimited(func() (io.ReadCloser, error) {
-			return w.r.cachedObject.Object.Open(&fs.RangeOption{Start: offset, End: end - 1})
+			return w.r.cachedObject.Object.Open(ctx, &fs.RangeOption{Start: offset, End: end - 1})
 		})
 		if err != nil {
 			return nil, err
@@ -390,7 +393,7 @@ func (w *worker) reader(offset, end int64, closeOpen bool) (io.ReadCloser, error
 
 	_ = w.rc.Close()
 	return w.r.cacheFs().openRateLimited(func() (io.ReadCloser, error) {
-		r, err = w.r.cachedObject.Object.Open(&fs.RangeOption{Start: offset, End: end - 1})
+		r, err = w.r.cachedObject.Object.Open(ctx, &fs.RangeOption{Start: offset, End: end - 1})
 		if err != nil {
 			return nil, err
 		}
@@ -411,7 +414,7 @@ func (w *worker) setRunning(f bool) {
 }
 
 // run is the main loop for the worker which receives offsets to preload
-func (w *worker) run() {
+func (w *worker) run(ctx context.Context) {
 	var err error
 	var data []byte
 	defer w.setRunning(false)
@@ -457,11 +460,11 @@ func (w *worker) run() {
 		//	chunkEnd = w.r.cachedObject.Size()
 		//}
 
-		w.download(chunkStart, chunkEnd, 0)
+		w.download(ctx, chunkStart, chunkEnd, 0)
 	}
 }
 
-func (w *worker) download(chunkStart, chunkEnd int64, retry int) {
+func (w *worker) download(ctx context.Context, chunkStart, chunkEnd int64, retry int) {
 	var err error
 	var data []byte
 
@@ -478,15 +481,15 @@ func (w *worker) download(chunkStart, chunkEnd int64, retry int) {
 	if retry > 0 {
 		closeOpen = true
 	}
-	w.rc, err = w.reader(chunkStart, chunkEnd, closeOpen)
+	w.rc, err = w.reader(ctx, chunkStart, chunkEnd, closeOpen)
 	// we seem to be getting only errors so we abort
 	if err != nil {
 		fs.Errorf(w, "object open failed %v: %v", chunkStart, err)
-		err = w.r.cachedObject.refreshFromSource(true)
+		err = w.r.cachedObject.refreshFromSource(ctx, true)
 		if err != nil {
 			fs.Errorf(w, "%v", err)
 		}
-		w.download(chunkStart, chunkEnd, retry+1)
+		w.download(ctx, chunkStart, chunkEnd, retry+1)
 		return
 	}
 
@@ -495,11 +498,11 @@ func (w *worker) download(chunkStart, chunkEnd int64, retry int) {
 	sourceRead, err = io.ReadFull(w.rc, data)
 	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
 		fs.Errorf(w, "failed to read chunk %v: %v", chunkStart, err)
-		err = w.r.cachedObject.refreshFromSource(true)
+		err = w.r.cachedObject.refreshFromSource(ctx, true)
 		if err != nil {
 			fs.Errorf(w, "%v", err)
 		}
-		w.download(chunkStart, chunkEnd, retry+1)
+		w.download(ctx, chunkStart, chunkEnd, retry+1)
 		return
 	}
 	data = data[:sourceRead] // reslice to remove extra garbage
@@ -591,7 +594,7 @@ func (b *backgroundWriter) notify(remote string, status int, err error) {
 	}
 }
 
-func (b *backgroundWriter) run() {
+func (b *backgroundWriter) run(ctx context.Context) {
 	state := 0
 	for {
 		b.mu.Lock()
@@ -621,7 +624,7 @@ func (b *backgroundWriter) run() {
 		remote := b.fs.cleanRootFromPath(absPath)
 		b.notify(remote, BackgroundUploadStarted, nil)
 		fs.Infof(remote, "background upload: started upload")
-		err = operations.MoveFile(b.fs.UnWrap(), b.fs.tempFs, remote, remote)
+		err = operations.MoveFile(ctx, b.fs.UnWrap(), b.fs.tempFs, remote, remote)
 		if err != nil {
 			b.notify(remote, BackgroundUploadError, err)
 			_ = b.fs.cache.rollbackPendingUpload(absPath)
@@ -631,14 +634,14 @@ func (b *backgroundWriter) run() {
 		// clean empty dirs up to root
 		thisDir := cleanPath(path.Dir(remote))
 		for thisDir != "" {
-			thisList, err := b.fs.tempFs.List(thisDir)
+			thisList, err := b.fs.tempFs.List(ctx, thisDir)
 			if err != nil {
 				break
 			}
 			if len(thisList) > 0 {
 				break
 			}
-			err = b.fs.tempFs.Rmdir(thisDir)
+			err = b.fs.tempFs.Rmdir(ctx, thisDir)
 			fs.Debugf(thisDir, "cleaned from temp path")
 			if err != nil {
 				break
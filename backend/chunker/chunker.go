@@ -0,0 +1,1021 @@
+// Package chunker provides wrappers for Fs and Object which split large
+// files into chunks
+package chunker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/configmap"
+	"github.com/artpar/rclone/fs/config/configstruct"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/fs/object"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultChunkSize = 2 * fs.GibiByte
+	chunkSuffix      = ".rclone_chunk."
+	maxMetadataSize  = 1024 // objects bigger than this can't be metadata
+	metadataVersion  = 1    // version of the metadata written
+)
+
+// chunkNameRe matches the names of chunks, with the name of the file
+// and the chunk number as sub expressions
+var chunkNameRe = regexp.MustCompile(`^(.+)` + regexp.QuoteMeta(chunkSuffix) + `([0-9]{3,})$`)
+
+// Register with Fs
+func init() {
+	fs.Register(&fs.RegInfo{
+		Name:        "chunker",
+		Description: "Transparently chunk/split large files",
+		NewFs:       NewFs,
+		Options: []fs.Option{{
+			Name:     "remote",
+			Help:     "Remote to chunk/unchunk.\nNormally should contain a ':' and a path, eg \"myremote:path/to/dir\",\n\"myremote:bucket\" or maybe \"myremote:\" (not recommended).",
+			Required: true,
+		}, {
+			Name:    "chunk_size",
+			Help:    "Files larger than chunk size will be split in chunks.",
+			Default: fs.SizeSuffix(defaultChunkSize),
+		}, {
+			Name:    "hash_type",
+			Help:    "Hash of the whole file to store with chunked files.",
+			Default: "md5",
+			Examples: []fs.OptionExample{{
+				Value: "none",
+				Help:  "Don't store a hash - files will have no hashes.",
+			}, {
+				Value: "md5",
+				Help:  "Store the MD5 of chunked files, other files use the remote's MD5.",
+			}, {
+				Value: "sha1",
+				Help:  "Store the SHA1 of chunked files, other files use the remote's SHA1.",
+			}},
+			Advanced: true,
+		}},
+	})
+}
+
+// Options defines the configuration for this backend
+type Options struct {
+	Remote    string        `config:"remote"`
+	ChunkSize fs.SizeSuffix `config:"chunk_size"`
+	HashType  string        `config:"hash_type"`
+}
+
+// Fs represents a wrapped fs.Fs
+type Fs struct {
+	fs.Fs
+	name     string
+	root     string
+	opt      Options
+	features *fs.Features // optional features
+	hashType hash.Type    // hash stored in the metadata or hash.None
+}
+
+// NewFs contstructs an Fs from the path, container:path
+func NewFs(name, rpath string, m configmap.Mapper) (fs.Fs, error) {
+	// Parse config into Options struct
+	opt := new(Options)
+	err := configstruct.Set(m, opt)
+	if err != nil {
+		return nil, err
+	}
+	if opt.ChunkSize <= 0 {
+		return nil, errors.Errorf("chunk_size must be greater than 0 - got %v", opt.ChunkSize)
+	}
+	var hashType hash.Type
+	switch opt.HashType {
+	case "none":
+		hashType = hash.None
+	case "md5":
+		hashType = hash.MD5
+	case "sha1":
+		hashType = hash.SHA1
+	default:
+		return nil, errors.Errorf("unknown hash_type %q", opt.HashType)
+	}
+	remote := opt.Remote
+	if strings.HasPrefix(remote, name+":") {
+		return nil, errors.New("can't point chunker remote at itself - check the value of the remote setting")
+	}
+	remotePath := path.Join(remote, rpath)
+	wrappedFs, err := fs.NewFs(remotePath)
+	if err != fs.ErrorIsFile && err != nil {
+		return nil, errors.Wrapf(err, "failed to make remote %q to wrap", remotePath)
+	}
+	f := &Fs{
+		Fs:       wrappedFs,
+		name:     name,
+		root:     rpath,
+		opt:      *opt,
+		hashType: hashType,
+	}
+	// Only use the hash if the wrapped remote can supply it for
+	// the files which aren't chunked
+	if !wrappedFs.Hashes().Contains(hashType) {
+		f.hashType = hash.None
+	}
+	// the features here are ones we could support, and they are
+	// ANDed with the ones from wrappedFs
+	f.features = (&fs.Features{
+		CaseInsensitive:         false,
+		DuplicateFiles:          false,
+		ReadMimeType:            false, // MimeTypes not supported with chunker
+		WriteMimeType:           false,
+		BucketBased:             true,
+		CanHaveEmptyDirectories: true,
+	}).Fill(f).Mask(wrappedFs).WrapsFs(f, wrappedFs)
+
+	doChangeNotify := wrappedFs.Features().ChangeNotify
+	if doChangeNotify != nil {
+		f.features.ChangeNotify = func(notifyFunc func(string, fs.EntryType), pollInterval time.Duration) chan bool {
+			wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
+				if mainName, _, ok := parseChunkName(path); ok {
+					path, entryType = mainName, fs.EntryObject
+				}
+				notifyFunc(path, entryType)
+			}
+			return doChangeNotify(wrappedNotifyFunc, pollInterval)
+		}
+	}
+
+	return f, err
+}
+
+// Name of the remote (as passed into NewFs)
+func (f *Fs) Name() string {
+	return f.name
+}
+
+// Root of the remote (as passed into NewFs)
+func (f *Fs) Root() string {
+	return f.root
+}
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features {
+	return f.features
+}
+
+// String returns a description of the FS
+func (f *Fs) String() string {
+	return fmt.Sprintf("Chunked '%s:%s'", f.name, f.root)
+}
+
+// chunkName returns the name of chunk number i (counting from 0) of
+// the file remote
+func chunkName(remote string, i int) string {
+	return fmt.Sprintf("%s%s%03d", remote, chunkSuffix, i+1)
+}
+
+// parseChunkName returns the name of the file and the chunk number
+// (counting from 0) if remote is the name of a chunk
+func parseChunkName(remote string) (mainName string, i int, ok bool) {
+	match := chunkNameRe.FindStringSubmatch(remote)
+	if match == nil {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(match[2])
+	if err != nil || n < 1 {
+		return "", 0, false
+	}
+	return match[1], n - 1, true
+}
+
+// chunkEntry is a chunk found in a listing
+type chunkEntry struct {
+	i int
+	o fs.Object
+}
+
+// processEntries hides the chunks in entries and attaches them to the
+// objects they belong to.  This alters entries returning it as
+// newEntries.
+func (f *Fs) processEntries(entries fs.DirEntries) (newEntries fs.DirEntries, err error) {
+	chunks := make(map[string][]chunkEntry)
+	newEntries = entries[:0] // in place filter
+	for _, entry := range entries {
+		switch x := entry.(type) {
+		case fs.Object:
+			if mainName, i, ok := parseChunkName(x.Remote()); ok {
+				chunks[mainName] = append(chunks[mainName], chunkEntry{i: i, o: x})
+				continue
+			}
+			newEntries = append(newEntries, f.newObject(x, nil))
+		case fs.Directory:
+			newEntries = append(newEntries, x)
+		default:
+			return nil, errors.Errorf("Unknown object type %T", entry)
+		}
+	}
+	for _, entry := range newEntries {
+		o, ok := entry.(*Object)
+		if !ok {
+			continue
+		}
+		cs, found := chunks[o.Remote()]
+		if !found {
+			continue
+		}
+		delete(chunks, o.Remote())
+		if o.Object.Size() > maxMetadataSize {
+			fs.Debugf(o, "Ignoring chunks as file is too big to be metadata")
+			continue
+		}
+		sort.Slice(cs, func(i, j int) bool { return cs[i].i < cs[j].i })
+		o.chunks = make([]fs.Object, len(cs))
+		for i, c := range cs {
+			if c.i != i {
+				fs.Debugf(o, "Ignoring chunks as chunk %d is missing", i+1)
+				o.chunks = nil
+				break
+			}
+			o.chunks[i] = c.o
+		}
+	}
+	for mainName := range chunks {
+		fs.Debugf(mainName, "Ignoring chunks with no metadata")
+	}
+	return newEntries, nil
+}
+
+// List the objects and directories in dir into entries.  The
+// entries can be returned in any order but should be for a
+// complete directory.
+//
+// dir should be "" to list the root, and should not have
+// trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	entries, err = f.Fs.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	return f.processEntries(entries)
+}
+
+// NewObject finds the Object at remote.
+//
+// If the object is small enough to be metadata and its first chunk
+// exists then the metadata is read to find the rest of the chunks.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	if _, _, ok := parseChunkName(remote); ok {
+		return nil, fs.ErrorObjectNotFound
+	}
+	main, err := f.Fs.NewObject(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	o := f.newObject(main, nil)
+	if main.Size() > maxMetadataSize {
+		return o, nil
+	}
+	first, err := f.Fs.NewObject(ctx, chunkName(remote, 0))
+	if err == fs.ErrorObjectNotFound {
+		return o, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to find first chunk")
+	}
+	meta, err := readMetadata(ctx, main)
+	if err != nil {
+		fs.Debugf(o, "Ignoring chunks: %v", err)
+		return o, nil
+	}
+	chunks := []fs.Object{first}
+	for i := 1; i < meta.Chunks; i++ {
+		chunk, err := f.Fs.NewObject(ctx, chunkName(remote, i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find chunk %d", i+1)
+		}
+		chunks = append(chunks, chunk)
+	}
+	o.chunks = chunks
+	err = o.checkMetadata(meta)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// put implements Put or PutStream
+//
+// If the object exists already it is updated.
+func (f *Fs) put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption) (fs.Object, error) {
+	o, err := f.NewObject(ctx, src.Remote())
+	switch err {
+	case nil:
+		return o, o.Update(ctx, in, src, options...)
+	case fs.ErrorObjectNotFound:
+		return f.upload(ctx, in, src, options, nil)
+	default:
+		return nil, err
+	}
+}
+
+// upload the data in in as a single object if it is small enough or
+// as chunks and a metadata object if not
+//
+// If old is not nil it is the object being replaced and its chunks
+// which aren't needed any more are removed.
+func (f *Fs) upload(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, old *Object) (o *Object, err error) {
+	var oldChunks []fs.Object
+	if old != nil {
+		oldChunks = old.chunks
+	}
+	size := src.Size()
+	chunkSize := int64(f.opt.ChunkSize)
+	if size >= 0 && size <= chunkSize {
+		// Small enough to upload in one piece
+		var main fs.Object
+		if old != nil {
+			main = old.Object
+			err = main.Update(ctx, in, src, options...)
+		} else {
+			main, err = f.Fs.Put(ctx, in, src, options...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.removeChunks(ctx, oldChunks)
+		return f.newObject(main, nil), nil
+	}
+
+	// Hash the whole file as it goes past
+	var hasher *hash.MultiHasher
+	if f.hashType != hash.None {
+		hasher, err = hash.NewMultiHasherTypes(hash.NewHashSet(f.hashType))
+		if err != nil {
+			return nil, err
+		}
+		in = io.TeeReader(in, hasher)
+	}
+
+	// Upload the chunks, removing any new ones on failure
+	var chunks []fs.Object
+	defer func() {
+		if err != nil && len(chunks) > len(oldChunks) {
+			f.removeChunks(ctx, chunks[len(oldChunks):])
+		}
+	}()
+	br := bufio.NewReader(in)
+	var total int64
+	for i := 0; ; i++ {
+		chunkLen := int64(-1)
+		if size >= 0 {
+			chunkLen = size - total
+			if chunkLen > chunkSize {
+				chunkLen = chunkSize
+			}
+			if chunkLen == 0 {
+				break
+			}
+		} else if i > 0 {
+			// Stop at the end of the stream
+			if _, err = br.Peek(1); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, errors.Wrap(err, "failed to read source")
+			}
+		}
+		lr := &io.LimitedReader{R: br, N: chunkSize}
+		info := object.NewStaticObjectInfo(chunkName(src.Remote(), i), src.ModTime(), chunkLen, true, nil, f)
+		var chunk fs.Object
+		if chunkLen < 0 {
+			do := f.Fs.Features().PutStream
+			if do == nil {
+				return nil, errors.New("can't upload chunks of unknown size")
+			}
+			chunk, err = do(ctx, lr, info, options...)
+		} else {
+			chunk, err = f.Fs.Put(ctx, lr, info, options...)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to upload chunk %d", i+1)
+		}
+		chunks = append(chunks, chunk)
+		read := chunkSize - lr.N
+		total += read
+		if chunkLen < 0 && read < chunkSize {
+			break
+		}
+	}
+	if size >= 0 && total != size {
+		return nil, errors.Errorf("corrupted on transfer: sizes differ %d vs %d", size, total)
+	}
+
+	// Check the hash of the whole file if we can
+	meta := &metadata{
+		Version: metadataVersion,
+		Size:    total,
+		ModTime: src.ModTime(),
+		Chunks:  len(chunks),
+	}
+	if hasher != nil {
+		sum := hasher.Sums()[f.hashType]
+		srcSum, _ := src.Hash(f.hashType)
+		if !hash.Equals(srcSum, sum) {
+			return nil, errors.Errorf("corrupted on transfer: %v hash differ %q vs %q", f.hashType, srcSum, sum)
+		}
+		meta.setHash(f.hashType, sum)
+	}
+
+	// Write the metadata to the main object
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make metadata")
+	}
+	info := object.NewStaticObjectInfo(src.Remote(), src.ModTime(), int64(len(data)), true, nil, f)
+	var main fs.Object
+	if old != nil {
+		main = old.Object
+		err = main.Update(ctx, bytes.NewReader(data), info)
+	} else {
+		main, err = f.Fs.Put(ctx, bytes.NewReader(data), info)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to upload metadata")
+	}
+	if len(oldChunks) > len(chunks) {
+		f.removeChunks(ctx, oldChunks[len(chunks):])
+	}
+	o = f.newObject(main, chunks)
+	o.meta = meta
+	return o, nil
+}
+
+// removeChunks removes the chunks passed in, logging any errors
+func (f *Fs) removeChunks(ctx context.Context, chunks []fs.Object) {
+	for _, chunk := range chunks {
+		err := chunk.Remove(ctx)
+		if err != nil {
+			fs.Errorf(chunk, "Failed to remove chunk: %v", err)
+		}
+	}
+}
+
+// Put in to the remote path with the modTime given of the given size
+//
+// May create the object even if it returns an error - if so
+// will return the object and the error, otherwise will return
+// nil and the error
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.put(ctx, in, src, options)
+}
+
+// PutStream uploads to the remote path with the modTime given of indeterminate size
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.put(ctx, in, src, options)
+}
+
+// Hashes returns the supported hash sets.
+func (f *Fs) Hashes() hash.Set {
+	return hash.Set(f.hashType)
+}
+
+// Purge all files in the root and the root directory
+//
+// Implement this if you have a way of deleting all the files
+// quicker than just running Remove() on the result of List()
+//
+// Return an error if it doesn't exist
+func (f *Fs) Purge(ctx context.Context) error {
+	do := f.Fs.Features().Purge
+	if do == nil {
+		return fs.ErrorCantPurge
+	}
+	return do(ctx)
+}
+
+type copyMoveFn func(context.Context, fs.Object, string) (fs.Object, error)
+
+// copyOrMove implements Copy or Move by applying do to each of the
+// chunks and then the main object
+//
+// Any chunks left over from an object being overwritten at remote
+// are removed afterwards.  If isMove is set and the move fails part
+// way then the chunks already moved are moved back to the source.
+func (f *Fs) copyOrMove(ctx context.Context, o *Object, remote string, do copyMoveFn, opName string, isMove bool) (fs.Object, error) {
+	var oldChunks []fs.Object
+	if dst, err := f.NewObject(ctx, remote); err == nil {
+		oldChunks = dst.(*Object).chunks
+	}
+	var chunks []fs.Object
+	for i, chunk := range o.chunks {
+		newChunk, err := do(ctx, chunk, chunkName(remote, i))
+		if err != nil {
+			if isMove {
+				f.moveChunksBack(ctx, o, chunks, do)
+			}
+			return nil, errors.Wrapf(err, "failed to %s chunk %d", opName, i+1)
+		}
+		chunks = append(chunks, newChunk)
+	}
+	main, err := do(ctx, o.Object, remote)
+	if err != nil {
+		if isMove {
+			f.moveChunksBack(ctx, o, chunks, do)
+		}
+		return nil, err
+	}
+	if len(oldChunks) > len(chunks) {
+		f.removeChunks(ctx, oldChunks[len(chunks):])
+	}
+	newO := f.newObject(main, chunks)
+	newO.meta = o.meta
+	return newO, nil
+}
+
+// moveChunksBack moves the chunks of o which have been moved to
+// moved back to where they were after a failed move
+func (f *Fs) moveChunksBack(ctx context.Context, o *Object, moved []fs.Object, do copyMoveFn) {
+	for i, chunk := range moved {
+		_, err := do(ctx, chunk, o.chunks[i].Remote())
+		if err != nil {
+			fs.Errorf(chunk, "Failed to move chunk back after failed move: %v", err)
+		}
+	}
+}
+
+// Copy src to this remote using server side copy operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantCopy
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Copy
+	if do == nil {
+		return nil, fs.ErrorCantCopy
+	}
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, fs.ErrorCantCopy
+	}
+	return f.copyOrMove(ctx, o, remote, do, "copy", false)
+}
+
+// Move src to this remote using server side move operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantMove
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Move
+	if do == nil {
+		return nil, fs.ErrorCantMove
+	}
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, fs.ErrorCantMove
+	}
+	return f.copyOrMove(ctx, o, remote, do, "move", true)
+}
+
+// DirMove moves src, srcRemote to this remote at dstRemote
+// using server side move operations.
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantDirMove
+//
+// If destination exists then return fs.ErrorDirExists
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
+	do := f.Fs.Features().DirMove
+	if do == nil {
+		return fs.ErrorCantDirMove
+	}
+	srcFs, ok := src.(*Fs)
+	if !ok {
+		fs.Debugf(src, "Can't move directory - not same remote type")
+		return fs.ErrorCantDirMove
+	}
+	return do(ctx, srcFs.Fs, srcRemote, dstRemote)
+}
+
+// CleanUp the trash in the Fs
+//
+// Implement this if you have a way of emptying the trash or
+// otherwise cleaning up old versions of files.
+func (f *Fs) CleanUp() error {
+	do := f.Fs.Features().CleanUp
+	if do == nil {
+		return errors.New("can't CleanUp")
+	}
+	return do()
+}
+
+// About gets quota information from the Fs
+func (f *Fs) About() (*fs.Usage, error) {
+	do := f.Fs.Features().About
+	if do == nil {
+		return nil, errors.New("About not supported")
+	}
+	return do()
+}
+
+// UnWrap returns the Fs that this Fs is wrapping
+func (f *Fs) UnWrap() fs.Fs {
+	return f.Fs
+}
+
+// metadata is stored in the main object of a chunked file
+type metadata struct {
+	Version int       `json:"ver"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modtime"`
+	Chunks  int       `json:"nchunks"`
+	MD5     string    `json:"md5,omitempty"`
+	SHA1    string    `json:"sha1,omitempty"`
+}
+
+// hash returns the hash of type ht or "" if it isn't stored
+func (meta *metadata) hash(ht hash.Type) string {
+	switch ht {
+	case hash.MD5:
+		return meta.MD5
+	case hash.SHA1:
+		return meta.SHA1
+	}
+	return ""
+}
+
+// setHash stores sum as the hash of type ht
+func (meta *metadata) setHash(ht hash.Type, sum string) {
+	switch ht {
+	case hash.MD5:
+		meta.MD5 = sum
+	case hash.SHA1:
+		meta.SHA1 = sum
+	}
+}
+
+// readMetadata reads and decodes the metadata stored in main
+func readMetadata(ctx context.Context, main fs.Object) (meta *metadata, err error) {
+	if main.Size() > maxMetadataSize {
+		return nil, errors.New("too big to be metadata")
+	}
+	in, err := main.Open(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open metadata")
+	}
+	defer fs.CheckClose(in, &err)
+	data, err := ioutil.ReadAll(io.LimitReader(in, maxMetadataSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read metadata")
+	}
+	meta = new(metadata)
+	err = json.Unmarshal(data, meta)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode metadata")
+	}
+	if meta.Version < 1 || meta.Chunks < 1 {
+		return nil, errors.New("not metadata")
+	}
+	if meta.Version > metadataVersion {
+		return nil, errors.Errorf("unsupported metadata version %d", meta.Version)
+	}
+	return meta, nil
+}
+
+// Object describes a wrapped object which may be split into chunks
+//
+// If the file is chunked then Object is the metadata and chunks is
+// the data in order, otherwise Object is the data.
+type Object struct {
+	fs.Object
+	f      *Fs
+	chunks []fs.Object // chunks of the data or nil if not chunked
+	mu     sync.Mutex  // protects meta
+	meta   *metadata   // metadata if chunked and read yet
+}
+
+func (f *Fs) newObject(o fs.Object, chunks []fs.Object) *Object {
+	return &Object{
+		Object: o,
+		f:      f,
+		chunks: chunks,
+	}
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info {
+	return o.f
+}
+
+// Return a string version
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.Remote()
+}
+
+// isChunked returns whether the object is split into chunks
+func (o *Object) isChunked() bool {
+	return len(o.chunks) > 0
+}
+
+// checkMetadata checks meta against the chunks found and stores it
+func (o *Object) checkMetadata(meta *metadata) error {
+	if meta.Chunks != len(o.chunks) {
+		return errors.Errorf("chunked file is corrupt: expecting %d chunks but found %d", meta.Chunks, len(o.chunks))
+	}
+	if size := o.Size(); meta.Size != size {
+		return errors.Errorf("chunked file is corrupt: expecting size %d but chunks add up to %d", meta.Size, size)
+	}
+	o.meta = meta
+	return nil
+}
+
+// readMetadata reads the metadata for a chunked object if it hasn't
+// been read already
+func (o *Object) readMetadata(ctx context.Context) (*metadata, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.meta != nil {
+		return o.meta, nil
+	}
+	meta, err := readMetadata(ctx, o.Object)
+	if err != nil {
+		return nil, err
+	}
+	err = o.checkMetadata(meta)
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// Size returns the size of the file
+func (o *Object) Size() int64 {
+	if !o.isChunked() {
+		return o.Object.Size()
+	}
+	var size int64
+	for _, chunk := range o.chunks {
+		size += chunk.Size()
+	}
+	return size
+}
+
+// ModTime returns the modification time of the file
+func (o *Object) ModTime() time.Time {
+	if !o.isChunked() {
+		return o.Object.ModTime()
+	}
+	meta, err := o.readMetadata(context.TODO())
+	if err != nil {
+		fs.Errorf(o, "Failed to read modification time: %v", err)
+		return o.Object.ModTime()
+	}
+	return meta.ModTime
+}
+
+// SetModTime sets the modification time of the file
+//
+// For chunked files this rewrites the metadata.
+func (o *Object) SetModTime(t time.Time) error {
+	if !o.isChunked() {
+		return o.Object.SetModTime(t)
+	}
+	ctx := context.TODO()
+	meta, err := o.readMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	newMeta := *meta
+	newMeta.ModTime = t
+	data, err := json.Marshal(&newMeta)
+	if err != nil {
+		return errors.Wrap(err, "failed to make metadata")
+	}
+	info := object.NewStaticObjectInfo(o.Remote(), t, int64(len(data)), true, nil, o.f)
+	err = o.Object.Update(ctx, bytes.NewReader(data), info)
+	if err != nil {
+		return errors.Wrap(err, "failed to update metadata")
+	}
+	o.mu.Lock()
+	o.meta = &newMeta
+	o.mu.Unlock()
+	return nil
+}
+
+// Hash returns the selected checksum of the file
+// If no checksum is available it returns ""
+func (o *Object) Hash(ht hash.Type) (string, error) {
+	if ht == hash.None || ht != o.f.hashType {
+		return "", hash.ErrUnsupported
+	}
+	if !o.isChunked() {
+		return o.Object.Hash(ht)
+	}
+	meta, err := o.readMetadata(context.TODO())
+	if err != nil {
+		return "", err
+	}
+	return meta.hash(ht), nil
+}
+
+// UnWrap returns the wrapped Object
+func (o *Object) UnWrap() fs.Object {
+	return o.Object
+}
+
+// Open opens the file for read.  Call Close() on the returned io.ReadCloser
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.ReadCloser, err error) {
+	if !o.isChunked() {
+		return o.Object.Open(ctx, options...)
+	}
+	_, err = o.readMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var openOptions []fs.OpenOption
+	var offset, limit int64 = 0, -1
+	for _, option := range options {
+		switch x := option.(type) {
+		case *fs.SeekOption:
+			offset = x.Offset
+		case *fs.RangeOption:
+			offset, limit = x.Decode(o.Size())
+		default:
+			// pass on Options to the chunks if appropriate
+			openOptions = append(openOptions, option)
+		}
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return &chunkedReader{
+		ctx:       ctx,
+		chunks:    o.chunks,
+		options:   openOptions,
+		offset:    offset,
+		remaining: limit,
+	}, nil
+}
+
+// Update in to the object with the modTime given of the given size
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	newO, err := o.f.upload(ctx, in, src, options, o)
+	if err != nil {
+		return err
+	}
+	o.mu.Lock()
+	o.Object = newO.Object
+	o.chunks = newO.chunks
+	o.meta = newO.meta
+	o.mu.Unlock()
+	return nil
+}
+
+// Remove an object
+//
+// The main object is removed first so the file disappears even if
+// removing the chunks fails.
+func (o *Object) Remove(ctx context.Context) error {
+	err := o.Object.Remove(ctx)
+	if err != nil {
+		return err
+	}
+	for i, chunk := range o.chunks {
+		err = chunk.Remove(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove chunk %d", i+1)
+		}
+	}
+	return nil
+}
+
+// chunkedReader reads the data of a chunked file, opening the chunks
+// as they are needed
+type chunkedReader struct {
+	ctx        context.Context
+	chunks     []fs.Object     // the chunks of the file
+	options    []fs.OpenOption // options to open each chunk with
+	offset     int64           // offset in the file of the next read
+	remaining  int64           // bytes left to read or -1 for all
+	i          int             // index of the current chunk
+	chunkStart int64           // offset in the file of the current chunk
+	in         io.ReadCloser   // the open chunk or nil
+}
+
+// open the chunk containing the current offset, returning io.EOF if
+// there isn't one
+func (cr *chunkedReader) open() error {
+	for cr.i < len(cr.chunks) {
+		chunk := cr.chunks[cr.i]
+		size := chunk.Size()
+		if cr.offset < cr.chunkStart+size {
+			start := cr.offset - cr.chunkStart
+			end := size - 1
+			if cr.remaining >= 0 && start+cr.remaining-1 < end {
+				end = start + cr.remaining - 1
+			}
+			options := cr.options
+			if start != 0 || end != size-1 {
+				options = append(options[:len(options):len(options)], &fs.RangeOption{Start: start, End: end})
+			}
+			in, err := chunk.Open(cr.ctx, options...)
+			if err != nil {
+				return errors.Wrapf(err, "failed to open chunk %d", cr.i+1)
+			}
+			cr.in = in
+			return nil
+		}
+		cr.chunkStart += size
+		cr.i++
+	}
+	return io.EOF
+}
+
+// Read bytes from the chunks
+func (cr *chunkedReader) Read(p []byte) (n int, err error) {
+	for {
+		if cr.remaining == 0 {
+			return 0, io.EOF
+		}
+		if cr.in == nil {
+			err = cr.open()
+			if err != nil {
+				return 0, err
+			}
+		}
+		if cr.remaining > 0 && int64(len(p)) > cr.remaining {
+			p = p[:cr.remaining]
+		}
+		n, err = cr.in.Read(p)
+		cr.offset += int64(n)
+		if cr.remaining > 0 {
+			cr.remaining -= int64(n)
+		}
+		if err == io.EOF {
+			chunkEnd := cr.chunkStart + cr.chunks[cr.i].Size()
+			if cr.offset != chunkEnd && cr.remaining != 0 {
+				return n, io.ErrUnexpectedEOF
+			}
+			err = cr.in.Close()
+			cr.in = nil
+			if err != nil {
+				return n, err
+			}
+			if cr.offset == chunkEnd {
+				cr.chunkStart = chunkEnd
+				cr.i++
+			}
+			if n == 0 {
+				continue
+			}
+		}
+		return n, err
+	}
+}
+
+// Close the current chunk if open
+func (cr *chunkedReader) Close() error {
+	if cr.in == nil {
+		return nil
+	}
+	err := cr.in.Close()
+	cr.in = nil
+	return err
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs              = (*Fs)(nil)
+	_ fs.Purger          = (*Fs)(nil)
+	_ fs.Copier          = (*Fs)(nil)
+	_ fs.Mover           = (*Fs)(nil)
+	_ fs.DirMover        = (*Fs)(nil)
+	_ fs.PutStreamer     = (*Fs)(nil)
+	_ fs.CleanUpper      = (*Fs)(nil)
+	_ fs.UnWrapper       = (*Fs)(nil)
+	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.Object          = (*Object)(nil)
+	_ fs.ObjectUnWrapper = (*Object)(nil)
+)
@@ -0,0 +1,220 @@
+package chunker
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/artpar/rclone/backend/local" // pull in test backend
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/configmap"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/fstest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeChunker makes a chunker with the chunk size given wrapping a
+// temporary directory
+func makeChunker(t *testing.T, chunkSize string) (f *Fs, dir string, cleanup func()) {
+	dir, cleanup = fstest.TempDir(t, "rclone-chunker-internal")
+	fi, err := NewFs("TestChunkerInternal", "", configmap.Simple{
+		"remote":     dir,
+		"chunk_size": chunkSize,
+		"hash_type":  "md5",
+	})
+	require.NoError(t, err)
+	return fi.(*Fs), dir, cleanup
+}
+
+func TestChunkNames(t *testing.T) {
+	assert.Equal(t, "dir/file.txt.rclone_chunk.001", chunkName("dir/file.txt", 0))
+	assert.Equal(t, "file.rclone_chunk.1000", chunkName("file", 999))
+	for _, test := range []struct {
+		in       string
+		mainName string
+		i        int
+		ok       bool
+	}{
+		{"dir/file.txt.rclone_chunk.001", "dir/file.txt", 0, true},
+		{"file.rclone_chunk.1000", "file", 999, true},
+		{"file.rclone_chunk.000", "", 0, false},
+		{"file.rclone_chunk.01", "", 0, false},
+		{".rclone_chunk.001", "", 0, false},
+		{"file.txt", "", 0, false},
+	} {
+		mainName, i, ok := parseChunkName(test.in)
+		assert.Equal(t, test.mainName, mainName, test.in)
+		assert.Equal(t, test.i, i, test.in)
+		assert.Equal(t, test.ok, ok, test.in)
+	}
+}
+
+func TestNewFsErrors(t *testing.T) {
+	_, err := NewFs("TestChunkerInternal", "", configmap.Simple{"remote": "/tmp", "chunk_size": "0"})
+	assert.Error(t, err)
+	_, err = NewFs("TestChunkerInternal", "", configmap.Simple{"remote": "/tmp", "hash_type": "potato"})
+	assert.Error(t, err)
+	_, err = NewFs("TestChunkerInternal", "", configmap.Simple{"remote": "TestChunkerInternal:dir"})
+	assert.Error(t, err)
+}
+
+func TestChunking(t *testing.T) {
+	ctx := context.Background()
+	f, dir, cleanup := makeChunker(t, "10b")
+	defer cleanup()
+
+	const contents = "0123456789abcdefghijklmnopqrstuvwxy"
+	o := fstest.PutString(t, f, "file.txt", contents)
+	fstest.PutString(t, f, "small.txt", "small")
+	assert.Equal(t, []string{
+		"file.txt",
+		"file.txt.rclone_chunk.001",
+		"file.txt.rclone_chunk.002",
+		"file.txt.rclone_chunk.003",
+		"file.txt.rclone_chunk.004",
+		"small.txt",
+	}, fstest.DirNames(t, dir))
+
+	// The metadata records the size and hash
+	meta, err := readMetadata(ctx, o.(*Object).Object)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(contents)), meta.Size)
+	assert.Equal(t, 4, meta.Chunks)
+	wantMD5, err := hash.StreamTypes(bytes.NewBufferString(contents), hash.NewHashSet(hash.MD5))
+	require.NoError(t, err)
+	assert.Equal(t, wantMD5[hash.MD5], meta.MD5)
+
+	// The chunks are hidden from the listing
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(entries))
+	for _, entry := range entries {
+		if entry.Remote() == "file.txt" {
+			assert.Equal(t, int64(len(contents)), entry.Size())
+		}
+	}
+
+	// The object can be found and read in parts
+	o, err = f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(contents)), o.Size())
+	md5, err := o.Hash(hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, wantMD5[hash.MD5], md5)
+	assert.Equal(t, contents, fstest.ReadObject(t, o))
+	assert.Equal(t, contents[15:], fstest.ReadObject(t, o, &fs.SeekOption{Offset: 15}))
+	assert.Equal(t, contents[5:25], fstest.ReadObject(t, o, &fs.RangeOption{Start: 5, End: 24}))
+	assert.Equal(t, contents[10:20], fstest.ReadObject(t, o, &fs.RangeOption{Start: 10, End: 19}))
+	assert.Equal(t, contents[30:], fstest.ReadObject(t, o, &fs.RangeOption{Start: -1, End: 5}))
+	assert.Equal(t, "", fstest.ReadObject(t, o, &fs.SeekOption{Offset: int64(len(contents))}))
+
+	// The chunks themselves can't be found
+	_, err = f.NewObject(ctx, "file.txt.rclone_chunk.001")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+
+	// Updating with fewer chunks removes the spare ones
+	fstest.PutString(t, f, "file.txt", "0123456789abc")
+	assert.Equal(t, []string{
+		"file.txt",
+		"file.txt.rclone_chunk.001",
+		"file.txt.rclone_chunk.002",
+		"small.txt",
+	}, fstest.DirNames(t, dir))
+
+	// Updating to a small file removes them all
+	o = fstest.PutString(t, f, "file.txt", "tiny")
+	assert.Equal(t, []string{"file.txt", "small.txt"}, fstest.DirNames(t, dir))
+	assert.Equal(t, "tiny", fstest.ReadObject(t, o))
+
+	// Removing a chunked file removes the chunks
+	o = fstest.PutString(t, f, "file.txt", contents)
+	require.NoError(t, o.Remove(ctx))
+	assert.Equal(t, []string{"small.txt"}, fstest.DirNames(t, dir))
+}
+
+func TestSetModTime(t *testing.T) {
+	ctx := context.Background()
+	f, _, cleanup := makeChunker(t, "10b")
+	defer cleanup()
+
+	o := fstest.PutString(t, f, "file.txt", "0123456789abcdefghij")
+	when := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	require.NoError(t, o.SetModTime(when))
+	o, err := f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.True(t, when.Equal(o.ModTime()))
+	assert.Equal(t, "0123456789abcdefghij", fstest.ReadObject(t, o))
+}
+
+func TestMove(t *testing.T) {
+	ctx := context.Background()
+	f, dir, cleanup := makeChunker(t, "10b")
+	defer cleanup()
+
+	// Overwrite a file with more chunks to check they are tidied
+	src := fstest.PutString(t, f, "src.txt", "0123456789abcdefghij")
+	fstest.PutString(t, f, "dst.txt", "0123456789abcdefghij0123456789")
+	dst, err := f.Move(ctx, src, "dst.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdefghij", fstest.ReadObject(t, dst))
+	assert.Equal(t, []string{
+		"dst.txt",
+		"dst.txt.rclone_chunk.001",
+		"dst.txt.rclone_chunk.002",
+	}, fstest.DirNames(t, dir))
+}
+
+// Check a move which fails part way leaves the source as it was
+func TestMoveFailed(t *testing.T) {
+	ctx := context.Background()
+	f, dir, cleanup := makeChunker(t, "10b")
+	defer cleanup()
+
+	src := fstest.PutString(t, f, "src.txt", "0123456789abcdefghij0123456789")
+	for failAt := 1; failAt <= 4; failAt++ {
+		calls := 0
+		move := f.Fs.Features().Move
+		do := func(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+			calls++
+			if calls == failAt {
+				return nil, errors.New("move failed")
+			}
+			return move(ctx, src, remote)
+		}
+		_, err := f.copyOrMove(ctx, src.(*Object), "dst.txt", do, "move", true)
+		require.Error(t, err)
+		assert.Equal(t, []string{
+			"src.txt",
+			"src.txt.rclone_chunk.001",
+			"src.txt.rclone_chunk.002",
+			"src.txt.rclone_chunk.003",
+		}, fstest.DirNames(t, dir), "failAt=%d", failAt)
+	}
+	o, err := f.NewObject(ctx, "src.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdefghij0123456789", fstest.ReadObject(t, o))
+}
+
+func TestOrphanChunks(t *testing.T) {
+	ctx := context.Background()
+	f, dir, cleanup := makeChunker(t, "10b")
+	defer cleanup()
+
+	// Chunks with no metadata are hidden and a file which
+	// isn't metadata is read as is
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "orphan.rclone_chunk.001"), []byte("orphan"), 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "plain"), []byte("not metadata"), 0666))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "plain.rclone_chunk.001"), []byte("orphan"), 0666))
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, "plain", entries[0].Remote())
+	o, err := f.NewObject(ctx, "plain")
+	require.NoError(t, err)
+	assert.Equal(t, "not metadata", fstest.ReadObject(t, o))
+}
@@ -0,0 +1,44 @@
+// Test Chunker filesystem interface
+package chunker_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/artpar/rclone/backend/chunker"
+	_ "github.com/artpar/rclone/backend/local"
+	"github.com/artpar/rclone/fstest/fstests"
+)
+
+// TestIntegration runs integration tests against the remote with
+// chunks small enough that most of the test files are split
+func TestIntegration(t *testing.T) {
+	tempdir := filepath.Join(os.TempDir(), "rclone-chunker-test")
+	name := "TestChunker"
+	fstests.Run(t, &fstests.Opt{
+		RemoteName: name + ":",
+		NilObject:  (*chunker.Object)(nil),
+		ExtraConfig: []fstests.ExtraConfigItem{
+			{Name: name, Key: "type", Value: "chunker"},
+			{Name: name, Key: "remote", Value: tempdir},
+			{Name: name, Key: "chunk_size", Value: "64b"},
+		},
+	})
+}
+
+// TestSHA1 runs integration tests against the remote storing SHA1
+// hashes with chunks bigger than the test files
+func TestSHA1(t *testing.T) {
+	tempdir := filepath.Join(os.TempDir(), "rclone-chunker-test-sha1")
+	name := "TestChunker2"
+	fstests.Run(t, &fstests.Opt{
+		RemoteName: name + ":",
+		NilObject:  (*chunker.Object)(nil),
+		ExtraConfig: []fstests.ExtraConfigItem{
+			{Name: name, Key: "type", Value: "chunker"},
+			{Name: name, Key: "remote", Value: tempdir},
+			{Name: name, Key: "hash_type", Value: "sha1"},
+		},
+	})
+}
@@ -0,0 +1,997 @@
+// Package compress provides wrappers for Fs and Object which gzip the
+// data
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/configmap"
+	"github.com/artpar/rclone/fs/config/configstruct"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/fs/object"
+	"github.com/pkg/errors"
+)
+
+const (
+	gzSuffix        = ".gz"   // suffix of compressed data
+	binSuffix       = ".bin"  // suffix of data stored as is
+	metaSuffix      = ".json" // suffix of the metadata for compressed data
+	metadataVersion = 1       // version of the metadata written
+	maxMetadataSize = 4096    // largest metadata object read
+	gzipMimeType    = "application/gzip"
+)
+
+// gzNameRe matches the names of compressed data objects, with the
+// original name and the uncompressed size as sub expressions
+var gzNameRe = regexp.MustCompile(`^(.+)\.([0-9]+)` + regexp.QuoteMeta(gzSuffix) + `$`)
+
+// hashes which are stored for compressed objects
+var compressHashes = hash.NewHashSet(hash.MD5, hash.SHA1)
+
+// compressedMimeTypes are the types which are already compressed so
+// aren't worth compressing again
+var compressedMimeTypes = map[string]bool{
+	"application/gzip":             true,
+	"application/x-gzip":           true,
+	"application/zip":              true,
+	"application/x-bzip2":          true,
+	"application/x-xz":             true,
+	"application/x-7z-compressed":  true,
+	"application/x-rar-compressed": true,
+	"application/vnd.rar":          true,
+	"application/zstd":             true,
+	"application/x-compress":       true,
+	"image/jpeg":                   true,
+	"image/png":                    true,
+	"image/gif":                    true,
+	"image/webp":                   true,
+}
+
+// Register with Fs
+func init() {
+	fs.Register(&fs.RegInfo{
+		Name:        "compress",
+		Description: "Compress a remote",
+		NewFs:       NewFs,
+		Options: []fs.Option{{
+			Name:     "remote",
+			Help:     "Remote to compress.\nNormally should contain a ':' and a path, eg \"myremote:path/to/dir\",\n\"myremote:bucket\" or maybe \"myremote:\" (not recommended).",
+			Required: true,
+		}, {
+			Name:     "compression_level",
+			Help:     "GZIP compression level (-1 to 9).\n-1 is the default level, 1 is the fastest and 9 compresses the most.\n0 turns compression off.",
+			Default:  gzip.DefaultCompression,
+			Advanced: true,
+		}},
+	})
+}
+
+// Options defines the configuration for this backend
+type Options struct {
+	Remote           string `config:"remote"`
+	CompressionLevel int    `config:"compression_level"`
+}
+
+// Fs represents a wrapped fs.Fs
+type Fs struct {
+	fs.Fs
+	name     string
+	root     string
+	opt      Options
+	features *fs.Features // optional features
+}
+
+// NewFs contstructs an Fs from the path, container:path
+func NewFs(name, rpath string, m configmap.Mapper) (fs.Fs, error) {
+	// Parse config into Options struct
+	opt := new(Options)
+	err := configstruct.Set(m, opt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = gzip.NewWriterLevel(ioutil.Discard, opt.CompressionLevel); err != nil {
+		return nil, errors.Wrap(err, "bad compression_level")
+	}
+	remote := opt.Remote
+	if strings.HasPrefix(remote, name+":") {
+		return nil, errors.New("can't point compress remote at itself - check the value of the remote setting")
+	}
+	// Look for a file first, either stored as is or compressed
+	var wrappedFs fs.Fs
+	err = nil
+	if rpath != "" {
+		for _, suffix := range []string{binSuffix, metaSuffix} {
+			wrappedFs, err = fs.NewFs(path.Join(remote, rpath+suffix))
+			if err == fs.ErrorIsFile {
+				break
+			}
+		}
+	}
+	// if that didn't produce a file, look for a directory
+	remotePath := path.Join(remote, rpath)
+	if err != fs.ErrorIsFile {
+		wrappedFs, err = fs.NewFs(remotePath)
+	}
+	if err != fs.ErrorIsFile && err != nil {
+		return nil, errors.Wrapf(err, "failed to make remote %q to wrap", remotePath)
+	}
+	f := &Fs{
+		Fs:   wrappedFs,
+		name: name,
+		root: rpath,
+		opt:  *opt,
+	}
+	// the features here are ones we could support, and they are
+	// ANDed with the ones from wrappedFs
+	f.features = (&fs.Features{
+		CaseInsensitive:         true,
+		DuplicateFiles:          false,
+		ReadMimeType:            true,
+		WriteMimeType:           true,
+		BucketBased:             true,
+		CanHaveEmptyDirectories: true,
+	}).Fill(f).Mask(wrappedFs).WrapsFs(f, wrappedFs)
+
+	doChangeNotify := wrappedFs.Features().ChangeNotify
+	if doChangeNotify != nil {
+		f.features.ChangeNotify = func(notifyFunc func(string, fs.EntryType), pollInterval time.Duration) chan bool {
+			wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
+				if entryType == fs.EntryObject {
+					remote, _, _, ok := parseDataName(path)
+					if !ok {
+						return
+					}
+					path = remote
+				}
+				notifyFunc(path, entryType)
+			}
+			return doChangeNotify(wrappedNotifyFunc, pollInterval)
+		}
+	}
+
+	return f, err
+}
+
+// Name of the remote (as passed into NewFs)
+func (f *Fs) Name() string {
+	return f.name
+}
+
+// Root of the remote (as passed into NewFs)
+func (f *Fs) Root() string {
+	return f.root
+}
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features {
+	return f.features
+}
+
+// String returns a description of the FS
+func (f *Fs) String() string {
+	return fmt.Sprintf("Compressed drive '%s:%s'", f.name, f.root)
+}
+
+// dataName returns the name of the object holding the data of remote
+func dataName(remote string, size int64, compressed bool) string {
+	if !compressed {
+		return remote + binSuffix
+	}
+	return remote + "." + strconv.FormatInt(size, 10) + gzSuffix
+}
+
+// parseDataName returns the original name and whether the data is
+// compressed for a data object.  The size is only returned for
+// compressed data.
+func parseDataName(name string) (remote string, size int64, compressed bool, ok bool) {
+	if strings.HasSuffix(name, binSuffix) && len(name) > len(binSuffix) {
+		return strings.TrimSuffix(name, binSuffix), -1, false, true
+	}
+	match := gzNameRe.FindStringSubmatch(name)
+	if match == nil {
+		return "", 0, false, false
+	}
+	size, err := strconv.ParseInt(match[2], 10, 64)
+	if err != nil {
+		return "", 0, false, false
+	}
+	return match[1], size, true, true
+}
+
+// metaName returns the name of the metadata object for remote
+func metaName(remote string) string {
+	return remote + metaSuffix
+}
+
+// isCompressible returns whether data of mimeType is worth compressing
+func isCompressible(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	if strings.HasPrefix(mimeType, "audio/") || strings.HasPrefix(mimeType, "video/") {
+		return false
+	}
+	return !compressedMimeTypes[mimeType]
+}
+
+// processEntries turns the data objects in entries into Objects and
+// hides the metadata.  This alters entries returning it as
+// newEntries.
+func (f *Fs) processEntries(entries fs.DirEntries) (newEntries fs.DirEntries, err error) {
+	newEntries = entries[:0] // in place filter
+	for _, entry := range entries {
+		switch x := entry.(type) {
+		case fs.Object:
+			if strings.HasSuffix(x.Remote(), metaSuffix) {
+				continue
+			}
+			o, err := f.newObject(x)
+			if err != nil {
+				fs.Debugf(x, "Skipping: %v", err)
+				continue
+			}
+			newEntries = append(newEntries, o)
+		case fs.Directory:
+			newEntries = append(newEntries, x)
+		default:
+			return nil, errors.Errorf("Unknown object type %T", entry)
+		}
+	}
+	return newEntries, nil
+}
+
+// List the objects and directories in dir into entries.  The
+// entries can be returned in any order but should be for a
+// complete directory.
+//
+// dir should be "" to list the root, and should not have
+// trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	entries, err = f.Fs.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	return f.processEntries(entries)
+}
+
+// ListR lists the objects and directories of the Fs starting
+// from dir recursively into out.
+//
+// dir should be "" to start from the root, and should not
+// have trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+//
+// It should call callback for each tranche of entries read.
+// These need not be returned in any particular order.  If
+// callback returns an error then the listing will stop
+// immediately.
+//
+// Don't implement this unless you have a more efficient way
+// of listing recursively that doing a directory traversal.
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
+	return f.Fs.Features().ListR(ctx, dir, func(entries fs.DirEntries) error {
+		newEntries, err := f.processEntries(entries)
+		if err != nil {
+			return err
+		}
+		return callback(newEntries)
+	})
+}
+
+// NewObject finds the Object at remote.
+//
+// Data stored as is is looked for first, then the metadata is read
+// to find the name of the compressed data.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	data, err := f.Fs.NewObject(ctx, dataName(remote, -1, false))
+	if err == nil {
+		return f.newObject(data)
+	} else if err != fs.ErrorObjectNotFound {
+		return nil, err
+	}
+	metaObject, err := f.Fs.NewObject(ctx, metaName(remote))
+	if err != nil {
+		return nil, err
+	}
+	meta, err := readMetadata(ctx, metaObject)
+	if err != nil {
+		return nil, err
+	}
+	data, err = f.Fs.NewObject(ctx, dataName(remote, meta.Size, true))
+	if err != nil {
+		return nil, err
+	}
+	o, err := f.newObject(data)
+	if err != nil {
+		return nil, err
+	}
+	o.meta = meta
+	return o, nil
+}
+
+// put implements Put and PutStream, updating the object if it exists
+// already
+func (f *Fs) put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption) (fs.Object, error) {
+	o, err := f.NewObject(ctx, src.Remote())
+	switch err {
+	case nil:
+		return o, o.Update(ctx, in, src, options...)
+	case fs.ErrorObjectNotFound:
+		return f.upload(ctx, in, src, options, nil)
+	default:
+		return nil, err
+	}
+}
+
+// upload the data in in, compressing it if it is worth it
+//
+// If old is not nil it is the object being replaced and any of its
+// objects which aren't needed any more are removed.
+func (f *Fs) upload(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, old *Object) (*Object, error) {
+	mimeType := fs.MimeType(src)
+	if f.opt.CompressionLevel == gzip.NoCompression || !isCompressible(mimeType) {
+		return f.uploadUncompressed(ctx, in, src, options, old, mimeType)
+	}
+	return f.uploadCompressed(ctx, in, src, options, old, mimeType)
+}
+
+// uploadUncompressed uploads the data as is
+func (f *Fs) uploadUncompressed(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, old *Object, mimeType string) (*Object, error) {
+	info := f.newObjectInfo(src, dataName(src.Remote(), -1, false), src.Size(), mimeType)
+	var data fs.Object
+	var err error
+	if old != nil && !old.compressed {
+		data = old.Object
+		err = data.Update(ctx, in, info, options...)
+	} else if src.Size() < 0 {
+		do := f.Fs.Features().PutStream
+		if do == nil {
+			return nil, errors.New("can't upload files of unknown size")
+		}
+		data, err = do(ctx, in, info, options...)
+	} else {
+		data, err = f.Fs.Put(ctx, in, info, options...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	o, err := f.newObject(data)
+	if err != nil {
+		return nil, err
+	}
+	f.removeStale(ctx, old, o)
+	return o, nil
+}
+
+// compress reads in compressing it to out
+func (f *Fs) compress(out io.Writer, in io.Reader) error {
+	gz, err := gzip.NewWriterLevel(out, f.opt.CompressionLevel)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(gz, in)
+	closeErr := gz.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// uploadCompressed compresses the data as it is uploaded then
+// writes the metadata
+//
+// If the size is known and the wrapped remote can stream uploads the
+// compressed data is streamed, otherwise it is compressed to a
+// temporary file first so its size is known.
+func (f *Fs) uploadCompressed(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, old *Object, mimeType string) (o *Object, err error) {
+	hasher, err := hash.NewMultiHasherTypes(compressHashes)
+	if err != nil {
+		return nil, err
+	}
+	in = io.TeeReader(in, hasher)
+
+	var data fs.Object
+	srcSize := src.Size()
+	size := srcSize // the uncompressed size for the data name
+	if do := f.Fs.Features().PutStream; size >= 0 && do != nil {
+		pr, pw := io.Pipe()
+		errChan := make(chan error, 1)
+		go func() {
+			err := f.compress(pw, in)
+			_ = pw.CloseWithError(err)
+			errChan <- err
+		}()
+		info := f.newObjectInfo(src, dataName(src.Remote(), size, true), -1, gzipMimeType)
+		data, err = do(ctx, pr, info, options...)
+		_ = pr.Close()
+		compressErr := <-errChan
+		if err == nil && compressErr != nil {
+			err = compressErr
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		tmp, err := ioutil.TempFile("", "rclone-compress")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to make temporary file")
+		}
+		defer func() {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}()
+		err = f.compress(tmp, in)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to compress")
+		}
+		compressedSize, err := tmp.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		size = hasher.Size()
+		info := f.newObjectInfo(src, dataName(src.Remote(), size, true), compressedSize, gzipMimeType)
+		data, err = f.Fs.Put(ctx, tmp, info, options...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Check the data against the source, removing it if corrupted
+	meta := &metadata{
+		Version:  metadataVersion,
+		Size:     hasher.Size(),
+		MimeType: mimeType,
+	}
+	if srcSize >= 0 && meta.Size != srcSize {
+		err = errors.Errorf("corrupted on transfer: sizes differ %d vs %d", srcSize, meta.Size)
+	}
+	sums := hasher.Sums()
+	for _, ht := range compressHashes.Array() {
+		srcSum, _ := src.Hash(ht)
+		if err == nil && !hash.Equals(srcSum, sums[ht]) {
+			err = errors.Errorf("corrupted on transfer: %v hash differ %q vs %q", ht, srcSum, sums[ht])
+		}
+	}
+	if err != nil {
+		if removeErr := data.Remove(ctx); removeErr != nil {
+			fs.Errorf(data, "Failed to remove corrupted object: %v", removeErr)
+		}
+		return nil, err
+	}
+	meta.MD5, meta.SHA1 = sums[hash.MD5], sums[hash.SHA1]
+
+	// Write the metadata
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make metadata")
+	}
+	info := object.NewStaticObjectInfo(metaName(src.Remote()), src.ModTime(), int64(len(metaData)), true, nil, f)
+	if old != nil && old.compressed {
+		var metaObject fs.Object
+		metaObject, err = f.Fs.NewObject(ctx, metaName(src.Remote()))
+		if err == nil {
+			err = metaObject.Update(ctx, bytes.NewReader(metaData), info)
+		}
+	} else {
+		_, err = f.Fs.Put(ctx, bytes.NewReader(metaData), info)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to upload metadata")
+	}
+	o, err = f.newObject(data)
+	if err != nil {
+		return nil, err
+	}
+	o.meta = meta
+	f.removeStale(ctx, old, o)
+	return o, nil
+}
+
+// removeStale removes the objects belonging to old which aren't
+// being used by o
+func (f *Fs) removeStale(ctx context.Context, old, o *Object) {
+	if old == nil {
+		return
+	}
+	if old.Object.Remote() != o.Object.Remote() {
+		if err := old.Object.Remove(ctx); err != nil {
+			fs.Errorf(old.Object, "Failed to remove old data: %v", err)
+		}
+	}
+	if old.compressed && !o.compressed {
+		if err := f.removeMetadata(ctx, old.Remote()); err != nil {
+			fs.Errorf(old, "Failed to remove old metadata: %v", err)
+		}
+	}
+}
+
+// removeMetadata removes the metadata for remote if it exists
+func (f *Fs) removeMetadata(ctx context.Context, remote string) error {
+	metaObject, err := f.Fs.NewObject(ctx, metaName(remote))
+	if err == fs.ErrorObjectNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return metaObject.Remove(ctx)
+}
+
+// Put in to the remote path with the modTime given of the given size
+//
+// May create the object even if it returns an error - if so
+// will return the object and the error, otherwise will return
+// nil and the error
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.put(ctx, in, src, options)
+}
+
+// PutStream uploads to the remote path with the modTime given of indeterminate size
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.put(ctx, in, src, options)
+}
+
+// Hashes returns the supported hash sets.
+func (f *Fs) Hashes() hash.Set {
+	return compressHashes
+}
+
+// Purge all files in the root and the root directory
+//
+// Implement this if you have a way of deleting all the files
+// quicker than just running Remove() on the result of List()
+//
+// Return an error if it doesn't exist
+func (f *Fs) Purge(ctx context.Context) error {
+	do := f.Fs.Features().Purge
+	if do == nil {
+		return fs.ErrorCantPurge
+	}
+	return do(ctx)
+}
+
+type copyMoveFn func(context.Context, fs.Object, string) (fs.Object, error)
+
+// copyOrMove implements Copy or Move by applying do to the metadata
+// then the data
+func (f *Fs) copyOrMove(ctx context.Context, o *Object, remote string, do copyMoveFn) (fs.Object, error) {
+	var old *Object
+	if dst, err := f.NewObject(ctx, remote); err == nil {
+		old = dst.(*Object)
+	}
+	if o.compressed {
+		metaObject, err := o.f.Fs.NewObject(ctx, metaName(o.Remote()))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find metadata")
+		}
+		_, err = do(ctx, metaObject, metaName(remote))
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := do(ctx, o.Object, dataName(remote, o.size, o.compressed))
+	if err != nil {
+		return nil, err
+	}
+	newO, err := f.newObject(data)
+	if err != nil {
+		return nil, err
+	}
+	newO.meta = o.meta
+	f.removeStale(ctx, old, newO)
+	return newO, nil
+}
+
+// Copy src to this remote using server side copy operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantCopy
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Copy
+	if do == nil {
+		return nil, fs.ErrorCantCopy
+	}
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, fs.ErrorCantCopy
+	}
+	return f.copyOrMove(ctx, o, remote, do)
+}
+
+// Move src to this remote using server side move operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantMove
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Move
+	if do == nil {
+		return nil, fs.ErrorCantMove
+	}
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, fs.ErrorCantMove
+	}
+	return f.copyOrMove(ctx, o, remote, do)
+}
+
+// DirMove moves src, srcRemote to this remote at dstRemote
+// using server side move operations.
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantDirMove
+//
+// If destination exists then return fs.ErrorDirExists
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
+	do := f.Fs.Features().DirMove
+	if do == nil {
+		return fs.ErrorCantDirMove
+	}
+	srcFs, ok := src.(*Fs)
+	if !ok {
+		fs.Debugf(src, "Can't move directory - not same remote type")
+		return fs.ErrorCantDirMove
+	}
+	return do(ctx, srcFs.Fs, srcRemote, dstRemote)
+}
+
+// CleanUp the trash in the Fs
+//
+// Implement this if you have a way of emptying the trash or
+// otherwise cleaning up old versions of files.
+func (f *Fs) CleanUp() error {
+	do := f.Fs.Features().CleanUp
+	if do == nil {
+		return errors.New("can't CleanUp")
+	}
+	return do()
+}
+
+// About gets quota information from the Fs
+func (f *Fs) About() (*fs.Usage, error) {
+	do := f.Fs.Features().About
+	if do == nil {
+		return nil, errors.New("About not supported")
+	}
+	return do()
+}
+
+// UnWrap returns the Fs that this Fs is wrapping
+func (f *Fs) UnWrap() fs.Fs {
+	return f.Fs
+}
+
+// metadata is stored next to compressed data
+type metadata struct {
+	Version  int    `json:"ver"`
+	Size     int64  `json:"size"`
+	MD5      string `json:"md5,omitempty"`
+	SHA1     string `json:"sha1,omitempty"`
+	MimeType string `json:"mime,omitempty"`
+}
+
+// readMetadata reads and decodes the metadata object passed in
+func readMetadata(ctx context.Context, metaObject fs.Object) (meta *metadata, err error) {
+	in, err := metaObject.Open(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open metadata")
+	}
+	defer fs.CheckClose(in, &err)
+	data, err := ioutil.ReadAll(io.LimitReader(in, maxMetadataSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read metadata")
+	}
+	meta = new(metadata)
+	err = json.Unmarshal(data, meta)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode metadata")
+	}
+	if meta.Version < 1 || meta.Version > metadataVersion {
+		return nil, errors.Errorf("unsupported metadata version %d", meta.Version)
+	}
+	return meta, nil
+}
+
+// Object describes a wrapped object which may be compressed
+type Object struct {
+	fs.Object             // the data
+	f          *Fs        // the Fs this object is in
+	remote     string     // the name without the suffixes
+	size       int64      // the uncompressed size
+	compressed bool       // set if the data is compressed
+	mu         sync.Mutex // protects meta
+	meta       *metadata  // metadata of compressed data if read
+}
+
+// newObject makes an Object from the data object passed in
+func (f *Fs) newObject(data fs.Object) (*Object, error) {
+	remote, size, compressed, ok := parseDataName(data.Remote())
+	if !ok {
+		return nil, errors.Errorf("not a compressed remote object: %q", data.Remote())
+	}
+	if !compressed {
+		size = data.Size()
+	}
+	return &Object{
+		Object:     data,
+		f:          f,
+		remote:     remote,
+		size:       size,
+		compressed: compressed,
+	}, nil
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info {
+	return o.f
+}
+
+// Return a string version
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.Remote()
+}
+
+// Remote returns the remote path
+func (o *Object) Remote() string {
+	return o.remote
+}
+
+// Size returns the uncompressed size of the file
+func (o *Object) Size() int64 {
+	return o.size
+}
+
+// readMetadata reads the metadata for compressed data if it hasn't
+// been read already
+func (o *Object) readMetadata(ctx context.Context) (*metadata, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.meta != nil {
+		return o.meta, nil
+	}
+	metaObject, err := o.f.Fs.NewObject(ctx, metaName(o.remote))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find metadata")
+	}
+	meta, err := readMetadata(ctx, metaObject)
+	if err != nil {
+		return nil, err
+	}
+	o.meta = meta
+	return meta, nil
+}
+
+// Hash returns the selected checksum of the uncompressed file
+// If no checksum is available it returns ""
+func (o *Object) Hash(ht hash.Type) (string, error) {
+	if !compressHashes.Contains(ht) {
+		return "", hash.ErrUnsupported
+	}
+	if !o.compressed {
+		sum, err := o.Object.Hash(ht)
+		if err == hash.ErrUnsupported {
+			return "", nil
+		}
+		return sum, err
+	}
+	meta, err := o.readMetadata(context.TODO())
+	if err != nil {
+		return "", err
+	}
+	if ht == hash.MD5 {
+		return meta.MD5, nil
+	}
+	return meta.SHA1, nil
+}
+
+// MimeType returns the content type of the uncompressed file
+func (o *Object) MimeType() string {
+	if o.compressed {
+		meta, err := o.readMetadata(context.TODO())
+		if err == nil && meta.MimeType != "" {
+			return meta.MimeType
+		}
+	} else if do, ok := o.Object.(fs.MimeTyper); ok {
+		if mimeType := do.MimeType(); mimeType != "" {
+			return mimeType
+		}
+	}
+	return fs.MimeTypeFromName(o.remote)
+}
+
+// UnWrap returns the wrapped Object
+func (o *Object) UnWrap() fs.Object {
+	return o.Object
+}
+
+// Open opens the file for read.  Call Close() on the returned io.ReadCloser
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.ReadCloser, err error) {
+	if !o.compressed {
+		return o.Object.Open(ctx, options...)
+	}
+	var openOptions []fs.OpenOption
+	var offset, limit int64 = 0, -1
+	for _, option := range options {
+		switch x := option.(type) {
+		case *fs.SeekOption:
+			offset = x.Offset
+		case *fs.RangeOption:
+			offset, limit = x.Decode(o.Size())
+		default:
+			// pass on Options to underlying open if appropriate
+			openOptions = append(openOptions, option)
+		}
+	}
+	in, err := o.Object.Open(ctx, openOptions...)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		_ = in.Close()
+		return nil, errors.Wrap(err, "failed to read compressed data")
+	}
+	// Gzip can't seek so read up to the offset
+	if offset > 0 {
+		_, err = io.CopyN(ioutil.Discard, gz, offset)
+		if err != nil && err != io.EOF {
+			_ = gz.Close()
+			_ = in.Close()
+			return nil, errors.Wrap(err, "failed to seek in compressed data")
+		}
+	}
+	var r io.Reader = gz
+	if limit >= 0 {
+		r = io.LimitReader(gz, limit)
+	}
+	return &decompressor{Reader: r, gz: gz, in: in}, nil
+}
+
+// decompressor reads the uncompressed data closing the gzip reader
+// and the underlying object when done
+type decompressor struct {
+	io.Reader
+	gz *gzip.Reader
+	in io.ReadCloser
+}
+
+// Close the decompressor
+func (d *decompressor) Close() error {
+	err := d.gz.Close()
+	closeErr := d.in.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// Update in to the object with the modTime given of the given size
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	newO, err := o.f.upload(ctx, in, src, options, o)
+	if err != nil {
+		return err
+	}
+	o.mu.Lock()
+	o.Object = newO.Object
+	o.size = newO.size
+	o.compressed = newO.compressed
+	o.meta = newO.meta
+	o.mu.Unlock()
+	return nil
+}
+
+// Remove an object
+func (o *Object) Remove(ctx context.Context) error {
+	err := o.Object.Remove(ctx)
+	if err != nil {
+		return err
+	}
+	if o.compressed {
+		return o.f.removeMetadata(ctx, o.remote)
+	}
+	return nil
+}
+
+// ObjectInfo describes a wrapped fs.ObjectInfo for being the source
+//
+// This changes the remote name and size to those of the data stored
+type ObjectInfo struct {
+	fs.ObjectInfo
+	f          *Fs
+	remote     string
+	size       int64
+	mimeType   string
+	compressed bool // set if the data stored is compressed
+}
+
+func (f *Fs) newObjectInfo(src fs.ObjectInfo, remote string, size int64, mimeType string) *ObjectInfo {
+	_, _, compressed, _ := parseDataName(remote)
+	return &ObjectInfo{
+		ObjectInfo: src,
+		f:          f,
+		remote:     remote,
+		size:       size,
+		mimeType:   mimeType,
+		compressed: compressed,
+	}
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *ObjectInfo) Fs() fs.Info {
+	return o.f
+}
+
+// Remote returns the remote path
+func (o *ObjectInfo) Remote() string {
+	return o.remote
+}
+
+// Size returns the size of the data stored
+func (o *ObjectInfo) Size() int64 {
+	return o.size
+}
+
+// Hash returns the selected checksum of the file
+// If no checksum is available it returns ""
+func (o *ObjectInfo) Hash(ht hash.Type) (string, error) {
+	if o.compressed {
+		return "", nil
+	}
+	return o.ObjectInfo.Hash(ht)
+}
+
+// MimeType returns the content type of the data stored
+func (o *ObjectInfo) MimeType() string {
+	return o.mimeType
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs              = (*Fs)(nil)
+	_ fs.Purger          = (*Fs)(nil)
+	_ fs.Copier          = (*Fs)(nil)
+	_ fs.Mover           = (*Fs)(nil)
+	_ fs.DirMover        = (*Fs)(nil)
+	_ fs.PutStreamer     = (*Fs)(nil)
+	_ fs.CleanUpper      = (*Fs)(nil)
+	_ fs.UnWrapper       = (*Fs)(nil)
+	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.ObjectInfo      = (*ObjectInfo)(nil)
+	_ fs.MimeTyper       = (*ObjectInfo)(nil)
+	_ fs.Object          = (*Object)(nil)
+	_ fs.ObjectUnWrapper = (*Object)(nil)
+	_ fs.MimeTyper       = (*Object)(nil)
+)
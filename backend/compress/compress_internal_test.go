@@ -0,0 +1,207 @@
+package compress
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/artpar/rclone/backend/local" // pull in test backend
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/configmap"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/fs/object"
+	"github.com/artpar/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeCompress makes a temporary directory and a compress remote
+// wrapping it
+func makeCompress(t *testing.T) (f *Fs, dir string, cleanup func()) {
+	dir, cleanup = fstest.TempDir(t, "rclone-compress-internal")
+	fi, err := NewFs("TestCompressInternal", "", configmap.Simple{
+		"remote":            dir,
+		"compression_level": "-1",
+	})
+	require.NoError(t, err)
+	return fi.(*Fs), dir, cleanup
+}
+
+func TestDataNames(t *testing.T) {
+	for _, test := range []struct {
+		remote     string
+		size       int64
+		compressed bool
+		want       string
+	}{
+		{"file.txt", 123, true, "file.txt.123.gz"},
+		{"dir/file.txt", 0, true, "dir/file.txt.0.gz"},
+		{"file.jpg", 123, false, "file.jpg.bin"},
+		{"file.1.gz", 1, true, "file.1.gz.1.gz"},
+	} {
+		got := dataName(test.remote, test.size, test.compressed)
+		assert.Equal(t, test.want, got)
+		remote, size, compressed, ok := parseDataName(got)
+		assert.True(t, ok, got)
+		assert.Equal(t, test.remote, remote, got)
+		assert.Equal(t, test.compressed, compressed, got)
+		if test.compressed {
+			assert.Equal(t, test.size, size, got)
+		}
+	}
+	for _, bad := range []string{"file.txt", ".bin", "file.gz", "file.x.gz", "file.txt.json"} {
+		_, _, _, ok := parseDataName(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestIsCompressible(t *testing.T) {
+	assert.True(t, isCompressible("text/plain; charset=utf-8"))
+	assert.True(t, isCompressible("application/octet-stream"))
+	assert.False(t, isCompressible("image/jpeg"))
+	assert.False(t, isCompressible("application/zip"))
+	assert.False(t, isCompressible("video/mp4"))
+	assert.False(t, isCompressible("Audio/MPEG"))
+}
+
+func TestNewFsErrors(t *testing.T) {
+	_, err := NewFs("TestCompressInternal", "", configmap.Simple{"remote": "TestCompressInternal:dir"})
+	assert.Error(t, err)
+	_, err = NewFs("TestCompressInternal", "", configmap.Simple{"remote": "/tmp", "compression_level": "10"})
+	assert.Error(t, err)
+}
+
+func TestCompressed(t *testing.T) {
+	ctx := context.Background()
+	f, dir, cleanup := makeCompress(t)
+	defer cleanup()
+
+	contents := strings.Repeat("hello world ", 1000)
+	fstest.PutString(t, f, "file.txt", contents)
+	assert.Equal(t, []string{"file.txt.12000.gz", "file.txt.json"}, fstest.DirNames(t, dir))
+	fi, err := os.Stat(filepath.Join(dir, "file.txt.12000.gz"))
+	require.NoError(t, err)
+	assert.True(t, fi.Size() < int64(len(contents)))
+
+	// The listing shows the original name and size
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, "file.txt", entries[0].Remote())
+	assert.Equal(t, int64(len(contents)), entries[0].Size())
+
+	o, err := f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(contents)), o.Size())
+	assert.Equal(t, contents, fstest.ReadObject(t, o))
+	md5sum, err := o.Hash(hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, fstest.StringHash(t, hash.MD5, contents), md5sum)
+	sha1sum, err := o.Hash(hash.SHA1)
+	require.NoError(t, err)
+	assert.Equal(t, fstest.StringHash(t, hash.SHA1, contents), sha1sum)
+	assert.Equal(t, "text/plain; charset=utf-8", o.(fs.MimeTyper).MimeType())
+
+	// Ranges and seeks are decompressed from the start
+	assert.Equal(t, "world", fstest.ReadObject(t, o, &fs.RangeOption{Start: 6, End: 10}))
+	assert.Equal(t, "hello world ", fstest.ReadObject(t, o, &fs.SeekOption{Offset: int64(len(contents) - 12)}))
+
+	// Remove removes the metadata too
+	require.NoError(t, o.Remove(ctx))
+	assert.Equal(t, []string(nil), fstest.DirNames(t, dir))
+}
+
+func TestAlreadyCompressed(t *testing.T) {
+	ctx := context.Background()
+	f, dir, cleanup := makeCompress(t)
+	defer cleanup()
+
+	fstest.PutString(t, f, "image.jpg", "not really a jpeg")
+	assert.Equal(t, []string{"image.jpg.bin"}, fstest.DirNames(t, dir))
+	o, err := f.NewObject(ctx, "image.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("not really a jpeg")), o.Size())
+	assert.Equal(t, "not really a jpeg", fstest.ReadObject(t, o))
+	assert.Equal(t, "really", fstest.ReadObject(t, o, &fs.RangeOption{Start: 4, End: 9}))
+	assert.Equal(t, "image/jpeg", o.(fs.MimeTyper).MimeType())
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	f, dir, cleanup := makeCompress(t)
+	defer cleanup()
+
+	fstest.PutString(t, f, "file.txt", "hello")
+	assert.Equal(t, []string{"file.txt.5.gz", "file.txt.json"}, fstest.DirNames(t, dir))
+
+	// Changing the size replaces the data object
+	o := fstest.PutString(t, f, "file.txt", "hello world")
+	assert.Equal(t, []string{"file.txt.11.gz", "file.txt.json"}, fstest.DirNames(t, dir))
+	assert.Equal(t, int64(11), o.Size())
+	o, err := f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", fstest.ReadObject(t, o))
+
+	// Turning compression off removes the metadata
+	f.opt.CompressionLevel = 0
+	fstest.PutString(t, f, "file.txt", "hello again")
+	assert.Equal(t, []string{"file.txt.bin"}, fstest.DirNames(t, dir))
+	o, err = f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "hello again", fstest.ReadObject(t, o))
+}
+
+func TestMove(t *testing.T) {
+	ctx := context.Background()
+	f, dir, cleanup := makeCompress(t)
+	defer cleanup()
+
+	o := fstest.PutString(t, f, "file.txt", "hello")
+	dst, err := f.Move(ctx, o, "moved.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "moved.txt", dst.Remote())
+	assert.Equal(t, []string{"moved.txt.5.gz", "moved.txt.json"}, fstest.DirNames(t, dir))
+	assert.Equal(t, "hello", fstest.ReadObject(t, dst))
+}
+
+func TestShortInput(t *testing.T) {
+	ctx := context.Background()
+	f, dir, cleanup := makeCompress(t)
+	defer cleanup()
+
+	for _, stream := range []bool{true, false} {
+		if !stream {
+			// compress to a temporary file first
+			f.Fs.Features().PutStream = nil
+		}
+		src := object.NewStaticObjectInfo("file.txt", time.Now(), 100, true, nil, nil)
+		_, err := f.Put(ctx, bytes.NewBufferString("too short"), src)
+		require.Error(t, err, "stream=%v", stream)
+		assert.Contains(t, err.Error(), "sizes differ", "stream=%v", stream)
+		assert.Equal(t, []string(nil), fstest.DirNames(t, dir), "stream=%v", stream)
+	}
+}
+
+func TestObjectInfoHash(t *testing.T) {
+	f, _, cleanup := makeCompress(t)
+	defer cleanup()
+
+	md5sum := fstest.StringHash(t, hash.MD5, "gzipped")
+	src := object.NewStaticObjectInfo("file.tar.gz", time.Now(), 7, true, map[hash.Type]string{hash.MD5: md5sum}, nil)
+
+	// An uncompressible source of type application/gzip keeps its hash
+	info := f.newObjectInfo(src, dataName("file.tar.gz", -1, false), 7, gzipMimeType)
+	got, err := info.Hash(hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, md5sum, got)
+
+	// The compressed data doesn't have the hash of the source
+	info = f.newObjectInfo(src, dataName("file.tar.gz", 7, true), -1, gzipMimeType)
+	got, err = info.Hash(hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, "", got)
+}
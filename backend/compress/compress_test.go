@@ -0,0 +1,26 @@
+// Test Compress filesystem interface
+package compress_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/artpar/rclone/backend/compress"
+	_ "github.com/artpar/rclone/backend/local"
+	"github.com/artpar/rclone/fstest/fstests"
+)
+
+// TestIntegration runs integration tests against the remote
+func TestIntegration(t *testing.T) {
+	tempdir := filepath.Join(os.TempDir(), "rclone-compress-test")
+	name := "TestCompress"
+	fstests.Run(t, &fstests.Opt{
+		RemoteName: name + ":",
+		NilObject:  (*compress.Object)(nil),
+		ExtraConfig: []fstests.ExtraConfigItem{
+			{Name: name, Key: "type", Value: "compress"},
+			{Name: name, Key: "remote", Value: tempdir},
+		},
+	})
+}
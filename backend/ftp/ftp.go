@@ -2,11 +2,14 @@
 package ftp
 
 import (
+	"context"
 	"io"
+	"net"
 	"net/textproto"
 	"os"
 	"path"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jlaffaye/ftp"
@@ -70,6 +73,8 @@ type Fs struct {
 	dialAddr string
 	poolMu   sync.Mutex
 	pool     []*ftp.ServerConn
+	sitePool []*siteConn // logged in connections for SITE commands
+	noCopy   int32       // set to 1 if the server can't do SITE CPFR/CPTO
 }
 
 // Object describes an FTP file
@@ -168,6 +173,7 @@ func (f *Fs) putFtpConnection(pc **ftp.ServerConn, err error) {
 
 // NewFs contstructs an Fs from the path, container:path
 func NewFs(name, root string, m configmap.Mapper) (ff fs.Fs, err error) {
+	ctx := context.Background()
 	// defer fs.Trace(nil, "name=%q, root=%q", name, root)("fs=%v, err=%v", &ff, &err)
 	// Parse config into Options struct
 	opt := new(Options)
@@ -215,7 +221,7 @@ func NewFs(name, root string, m configmap.Mapper) (ff fs.Fs, err error) {
 		if f.root == "." {
 			f.root = ""
 		}
-		_, err := f.NewObject(remote)
+		_, err := f.NewObject(ctx, remote)
 		if err != nil {
 			if err == fs.ErrorObjectNotFound || errors.Cause(err) == fs.ErrorNotAFile {
 				// File doesn't exist so return old f
@@ -280,7 +286,7 @@ func (f *Fs) findItem(remote string) (entry *ftp.Entry, err error) {
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (o fs.Object, err error) {
+func (f *Fs) NewObject(ctx context.Context, remote string) (o fs.Object, err error) {
 	// defer fs.Trace(remote, "")("o=%v, err=%v", &o, &err)
 	entry, err := f.findItem(remote)
 	if err != nil {
@@ -324,7 +330,7 @@ func (f *Fs) dirExists(remote string) (exists bool, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	// defer fs.Trace(dir, "curlevel=%d", curlevel)("")
 	c, err := f.getFtpConnection()
 	if err != nil {
@@ -389,7 +395,7 @@ func (f *Fs) Precision() time.Duration {
 // May create the object even if it returns an error - if so
 // will return the object and the error, otherwise will return
 // nil and the error
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// fs.Debugf(f, "Trying to put file %s", src.Remote())
 	err := f.mkParentDir(src.Remote())
 	if err != nil {
@@ -399,13 +405,13 @@ func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.
 		fs:     f,
 		remote: src.Remote(),
 	}
-	err = o.Update(in, src, options...)
+	err = o.Update(ctx, in, src, options...)
 	return o, err
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // getInfo reads the FileInfo for a path
@@ -483,7 +489,7 @@ func (f *Fs) mkParentDir(remote string) error {
 }
 
 // Mkdir creates the directory if it doesn't exist
-func (f *Fs) Mkdir(dir string) (err error) {
+func (f *Fs) Mkdir(ctx context.Context, dir string) (err error) {
 	// defer fs.Trace(dir, "")("err=%v", &err)
 	root := path.Join(f.root, dir)
 	return f.mkdir(root)
@@ -492,7 +498,7 @@ func (f *Fs) Mkdir(dir string) (err error) {
 // Rmdir removes the directory (container, bucket) if empty
 //
 // Return an error if it doesn't exist or isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	c, err := f.getFtpConnection()
 	if err != nil {
 		return errors.Wrap(translateErrorFile(err), "Rmdir")
@@ -503,7 +509,7 @@ func (f *Fs) Rmdir(dir string) error {
 }
 
 // Move renames a remote file object
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	srcObj, ok := src.(*Object)
 	if !ok {
 		fs.Debugf(src, "Can't move - not same remote type")
@@ -525,13 +531,175 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "Move Rename failed")
 	}
-	dstObj, err := f.NewObject(remote)
+	dstObj, err := f.NewObject(ctx, remote)
 	if err != nil {
 		return nil, errors.Wrap(err, "Move NewObject failed")
 	}
 	return dstObj, nil
 }
 
+// siteConn is a logged in control connection used to send the SITE
+// commands for server side copies as the FTP library can't send them
+type siteConn struct {
+	nConn net.Conn
+	*textproto.Conn
+}
+
+// cmd sends a command and reads the reply, returning the reply code
+func (c *siteConn) cmd(expectCode int, format string, args ...interface{}) (int, error) {
+	_, err := c.Cmd(format, args...)
+	if err != nil {
+		return 0, err
+	}
+	code, _, err := c.ReadResponse(expectCode)
+	return code, err
+}
+
+// Open a new SITE connection to the FTP server and log in
+func (f *Fs) siteConnection(ctx context.Context) (*siteConn, error) {
+	fs.Debugf(f, "Connecting to FTP server for SITE commands")
+	dialer := net.Dialer{Timeout: fs.Config.ConnectTimeout}
+	nConn, err := dialer.DialContext(ctx, "tcp", f.dialAddr)
+	if err != nil {
+		return nil, errors.Wrap(err, "siteConnection Dial")
+	}
+	c := &siteConn{nConn: nConn, Conn: textproto.NewConn(nConn)}
+	_, _, err = c.ReadResponse(ftp.StatusReady)
+	if err == nil {
+		var code int
+		code, err = c.cmd(ftp.StatusLoggedIn, "USER %s", f.user)
+		if code == ftp.StatusUserOK {
+			_, err = c.cmd(ftp.StatusLoggedIn, "PASS %s", f.pass)
+		}
+	}
+	if err != nil {
+		_ = c.Close()
+		return nil, errors.Wrap(err, "siteConnection Login")
+	}
+	return c, nil
+}
+
+// Get a SITE connection from the pool, or open a new one
+func (f *Fs) getSiteConnection(ctx context.Context) (c *siteConn, err error) {
+	f.poolMu.Lock()
+	if len(f.sitePool) > 0 {
+		c = f.sitePool[0]
+		f.sitePool = f.sitePool[1:]
+	}
+	f.poolMu.Unlock()
+	if c != nil {
+		return c, nil
+	}
+	return f.siteConnection(ctx)
+}
+
+// Return a SITE connection to the pool
+//
+// It nils the pointed to connection out so it can't be reused
+//
+// if err is not a regular FTP error then the connection is closed
+func (f *Fs) putSiteConnection(pc **siteConn, err error) {
+	c := *pc
+	*pc = nil
+	if err != nil {
+		if _, isRegularError := errors.Cause(err).(*textproto.Error); !isRegularError {
+			fs.Debugf(f, "SITE connection failed, closing: %v", err)
+			_ = c.Close()
+			return
+		}
+	}
+	f.poolMu.Lock()
+	f.sitePool = append(f.sitePool, c)
+	f.poolMu.Unlock()
+}
+
+// siteCopy copies srcPath to dstPath on the server with the SITE CPFR
+// and SITE CPTO commands, as provided by ProFTPD's mod_copy.
+//
+// If the server refuses either command then it returns
+// fs.ErrorCantCopy and doesn't try again on this Fs.
+func (f *Fs) siteCopy(ctx context.Context, srcPath, dstPath string) error {
+	if atomic.LoadInt32(&f.noCopy) != 0 {
+		return fs.ErrorCantCopy
+	}
+	c, err := f.getSiteConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Abort the commands if the context is cancelled
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-ctx.Done():
+			_ = c.nConn.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
+	_, err = c.cmd(ftp.StatusRequestFilePending, "SITE CPFR %s", srcPath)
+	if err == nil {
+		_, err = c.cmd(ftp.StatusRequestedFileActionOK, "SITE CPTO %s", dstPath)
+	}
+	close(done)
+	<-finished
+	if ctx.Err() != nil {
+		// don't reuse the connection as its deadline may be set
+		err = ctx.Err()
+	}
+	f.putSiteConnection(&c, err)
+
+	if tpErr, ok := err.(*textproto.Error); ok && tpErr.Code/100 == 5 {
+		fs.Debugf(f, "Server side copy with SITE CPFR/CPTO not available: %v", err)
+		atomic.StoreInt32(&f.noCopy, 1)
+		return fs.ErrorCantCopy
+	}
+	if err != nil {
+		return errors.Wrap(err, "siteCopy")
+	}
+	return nil
+}
+
+// Copy src to this remote using server side copy operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantCopy
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	srcObj, ok := src.(*Object)
+	if !ok {
+		fs.Debugf(src, "Can't copy - not same remote type")
+		return nil, fs.ErrorCantCopy
+	}
+	if atomic.LoadInt32(&f.noCopy) != 0 {
+		return nil, fs.ErrorCantCopy
+	}
+	err := f.mkParentDir(remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "Copy mkParentDir failed")
+	}
+	err = f.siteCopy(ctx,
+		path.Join(srcObj.fs.root, srcObj.remote),
+		path.Join(f.root, remote),
+	)
+	if err == fs.ErrorCantCopy {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Copy failed")
+	}
+	dstObj, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "Copy NewObject failed")
+	}
+	return dstObj, nil
+}
+
 // DirMove moves src, srcRemote to this remote at dstRemote
 // using server side move operations.
 //
@@ -540,7 +708,7 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	srcFs, ok := src.(*Fs)
 	if !ok {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
@@ -665,7 +833,7 @@ func (f *ftpReadCloser) Close() error {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (rc io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.ReadCloser, err error) {
 	// defer fs.Trace(o, "")("rc=%v, err=%v", &rc, &err)
 	path := path.Join(o.fs.root, o.remote)
 	var offset, limit int64 = 0, -1
@@ -699,12 +867,12 @@ func (o *Object) Open(options ...fs.OpenOption) (rc io.ReadCloser, err error) {
 // Copy the reader into the object updating modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
 	// defer fs.Trace(o, "src=%v", src)("err=%v", &err)
 	path := path.Join(o.fs.root, o.remote)
 	// remove the file if upload failed
 	remove := func() {
-		removeErr := o.Remove()
+		removeErr := o.Remove(ctx)
 		if removeErr != nil {
 			fs.Debugf(o, "Failed to remove: %v", removeErr)
 		} else {
@@ -730,7 +898,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 }
 
 // Remove an object
-func (o *Object) Remove() (err error) {
+func (o *Object) Remove(ctx context.Context) (err error) {
 	// defer fs.Trace(o, "")("err=%v", &err)
 	path := path.Join(o.fs.root, o.remote)
 	// Check if it's a directory or a file
@@ -739,7 +907,7 @@ func (o *Object) Remove() (err error) {
 		return err
 	}
 	if info.IsDir {
-		err = o.fs.Rmdir(o.remote)
+		err = o.fs.Rmdir(ctx, o.remote)
 	} else {
 		c, err := o.fs.getFtpConnection()
 		if err != nil {
@@ -0,0 +1,131 @@
+package ftp
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeServer starts a server for a single control connection
+// which answers each command with the reply for its first word, or
+// 500 if there isn't one.  Commands whose reply is "" get no reply.
+// The commands received are sent on the channel returned when the
+// connection closes.
+func startFakeServer(t *testing.T, replies map[string]string) (addr string, commands <-chan []string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	out := make(chan []string, 1)
+	go func() {
+		var received []string
+		defer func() { out <- received }()
+		nConn, err := ln.Accept()
+		_ = ln.Close()
+		if err != nil {
+			return
+		}
+		c := textproto.NewConn(nConn)
+		defer func() { _ = c.Close() }()
+		_ = c.PrintfLine("220 fake")
+		for {
+			line, err := c.ReadLine()
+			if err != nil {
+				return
+			}
+			received = append(received, line)
+			verb := strings.Fields(line)[0]
+			if verb == "SITE" {
+				verb = strings.Join(strings.Fields(line)[:2], " ")
+			}
+			reply, ok := replies[verb]
+			if !ok {
+				reply = "500 unknown command"
+			}
+			if reply != "" {
+				_ = c.PrintfLine("%s", reply)
+			}
+		}
+	}()
+	return ln.Addr().String(), out
+}
+
+// closeSitePool closes the pooled SITE connections
+func closeSitePool(f *Fs) {
+	for _, c := range f.sitePool {
+		_ = c.Close()
+	}
+	f.sitePool = nil
+}
+
+func TestSiteCopy(t *testing.T) {
+	ctx := context.Background()
+	addr, commands := startFakeServer(t, map[string]string{
+		"USER":      "331 password please",
+		"PASS":      "230 logged in",
+		"SITE CPFR": "350 file exists, ready for destination name",
+		"SITE CPTO": "250 copy successful",
+	})
+	f := &Fs{dialAddr: addr, user: "user", pass: "pass"}
+	require.NoError(t, f.siteCopy(ctx, "dir/src.txt", "dir/dst.txt"))
+	require.NoError(t, f.siteCopy(ctx, "dir/src.txt", "dir/dst2.txt"))
+	closeSitePool(f)
+
+	// The connection is reused so it only logs in once
+	assert.Equal(t, []string{
+		"USER user",
+		"PASS pass",
+		"SITE CPFR dir/src.txt",
+		"SITE CPTO dir/dst.txt",
+		"SITE CPFR dir/src.txt",
+		"SITE CPTO dir/dst2.txt",
+	}, <-commands)
+}
+
+func TestSiteCopyUnsupported(t *testing.T) {
+	ctx := context.Background()
+	for _, replies := range []map[string]string{
+		{"SITE CPFR": "500 unknown command"},
+		{"SITE CPFR": "550 src.txt: No such file or directory"},
+		{"SITE CPFR": "350 ok", "SITE CPTO": "501 not allowed"},
+	} {
+		replies["USER"] = "230 logged in"
+		addr, commands := startFakeServer(t, replies)
+		f := &Fs{dialAddr: addr, user: "anonymous"}
+		assert.Equal(t, fs.ErrorCantCopy, f.siteCopy(ctx, "src.txt", "dst.txt"))
+
+		// It doesn't try again
+		assert.Equal(t, fs.ErrorCantCopy, f.siteCopy(ctx, "src.txt", "dst.txt"))
+		closeSitePool(f)
+		assert.Equal(t, 1, strings.Count(strings.Join(<-commands, "\n"), "SITE CPFR"))
+	}
+}
+
+func TestSiteCopyCancel(t *testing.T) {
+	addr, _ := startFakeServer(t, map[string]string{
+		"USER":      "230 logged in",
+		"SITE CPFR": "", // never replies
+	})
+	f := &Fs{dialAddr: addr, user: "anonymous"}
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := f.getSiteConnection(ctx)
+	require.NoError(t, err)
+	f.putSiteConnection(&c, nil)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- f.siteCopy(ctx, "src.txt", "dst.txt")
+	}()
+	cancel()
+	err = <-errChan
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+
+	// The connection was thrown away
+	assert.Equal(t, 0, len(f.sitePool))
+	assert.Equal(t, int32(0), f.noCopy)
+}
@@ -0,0 +1,200 @@
+// +build !plan9
+
+package hasher
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/lib/atexit"
+	"github.com/pkg/errors"
+)
+
+const (
+	hashBucket = "hashes"        // bucket the records are stored in
+	dbWaitTime = 5 * time.Second // time to wait for the DB to be available
+)
+
+var (
+	dbMu  sync.Mutex
+	dbMap = make(map[string]*bolt.DB) // open databases by path
+)
+
+// openDB opens the database at dbPath or returns the one already open
+//
+// The databases are closed when rclone exits.
+func openDB(dbPath string) (*bolt.DB, error) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	if db, ok := dbMap[dbPath]; ok {
+		return db, nil
+	}
+	err := os.MkdirAll(filepath.Dir(dbPath), os.ModePerm)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory for %q", dbPath)
+	}
+	db, err := bolt.Open(dbPath, 0644, &bolt.Options{Timeout: dbWaitTime})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open hash database %q - is there another rclone running on the same remote?", dbPath)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(hashBucket))
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.Wrapf(err, "failed to initialise hash database %q", dbPath)
+	}
+	if len(dbMap) == 0 {
+		atexit.Register(closeDBs)
+	}
+	dbMap[dbPath] = db
+	return db, nil
+}
+
+// closeDBs closes all the open databases
+func closeDBs() {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	for dbPath, db := range dbMap {
+		err := db.Close()
+		if err != nil {
+			fs.Errorf(nil, "Failed to close hash database %q: %v", dbPath, err)
+		}
+		delete(dbMap, dbPath)
+	}
+}
+
+// hashRecord is stored in the database for each object
+//
+// The hashes are only valid while the size and modification time of
+// the object match the fingerprint stored with them.
+type hashRecord struct {
+	Size    int64             `json:"size"`
+	ModTime time.Time         `json:"modtime"`
+	Hashes  map[string]string `json:"hashes"`
+}
+
+// newRecord makes a record with the fingerprint of o
+func newRecord(o fs.ObjectInfo) *hashRecord {
+	return &hashRecord{
+		Size:    o.Size(),
+		ModTime: o.ModTime(),
+		Hashes:  make(map[string]string),
+	}
+}
+
+// matches returns whether the record is for o in its current state
+//
+// The modification time isn't checked if the remote can't store it.
+func (rec *hashRecord) matches(o fs.ObjectInfo, precision time.Duration) bool {
+	if rec.Size != o.Size() {
+		return false
+	}
+	return precision == fs.ModTimeNotSupported || rec.ModTime.Equal(o.ModTime())
+}
+
+// hash returns the stored hash of type ht or "" if there isn't one
+func (rec *hashRecord) hash(ht hash.Type) string {
+	return rec.Hashes[ht.String()]
+}
+
+// setHashes stores the sums passed in
+func (rec *hashRecord) setHashes(sums map[hash.Type]string) {
+	for ht, sum := range sums {
+		if sum != "" {
+			rec.Hashes[ht.String()] = sum
+		}
+	}
+}
+
+// getRecord reads the record for key returning nil if not found
+func (f *Fs) getRecord(key string) (rec *hashRecord, err error) {
+	err = f.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(hashBucket)).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		rec = new(hashRecord)
+		return json.Unmarshal(data, rec)
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read hashes for %q", key)
+	}
+	return rec, nil
+}
+
+// putRecord stores rec for key
+func (f *Fs) putRecord(key string, rec *hashRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode hashes")
+	}
+	err = f.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(hashBucket)).Put([]byte(key), data)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to store hashes for %q", key)
+	}
+	return nil
+}
+
+// deleteRecord removes the record for key if there is one
+func (f *Fs) deleteRecord(key string) error {
+	err := f.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(hashBucket)).Delete([]byte(key))
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove hashes for %q", key)
+	}
+	return nil
+}
+
+// isInDir returns whether key is dir or inside it
+//
+// dir == "" matches everything
+func isInDir(key, dir string) bool {
+	return dir == "" || key == dir || strings.HasPrefix(key, dir+"/")
+}
+
+// moveRecords moves the records in srcDir to dstDir, or deletes them
+// if dstDir is nil
+func (f *Fs) moveRecords(srcDir string, dstDir *string) error {
+	err := f.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(hashBucket))
+		var keys []string
+		c := b.Cursor()
+		for k, _ := c.Seek([]byte(srcDir)); k != nil && strings.HasPrefix(string(k), srcDir); k, _ = c.Next() {
+			if isInDir(string(k), srcDir) {
+				keys = append(keys, string(k))
+			}
+		}
+		for _, key := range keys {
+			if dstDir != nil {
+				newKey := path.Join(*dstDir, strings.TrimPrefix(strings.TrimPrefix(key, srcDir), "/"))
+				data := append([]byte(nil), b.Get([]byte(key))...)
+				err := b.Put([]byte(newKey), data)
+				if err != nil {
+					return err
+				}
+			}
+			err := b.Delete([]byte(key))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update hashes in %q", srcDir)
+	}
+	return nil
+}
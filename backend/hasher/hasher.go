@@ -0,0 +1,677 @@
+// +build !plan9
+
+// Package hasher provides wrappers for Fs and Object which keep a
+// database of checksums for remotes which can't supply them
+package hasher
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config"
+	"github.com/artpar/rclone/fs/config/configmap"
+	"github.com/artpar/rclone/fs/config/configstruct"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/pkg/errors"
+)
+
+// Register with Fs
+func init() {
+	fs.Register(&fs.RegInfo{
+		Name:        "hasher",
+		Description: "Keep a database of checksums for a remote",
+		NewFs:       NewFs,
+		Options: []fs.Option{{
+			Name:     "remote",
+			Help:     "Remote to keep checksums for.\nNormally should contain a ':' and a path, eg \"myremote:path/to/dir\",\n\"myremote:bucket\" or maybe \"myremote:\".",
+			Required: true,
+		}, {
+			Name:    "hashes",
+			Help:    "Comma separated list of hashes to keep, eg \"md5,sha1\".",
+			Default: "md5,sha1",
+		}, {
+			Name:    "auto_size",
+			Help:    "Download files up to this size to compute missing checksums.\nThe default of 0 never downloads files.",
+			Default: fs.SizeSuffix(0),
+		}, {
+			Name:     "db_path",
+			Help:     "Directory to keep the checksum databases in.",
+			Default:  filepath.Join(config.CacheDir, "hasher"),
+			Advanced: true,
+		}},
+	})
+}
+
+// Options defines the configuration for this backend
+type Options struct {
+	Remote   string        `config:"remote"`
+	Hashes   string        `config:"hashes"`
+	AutoSize fs.SizeSuffix `config:"auto_size"`
+	DbPath   string        `config:"db_path"`
+}
+
+// Fs represents a wrapped fs.Fs
+type Fs struct {
+	fs.Fs
+	name     string
+	root     string
+	opt      Options
+	features *fs.Features // optional features
+	base     string       // root of the wrapped Fs relative to the remote setting
+	hashes   hash.Set     // hashes supported
+	ownSet   hash.Set     // hashes kept in the database
+	db       *bolt.DB     // the checksum database
+}
+
+// parseHashes turns a comma separated list of hash names into a Set
+//
+// The names are matched case insensitively ignoring "-" so "sha1"
+// and "SHA-1" are both accepted.
+func parseHashes(names string) (set hash.Set, err error) {
+	normalise := func(s string) string {
+		return strings.ToLower(strings.Replace(strings.TrimSpace(s), "-", "", -1))
+	}
+	for _, name := range strings.Split(names, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		found := false
+		for _, ht := range hash.Supported.Array() {
+			if normalise(ht.String()) == normalise(name) || normalise(ht.String()) == normalise(name)+"hash" {
+				set.Add(ht)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return set, errors.Errorf("unknown hash %q", name)
+		}
+	}
+	if set.Count() == 0 {
+		return set, errors.New("no hashes configured")
+	}
+	return set, nil
+}
+
+// NewFs contstructs an Fs from the path, container:path
+func NewFs(name, rpath string, m configmap.Mapper) (fs.Fs, error) {
+	// Parse config into Options struct
+	opt := new(Options)
+	err := configstruct.Set(m, opt)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := parseHashes(opt.Hashes)
+	if err != nil {
+		return nil, err
+	}
+	remote := opt.Remote
+	if strings.HasPrefix(remote, name+":") {
+		return nil, errors.New("can't point hasher remote at itself - check the value of the remote setting")
+	}
+	remotePath := path.Join(remote, rpath)
+	wrappedFs, err := fs.NewFs(remotePath)
+	if err != fs.ErrorIsFile && err != nil {
+		return nil, errors.Wrapf(err, "failed to make remote %q to wrap", remotePath)
+	}
+	isFile := err == fs.ErrorIsFile
+	db, err := openDB(filepath.Join(opt.DbPath, name+".db"))
+	if err != nil {
+		return nil, err
+	}
+	f := &Fs{
+		Fs:     wrappedFs,
+		name:   name,
+		root:   rpath,
+		opt:    *opt,
+		base:   path.Clean(rpath),
+		hashes: hashes,
+		db:     db,
+	}
+	if isFile {
+		f.base = path.Dir(f.base)
+	}
+	if f.base == "." || f.base == "/" {
+		f.base = ""
+	}
+	// Use the wrapped remote for any hashes it supports itself
+	wrappedHashes := wrappedFs.Hashes()
+	for _, ht := range hashes.Array() {
+		if !wrappedHashes.Contains(ht) {
+			f.ownSet.Add(ht)
+		}
+	}
+	f.hashes.Add(wrappedHashes.Array()...)
+	// the features here are ones we could support, and they are
+	// ANDed with the ones from wrappedFs
+	f.features = (&fs.Features{
+		CaseInsensitive:         true,
+		DuplicateFiles:          true,
+		ReadMimeType:            true,
+		WriteMimeType:           true,
+		BucketBased:             true,
+		CanHaveEmptyDirectories: true,
+	}).Fill(f).Mask(wrappedFs).WrapsFs(f, wrappedFs)
+	f.features.ChangeNotify = wrappedFs.Features().ChangeNotify
+
+	if isFile {
+		return f, fs.ErrorIsFile
+	}
+	return f, nil
+}
+
+// Name of the remote (as passed into NewFs)
+func (f *Fs) Name() string {
+	return f.name
+}
+
+// Root of the remote (as passed into NewFs)
+func (f *Fs) Root() string {
+	return f.root
+}
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features {
+	return f.features
+}
+
+// String returns a description of the FS
+func (f *Fs) String() string {
+	return fmt.Sprintf("Hasher '%s:%s'", f.name, f.root)
+}
+
+// Hashes returns the supported hash sets.
+func (f *Fs) Hashes() hash.Set {
+	return f.hashes
+}
+
+// key returns the database key for remote
+func (f *Fs) key(remote string) string {
+	return path.Join(f.base, remote)
+}
+
+// wrapEntries wraps the objects in entries.  This alters entries
+// returning it as newEntries.
+func (f *Fs) wrapEntries(entries fs.DirEntries) (newEntries fs.DirEntries, err error) {
+	newEntries = entries[:0] // in place filter
+	for _, entry := range entries {
+		switch x := entry.(type) {
+		case fs.Object:
+			newEntries = append(newEntries, f.newObject(x))
+		case fs.Directory:
+			newEntries = append(newEntries, x)
+		default:
+			return nil, errors.Errorf("Unknown object type %T", entry)
+		}
+	}
+	return newEntries, nil
+}
+
+// List the objects and directories in dir into entries.  The
+// entries can be returned in any order but should be for a
+// complete directory.
+//
+// dir should be "" to list the root, and should not have
+// trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	entries, err = f.Fs.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	return f.wrapEntries(entries)
+}
+
+// ListR lists the objects and directories of the Fs starting
+// from dir recursively into out.
+//
+// dir should be "" to start from the root, and should not
+// have trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+//
+// It should call callback for each tranche of entries read.
+// These need not be returned in any particular order.  If
+// callback returns an error then the listing will stop
+// immediately.
+//
+// Don't implement this unless you have a more efficient way
+// of listing recursively that doing a directory traversal.
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
+	return f.Fs.Features().ListR(ctx, dir, func(entries fs.DirEntries) error {
+		newEntries, err := f.wrapEntries(entries)
+		if err != nil {
+			return err
+		}
+		return callback(newEntries)
+	})
+}
+
+// NewObject finds the Object at remote.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	o, err := f.Fs.NewObject(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	return f.newObject(o), nil
+}
+
+type putFn func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error)
+
+// put implements Put, PutStream and Update, computing the hashes of
+// the data as it is uploaded and storing them if it succeeds
+func (f *Fs) put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, put putFn) (fs.Object, error) {
+	if f.ownSet.Count() == 0 {
+		o, err := put(ctx, in, src, options...)
+		if err != nil {
+			return nil, err
+		}
+		return f.newObject(o), nil
+	}
+	hasher, err := hash.NewMultiHasherTypes(f.ownSet)
+	if err != nil {
+		return nil, err
+	}
+	o, err := put(ctx, io.TeeReader(in, hasher), src, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the hashes of the data against the source
+	sums := hasher.Sums()
+	for ht, sum := range sums {
+		srcSum, _ := src.Hash(ht)
+		if !hash.Equals(srcSum, sum) {
+			err = o.Remove(ctx)
+			if err != nil {
+				fs.Errorf(o, "Failed to remove corrupted object: %v", err)
+			}
+			return nil, errors.Errorf("corrupted on transfer: %v hash differ %q vs %q", ht, srcSum, sum)
+		}
+	}
+
+	// Store them if the whole object was read
+	key := f.key(o.Remote())
+	if hasher.Size() != o.Size() {
+		fs.Debugf(o, "Not storing hashes as only read %d of %d bytes", hasher.Size(), o.Size())
+		return f.newObject(o), f.deleteRecord(key)
+	}
+	rec := newRecord(o)
+	rec.setHashes(sums)
+	return f.newObject(o), f.putRecord(key, rec)
+}
+
+// Put in to the remote path with the modTime given of the given size
+//
+// May create the object even if it returns an error - if so
+// will return the object and the error, otherwise will return
+// nil and the error
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.put(ctx, in, src, options, f.Fs.Put)
+}
+
+// PutStream uploads to the remote path with the modTime given of indeterminate size
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.put(ctx, in, src, options, f.Fs.Features().PutStream)
+}
+
+// Purge all files in the root and the root directory
+//
+// Implement this if you have a way of deleting all the files
+// quicker than just running Remove() on the result of List()
+//
+// Return an error if it doesn't exist
+func (f *Fs) Purge(ctx context.Context) error {
+	do := f.Fs.Features().Purge
+	if do == nil {
+		return fs.ErrorCantPurge
+	}
+	err := do(ctx)
+	if err != nil {
+		return err
+	}
+	return f.moveRecords(f.base, nil)
+}
+
+// copyRecord stores the hashes of src against dst if they are still
+// valid and dst looks the same
+func (f *Fs) copyRecord(src *Object, dst fs.Object) {
+	rec, err := src.f.getRecord(src.key())
+	if err != nil || rec == nil || !rec.matches(src.Object, src.f.Precision()) || rec.Size != dst.Size() {
+		return
+	}
+	rec.ModTime = dst.ModTime()
+	err = f.putRecord(f.key(dst.Remote()), rec)
+	if err != nil {
+		fs.Errorf(dst, "Failed to copy hashes: %v", err)
+	}
+}
+
+// Copy src to this remote using server side copy operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantCopy
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Copy
+	if do == nil {
+		return nil, fs.ErrorCantCopy
+	}
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, fs.ErrorCantCopy
+	}
+	oResult, err := do(ctx, o.Object, remote)
+	if err != nil {
+		return nil, err
+	}
+	f.copyRecord(o, oResult)
+	return f.newObject(oResult), nil
+}
+
+// Move src to this remote using server side move operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantMove
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Move
+	if do == nil {
+		return nil, fs.ErrorCantMove
+	}
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, fs.ErrorCantMove
+	}
+	oResult, err := do(ctx, o.Object, remote)
+	if err != nil {
+		return nil, err
+	}
+	f.copyRecord(o, oResult)
+	err = o.f.deleteRecord(o.key())
+	if err != nil {
+		fs.Errorf(o, "Failed to remove hashes: %v", err)
+	}
+	return f.newObject(oResult), nil
+}
+
+// DirMove moves src, srcRemote to this remote at dstRemote
+// using server side move operations.
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantDirMove
+//
+// If destination exists then return fs.ErrorDirExists
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
+	do := f.Fs.Features().DirMove
+	if do == nil {
+		return fs.ErrorCantDirMove
+	}
+	srcFs, ok := src.(*Fs)
+	if !ok {
+		fs.Debugf(src, "Can't move directory - not same remote type")
+		return fs.ErrorCantDirMove
+	}
+	err := do(ctx, srcFs.Fs, srcRemote, dstRemote)
+	if err != nil {
+		return err
+	}
+	if srcFs.db != f.db {
+		return srcFs.moveRecords(srcFs.key(srcRemote), nil)
+	}
+	dstDir := f.key(dstRemote)
+	return f.moveRecords(srcFs.key(srcRemote), &dstDir)
+}
+
+// CleanUp the trash in the Fs
+//
+// Implement this if you have a way of emptying the trash or
+// otherwise cleaning up old versions of files.
+func (f *Fs) CleanUp() error {
+	do := f.Fs.Features().CleanUp
+	if do == nil {
+		return errors.New("can't CleanUp")
+	}
+	return do()
+}
+
+// About gets quota information from the Fs
+func (f *Fs) About() (*fs.Usage, error) {
+	do := f.Fs.Features().About
+	if do == nil {
+		return nil, errors.New("About not supported")
+	}
+	return do()
+}
+
+// UnWrap returns the Fs that this Fs is wrapping
+func (f *Fs) UnWrap() fs.Fs {
+	return f.Fs
+}
+
+// Import reads a file in the format produced by md5sum, sha1sum or
+// rclone hashsum from in and stores the checksums of type ht in it
+// for the objects found.
+//
+// The paths in the file are relative to the root of f.  Objects which
+// can't be found are logged and counted and an error is returned at
+// the end if there were any.
+func (f *Fs) Import(ctx context.Context, ht hash.Type, in io.Reader) error {
+	if !f.hashes.Contains(ht) {
+		return errors.Errorf("hash %v isn't configured for %v", ht, f)
+	}
+	if !f.ownSet.Contains(ht) {
+		return errors.Errorf("hash %v is supplied by the wrapped remote so doesn't need importing", ht)
+	}
+	var imported, errs int
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 || len(fields[1]) < 2 || (fields[1][0] != ' ' && fields[1][0] != '*') {
+			fs.Errorf(nil, "line %d: can't parse %q", lineNumber, line)
+			errs++
+			continue
+		}
+		sum, remote := strings.ToLower(fields[0]), fields[1][1:]
+		o, err := f.Fs.NewObject(ctx, remote)
+		if err != nil {
+			fs.Errorf(remote, "Can't import checksum: %v", err)
+			errs++
+			continue
+		}
+		key := f.key(remote)
+		rec, err := f.getRecord(key)
+		if err != nil {
+			return err
+		}
+		if rec == nil || !rec.matches(o, f.Precision()) {
+			rec = newRecord(o)
+		}
+		rec.setHashes(map[hash.Type]string{ht: sum})
+		err = f.putRecord(key, rec)
+		if err != nil {
+			return err
+		}
+		imported++
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "failed to read checksums")
+	}
+	fs.Infof(f, "Imported %d %v checksums", imported, ht)
+	if errs != 0 {
+		return errors.Errorf("failed to import %d checksums", errs)
+	}
+	return nil
+}
+
+// Object describes a wrapped object with checksums from the database
+type Object struct {
+	fs.Object
+	f *Fs
+}
+
+func (f *Fs) newObject(o fs.Object) *Object {
+	return &Object{
+		Object: o,
+		f:      f,
+	}
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info {
+	return o.f
+}
+
+// Return a string version
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.Remote()
+}
+
+// key returns the database key for the object
+func (o *Object) key() string {
+	return o.f.key(o.Remote())
+}
+
+// UnWrap returns the wrapped Object
+func (o *Object) UnWrap() fs.Object {
+	return o.Object
+}
+
+// MimeType returns the content type of the Object if
+// known, or "" if not
+func (o *Object) MimeType() string {
+	return fs.MimeType(o.Object)
+}
+
+// Hash returns the selected checksum of the file
+//
+// Hashes the wrapped remote supports come from it, the others from
+// the database if they are still valid.  If they aren't and the
+// object is no bigger than auto_size it is downloaded to compute
+// them, otherwise it returns "".
+func (o *Object) Hash(ht hash.Type) (string, error) {
+	if !o.f.ownSet.Contains(ht) {
+		if o.f.hashes.Contains(ht) {
+			return o.Object.Hash(ht)
+		}
+		return "", hash.ErrUnsupported
+	}
+	rec, err := o.f.getRecord(o.key())
+	if err != nil {
+		return "", err
+	}
+	if rec != nil && rec.matches(o.Object, o.f.Precision()) {
+		if sum := rec.hash(ht); sum != "" {
+			return sum, nil
+		}
+	}
+	size := o.Size()
+	if size < 0 || size > int64(o.f.opt.AutoSize) {
+		return "", nil
+	}
+	sums, err := o.hashByDownload(context.TODO())
+	if err != nil {
+		return "", err
+	}
+	return sums[ht], nil
+}
+
+// hashByDownload reads the object computing all the hashes kept in
+// the database and stores them
+func (o *Object) hashByDownload(ctx context.Context) (sums map[hash.Type]string, err error) {
+	fs.Debugf(o, "Downloading to compute hashes")
+	in, err := o.Object.Open(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open object to hash")
+	}
+	defer fs.CheckClose(in, &err)
+	sums, err = hash.StreamTypes(in, o.f.ownSet)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to hash object")
+	}
+	rec := newRecord(o.Object)
+	rec.setHashes(sums)
+	err = o.f.putRecord(o.key(), rec)
+	if err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+// SetModTime sets the modification time of the file, keeping the
+// stored hashes if they were valid
+func (o *Object) SetModTime(t time.Time) error {
+	rec, err := o.f.getRecord(o.key())
+	if err != nil {
+		return err
+	}
+	valid := rec != nil && rec.matches(o.Object, o.f.Precision())
+	err = o.Object.SetModTime(t)
+	if err != nil || !valid {
+		return err
+	}
+	rec.ModTime = o.Object.ModTime()
+	return o.f.putRecord(o.key(), rec)
+}
+
+// Update in to the object with the modTime given of the given size
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	update := func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+		return o.Object, o.Object.Update(ctx, in, src, options...)
+	}
+	_, err := o.f.put(ctx, in, src, options, update)
+	return err
+}
+
+// Remove an object
+func (o *Object) Remove(ctx context.Context) error {
+	err := o.Object.Remove(ctx)
+	if err != nil {
+		return err
+	}
+	return o.f.deleteRecord(o.key())
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs              = (*Fs)(nil)
+	_ fs.Purger          = (*Fs)(nil)
+	_ fs.Copier          = (*Fs)(nil)
+	_ fs.Mover           = (*Fs)(nil)
+	_ fs.DirMover        = (*Fs)(nil)
+	_ fs.PutStreamer     = (*Fs)(nil)
+	_ fs.CleanUpper      = (*Fs)(nil)
+	_ fs.UnWrapper       = (*Fs)(nil)
+	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.Object          = (*Object)(nil)
+	_ fs.ObjectUnWrapper = (*Object)(nil)
+	_ fs.MimeTyper       = (*Object)(nil)
+)
@@ -0,0 +1,190 @@
+// +build !plan9
+
+package hasher
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/artpar/rclone/backend/crypt" // pull in test backends
+	_ "github.com/artpar/rclone/backend/local"
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config"
+	"github.com/artpar/rclone/fs/config/configmap"
+	"github.com/artpar/rclone/fs/config/obscure"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	helloMD5  = "5d41402abc4b2a76b9719d911017c592"
+	helloSHA1 = "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d"
+)
+
+// makeHasher makes a hasher wrapping a crypt remote in a temporary
+// directory so none of the hashes are supplied by the wrapped remote
+func makeHasher(t *testing.T, name, root string, extra configmap.Simple) (f *Fs, cleanup func()) {
+	dir, cleanup := fstest.TempDir(t, "rclone-hasher-internal")
+	config.FileSet(name+"Crypt", "type", "crypt")
+	config.FileSet(name+"Crypt", "remote", filepath.Join(dir, "data"))
+	config.FileSet(name+"Crypt", "password", obscure.MustObscure("potato"))
+	m := configmap.Simple{
+		"remote":  name + "Crypt:",
+		"hashes":  "md5,sha1",
+		"db_path": filepath.Join(dir, "db"),
+	}
+	for k, v := range extra {
+		m[k] = v
+	}
+	fi, err := NewFs(name, root, m)
+	require.NoError(t, err)
+	return fi.(*Fs), cleanup
+}
+
+func TestParseHashes(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		want    hash.Set
+		wantErr bool
+	}{
+		{"md5", hash.NewHashSet(hash.MD5), false},
+		{"MD5, SHA-1", hash.NewHashSet(hash.MD5, hash.SHA1), false},
+		{"sha1,dropbox,quickxor", hash.NewHashSet(hash.SHA1, hash.Dropbox, hash.QuickXorHash), false},
+		{"", hash.Set(hash.None), true},
+		{"md5,potato", hash.Set(hash.None), true},
+	} {
+		got, err := parseHashes(test.in)
+		assert.Equal(t, test.wantErr, err != nil, test.in)
+		if err == nil {
+			assert.Equal(t, test.want, got, test.in)
+		}
+	}
+}
+
+func TestHashes(t *testing.T) {
+	ctx := context.Background()
+	f, cleanup := makeHasher(t, "TestHasherHashes", "", nil)
+	defer cleanup()
+	assert.Equal(t, hash.NewHashSet(hash.MD5, hash.SHA1), f.Hashes())
+
+	// Hashes are stored on upload
+	o := fstest.PutString(t, f, "dir/file.txt", "hello")
+	assert.Equal(t, helloMD5, fstest.ObjectHash(t, o, hash.MD5))
+	assert.Equal(t, helloSHA1, fstest.ObjectHash(t, o, hash.SHA1))
+	_, err := o.Hash(hash.Dropbox)
+	assert.Equal(t, hash.ErrUnsupported, err)
+
+	// And survive finding the object again
+	o, err = f.NewObject(ctx, "dir/file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, helloMD5, fstest.ObjectHash(t, o, hash.MD5))
+
+	// Changing the file behind the hasher's back invalidates them
+	_ = fstest.PutString(t, f.Fs, "dir/file.txt", "HELLO")
+	o, err = f.NewObject(ctx, "dir/file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "", fstest.ObjectHash(t, o, hash.MD5))
+
+	// Moves take the hashes with them
+	o = fstest.PutString(t, f, "dir/file.txt", "hello")
+	if f.Features().Move != nil {
+		o, err = f.Features().Move(ctx, o, "moved.txt")
+		require.NoError(t, err)
+		assert.Equal(t, helloMD5, fstest.ObjectHash(t, o, hash.MD5))
+		rec, err := f.getRecord("dir/file.txt")
+		require.NoError(t, err)
+		assert.Nil(t, rec)
+	}
+
+	// As do directory moves
+	_ = fstest.PutString(t, f, "src/sub/file.txt", "hello")
+	if f.Features().DirMove != nil {
+		require.NoError(t, f.Features().DirMove(ctx, f, "src", "dst"))
+		o, err = f.NewObject(ctx, "dst/sub/file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, helloMD5, fstest.ObjectHash(t, o, hash.MD5))
+	}
+
+	// Removing the object removes the hashes
+	require.NoError(t, o.Remove(ctx))
+	rec, err := f.getRecord(o.(*Object).key())
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
+
+func TestSubdirKeys(t *testing.T) {
+	ctx := context.Background()
+	f, cleanup := makeHasher(t, "TestHasherSubdir", "", nil)
+	defer cleanup()
+	_ = fstest.PutString(t, f, "dir/file.txt", "hello")
+
+	// A hasher on a subdirectory shares the records
+	fi, err := NewFs("TestHasherSubdir", "dir", configmap.Simple{
+		"remote":  "TestHasherSubdirCrypt:",
+		"hashes":  "md5,sha1",
+		"db_path": f.opt.DbPath,
+	})
+	require.NoError(t, err)
+	o, err := fi.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, helloMD5, fstest.ObjectHash(t, o, hash.MD5))
+
+	// Including when pointed at the file
+	fi, err = NewFs("TestHasherSubdir", "dir/file.txt", configmap.Simple{
+		"remote":  "TestHasherSubdirCrypt:",
+		"hashes":  "md5,sha1",
+		"db_path": f.opt.DbPath,
+	})
+	require.Equal(t, fs.ErrorIsFile, err)
+	o, err = fi.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, helloMD5, fstest.ObjectHash(t, o, hash.MD5))
+}
+
+func TestAutoSize(t *testing.T) {
+	ctx := context.Background()
+	f, cleanup := makeHasher(t, "TestHasherAuto", "", configmap.Simple{"auto_size": "4b"})
+	defer cleanup()
+
+	// Files uploaded behind the hasher's back are hashed by
+	// downloading if they are small enough
+	_ = fstest.PutString(t, f.Fs, "small.txt", "hell")
+	_ = fstest.PutString(t, f.Fs, "big.txt", "hello")
+	o, err := f.NewObject(ctx, "small.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "4229d691b07b13341da53f17ab9f2416", fstest.ObjectHash(t, o, hash.MD5))
+	rec, err := f.getRecord("small.txt")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, 2, len(rec.Hashes))
+	o, err = f.NewObject(ctx, "big.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "", fstest.ObjectHash(t, o, hash.MD5))
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	f, cleanup := makeHasher(t, "TestHasherImport", "", nil)
+	defer cleanup()
+
+	_ = fstest.PutString(t, f.Fs, "file.txt", "hello")
+	_ = fstest.PutString(t, f.Fs, "dir/file two.txt", "hello")
+	sums := helloMD5 + "  file.txt\n" +
+		strings.ToUpper(helloMD5) + " *dir/file two.txt\n" +
+		"\n" +
+		helloMD5 + "  missing.txt\n" +
+		"garbage\n"
+	err := f.Import(ctx, hash.MD5, strings.NewReader(sums))
+	assert.EqualError(t, err, "failed to import 2 checksums")
+	for _, remote := range []string{"file.txt", "dir/file two.txt"} {
+		o, err := f.NewObject(ctx, remote)
+		require.NoError(t, err)
+		assert.Equal(t, helloMD5, fstest.ObjectHash(t, o, hash.MD5), remote)
+		assert.Equal(t, "", fstest.ObjectHash(t, o, hash.SHA1), remote)
+	}
+	assert.Error(t, f.Import(ctx, hash.Dropbox, strings.NewReader(sums)))
+}
@@ -0,0 +1,35 @@
+// +build !plan9
+
+// Test Hasher filesystem interface
+package hasher_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/artpar/rclone/backend/crypt"
+	"github.com/artpar/rclone/backend/hasher"
+	_ "github.com/artpar/rclone/backend/local"
+	"github.com/artpar/rclone/fs/config/obscure"
+	"github.com/artpar/rclone/fstest/fstests"
+)
+
+// TestIntegration runs integration tests against the remote wrapping
+// a crypt remote which can't supply any hashes itself
+func TestIntegration(t *testing.T) {
+	tempdir := filepath.Join(os.TempDir(), "rclone-hasher-test")
+	name := "TestHasher"
+	fstests.Run(t, &fstests.Opt{
+		RemoteName: name + ":",
+		NilObject:  (*hasher.Object)(nil),
+		ExtraConfig: []fstests.ExtraConfigItem{
+			{Name: name + "Crypt", Key: "type", Value: "crypt"},
+			{Name: name + "Crypt", Key: "remote", Value: filepath.Join(tempdir, "data")},
+			{Name: name + "Crypt", Key: "password", Value: obscure.MustObscure("potato")},
+			{Name: name, Key: "type", Value: "hasher"},
+			{Name: name, Key: "remote", Value: name + "Crypt:"},
+			{Name: name, Key: "db_path", Value: filepath.Join(tempdir, "db")},
+		},
+	})
+}
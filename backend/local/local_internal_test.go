@@ -1,11 +1,15 @@
 package local
 
 import (
+	"context"
+	"io/ioutil"
 	"os"
 	"path"
+	"runtime"
 	"testing"
 	"time"
 
+	"github.com/artpar/rclone/fs"
 	"github.com/artpar/rclone/fs/hash"
 	"github.com/artpar/rclone/fstest"
 	"github.com/artpar/rclone/lib/readers"
@@ -72,3 +76,59 @@ func TestUpdatingCheck(t *testing.T) {
 	require.NoError(t, err)
 
 }
+
+func TestOpenWriterAt(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	f := r.Flocal.(*Fs)
+
+	filePath := "sub dir/writer at"
+	wc, err := f.OpenWriterAt(ctx, filePath, 11)
+	require.NoError(t, err)
+	_, err = wc.WriteAt([]byte("world"), 6)
+	require.NoError(t, err)
+	_, err = wc.WriteAt([]byte("hello "), 0)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+
+	data, err := ioutil.ReadFile(path.Join(r.LocalName, filePath))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+}
+
+func TestMetadata(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	f := r.Flocal.(*Fs)
+
+	filePath := "metadata file"
+	t1 := fstest.Time("2001-02-03T04:05:06.499999999Z")
+	r.WriteFile(filePath, "content", t1)
+	obj, err := f.NewObject(ctx, filePath)
+	require.NoError(t, err)
+	o := obj.(*Object)
+
+	metadata, err := o.Metadata()
+	require.NoError(t, err)
+	assert.Equal(t, t1.Format(time.RFC3339Nano), metadata[metadataMtime])
+	assert.NotEqual(t, "", metadata[metadataMode])
+
+	t2 := fstest.Time("2011-12-25T12:59:59.123456789Z")
+	err = o.writeMetadata(fs.Metadata{
+		metadataMode:  "0100600",
+		metadataMtime: t2.Format(time.RFC3339Nano),
+	})
+	require.NoError(t, err)
+
+	fi, err := os.Stat(o.path)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	}
+	assert.True(t, t2.Equal(fi.ModTime()))
+
+	err = o.writeMetadata(fs.Metadata{metadataMode: "potato"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+// System metadata keys read and written by the local backend
+const (
+	metadataMode  = "mode"
+	metadataUID   = "uid"
+	metadataGID   = "gid"
+	metadataMtime = "mtime"
+)
+
+// metadataInfo describes the metadata the local backend supports
+var metadataInfo = &fs.MetadataInfo{
+	System: map[string]fs.MetadataHelp{
+		metadataMode: {
+			Help:    "File type and mode",
+			Type:    "octal, unix style",
+			Example: "0100664",
+		},
+		metadataUID: {
+			Help:    "User ID of owner",
+			Type:    "decimal number",
+			Example: "500",
+		},
+		metadataGID: {
+			Help:    "Group ID of owner",
+			Type:    "decimal number",
+			Example: "500",
+		},
+		metadataMtime: {
+			Help:    "Time of last modification",
+			Type:    "RFC 3339",
+			Example: "2006-01-02T15:04:05.999999999Z07:00",
+		},
+	},
+	Help: `Depending on which OS is in use the local backend may return only some
+of the system metadata. Setting system metadata is supported on all
+OSes but setting user metadata is only supported on linux.
+
+User metadata is stored as extended attributes in the "user."
+namespace with the "user." prefix removed from the key.`,
+}
+
+// Metadata returns metadata for an object
+//
+// It should return nil if there is no Metadata
+func (o *Object) Metadata() (metadata fs.Metadata, err error) {
+	info, err := o.fs.lstat(o.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read metadata")
+	}
+	metadata = make(fs.Metadata, 4)
+	metadata[metadataMtime] = info.ModTime().Format(time.RFC3339Nano)
+	readMetadataFromInfo(metadata, info)
+	err = readXattrs(o.path, metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read extended attributes")
+	}
+	return metadata, nil
+}
+
+// writeMetadata sets the metadata passed in on the object
+func (o *Object) writeMetadata(metadata fs.Metadata) (err error) {
+	uid, gid := -1, -1
+	var mode uint64
+	setMode := false
+	user := fs.Metadata{}
+	for k, v := range metadata {
+		switch k {
+		case metadataMode:
+			mode, err = strconv.ParseUint(v, 8, 32)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse %s %q", k, v)
+			}
+			setMode = true
+		case metadataUID, metadataGID:
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse %s %q", k, v)
+			}
+			if k == metadataUID {
+				uid = id
+			} else {
+				gid = id
+			}
+		case metadataMtime:
+			mtime, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse %s %q", k, v)
+			}
+			err = os.Chtimes(o.path, mtime, mtime)
+			if err != nil {
+				return errors.Wrap(err, "failed to set mtime")
+			}
+		default:
+			user[k] = v
+		}
+	}
+	if uid >= 0 || gid >= 0 {
+		err = setOwner(o.path, uid, gid)
+		if err != nil {
+			return errors.Wrap(err, "failed to set owner")
+		}
+	}
+	if len(user) > 0 {
+		err = writeXattrs(o.path, user)
+		if err != nil {
+			return errors.Wrap(err, "failed to set extended attributes")
+		}
+	}
+	// Set the mode last in case it makes the file read only
+	if setMode {
+		err = os.Chmod(o.path, os.FileMode(mode&0777))
+		if err != nil {
+			return errors.Wrap(err, "failed to set mode")
+		}
+	}
+	return nil
+}
+
+// formatMode returns mode in the octal form used for metadata
+func formatMode(mode uint64) string {
+	return fmt.Sprintf("%#o", mode)
+}
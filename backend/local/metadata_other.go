@@ -0,0 +1,23 @@
+// Metadata functions
+
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package local
+
+import (
+	"os"
+
+	"github.com/artpar/rclone/fs"
+)
+
+// readMetadataFromInfo reads the system metadata out of info into
+// metadata
+func readMetadataFromInfo(metadata fs.Metadata, info os.FileInfo) {
+	metadata[metadataMode] = formatMode(uint64(info.Mode().Perm()))
+}
+
+// setOwner does nothing as file owners aren't supported on this OS
+func setOwner(path string, uid, gid int) error {
+	fs.Debugf(path, "Ignoring uid and gid metadata as not supported on this OS")
+	return nil
+}
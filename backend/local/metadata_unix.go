@@ -0,0 +1,32 @@
+// Metadata functions
+
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package local
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/artpar/rclone/fs"
+)
+
+// readMetadataFromInfo reads the system metadata out of info into
+// metadata
+func readMetadataFromInfo(metadata fs.Metadata, info os.FileInfo) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		metadata[metadataMode] = formatMode(uint64(info.Mode().Perm()))
+		return
+	}
+	metadata[metadataMode] = formatMode(uint64(stat.Mode))
+	metadata[metadataUID] = strconv.FormatUint(uint64(stat.Uid), 10)
+	metadata[metadataGID] = strconv.FormatUint(uint64(stat.Gid), 10)
+}
+
+// setOwner sets the owner of path - uid or gid may be -1 to leave
+// them unchanged
+func setOwner(path string, uid, gid int) error {
+	return os.Lchown(path, uid, gid)
+}
@@ -0,0 +1,71 @@
+// Extended attribute functions
+
+// +build linux
+
+package local
+
+import (
+	"strings"
+
+	"github.com/artpar/rclone/fs"
+	"golang.org/x/sys/unix"
+)
+
+// prefix for the extended attributes rclone reads and writes
+const xattrPrefix = "user."
+
+// isNotSupported returns true if err means extended attributes
+// aren't supported by the file system
+func isNotSupported(err error) bool {
+	return err == unix.ENOTSUP || err == unix.EOPNOTSUPP
+}
+
+// readXattrs reads the user extended attributes of path into metadata
+func readXattrs(path string, metadata fs.Metadata) error {
+	size, err := unix.Listxattr(path, nil)
+	if err != nil {
+		if isNotSupported(err) {
+			return nil
+		}
+		return err
+	}
+	if size == 0 {
+		return nil
+	}
+	buf := make([]byte, size)
+	size, err = unix.Listxattr(path, buf)
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Split(string(buf[:size]), "\x00") {
+		if !strings.HasPrefix(name, xattrPrefix) {
+			continue
+		}
+		valueSize, err := unix.Getxattr(path, name, nil)
+		if err != nil {
+			return err
+		}
+		value := make([]byte, valueSize)
+		valueSize, err = unix.Getxattr(path, name, value)
+		if err != nil {
+			return err
+		}
+		metadata[strings.ToLower(name[len(xattrPrefix):])] = string(value[:valueSize])
+	}
+	return nil
+}
+
+// writeXattrs writes metadata as user extended attributes on path
+func writeXattrs(path string, metadata fs.Metadata) error {
+	for k, v := range metadata {
+		err := unix.Setxattr(path, xattrPrefix+k, []byte(v), 0)
+		if err != nil {
+			if isNotSupported(err) {
+				fs.Debugf(path, "Ignoring user metadata as extended attributes not supported")
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+// Extended attribute functions
+
+// +build !linux
+
+package local
+
+import (
+	"github.com/artpar/rclone/fs"
+)
+
+// readXattrs does nothing as extended attributes aren't supported on
+// this OS
+func readXattrs(path string, metadata fs.Metadata) error {
+	return nil
+}
+
+// writeXattrs does nothing as extended attributes aren't supported on
+// this OS
+func writeXattrs(path string, metadata fs.Metadata) error {
+	fs.Debugf(path, "Ignoring user metadata as not supported on this OS")
+	return nil
+}
@@ -14,13 +14,16 @@ What happens if you CTRL-C a multipart upload
 */
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -41,8 +44,10 @@ import (
 	"github.com/artpar/rclone/fs/config/configstruct"
 	"github.com/artpar/rclone/fs/fshttp"
 	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/fs/operations"
 	"github.com/artpar/rclone/fs/walk"
 	"github.com/artpar/rclone/lib/rest"
+	"github.com/artpar/rclone/lib/version"
 	"github.com/ncw/swift"
 	"github.com/pkg/errors"
 )
@@ -53,6 +58,12 @@ func init() {
 		Name:        "s3",
 		Description: "Amazon S3 Compliant Storage Providers (AWS, Ceph, Dreamhost, IBM COS, Minio)",
 		NewFs:       NewFs,
+		CommandHelp: commandHelp,
+		MetadataInfo: &fs.MetadataInfo{
+			System: systemMetadataInfo,
+			Help: `User metadata is stored as x-amz-meta- keys. S3 metadata keys are case
+insensitive and are always returned in lower case.`,
+		},
 		Options: []fs.Option{{
 			Name: fs.ConfigProvider,
 			Help: "Choose your S3 provider.",
@@ -498,6 +509,56 @@ func init() {
 			}, {
 				Value: "AES256",
 				Help:  "AES256",
+			}, {
+				Value: "aws:kms",
+				Help:  "aws:kms",
+			}},
+		}, {
+			Name:     "sse_kms_key_id",
+			Help:     "If using KMS ID you must provide the ARN of Key.",
+			Provider: "AWS",
+			Examples: []fs.OptionExample{{
+				Value: "",
+				Help:  "None",
+			}, {
+				Value: "arn:aws:kms:us-east-1:*",
+				Help:  "arn:aws:kms:*",
+			}},
+		}, {
+			Name:     "sse_customer_algorithm",
+			Help:     "If using SSE-C, the server-side encryption algorithm used when storing this object in S3.",
+			Provider: "AWS",
+			Advanced: true,
+			Examples: []fs.OptionExample{{
+				Value: "",
+				Help:  "None",
+			}, {
+				Value: "AES256",
+				Help:  "AES256",
+			}},
+		}, {
+			Name: "sse_customer_key",
+			Help: `If using SSE-C you must provide the secret encryption key used to encrypt/decrypt your data.
+
+This is the raw key, 32 bytes long for AES256, which is base64
+encoded when it is sent to S3.`,
+			Provider: "AWS",
+			Advanced: true,
+			Examples: []fs.OptionExample{{
+				Value: "",
+				Help:  "None",
+			}},
+		}, {
+			Name: "sse_customer_key_md5",
+			Help: `If using SSE-C you may provide the secret encryption key MD5 checksum (optional).
+
+This should be the base64 encoded MD5 of the raw key. If it is blank
+it will be calculated from sse_customer_key.`,
+			Provider: "AWS",
+			Advanced: true,
+			Examples: []fs.OptionExample{{
+				Value: "",
+				Help:  "None",
 			}},
 		}, {
 			Name:     "storage_class",
@@ -521,9 +582,19 @@ func init() {
 			}},
 		}, {
 			Name:     "chunk_size",
-			Help:     "Chunk size to use for uploading",
+			Help:     "Chunk size to use for uploading and multipart copies",
 			Default:  fs.SizeSuffix(s3manager.MinUploadPartSize),
 			Advanced: true,
+		}, {
+			Name: "copy_cutoff",
+			Help: `Cutoff for switching to multipart copy
+
+Any files larger than this that need to be server side copied will be
+copied in chunks of chunk_size using upload_concurrency parts at once.
+
+The minimum is 0 and the maximum is 5GB.`,
+			Default:  fs.SizeSuffix(maxSizeForCopy),
+			Advanced: true,
 		}, {
 			Name:     "disable_checksum",
 			Help:     "Don't store MD5 checksum with object metadata",
@@ -536,7 +607,7 @@ func init() {
 			Advanced: true,
 		}, {
 			Name:     "upload_concurrency",
-			Help:     "Concurrency for multipart uploads.",
+			Help:     "Concurrency for multipart uploads and copies.",
 			Default:  2,
 			Advanced: true,
 		}, {
@@ -544,10 +615,74 @@ func init() {
 			Help:     "If true use path style access if false use virtual hosted style.\nSome providers (eg Aliyun OSS or Netease COS) require this.",
 			Default:  true,
 			Advanced: true,
+		}, {
+			Name: "link_expiry",
+			Help: `Expiry time of the links made by "rclone link"
+
+The links are presigned URLs for GET requests on the object. AWS S3
+allows a maximum of 7 days.`,
+			Default:  fs.Duration(7 * 24 * time.Hour),
+			Advanced: true,
+		}, {
+			Name:     "versions",
+			Help:     "Include old versions in directory listings.",
+			Default:  false,
+			Advanced: true,
+		}, {
+			Name: "version_at",
+			Help: `Show file versions as they were at the specified time.
+
+The parameter should be a date, "2006-01-02", datetime "2006-01-02
+15:04:05" or a duration for that long ago, eg "100d" or "1h".
+
+Note that when using this no file write operations are permitted,
+so you can't upload files or delete them.`,
+			Default:  "",
+			Advanced: true,
 		}},
 	})
 }
 
+// system metadata keys which this backend translates
+var systemMetadataInfo = map[string]fs.MetadataHelp{
+	"cache-control": {
+		Help:    "Cache-Control header",
+		Type:    "string",
+		Example: "no-cache",
+	},
+	"content-disposition": {
+		Help:    "Content-Disposition header",
+		Type:    "string",
+		Example: "inline",
+	},
+	"content-encoding": {
+		Help:    "Content-Encoding header",
+		Type:    "string",
+		Example: "gzip",
+	},
+	"content-language": {
+		Help:    "Content-Language header",
+		Type:    "string",
+		Example: "en-US",
+	},
+	"content-type": {
+		Help:    "Content-Type header",
+		Type:    "string",
+		Example: "text/plain",
+	},
+	"mtime": {
+		Help:    "Time of last modification, read from rclone metadata",
+		Type:    "RFC 3339",
+		Example: "2006-01-02T15:04:05.999999999Z07:00",
+	},
+	"tier": {
+		Help:     "Tier of the object",
+		Type:     "string",
+		Example:  "GLACIER",
+		ReadOnly: true,
+	},
+}
+
 // Constants
 const (
 	metaMtime      = "Mtime"                       // the meta key to store mtime in - eg X-Amz-Meta-Mtime
@@ -569,12 +704,20 @@ type Options struct {
 	LocationConstraint   string        `config:"location_constraint"`
 	ACL                  string        `config:"acl"`
 	ServerSideEncryption string        `config:"server_side_encryption"`
+	SSEKMSKeyID          string        `config:"sse_kms_key_id"`
+	SSECustomerAlgorithm string        `config:"sse_customer_algorithm"`
+	SSECustomerKey       string        `config:"sse_customer_key"`
+	SSECustomerKeyMD5    string        `config:"sse_customer_key_md5"`
 	StorageClass         string        `config:"storage_class"`
 	ChunkSize            fs.SizeSuffix `config:"chunk_size"`
+	CopyCutoff           fs.SizeSuffix `config:"copy_cutoff"`
 	DisableChecksum      bool          `config:"disable_checksum"`
 	SessionToken         string        `config:"session_token"`
 	UploadConcurrency    int           `config:"upload_concurrency"`
 	ForcePathStyle       bool          `config:"force_path_style"`
+	LinkExpiry           fs.Duration   `config:"link_expiry"`
+	Versions             bool          `config:"versions"`
+	VersionAt            string        `config:"version_at"`
 }
 
 // Fs represents a remote s3 server
@@ -589,6 +732,7 @@ type Fs struct {
 	bucketOKMu    sync.Mutex       // mutex to protect bucket OK
 	bucketOK      bool             // true if we have created the bucket
 	bucketDeleted bool             // true if we have deleted the bucket
+	versionAt     time.Time        // show the versions current at this time if set
 }
 
 // Object describes a s3 object
@@ -604,6 +748,19 @@ type Object struct {
 	lastModified time.Time          // Last modified
 	meta         map[string]*string // The object metadata if known - may be nil
 	mimeType     string             // MimeType of object - may be ""
+
+	// Metadata as pointers to strings as they often won't be present
+	storageClass       *string // e.g. GLACIER
+	cacheControl       *string // Cache-Control: header
+	contentDisposition *string // Content-Disposition: header
+	contentEncoding    *string // Content-Encoding: header
+	contentLanguage    *string // Content-Language: header
+
+	// Encryption settings of the object, preserved by server side copies
+	serverSideEncryption *string // e.g. AES256 or aws:kms
+	sseKMSKeyID          *string // the KMS key used if aws:kms
+
+	versionID *string // version of the object if it isn't the latest
 }
 
 // ------------------------------------------------------------
@@ -735,6 +892,7 @@ func s3Connection(opt *Options) (*s3.S3, *session.Session, error) {
 
 // NewFs constructs an Fs from the path, bucket:path
 func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
+	ctx := context.Background()
 	// Parse config into Options struct
 	opt := new(Options)
 	err := configstruct.Set(m, opt)
@@ -744,6 +902,33 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 	if opt.ChunkSize < fs.SizeSuffix(s3manager.MinUploadPartSize) {
 		return nil, errors.Errorf("s3 chunk size (%v) must be >= %v", opt.ChunkSize, fs.SizeSuffix(s3manager.MinUploadPartSize))
 	}
+	if opt.CopyCutoff > maxSizeForCopy {
+		return nil, errors.Errorf("s3 copy cutoff (%v) must be <= %v", opt.CopyCutoff, fs.SizeSuffix(maxSizeForCopy))
+	}
+	if opt.SSECustomerAlgorithm != "" && opt.SSECustomerKey == "" {
+		return nil, errors.New("s3: --s3-sse-customer-key must be set with --s3-sse-customer-algorithm")
+	}
+	if opt.SSECustomerKey != "" {
+		if opt.SSECustomerAlgorithm == "" {
+			return nil, errors.New("s3: --s3-sse-customer-algorithm must be set with --s3-sse-customer-key")
+		}
+		if opt.ServerSideEncryption != "" {
+			return nil, errors.New("s3: can't use --s3-server-side-encryption and --s3-sse-customer-key at the same time")
+		}
+	}
+	var versionAt time.Time
+	if opt.VersionAt != "" {
+		if opt.Versions {
+			return nil, errors.New("s3: can't use --s3-version-at and --s3-versions at the same time")
+		}
+		versionAt, err = parseVersionAt(opt.VersionAt, time.Now())
+		if err != nil {
+			return nil, errors.Wrap(err, "s3: bad --s3-version-at")
+		}
+	}
+	if opt.LinkExpiry <= 0 {
+		return nil, errors.Errorf("s3 link expiry (%v) must be > 0", opt.LinkExpiry)
+	}
 	bucket, directory, err := s3ParsePath(root)
 	if err != nil {
 		return nil, err
@@ -753,17 +938,21 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 		return nil, err
 	}
 	f := &Fs{
-		name:   name,
-		root:   directory,
-		opt:    *opt,
-		c:      c,
-		bucket: bucket,
-		ses:    ses,
+		name:      name,
+		root:      directory,
+		opt:       *opt,
+		c:         c,
+		bucket:    bucket,
+		ses:       ses,
+		versionAt: versionAt,
 	}
 	f.features = (&fs.Features{
-		ReadMimeType:  true,
-		WriteMimeType: true,
-		BucketBased:   true,
+		ReadMimeType:            true,
+		WriteMimeType:           true,
+		BucketBased:             true,
+		ServerSideAcrossConfigs: true,
+		ReadMetadata:            true,
+		WriteMetadata:           true,
 	}).Fill(f)
 	if f.root != "" {
 		f.root += "/"
@@ -772,7 +961,8 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 			Bucket: &f.bucket,
 			Key:    &directory,
 		}
-		_, err = f.c.HeadObject(&req)
+		req.SSECustomerAlgorithm, req.SSECustomerKey, req.SSECustomerKeyMD5 = f.sseCustomer()
+		_, err = f.c.HeadObjectWithContext(ctx, &req)
 		if err == nil {
 			f.root = path.Dir(directory)
 			if f.root == "." {
@@ -791,10 +981,11 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 // Return an Object from a path
 //
 //If it can't be found it returns the error ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(remote string, info *s3.Object) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *s3.Object, versionID *string) (fs.Object, error) {
 	o := &Object{
-		fs:     f,
-		remote: remote,
+		fs:        f,
+		remote:    remote,
+		versionID: versionID,
 	}
 	if info != nil {
 		// Set info but not meta
@@ -807,7 +998,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *s3.Object) (fs.Object, error
 		o.etag = aws.StringValue(info.ETag)
 		o.bytes = aws.Int64Value(info.Size)
 	} else {
-		err := o.readMetaData() // reads info and meta, returning an error
+		err := o.readMetaData(ctx) // reads info and meta, returning an error
 		if err != nil {
 			return nil, err
 		}
@@ -817,19 +1008,34 @@ func (f *Fs) newObjectWithInfo(remote string, info *s3.Object) (fs.Object, error
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(remote, nil)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	if f.versioned() {
+		return f.newVersionedObject(ctx, remote)
+	}
+	return f.newObjectWithInfo(ctx, remote, nil, nil)
 }
 
 // listFn is called from list to handle an object.
-type listFn func(remote string, object *s3.Object, isDirectory bool) error
+//
+// versionID is set if the object is a specific version of the key
+type listFn func(remote string, object *s3.Object, versionID *string, isDirectory bool) error
+
+// listPage is one page of a listing, normal or versioned
+type listPage struct {
+	commonPrefixes []*s3.CommonPrefix
+	contents       []*s3.Object
+	versionIDs     []*string // version IDs of contents - nil if not versioned
+}
 
 // list the objects into the function supplied
 //
 // dir is the starting directory, "" for root
 //
 // Set recurse to read sub directories
-func (f *Fs) list(dir string, recurse bool, fn listFn) error {
+//
+// If --s3-versions or --s3-version-at are in use then the object
+// versions are listed instead.
+func (f *Fs) list(ctx context.Context, dir string, recurse bool, fn listFn) error {
 	root := f.root
 	if dir != "" {
 		root += dir + "/"
@@ -839,17 +1045,61 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 	if !recurse {
 		delimiter = "/"
 	}
-	var marker *string
+	var (
+		marker          *string
+		keyMarker       *string
+		versionIDMarker *string
+		versions        = newVersionFilter(f.opt.Versions, f.versionAt)
+	)
 	for {
-		// FIXME need to implement ALL loop
-		req := s3.ListObjectsInput{
-			Bucket:    &f.bucket,
-			Delimiter: &delimiter,
-			Prefix:    &root,
-			MaxKeys:   &maxKeys,
-			Marker:    marker,
+		var page listPage
+		var isTruncated bool
+		var err error
+		if f.versioned() {
+			req := s3.ListObjectVersionsInput{
+				Bucket:          &f.bucket,
+				Delimiter:       &delimiter,
+				Prefix:          &root,
+				MaxKeys:         &maxKeys,
+				KeyMarker:       keyMarker,
+				VersionIdMarker: versionIDMarker,
+			}
+			var resp *s3.ListObjectVersionsOutput
+			resp, err = f.c.ListObjectVersionsWithContext(ctx, &req)
+			if err == nil {
+				page.commonPrefixes = resp.CommonPrefixes
+				page.contents, page.versionIDs = versions.filter(resp.Versions, resp.DeleteMarkers)
+				isTruncated = aws.BoolValue(resp.IsTruncated)
+				keyMarker, versionIDMarker = resp.NextKeyMarker, resp.NextVersionIdMarker
+			}
+		} else {
+			// FIXME need to implement ALL loop
+			req := s3.ListObjectsInput{
+				Bucket:    &f.bucket,
+				Delimiter: &delimiter,
+				Prefix:    &root,
+				MaxKeys:   &maxKeys,
+				Marker:    marker,
+			}
+			var resp *s3.ListObjectsOutput
+			resp, err = f.c.ListObjectsWithContext(ctx, &req)
+			if err == nil {
+				page.commonPrefixes = resp.CommonPrefixes
+				page.contents = resp.Contents
+				isTruncated = aws.BoolValue(resp.IsTruncated)
+				if isTruncated {
+					// Use NextMarker if set, otherwise use last Key
+					if resp.NextMarker == nil || *resp.NextMarker == "" {
+						if len(resp.Contents) == 0 {
+							return errors.New("s3 protocol error: received listing with IsTruncated set, no NextMarker and no Contents")
+						}
+						marker = resp.Contents[len(resp.Contents)-1].Key
+					} else {
+						marker = resp.NextMarker
+					}
+				}
+			}
 		}
-		resp, err := f.c.ListObjects(&req)
 		if err != nil {
 			if awsErr, ok := err.(awserr.RequestFailure); ok {
 				if awsErr.StatusCode() == http.StatusNotFound {
@@ -860,7 +1110,7 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 		}
 		rootLength := len(f.root)
 		if !recurse {
-			for _, commonPrefix := range resp.CommonPrefixes {
+			for _, commonPrefix := range page.commonPrefixes {
 				if commonPrefix.Prefix == nil {
 					fs.Logf(f, "Nil common prefix received")
 					continue
@@ -874,13 +1124,13 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 				if strings.HasSuffix(remote, "/") {
 					remote = remote[:len(remote)-1]
 				}
-				err = fn(remote, &s3.Object{Key: &remote}, true)
+				err = fn(remote, &s3.Object{Key: &remote}, nil, true)
 				if err != nil {
 					return err
 				}
 			}
 		}
-		for _, object := range resp.Contents {
+		for i, object := range page.contents {
 			key := aws.StringValue(object.Key)
 			if !strings.HasPrefix(key, f.root) {
 				fs.Logf(f, "Odd name received %q", key)
@@ -888,40 +1138,39 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 			}
 			remote := key[rootLength:]
 			// is this a directory marker?
-			if (strings.HasSuffix(remote, "/") || remote == "") && *object.Size == 0 {
+			if (strings.HasSuffix(remote, "/") || remote == "") && aws.Int64Value(object.Size) == 0 {
 				if recurse && remote != "" {
 					// add a directory in if --fast-list since will have no prefixes
 					remote = remote[:len(remote)-1]
-					err = fn(remote, &s3.Object{Key: &remote}, true)
+					err = fn(remote, &s3.Object{Key: &remote}, nil, true)
 					if err != nil {
 						return err
 					}
 				}
 				continue // skip directory marker
 			}
-			err = fn(remote, object, false)
+			var versionID *string
+			if page.versionIDs != nil {
+				versionID = page.versionIDs[i]
+				if f.opt.Versions && versionID != nil {
+					// old versions are shown with the version in the name
+					remote = version.Add(remote, aws.TimeValue(object.LastModified))
+				}
+			}
+			err = fn(remote, object, versionID, false)
 			if err != nil {
 				return err
 			}
 		}
-		if !aws.BoolValue(resp.IsTruncated) {
+		if !isTruncated {
 			break
 		}
-		// Use NextMarker if set, otherwise use last Key
-		if resp.NextMarker == nil || *resp.NextMarker == "" {
-			if len(resp.Contents) == 0 {
-				return errors.New("s3 protocol error: received listing with IsTruncated set, no NextMarker and no Contents")
-			}
-			marker = resp.Contents[len(resp.Contents)-1].Key
-		} else {
-			marker = resp.NextMarker
-		}
 	}
 	return nil
 }
 
 // Convert a list item into a DirEntry
-func (f *Fs) itemToDirEntry(remote string, object *s3.Object, isDirectory bool) (fs.DirEntry, error) {
+func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *s3.Object, versionID *string, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
 		size := int64(0)
 		if object.Size != nil {
@@ -930,7 +1179,7 @@ func (f *Fs) itemToDirEntry(remote string, object *s3.Object, isDirectory bool)
 		d := fs.NewDir(remote, time.Time{}).SetSize(size)
 		return d, nil
 	}
-	o, err := f.newObjectWithInfo(remote, object)
+	o, err := f.newObjectWithInfo(ctx, remote, object, versionID)
 	if err != nil {
 		return nil, err
 	}
@@ -948,10 +1197,10 @@ func (f *Fs) markBucketOK() {
 }
 
 // listDir lists files and directories to out
-func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listDir(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	// List the objects and directories
-	err = f.list(dir, false, func(remote string, object *s3.Object, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, false, func(remote string, object *s3.Object, versionID *string, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, versionID, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -969,12 +1218,12 @@ func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
 }
 
 // listBuckets lists the buckets to out
-func (f *Fs) listBuckets(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listBuckets(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if dir != "" {
 		return nil, fs.ErrorListBucketRequired
 	}
 	req := s3.ListBucketsInput{}
-	resp, err := f.c.ListBuckets(&req)
+	resp, err := f.c.ListBucketsWithContext(ctx, &req)
 	if err != nil {
 		return nil, err
 	}
@@ -994,11 +1243,11 @@ func (f *Fs) listBuckets(dir string) (entries fs.DirEntries, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if f.bucket == "" {
-		return f.listBuckets(dir)
+		return f.listBuckets(ctx, dir)
 	}
-	return f.listDir(dir)
+	return f.listDir(ctx, dir)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -1017,13 +1266,13 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	if f.bucket == "" {
 		return fs.ErrorListBucketRequired
 	}
 	list := walk.NewListRHelper(callback)
-	err = f.list(dir, true, func(remote string, object *s3.Object, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, true, func(remote string, object *s3.Object, versionID *string, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, versionID, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -1038,28 +1287,28 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 }
 
 // Put the Object into the bucket
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// Temporary Object under construction
 	fs := &Object{
 		fs:     f,
 		remote: src.Remote(),
 	}
-	return fs, fs.Update(in, src, options...)
+	return fs, fs.Update(ctx, in, src, options...)
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // Check if the bucket exists
 //
 // NB this can return incorrect results if called immediately after bucket deletion
-func (f *Fs) dirExists() (bool, error) {
+func (f *Fs) dirExists(ctx context.Context) (bool, error) {
 	req := s3.HeadBucketInput{
 		Bucket: &f.bucket,
 	}
-	_, err := f.c.HeadBucket(&req)
+	_, err := f.c.HeadBucketWithContext(ctx, &req)
 	if err == nil {
 		return true, nil
 	}
@@ -1072,14 +1321,17 @@ func (f *Fs) dirExists() (bool, error) {
 }
 
 // Mkdir creates the bucket if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	if f.versioned() {
+		return errNotWithVersions
+	}
 	f.bucketOKMu.Lock()
 	defer f.bucketOKMu.Unlock()
 	if f.bucketOK {
 		return nil
 	}
 	if !f.bucketDeleted {
-		exists, err := f.dirExists()
+		exists, err := f.dirExists(ctx)
 		if err == nil {
 			f.bucketOK = exists
 		}
@@ -1096,7 +1348,7 @@ func (f *Fs) Mkdir(dir string) error {
 			LocationConstraint: &f.opt.LocationConstraint,
 		}
 	}
-	_, err := f.c.CreateBucket(&req)
+	_, err := f.c.CreateBucketWithContext(ctx, &req)
 	if err, ok := err.(awserr.Error); ok {
 		if err.Code() == "BucketAlreadyOwnedByYou" {
 			err = nil
@@ -1112,7 +1364,10 @@ func (f *Fs) Mkdir(dir string) error {
 // Rmdir deletes the bucket if the fs is at the root
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	if f.versioned() {
+		return errNotWithVersions
+	}
 	f.bucketOKMu.Lock()
 	defer f.bucketOKMu.Unlock()
 	if f.root != "" || dir != "" {
@@ -1121,7 +1376,7 @@ func (f *Fs) Rmdir(dir string) error {
 	req := s3.DeleteBucketInput{
 		Bucket: &f.bucket,
 	}
-	_, err := f.c.DeleteBucket(&req)
+	_, err := f.c.DeleteBucketWithContext(ctx, &req)
 	if err == nil {
 		f.bucketOK = false
 		f.bucketDeleted = true
@@ -1134,6 +1389,26 @@ func (f *Fs) Precision() time.Duration {
 	return time.Nanosecond
 }
 
+// sseCustomer returns the SSE-C algorithm, key and key MD5 to send
+// with requests for objects in this Fs, or nils if SSE-C isn't in use
+func (f *Fs) sseCustomer() (algorithm, key, keyMD5 *string) {
+	if f.opt.SSECustomerKey == "" {
+		return nil, nil, nil
+	}
+	algorithm = &f.opt.SSECustomerAlgorithm
+	key = &f.opt.SSECustomerKey
+	if f.opt.SSECustomerKeyMD5 != "" {
+		keyMD5 = &f.opt.SSECustomerKeyMD5
+	}
+	return algorithm, key, keyMD5
+}
+
+// etagIsNotMD5 returns true if the ETags of objects written by this
+// Fs won't be their MD5 because they are encrypted with KMS or SSE-C
+func (f *Fs) etagIsNotMD5() bool {
+	return f.opt.ServerSideEncryption == "aws:kms" || f.opt.SSECustomerKey != ""
+}
+
 // pathEscape escapes s as for a URL path.  It uses rest.URLPathEscape
 // but also escapes '+' for S3 and Digital Ocean spaces compatibility
 func pathEscape(s string) string {
@@ -1149,8 +1424,11 @@ func pathEscape(s string) string {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
-	err := f.Mkdir("")
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	if f.versioned() {
+		return nil, errNotWithVersions
+	}
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		return nil, err
 	}
@@ -1159,20 +1437,444 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 		fs.Debugf(src, "Can't copy - not same remote type")
 		return nil, fs.ErrorCantCopy
 	}
-	srcFs := srcObj.fs
 	key := f.root + remote
-	source := pathEscape(srcFs.bucket + "/" + srcFs.root + srcObj.remote)
 	req := s3.CopyObjectInput{
-		Bucket:            &f.bucket,
-		Key:               &key,
-		CopySource:        &source,
 		MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
 	}
-	_, err = f.c.CopyObject(&req)
+	err = f.copy(ctx, &req, key, srcObj)
 	if err != nil {
 		return nil, err
 	}
-	return f.NewObject(remote)
+	return f.NewObject(ctx, remote)
+}
+
+// copy does a server side copy of src to dstKey in this bucket
+//
+// It fills in the bucket, key, source, ACL, storage class and
+// encryption on req. The storage class and encryption of src are
+// preserved unless they are set in the config.
+//
+// Objects of copy_cutoff or larger are copied with copyMultipart.
+func (f *Fs) copy(ctx context.Context, req *s3.CopyObjectInput, dstKey string, src *Object) error {
+	err := src.readMetaData(ctx)
+	if err != nil {
+		return err
+	}
+	source := pathEscape(src.fs.bucket + "/" + src.bucketKey())
+	if src.versionID != nil {
+		source += "?versionId=" + url.QueryEscape(*src.versionID)
+	}
+	req.Bucket = &f.bucket
+	req.Key = &dstKey
+	req.CopySource = &source
+	req.ACL = &f.opt.ACL
+	if f.opt.ServerSideEncryption != "" {
+		req.ServerSideEncryption = &f.opt.ServerSideEncryption
+		if f.opt.SSEKMSKeyID != "" {
+			req.SSEKMSKeyId = &f.opt.SSEKMSKeyID
+		}
+	} else if f.opt.SSECustomerKey == "" && aws.StringValue(src.serverSideEncryption) != "" {
+		req.ServerSideEncryption = src.serverSideEncryption
+		req.SSEKMSKeyId = src.sseKMSKeyID
+	}
+	req.SSECustomerAlgorithm, req.SSECustomerKey, req.SSECustomerKeyMD5 = f.sseCustomer()
+	req.CopySourceSSECustomerAlgorithm, req.CopySourceSSECustomerKey, req.CopySourceSSECustomerKeyMD5 = src.fs.sseCustomer()
+	if f.opt.StorageClass != "" {
+		req.StorageClass = &f.opt.StorageClass
+	} else if aws.StringValue(src.storageClass) != "" {
+		req.StorageClass = src.storageClass
+	}
+	if src.bytes > 0 && src.bytes >= int64(f.opt.CopyCutoff) {
+		return f.copyMultipart(ctx, req, src)
+	}
+	_, err = f.c.CopyObjectWithContext(ctx, req)
+	return err
+}
+
+// copyPartSize returns the part size to use for a multipart copy of
+// size bytes
+//
+// This is chunk_size unless that would need too many parts
+func (f *Fs) copyPartSize(size int64) int64 {
+	partSize := int64(f.opt.ChunkSize)
+	if size/partSize >= s3manager.MaxUploadParts {
+		// Calculate partition size rounded up to the nearest MB
+		partSize = (((size / s3manager.MaxUploadParts) >> 20) + 1) << 20
+	}
+	return partSize
+}
+
+// copyMultipart does a server side copy of src using a multipart
+// upload with each part copied with UploadPartCopy
+//
+// req should have been filled in by copy. Parts are copied at most
+// upload_concurrency at once.
+func (f *Fs) copyMultipart(ctx context.Context, req *s3.CopyObjectInput, src *Object) (err error) {
+	// Multipart uploads don't copy the metadata from the source
+	// so set it here if we are asked to copy it
+	if aws.StringValue(req.MetadataDirective) == s3.MetadataDirectiveCopy {
+		req.Metadata = src.meta
+		if src.mimeType != "" {
+			req.ContentType = aws.String(src.mimeType)
+		}
+		req.CacheControl = src.cacheControl
+		req.ContentDisposition = src.contentDisposition
+		req.ContentEncoding = src.contentEncoding
+		req.ContentLanguage = src.contentLanguage
+	}
+	cout, err := f.c.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:               req.Bucket,
+		Key:                  req.Key,
+		ACL:                  req.ACL,
+		Metadata:             req.Metadata,
+		ContentType:          req.ContentType,
+		CacheControl:         req.CacheControl,
+		ContentDisposition:   req.ContentDisposition,
+		ContentEncoding:      req.ContentEncoding,
+		ContentLanguage:      req.ContentLanguage,
+		ServerSideEncryption: req.ServerSideEncryption,
+		SSEKMSKeyId:          req.SSEKMSKeyId,
+		SSECustomerAlgorithm: req.SSECustomerAlgorithm,
+		SSECustomerKey:       req.SSECustomerKey,
+		SSECustomerKeyMD5:    req.SSECustomerKeyMD5,
+		StorageClass:         req.StorageClass,
+	})
+	if err != nil {
+		return errors.Wrap(err, "multipart copy: failed to create upload")
+	}
+	uid := cout.UploadId
+	defer func() {
+		if err != nil {
+			// We can try to abort the upload, but ignore the error
+			fs.Debugf(src, "Cancelling multipart copy: %v", err)
+			_, _ = f.c.AbortMultipartUploadWithContext(context.Background(), &s3.AbortMultipartUploadInput{
+				Bucket:   req.Bucket,
+				Key:      req.Key,
+				UploadId: uid,
+			})
+		}
+	}()
+
+	size := src.bytes
+	partSize := f.copyPartSize(size)
+	numParts := (size-1)/partSize + 1
+	concurrency := f.opt.UploadConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	fs.Debugf(src, "Starting multipart copy with %d parts of size %v", numParts, fs.SizeSuffix(partSize))
+
+	partCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		parts    = make([]*s3.CompletedPart, numParts)
+		tokens   = make(chan struct{}, concurrency)
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for partNum := int64(1); partNum <= numParts; partNum++ {
+		select {
+		case tokens <- struct{}{}:
+		case <-partCtx.Done():
+		}
+		if partCtx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(partNum int64) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			part, err := f.copyPart(partCtx, req, uid, partNum, partSize, size)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+				return
+			}
+			parts[partNum-1] = part
+		}(partNum)
+	}
+	wg.Wait()
+	err = firstErr
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = f.c.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket: req.Bucket,
+		Key:    req.Key,
+		MultipartUpload: &s3.CompletedMultipartUpload{
+			Parts: parts,
+		},
+		UploadId: uid,
+	})
+	if err != nil {
+		return errors.Wrap(err, "multipart copy: failed to complete upload")
+	}
+	return nil
+}
+
+// copyPart copies part number partNum of the source in req, which is
+// size bytes long, into the multipart upload uid
+func (f *Fs) copyPart(ctx context.Context, req *s3.CopyObjectInput, uid *string, partNum, partSize, size int64) (*s3.CompletedPart, error) {
+	start := (partNum - 1) * partSize
+	end := start + partSize
+	if end > size {
+		end = size
+	}
+	uout, err := f.c.UploadPartCopyWithContext(ctx, &s3.UploadPartCopyInput{
+		Bucket:                         req.Bucket,
+		Key:                            req.Key,
+		CopySource:                     req.CopySource,
+		CopySourceRange:                aws.String(fmt.Sprintf("bytes=%d-%d", start, end-1)),
+		CopySourceSSECustomerAlgorithm: req.CopySourceSSECustomerAlgorithm,
+		CopySourceSSECustomerKey:       req.CopySourceSSECustomerKey,
+		CopySourceSSECustomerKeyMD5:    req.CopySourceSSECustomerKeyMD5,
+		SSECustomerAlgorithm:           req.SSECustomerAlgorithm,
+		SSECustomerKey:                 req.SSECustomerKey,
+		SSECustomerKeyMD5:              req.SSECustomerKeyMD5,
+		PartNumber:                     &partNum,
+		UploadId:                       uid,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "multipart copy: failed to copy part %d", partNum)
+	}
+	return &s3.CompletedPart{
+		ETag:       uout.CopyPartResult.ETag,
+		PartNumber: aws.Int64(partNum),
+	}, nil
+}
+
+var commandHelp = []fs.CommandHelp{{
+	Name:  "restore",
+	Short: "Restore objects from GLACIER to normal storage",
+	Long: `This command can be used to restore one or more objects from GLACIER
+to normal storage.
+
+Usage Examples:
+
+    rclone backend restore s3:bucket/path/to/object [-o priority=PRIORITY] [-o lifetime=DAYS]
+    rclone backend restore s3:bucket/path/to/directory [-o priority=PRIORITY] [-o lifetime=DAYS]
+    rclone backend restore s3:bucket -o priority=Bulk -o lifetime=1 path/to/object1 path/to/object2
+
+With no arguments all the objects under the remote are restored,
+otherwise only the objects named in the arguments, relative to the
+remote, are restored.
+
+This command returns a list of the objects it attempted to restore
+with their status
+
+    [
+        {
+            "Status": "OK",
+            "Remote": "test.txt"
+        },
+        {
+            "Status": "OK",
+            "Remote": "test/file4.txt"
+        }
+    ]
+`,
+	Opts: map[string]string{
+		"priority":    "Priority of restore: Standard|Expedited|Bulk",
+		"lifetime":    "Lifetime of the active copy in days",
+		"description": "The optional description for the job.",
+	},
+}, {
+	Name:  "versions",
+	Short: "List the versions of objects",
+	Long: `This command lists all the versions of the objects under the remote,
+including delete markers, newest first. An optional argument restricts
+the listing to keys starting with that path.
+
+Usage Examples:
+
+    rclone backend versions s3:bucket/path/to/dir
+    rclone backend versions s3:bucket path/to/file.txt
+
+This returns a JSON list of versions like this
+
+    [
+        {
+            "Remote": "file.txt",
+            "VersionID": "3HL4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY+MTRCxf3vjVBH40Nr8X8gdRQBpUMLUo",
+            "LastModified": "2019-08-01T12:00:00Z",
+            "Size": 6,
+            "IsLatest": true
+        }
+    ]
+
+The VersionID can be passed to the version-restore and
+version-delete commands.
+`,
+}, {
+	Name:  "version-restore",
+	Short: "Restore an old version of an object",
+	Long: `This command makes the given version of an object the current version
+by copying it over the top of the object on the server.
+
+Usage Example:
+
+    rclone backend version-restore s3:bucket path/to/file.txt VERSION_ID
+
+This works with --s3-versions and --s3-version-at.
+`,
+}, {
+	Name:  "version-delete",
+	Short: "Permanently delete versions of an object",
+	Long: `This command permanently deletes the given versions of an object.
+This can't be undone.
+
+Usage Example:
+
+    rclone backend version-delete s3:bucket path/to/file.txt VERSION_ID [VERSION_ID+]
+
+It returns a list of the versions it attempted to delete with their
+status.
+`,
+}}
+
+// commandStatus is the result of a command on a single object
+type commandStatus struct {
+	Status    string
+	Remote    string
+	VersionID string `json:",omitempty"`
+}
+
+// Command the backend to run a named command
+//
+// The command run is name
+// args may be used to read arguments from
+// opts may be used to read optional arguments from
+//
+// The result should be capable of being JSON encoded
+// If it is a string or a []string it will be shown to the user
+// otherwise it will be JSON encoded and shown to the user like that
+func (f *Fs) Command(ctx context.Context, name string, arg []string, opt map[string]string) (out interface{}, err error) {
+	switch name {
+	case "restore":
+		req := s3.RestoreObjectInput{
+			Bucket:         &f.bucket,
+			RestoreRequest: &s3.RestoreRequest{},
+		}
+		if lifetime := opt["lifetime"]; lifetime != "" {
+			ilifetime, err := strconv.ParseInt(lifetime, 10, 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "bad lifetime")
+			}
+			req.RestoreRequest.Days = &ilifetime
+		}
+		if priority := opt["priority"]; priority != "" {
+			req.RestoreRequest.GlacierJobParameters = &s3.GlacierJobParameters{
+				Tier: &priority,
+			}
+		}
+		if description := opt["description"]; description != "" {
+			req.RestoreRequest.Description = &description
+		}
+		return f.restore(ctx, &req, arg)
+	case "versions":
+		dir := ""
+		if len(arg) > 0 {
+			dir = arg[0]
+		}
+		return f.listVersions(ctx, dir)
+	case "version-restore":
+		if len(arg) != 2 {
+			return nil, errors.New("need path and version ID")
+		}
+		return f.restoreVersion(ctx, arg[0], arg[1])
+	case "version-delete":
+		if len(arg) < 2 {
+			return nil, errors.New("need path and one or more version IDs")
+		}
+		return f.deleteVersions(ctx, arg[0], arg[1:])
+	default:
+		return nil, fs.ErrorCommandNotFound
+	}
+}
+
+// restore the objects named in remotes, or all the objects under the
+// root if there are none, using req as a template
+//
+// The listing of the root obeys the filters so when the remote points
+// to a single file only that file is restored.
+func (f *Fs) restore(ctx context.Context, req *s3.RestoreObjectInput, remotes []string) (out []commandStatus, err error) {
+	type item struct {
+		remote    string
+		versionID *string
+	}
+	var items []item
+	if len(remotes) == 0 {
+		err = operations.ListFn(ctx, f, func(obj fs.Object) {
+			o, ok := obj.(*Object)
+			if ok {
+				items = append(items, item{remote: o.remote, versionID: o.versionID})
+			}
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "restore: failed to list objects")
+		}
+	}
+	for _, remote := range remotes {
+		items = append(items, item{remote: remote})
+	}
+	out = make([]commandStatus, 0, len(items))
+	for _, item := range items {
+		key := f.versionKey(item.remote, item.versionID)
+		objReq := *req
+		objReq.Key = &key
+		objReq.VersionId = item.versionID
+		st := commandStatus{Remote: item.remote, Status: "OK"}
+		_, err := f.c.RestoreObjectWithContext(ctx, &objReq)
+		if err != nil {
+			st.Status = err.Error()
+			fs.Errorf(item.remote, "Failed to restore: %v", err)
+		} else {
+			fs.Infof(item.remote, "Restore requested")
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// PublicLink generates a public link to the remote path (usually readable by anyone)
+//
+// This is a presigned URL for a GET of the object which expires after
+// link_expiry.
+//
+// It isn't possible to make links when SSE-C is in use as downloading
+// the object needs the customer key to be sent too.
+func (f *Fs) PublicLink(remote string) (link string, err error) {
+	if f.opt.SSECustomerKey != "" {
+		return "", errors.New("can't make public links when using --s3-sse-customer-key")
+	}
+	ctx := context.TODO()
+	obj, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return "", err
+	}
+	o := obj.(*Object)
+	key := o.bucketKey()
+	req, _ := f.c.GetObjectRequest(&s3.GetObjectInput{
+		Bucket:    &f.bucket,
+		Key:       &key,
+		VersionId: o.versionID,
+	})
+	link, err = req.Presign(time.Duration(f.opt.LinkExpiry))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to presign link")
+	}
+	return link, nil
 }
 
 // Hashes returns the supported hash sets.
@@ -1204,13 +1906,14 @@ var matchMd5 = regexp.MustCompile(`^[0-9a-f]{32}$`)
 
 // Hash returns the Md5sum of an object returning a lowercase hex string
 func (o *Object) Hash(t hash.Type) (string, error) {
+	ctx := context.TODO()
 	if t != hash.MD5 {
 		return "", hash.ErrUnsupported
 	}
 	hash := strings.Trim(strings.ToLower(o.etag), `"`)
 	// Check the etag is a valid md5sum
-	if !matchMd5.MatchString(hash) {
-		err := o.readMetaData()
+	if !matchMd5.MatchString(hash) || o.fs.etagIsNotMD5() {
+		err := o.readMetaData(ctx)
 		if err != nil {
 			return "", err
 		}
@@ -1236,16 +1939,18 @@ func (o *Object) Size() int64 {
 // readMetaData gets the metadata if it hasn't already been fetched
 //
 // it also sets the info
-func (o *Object) readMetaData() (err error) {
+func (o *Object) readMetaData(ctx context.Context) (err error) {
 	if o.meta != nil {
 		return nil
 	}
-	key := o.fs.root + o.remote
+	key := o.bucketKey()
 	req := s3.HeadObjectInput{
-		Bucket: &o.fs.bucket,
-		Key:    &key,
+		Bucket:    &o.fs.bucket,
+		Key:       &key,
+		VersionId: o.versionID,
 	}
-	resp, err := o.fs.c.HeadObject(&req)
+	req.SSECustomerAlgorithm, req.SSECustomerKey, req.SSECustomerKeyMD5 = o.fs.sseCustomer()
+	resp, err := o.fs.c.HeadObjectWithContext(ctx, &req)
 	if err != nil {
 		if awsErr, ok := err.(awserr.RequestFailure); ok {
 			if awsErr.StatusCode() == http.StatusNotFound {
@@ -1270,6 +1975,13 @@ func (o *Object) readMetaData() (err error) {
 		o.lastModified = *resp.LastModified
 	}
 	o.mimeType = aws.StringValue(resp.ContentType)
+	o.storageClass = resp.StorageClass
+	o.cacheControl = resp.CacheControl
+	o.contentDisposition = resp.ContentDisposition
+	o.contentEncoding = resp.ContentEncoding
+	o.contentLanguage = resp.ContentLanguage
+	o.serverSideEncryption = resp.ServerSideEncryption
+	o.sseKMSKeyID = resp.SSEKMSKeyId
 	return nil
 }
 
@@ -1278,10 +1990,11 @@ func (o *Object) readMetaData() (err error) {
 // It attempts to read the objects mtime and if that isn't present the
 // LastModified returned in the http headers
 func (o *Object) ModTime() time.Time {
+	ctx := context.TODO()
 	if fs.Config.UseServerModTime {
 		return o.lastModified
 	}
-	err := o.readMetaData()
+	err := o.readMetaData(ctx)
 	if err != nil {
 		fs.Logf(o, "Failed to read metadata: %v", err)
 		return time.Now()
@@ -1302,35 +2015,35 @@ func (o *Object) ModTime() time.Time {
 
 // SetModTime sets the modification time of the local fs object
 func (o *Object) SetModTime(modTime time.Time) error {
-	err := o.readMetaData()
+	if o.fs.versioned() {
+		return errNotWithVersions
+	}
+	ctx := context.TODO()
+	err := o.readMetaData(ctx)
 	if err != nil {
 		return err
 	}
-	o.meta[metaMtime] = aws.String(swift.TimeToFloatString(modTime))
-
-	if o.bytes >= maxSizeForCopy {
-		fs.Debugf(o, "SetModTime is unsupported for objects bigger than %v bytes", fs.SizeSuffix(maxSizeForCopy))
-		return nil
+	if o.meta == nil {
+		o.meta = make(map[string]*string, 1)
 	}
+	o.meta[metaMtime] = aws.String(swift.TimeToFloatString(modTime))
 
 	// Guess the content type
 	mimeType := fs.MimeType(o)
 
 	// Copy the object to itself to update the metadata
 	key := o.fs.root + o.remote
-	sourceKey := o.fs.bucket + "/" + key
 	directive := s3.MetadataDirectiveReplace // replace metadata with that passed in
 	req := s3.CopyObjectInput{
-		Bucket:            &o.fs.bucket,
-		ACL:               &o.fs.opt.ACL,
-		Key:               &key,
-		ContentType:       &mimeType,
-		CopySource:        aws.String(pathEscape(sourceKey)),
-		Metadata:          o.meta,
-		MetadataDirective: &directive,
-	}
-	_, err = o.fs.c.CopyObject(&req)
-	return err
+		ContentType:        &mimeType,
+		Metadata:           o.meta,
+		MetadataDirective:  &directive,
+		CacheControl:       o.cacheControl,
+		ContentDisposition: o.contentDisposition,
+		ContentEncoding:    o.contentEncoding,
+		ContentLanguage:    o.contentLanguage,
+	}
+	return o.fs.copy(ctx, &req, key, o)
 }
 
 // Storable raturns a boolean indicating if this object is storable
@@ -1339,12 +2052,14 @@ func (o *Object) Storable() bool {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
-	key := o.fs.root + o.remote
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
+	key := o.bucketKey()
 	req := s3.GetObjectInput{
-		Bucket: &o.fs.bucket,
-		Key:    &key,
+		Bucket:    &o.fs.bucket,
+		Key:       &key,
+		VersionId: o.versionID,
 	}
+	req.SSECustomerAlgorithm, req.SSECustomerKey, req.SSECustomerKeyMD5 = o.fs.sseCustomer()
 	for _, option := range options {
 		switch option.(type) {
 		case *fs.RangeOption, *fs.SeekOption:
@@ -1356,7 +2071,7 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 			}
 		}
 	}
-	resp, err := o.fs.c.GetObject(&req)
+	resp, err := o.fs.c.GetObjectWithContext(ctx, &req)
 	if err, ok := err.(awserr.RequestFailure); ok {
 		if err.Code() == "InvalidObjectState" {
 			return nil, errors.Errorf("Object in GLACIER, restore first: %v", key)
@@ -1369,8 +2084,11 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 }
 
 // Update the Object from in with modTime and size
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
-	err := o.fs.Mkdir("")
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	if o.fs.versioned() {
+		return errNotWithVersions
+	}
+	err := o.fs.Mkdir(ctx, "")
 	if err != nil {
 		return err
 	}
@@ -1401,7 +2119,9 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		metaMtime: aws.String(swift.TimeToFloatString(modTime)),
 	}
 
-	if !o.fs.opt.DisableChecksum && size > uploader.PartSize {
+	// The ETag isn't the MD5 of encrypted objects so store it
+	// for those too
+	if !o.fs.opt.DisableChecksum && (size > uploader.PartSize || o.fs.etagIsNotMD5()) {
 		hash, err := src.Hash(hash.MD5)
 
 		if err == nil && matchMd5.MatchString(hash) {
@@ -1426,37 +2146,77 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		Metadata:    metadata,
 		//ContentLength: &size,
 	}
+
+	// Set the metadata if --metadata is in use
+	meta, err := fs.GetMetadataOptions(src, options)
+	if err != nil {
+		return errors.Wrap(err, "failed to read metadata from source object")
+	}
+	for k, v := range meta {
+		switch k {
+		case "cache-control":
+			req.CacheControl = aws.String(v)
+		case "content-disposition":
+			req.ContentDisposition = aws.String(v)
+		case "content-encoding":
+			req.ContentEncoding = aws.String(v)
+		case "content-language":
+			req.ContentLanguage = aws.String(v)
+		case "content-type":
+			req.ContentType = aws.String(v)
+		case "mtime":
+			// mtime in meta overrides source ModTime
+			metaModTime, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				fs.Debugf(o, "failed to parse metadata %s: %q: %v", k, v, err)
+			} else {
+				metadata[metaMtime] = aws.String(swift.TimeToFloatString(metaModTime))
+			}
+		case "tier":
+			// read only so ignore
+		default:
+			metadata[k] = aws.String(v)
+		}
+	}
 	if o.fs.opt.ServerSideEncryption != "" {
 		req.ServerSideEncryption = &o.fs.opt.ServerSideEncryption
+		if o.fs.opt.SSEKMSKeyID != "" {
+			req.SSEKMSKeyId = &o.fs.opt.SSEKMSKeyID
+		}
 	}
+	req.SSECustomerAlgorithm, req.SSECustomerKey, req.SSECustomerKeyMD5 = o.fs.sseCustomer()
 	if o.fs.opt.StorageClass != "" {
 		req.StorageClass = &o.fs.opt.StorageClass
 	}
-	_, err = uploader.Upload(&req)
+	_, err = uploader.UploadWithContext(ctx, &req)
 	if err != nil {
 		return err
 	}
 
 	// Read the metadata from the newly created object
 	o.meta = nil // wipe old metadata
-	err = o.readMetaData()
+	err = o.readMetaData(ctx)
 	return err
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
+	if o.fs.versioned() {
+		return errNotWithVersions
+	}
 	key := o.fs.root + o.remote
 	req := s3.DeleteObjectInput{
 		Bucket: &o.fs.bucket,
 		Key:    &key,
 	}
-	_, err := o.fs.c.DeleteObject(&req)
+	_, err := o.fs.c.DeleteObjectWithContext(ctx, &req)
 	return err
 }
 
 // MimeType of an Object if known, "" otherwise
 func (o *Object) MimeType() string {
-	err := o.readMetaData()
+	ctx := context.TODO()
+	err := o.readMetaData(ctx)
 	if err != nil {
 		fs.Logf(o, "Failed to read metadata: %v", err)
 		return ""
@@ -1464,12 +2224,54 @@ func (o *Object) MimeType() string {
 	return o.mimeType
 }
 
+// Metadata returns metadata for an object
+//
+// It should return nil if there is no Metadata
+func (o *Object) Metadata() (metadata fs.Metadata, err error) {
+	ctx := context.TODO()
+	err = o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata = make(fs.Metadata, len(o.meta)+7)
+	for k, v := range o.meta {
+		switch k {
+		case metaMtime:
+			if modTime, err := swift.FloatStringToTime(*v); err == nil {
+				metadata["mtime"] = modTime.Format(time.RFC3339Nano)
+			}
+		case metaMD5Hash:
+			// don't write hash metadata
+		default:
+			metadata[strings.ToLower(k)] = *v
+		}
+	}
+	if o.mimeType != "" {
+		metadata["content-type"] = o.mimeType
+	}
+	setMetadata := func(k string, v *string) {
+		if v == nil || *v == "" {
+			return
+		}
+		metadata[k] = *v
+	}
+	setMetadata("cache-control", o.cacheControl)
+	setMetadata("content-disposition", o.contentDisposition)
+	setMetadata("content-encoding", o.contentEncoding)
+	setMetadata("content-language", o.contentLanguage)
+	setMetadata("tier", o.storageClass)
+	return metadata, nil
+}
+
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = &Fs{}
-	_ fs.Copier      = &Fs{}
-	_ fs.PutStreamer = &Fs{}
-	_ fs.ListRer     = &Fs{}
-	_ fs.Object      = &Object{}
-	_ fs.MimeTyper   = &Object{}
+	_ fs.Fs           = &Fs{}
+	_ fs.Copier       = &Fs{}
+	_ fs.PutStreamer  = &Fs{}
+	_ fs.ListRer      = &Fs{}
+	_ fs.Commander    = &Fs{}
+	_ fs.PublicLinker = &Fs{}
+	_ fs.Object       = &Object{}
+	_ fs.MimeTyper    = &Object{}
+	_ fs.Metadataer   = &Object{}
 )
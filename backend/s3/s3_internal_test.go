@@ -0,0 +1,1075 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/configmap"
+	"github.com/artpar/rclone/fs/filter"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/fs/object"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// headers which are stored with fake objects
+var fakeHeaders = []string{
+	"Content-Type",
+	"Cache-Control",
+	"Content-Disposition",
+	"Content-Encoding",
+	"Content-Language",
+	"X-Amz-Acl",
+	"X-Amz-Storage-Class",
+	"X-Amz-Server-Side-Encryption",
+	"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id",
+	"X-Amz-Server-Side-Encryption-Customer-Algorithm",
+	"X-Amz-Server-Side-Encryption-Customer-Key-Md5",
+}
+
+// headers which set the encryption of an object
+var encryptionHeaders = []string{
+	"X-Amz-Server-Side-Encryption",
+	"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id",
+	"X-Amz-Server-Side-Encryption-Customer-Algorithm",
+	"X-Amz-Server-Side-Encryption-Customer-Key-Md5",
+}
+
+// fakeObject is an object stored in fakeS3
+type fakeObject struct {
+	data   []byte
+	header http.Header
+}
+
+// fakeUpload is a multipart upload in progress in fakeS3
+type fakeUpload struct {
+	key    string
+	header http.Header
+	parts  map[int][]byte
+}
+
+// fakeVersion is an old or current version of an object in fakeS3
+type fakeVersion struct {
+	key          string
+	versionID    string
+	lastModified time.Time
+	isLatest     bool
+	deleteMarker bool
+	object       *fakeObject
+}
+
+// fakeS3 is a minimal S3 compatible server with a single bucket
+// which supports enough of the protocol to test server side copies
+// and versioning
+type fakeS3 struct {
+	mu          sync.Mutex
+	objects     map[string]*fakeObject
+	versions    []*fakeVersion // versions of the objects if set
+	uploads     map[string]*fakeUpload
+	nextID      int
+	copies      int // number of CopyObject calls
+	partCopies  int // number of UploadPartCopy calls
+	aborts      int // number of AbortMultipartUpload calls
+	inFlight    int // number of UploadPartCopy calls in progress
+	maxInFlight int // maximum value of inFlight seen
+	failPart    int // if set UploadPartCopy of this part fails
+	restores    []string // keys restored with RestoreObject
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects: map[string]*fakeObject{},
+		uploads: map[string]*fakeUpload{},
+	}
+}
+
+// storedHeaders returns the headers from h which are stored with objects
+func storedHeaders(h http.Header) http.Header {
+	out := http.Header{}
+	for k, vs := range h {
+		k = http.CanonicalHeaderKey(k)
+		if strings.HasPrefix(k, "X-Amz-Meta-") {
+			out[k] = vs
+		}
+	}
+	for _, k := range fakeHeaders {
+		if v := h.Get(k); v != "" {
+			out.Set(k, v)
+		}
+	}
+	return out
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+// findVersion finds the version of key with versionID
+func (f *fakeS3) findVersion(key, versionID string) *fakeVersion {
+	for _, v := range f.versions {
+		if v.key == key && v.versionID == versionID {
+			return v
+		}
+	}
+	return nil
+}
+
+// findObject finds the object key with versionID, or the current
+// object if versionID is empty
+func (f *fakeS3) findObject(key, versionID string) (*fakeObject, bool) {
+	if versionID == "" {
+		o, ok := f.objects[key]
+		return o, ok
+	}
+	v := f.findVersion(key, versionID)
+	if v == nil || v.deleteMarker {
+		return nil, false
+	}
+	return v.object, true
+}
+
+// checkSSECustomer checks the SSE-C key in the headers prefixed with
+// prefix in r matches the key o was stored with, if any
+func checkSSECustomer(r *http.Request, prefix string, o *fakeObject) error {
+	want := o.header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5")
+	got := r.Header.Get(prefix + "Server-Side-Encryption-Customer-Key-Md5")
+	if got != want {
+		return fmt.Errorf("SSE-C key MD5 %q doesn't match %q", got, want)
+	}
+	return nil
+}
+
+// copySource reads the object named by the x-amz-copy-source header
+func (f *fakeS3) copySource(r *http.Request) (*fakeObject, error) {
+	header := r.Header.Get("X-Amz-Copy-Source")
+	versionID := ""
+	if i := strings.Index(header, "?versionId="); i >= 0 {
+		var err error
+		versionID, err = url.QueryUnescape(header[i+len("?versionId="):])
+		if err != nil {
+			return nil, err
+		}
+		header = header[:i]
+	}
+	source, err := url.PathUnescape(header)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(strings.TrimPrefix(source, "/"), "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("bad copy source %q", source)
+	}
+	o, ok := f.findObject(parts[1], versionID)
+	if !ok {
+		return nil, fmt.Errorf("copy source %q not found", source)
+	}
+	err = checkSSECustomer(r, "X-Amz-Copy-Source-", o)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// listObjects implements ListObjects without paging
+func (f *fakeS3) listObjects(w http.ResponseWriter, prefix, delimiter string) {
+	type xmlObject struct {
+		Key          string
+		LastModified time.Time
+		ETag         string
+		Size         int
+	}
+	type xmlPrefix struct {
+		Prefix string
+	}
+	var result struct {
+		XMLName        xml.Name `xml:"ListBucketResult"`
+		IsTruncated    bool
+		Contents       []xmlObject
+		CommonPrefixes []xmlPrefix
+	}
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				dir := key[:len(prefix)+i+1]
+				if !seen[dir] {
+					seen[dir] = true
+					result.CommonPrefixes = append(result.CommonPrefixes, xmlPrefix{Prefix: dir})
+				}
+				continue
+			}
+		}
+		result.Contents = append(result.Contents, xmlObject{
+			Key:          key,
+			LastModified: time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC),
+			ETag:         `"etag"`,
+			Size:         len(f.objects[key].data),
+		})
+	}
+	writeXML(w, result)
+}
+
+// listVersions implements ListObjectVersions without paging
+func (f *fakeS3) listVersions(w http.ResponseWriter, prefix string) {
+	type xmlVersion struct {
+		Key          string
+		VersionId    string
+		IsLatest     bool
+		LastModified time.Time
+		ETag         string `xml:",omitempty"`
+		Size         int    `xml:",omitempty"`
+	}
+	var result struct {
+		XMLName       xml.Name `xml:"ListVersionsResult"`
+		IsTruncated   bool
+		Versions      []xmlVersion `xml:"Version"`
+		DeleteMarkers []xmlVersion `xml:"DeleteMarker"`
+	}
+	versions := append([]*fakeVersion(nil), f.versions...)
+	sort.SliceStable(versions, func(i, j int) bool {
+		if versions[i].key != versions[j].key {
+			return versions[i].key < versions[j].key
+		}
+		return versions[i].lastModified.After(versions[j].lastModified)
+	})
+	for _, v := range versions {
+		if !strings.HasPrefix(v.key, prefix) {
+			continue
+		}
+		entry := xmlVersion{
+			Key:          v.key,
+			VersionId:    v.versionID,
+			IsLatest:     v.isLatest,
+			LastModified: v.lastModified,
+		}
+		if v.deleteMarker {
+			result.DeleteMarkers = append(result.DeleteMarkers, entry)
+		} else {
+			entry.ETag = `"etag"`
+			entry.Size = len(v.object.data)
+			result.Versions = append(result.Versions, entry)
+		}
+	}
+	writeXML(w, result)
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	key := ""
+	if len(path) > 1 {
+		key = path[1]
+	}
+	q := r.URL.Query()
+	_, isUploads := q["uploads"]
+	_, isVersions := q["versions"]
+	_, isRestore := q["restore"]
+	uploadID := q.Get("uploadId")
+	lastModified := time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	// UploadPartCopy is done outside the lock so we can check
+	// the concurrency
+	if r.Method == "PUT" && uploadID != "" {
+		f.uploadPartCopy(w, r, uploadID, q.Get("partNumber"))
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case key == "" && r.Method == "HEAD":
+		w.WriteHeader(http.StatusOK)
+	case key == "" && r.Method == "GET" && isVersions:
+		f.listVersions(w, q.Get("prefix"))
+	case key == "" && r.Method == "GET":
+		f.listObjects(w, q.Get("prefix"), q.Get("delimiter"))
+	case r.Method == "HEAD" || r.Method == "GET":
+		o, ok := f.findObject(key, q.Get("versionId"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		if checkSSECustomer(r, "X-Amz-", o) != nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequest")
+			return
+		}
+		for k, vs := range o.header {
+			w.Header()[k] = vs
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(o.data)))
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+		if r.Method == "GET" {
+			_, _ = w.Write(o.data)
+		}
+	case r.Method == "PUT" && r.Header.Get("X-Amz-Copy-Source") != "":
+		f.copies++
+		src, err := f.copySource(r)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		header := storedHeaders(r.Header)
+		if r.Header.Get("X-Amz-Metadata-Directive") != "REPLACE" {
+			header = storedHeaders(src.header)
+			for _, k := range append([]string{"X-Amz-Acl", "X-Amz-Storage-Class"}, encryptionHeaders...) {
+				header.Del(k)
+				if v := r.Header.Get(k); v != "" {
+					header.Set(k, v)
+				}
+			}
+		}
+		f.objects[key] = &fakeObject{data: append([]byte(nil), src.data...), header: header}
+		writeXML(w, struct {
+			XMLName      xml.Name `xml:"CopyObjectResult"`
+			ETag         string
+			LastModified time.Time
+		}{ETag: `"etag"`, LastModified: lastModified})
+	case r.Method == "PUT" && key != "":
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[key] = &fakeObject{data: data, header: storedHeaders(r.Header)}
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == "POST" && isRestore:
+		if _, ok := f.objects[key]; !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		f.restores = append(f.restores, key)
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == "POST" && isUploads:
+		f.nextID++
+		id := strconv.Itoa(f.nextID)
+		f.uploads[id] = &fakeUpload{
+			key:    key,
+			header: storedHeaders(r.Header),
+			parts:  map[int][]byte{},
+		}
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadId string
+		}{Bucket: path[0], Key: key, UploadId: id})
+	case r.Method == "POST" && uploadID != "":
+		upload, ok := f.uploads[uploadID]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+			} `xml:"Part"`
+		}
+		err := xml.NewDecoder(r.Body).Decode(&complete)
+		if err != nil || len(complete.Parts) == 0 {
+			writeError(w, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+		var data []byte
+		for i, part := range complete.Parts {
+			partData, ok := upload.parts[part.PartNumber]
+			if !ok || part.PartNumber != i+1 {
+				writeError(w, http.StatusBadRequest, "InvalidPart")
+				return
+			}
+			data = append(data, partData...)
+		}
+		delete(f.uploads, uploadID)
+		f.objects[upload.key] = &fakeObject{data: data, header: upload.header}
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Key     string
+			ETag    string
+		}{Key: key, ETag: `"etag-multipart"`})
+	case r.Method == "DELETE" && uploadID != "":
+		f.aborts++
+		delete(f.uploads, uploadID)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "DELETE" && q.Get("versionId") != "":
+		v := f.findVersion(key, q.Get("versionId"))
+		if v == nil {
+			writeError(w, http.StatusNotFound, "NoSuchVersion")
+			return
+		}
+		for i := range f.versions {
+			if f.versions[i] == v {
+				f.versions = append(f.versions[:i], f.versions[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// uploadPartCopy implements UploadPartCopy
+func (f *fakeS3) uploadPartCopy(w http.ResponseWriter, r *http.Request, uploadID string, partNumber string) {
+	f.mu.Lock()
+	f.partCopies++
+	f.inFlight++
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	f.mu.Unlock()
+
+	// give other parts a chance to run at the same time
+	time.Sleep(10 * time.Millisecond)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inFlight--
+	partNum, _ := strconv.Atoi(partNumber)
+	if partNum == f.failPart {
+		writeError(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	upload, ok := f.uploads[uploadID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+	src, err := f.copySource(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+	if r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5") != upload.header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5") {
+		writeError(w, http.StatusBadRequest, "InvalidRequest")
+		return
+	}
+	var start, end int
+	_, err = fmt.Sscanf(r.Header.Get("X-Amz-Copy-Source-Range"), "bytes=%d-%d", &start, &end)
+	if err != nil || start > end || end >= len(src.data) {
+		writeError(w, http.StatusBadRequest, "InvalidRange")
+		return
+	}
+	upload.parts[partNum] = append([]byte(nil), src.data[start:end+1]...)
+	writeXML(w, struct {
+		XMLName      xml.Name `xml:"CopyPartResult"`
+		ETag         string
+		LastModified time.Time
+	}{ETag: fmt.Sprintf(`"part-%d"`, partNum), LastModified: time.Now().UTC()})
+}
+
+// newFakeFs makes an Fs talking to a fakeS3 server
+func newFakeFs(t *testing.T, fake *fakeS3, opt configmap.Simple) (*Fs, func()) {
+	f, cleanup, err := newFakeFsRoot(t, fake, "bucket", opt)
+	require.NoError(t, err)
+	return f, cleanup
+}
+
+// newFakeFsRoot makes an Fs at root talking to a fakeS3 server,
+// returning the error from NewFs, eg fs.ErrorIsFile
+//
+// If SSE-C is in use the server uses https as the SDK won't send the
+// keys over http. The client which trusts its certificate is only set
+// after NewFs so these can't be rooted at a file.
+func newFakeFsRoot(t *testing.T, fake *fakeS3, root string, opt configmap.Simple) (*Fs, func(), error) {
+	useTLS := opt["sse_customer_key"] != ""
+	server := httptest.NewUnstartedServer(fake)
+	if useTLS {
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	m := configmap.Simple{
+		"provider":           "Other",
+		"access_key_id":      "key",
+		"secret_access_key":  "secret",
+		"endpoint":           server.URL,
+		"force_path_style":   "true",
+		"chunk_size":         fs.SizeSuffix(s3manager.MinUploadPartSize).String(),
+		"copy_cutoff":        fs.SizeSuffix(maxSizeForCopy).String(),
+		"upload_concurrency": "2",
+		"link_expiry":        "168h",
+	}
+	for k, v := range opt {
+		m[k] = v
+	}
+	f, err := NewFs("fakes3", root, m)
+	if f == nil {
+		server.Close()
+		require.NoError(t, err)
+	}
+	if useTLS {
+		// trust the certificate of the test server
+		f.(*Fs).c.Config.HTTPClient = server.Client()
+	}
+	return f.(*Fs), server.Close, err
+}
+
+// makeSource puts a random object of size bytes into fake
+func makeSource(fake *fakeS3, key string, size int64) []byte {
+	data := make([]byte, size)
+	_, _ = rand.New(rand.NewSource(1)).Read(data)
+	fake.objects[key] = &fakeObject{
+		data: data,
+		header: http.Header{
+			"Content-Type":                 {"image/jpeg"},
+			"Cache-Control":                {"no-cache"},
+			"Content-Language":             {"en"},
+			"X-Amz-Meta-Potato":            {"jersey royal"},
+			"X-Amz-Meta-Mtime":             {"1564660800.000000000"},
+			"X-Amz-Storage-Class":          {"STANDARD_IA"},
+			"X-Amz-Server-Side-Encryption": {"AES256"},
+		},
+	}
+	return data
+}
+
+func (f *Fs) checkCopy(t *testing.T, fake *fakeS3, want []byte) {
+	ctx := context.Background()
+	src, err := f.NewObject(ctx, "src")
+	require.NoError(t, err)
+	dst, err := f.Copy(ctx, src, "dst")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(want)), dst.Size())
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	got := fake.objects["dst"]
+	require.NotNil(t, got)
+	assert.True(t, bytes.Equal(want, got.data), "data differs")
+	assert.Equal(t, "image/jpeg", got.header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", got.header.Get("Cache-Control"))
+	assert.Equal(t, "en", got.header.Get("Content-Language"))
+	assert.Equal(t, "jersey royal", got.header.Get("X-Amz-Meta-Potato"))
+	assert.Equal(t, "1564660800.000000000", got.header.Get("X-Amz-Meta-Mtime"))
+	assert.Equal(t, "STANDARD_IA", got.header.Get("X-Amz-Storage-Class"))
+	assert.Equal(t, "AES256", got.header.Get("X-Amz-Server-Side-Encryption"))
+	assert.Equal(t, "bucket-owner-full-control", got.header.Get("X-Amz-Acl"))
+}
+
+func TestCopySingle(t *testing.T) {
+	fake := newFakeS3()
+	f, cleanup := newFakeFs(t, fake, configmap.Simple{"acl": "bucket-owner-full-control"})
+	defer cleanup()
+	want := makeSource(fake, "src", 1000)
+
+	f.checkCopy(t, fake, want)
+	assert.Equal(t, 1, fake.copies)
+	assert.Equal(t, 0, fake.partCopies)
+}
+
+func TestCopyMultipart(t *testing.T) {
+	fake := newFakeS3()
+	f, cleanup := newFakeFs(t, fake, configmap.Simple{
+		"acl":         "bucket-owner-full-control",
+		"copy_cutoff": "1M",
+	})
+	defer cleanup()
+	// 3 parts, the last one short
+	want := makeSource(fake, "src", 2*s3manager.MinUploadPartSize+12345)
+
+	f.checkCopy(t, fake, want)
+	assert.Equal(t, 0, fake.copies)
+	assert.Equal(t, 3, fake.partCopies)
+	assert.True(t, fake.maxInFlight <= 2, "concurrency %d exceeded upload_concurrency", fake.maxInFlight)
+	assert.Equal(t, 0, fake.aborts)
+	assert.Equal(t, 0, len(fake.uploads))
+}
+
+func TestCopyMultipartStorageClassOverride(t *testing.T) {
+	fake := newFakeS3()
+	f, cleanup := newFakeFs(t, fake, configmap.Simple{
+		"copy_cutoff":            "1M",
+		"storage_class":          "REDUCED_REDUNDANCY",
+		"server_side_encryption": "aws:kms",
+	})
+	defer cleanup()
+	makeSource(fake, "src", 2*s3manager.MinUploadPartSize)
+
+	ctx := context.Background()
+	src, err := f.NewObject(ctx, "src")
+	require.NoError(t, err)
+	_, err = f.Copy(ctx, src, "dst")
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.partCopies)
+	assert.Equal(t, "REDUCED_REDUNDANCY", fake.objects["dst"].header.Get("X-Amz-Storage-Class"))
+	assert.Equal(t, "aws:kms", fake.objects["dst"].header.Get("X-Amz-Server-Side-Encryption"))
+}
+
+func TestCopyMultipartError(t *testing.T) {
+	fake := newFakeS3()
+	fake.failPart = 2
+	f, cleanup := newFakeFs(t, fake, configmap.Simple{"copy_cutoff": "0"})
+	defer cleanup()
+	makeSource(fake, "src", 3*s3manager.MinUploadPartSize)
+
+	ctx := context.Background()
+	src, err := f.NewObject(ctx, "src")
+	require.NoError(t, err)
+	_, err = f.Copy(ctx, src, "dst")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to copy part 2")
+	assert.Equal(t, 1, fake.aborts)
+	assert.Equal(t, 0, len(fake.uploads))
+	assert.Nil(t, fake.objects["dst"])
+}
+
+func TestSetModTimeMultipart(t *testing.T) {
+	fake := newFakeS3()
+	f, cleanup := newFakeFs(t, fake, configmap.Simple{"copy_cutoff": "1M"})
+	defer cleanup()
+	want := makeSource(fake, "src", 2*s3manager.MinUploadPartSize)
+
+	ctx := context.Background()
+	o, err := f.NewObject(ctx, "src")
+	require.NoError(t, err)
+	modTime := time.Date(2019, 8, 2, 1, 2, 3, 0, time.UTC)
+	require.NoError(t, o.SetModTime(modTime))
+	assert.Equal(t, 2, fake.partCopies)
+
+	got := fake.objects["src"]
+	assert.True(t, bytes.Equal(want, got.data), "data differs")
+	assert.Equal(t, "1564707723", got.header.Get("X-Amz-Meta-Mtime"))
+	assert.Equal(t, "jersey royal", got.header.Get("X-Amz-Meta-Potato"))
+	assert.Equal(t, "no-cache", got.header.Get("Cache-Control"))
+	assert.Equal(t, "STANDARD_IA", got.header.Get("X-Amz-Storage-Class"))
+}
+
+func TestCopyPartSize(t *testing.T) {
+	f := &Fs{opt: Options{ChunkSize: fs.SizeSuffix(s3manager.MinUploadPartSize)}}
+	assert.Equal(t, int64(s3manager.MinUploadPartSize), f.copyPartSize(100))
+	assert.Equal(t, int64(s3manager.MinUploadPartSize), f.copyPartSize(6*1024*1024*1024))
+	size := int64(5 * 1024 * 1024 * 1024 * 1024)
+	partSize := f.copyPartSize(size)
+	assert.True(t, (size+partSize-1)/partSize <= s3manager.MaxUploadParts)
+	assert.Equal(t, int64(0), partSize%(1<<20))
+}
+
+func TestCopyCutoffTooBig(t *testing.T) {
+	_, err := NewFs("fakes3", "bucket", configmap.Simple{
+		"chunk_size":  "5M",
+		"copy_cutoff": "6G",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "copy cutoff")
+}
+
+var (
+	versionT1 = time.Date(2019, 8, 1, 10, 0, 0, 0, time.UTC)
+	versionT2 = time.Date(2019, 8, 2, 10, 0, 0, 0, time.UTC)
+	versionT3 = time.Date(2019, 8, 3, 10, 0, 0, 0, time.UTC)
+)
+
+// makeVersions sets up a versioned bucket in fake
+//
+//   - file.txt has versions "one" at T1 and "two" at T2
+//   - deleted.txt has version "gone" at T1 and was deleted at T2
+//   - new.txt has version "new" at T3
+func makeVersions(fake *fakeS3) {
+	object := func(data string) *fakeObject {
+		return &fakeObject{
+			data:   []byte(data),
+			header: http.Header{"X-Amz-Meta-Mtime": {"1564660800"}},
+		}
+	}
+	fake.versions = []*fakeVersion{
+		{key: "file.txt", versionID: "file1", lastModified: versionT1, object: object("one")},
+		{key: "file.txt", versionID: "file2", lastModified: versionT2, isLatest: true, object: object("two")},
+		{key: "deleted.txt", versionID: "deleted1", lastModified: versionT1, object: object("gone")},
+		{key: "deleted.txt", versionID: "deleted2", lastModified: versionT2, isLatest: true, deleteMarker: true},
+		{key: "new.txt", versionID: "new1", lastModified: versionT3, isLatest: true, object: object("new")},
+	}
+	for _, v := range fake.versions {
+		if v.isLatest && !v.deleteMarker {
+			fake.objects[v.key] = v.object
+		}
+	}
+}
+
+// listNames lists the names of the objects in f
+func listNames(t *testing.T, f *Fs) (names []string) {
+	entries, err := f.List(context.Background(), "")
+	require.NoError(t, err)
+	for _, entry := range entries {
+		names = append(names, entry.Remote())
+	}
+	return names
+}
+
+// readObject reads the contents of remote in f
+func readObject(t *testing.T, f *Fs, remote string) string {
+	ctx := context.Background()
+	o, err := f.NewObject(ctx, remote)
+	require.NoError(t, err, remote)
+	in, err := o.Open(ctx)
+	require.NoError(t, err, remote)
+	data, err := ioutil.ReadAll(in)
+	require.NoError(t, err, remote)
+	require.NoError(t, in.Close())
+	return string(data)
+}
+
+func TestVersions(t *testing.T) {
+	fake := newFakeS3()
+	makeVersions(fake)
+	f, cleanup := newFakeFs(t, fake, configmap.Simple{"versions": "true"})
+	defer cleanup()
+	ctx := context.Background()
+
+	assert.Equal(t, []string{
+		"deleted-v2019-08-01-100000-000.txt",
+		"file.txt",
+		"file-v2019-08-01-100000-000.txt",
+		"new.txt",
+	}, listNames(t, f))
+
+	assert.Equal(t, "one", readObject(t, f, "file-v2019-08-01-100000-000.txt"))
+	assert.Equal(t, "gone", readObject(t, f, "deleted-v2019-08-01-100000-000.txt"))
+	assert.Equal(t, "two", readObject(t, f, "file.txt"))
+	_, err := f.NewObject(ctx, "file-v2019-08-05-100000-000.txt")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+
+	// read only
+	o, err := f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, errNotWithVersions, o.Remove(ctx))
+	assert.Equal(t, errNotWithVersions, o.SetModTime(versionT3))
+	_, err = f.Copy(ctx, o, "copy.txt")
+	assert.Equal(t, errNotWithVersions, err)
+}
+
+func TestVersionAt(t *testing.T) {
+	fake := newFakeS3()
+	makeVersions(fake)
+	f, cleanup := newFakeFs(t, fake, configmap.Simple{"version_at": "2019-08-01T12:00:00Z"})
+	defer cleanup()
+	ctx := context.Background()
+
+	assert.Equal(t, []string{"deleted.txt", "file.txt"}, listNames(t, f))
+	assert.Equal(t, "one", readObject(t, f, "file.txt"))
+	assert.Equal(t, "gone", readObject(t, f, "deleted.txt"))
+	_, err := f.NewObject(ctx, "new.txt")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+
+	// read only
+	_, err = f.Put(ctx, bytes.NewBufferString("potato"), object.NewStaticObjectInfo("potato.txt", versionT3, 6, true, nil, nil))
+	assert.Equal(t, errNotWithVersions, err)
+	assert.Equal(t, errNotWithVersions, f.Mkdir(ctx, ""))
+	assert.Equal(t, errNotWithVersions, f.Rmdir(ctx, ""))
+
+	// after the delete
+	f.versionAt = versionT2.Add(time.Hour)
+	assert.Equal(t, []string{"file.txt"}, listNames(t, f))
+	assert.Equal(t, "two", readObject(t, f, "file.txt"))
+}
+
+func TestVersionsAndVersionAt(t *testing.T) {
+	_, err := NewFs("fakes3", "bucket", configmap.Simple{
+		"chunk_size": "5M",
+		"versions":   "true",
+		"version_at": "1h",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at the same time")
+}
+
+func TestVersionCommands(t *testing.T) {
+	fake := newFakeS3()
+	makeVersions(fake)
+	f, cleanup := newFakeFs(t, fake, nil)
+	defer cleanup()
+	ctx := context.Background()
+
+	out, err := f.Command(ctx, "versions", nil, nil)
+	require.NoError(t, err)
+	versions := out.([]versionInfo)
+	require.Equal(t, 5, len(versions))
+	assert.Equal(t, versionInfo{Remote: "deleted.txt", VersionID: "deleted2", LastModified: versionT2, IsLatest: true, DeleteMarker: true}, versions[0])
+	assert.Equal(t, versionInfo{Remote: "file.txt", VersionID: "file1", LastModified: versionT1, Size: 3}, versions[3])
+
+	out, err = f.Command(ctx, "versions", []string{"new"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []versionInfo{{Remote: "new.txt", VersionID: "new1", LastModified: versionT3, Size: 3, IsLatest: true}}, out)
+
+	_, err = f.Command(ctx, "version-restore", []string{"file.txt"}, nil)
+	assert.Error(t, err)
+	out, err = f.Command(ctx, "version-restore", []string{"file.txt", "file1"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, commandStatus{Remote: "file.txt", VersionID: "file1", Status: "OK"}, out)
+	assert.Equal(t, "one", string(fake.objects["file.txt"].data))
+
+	out, err = f.Command(ctx, "version-delete", []string{"deleted.txt", "deleted2", "potato"}, nil)
+	require.NoError(t, err)
+	status := out.([]commandStatus)
+	require.Equal(t, 2, len(status))
+	assert.Equal(t, commandStatus{Remote: "deleted.txt", VersionID: "deleted2", Status: "OK"}, status[0])
+	assert.NotEqual(t, "OK", status[1].Status)
+	assert.Nil(t, fake.findVersion("deleted.txt", "deleted2"))
+}
+
+func TestParseVersionAt(t *testing.T) {
+	now := time.Date(2019, 8, 10, 12, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		in   string
+		want time.Time
+		err  bool
+	}{
+		{"2019-08-01T12:34:56Z", time.Date(2019, 8, 1, 12, 34, 56, 0, time.UTC), false},
+		{"2019-08-01 12:34:56", time.Date(2019, 8, 1, 12, 34, 56, 0, time.Local), false},
+		{"2019-08-01", time.Date(2019, 8, 1, 0, 0, 0, 0, time.Local), false},
+		{"1h", now.Add(-time.Hour), false},
+		{"2d", now.Add(-48 * time.Hour), false},
+		{"potato", time.Time{}, true},
+	} {
+		got, err := parseVersionAt(test.in, now)
+		if test.err {
+			assert.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			assert.True(t, test.want.Equal(got), "%s: want %v got %v", test.in, test.want, got)
+		}
+	}
+}
+
+func TestVersionFilterPages(t *testing.T) {
+	version := func(key, id string, t time.Time, isLatest bool) *s3.ObjectVersion {
+		return &s3.ObjectVersion{Key: aws.String(key), VersionId: aws.String(id), LastModified: aws.Time(t), IsLatest: aws.Bool(isLatest)}
+	}
+	filter := newVersionFilter(false, versionT2.Add(time.Hour))
+	// the versions of b are split over two pages
+	objects, ids := filter.filter([]*s3.ObjectVersion{
+		version("a", "a1", versionT1, true),
+		version("b", "b3", versionT3, true),
+	}, nil)
+	assert.Equal(t, 1, len(objects))
+	assert.Equal(t, []*string{aws.String("a1")}, ids)
+	objects, ids = filter.filter([]*s3.ObjectVersion{
+		version("b", "b2", versionT2, false),
+		version("b", "b1", versionT1, false),
+	}, nil)
+	assert.Equal(t, 1, len(objects))
+	assert.Equal(t, []*string{aws.String("b2")}, ids)
+}
+
+// putObject uploads data to remote in f
+func putObject(t *testing.T, f *Fs, remote string, data []byte) fs.Object {
+	sum := md5.Sum(data)
+	hashes := map[hash.Type]string{hash.MD5: hex.EncodeToString(sum[:])}
+	src := object.NewStaticObjectInfo(remote, versionT1, int64(len(data)), true, hashes, nil)
+	o, err := f.Put(context.Background(), bytes.NewReader(data), src)
+	require.NoError(t, err)
+	return o
+}
+
+const testSSECustomerKey = "0123456789abcdef0123456789abcdef"
+
+func TestSSECustomer(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeS3()
+	f, cleanup := newFakeFs(t, fake, configmap.Simple{
+		"sse_customer_algorithm": "AES256",
+		"sse_customer_key":       testSSECustomerKey,
+		"copy_cutoff":            "0",
+	})
+	defer cleanup()
+	data := []byte("hello, encrypted world")
+	keyMD5 := md5.Sum([]byte(testSSECustomerKey))
+	wantKeyMD5 := base64.StdEncoding.EncodeToString(keyMD5[:])
+
+	// Put
+	o := putObject(t, f, "src", data)
+	stored := fake.objects["src"]
+	require.NotNil(t, stored)
+	assert.Equal(t, "AES256", stored.header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm"))
+	assert.Equal(t, wantKeyMD5, stored.header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5"))
+
+	// The ETag isn't the MD5 so it should be read from the metadata
+	dataMD5 := md5.Sum(data)
+	hash, err := o.Hash(hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(dataMD5[:]), hash)
+
+	// HEAD and Open
+	assert.Equal(t, string(data), readObject(t, f, "src"))
+
+	// Copy with a multipart copy
+	_, err = f.Copy(ctx, o, "dst")
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.partCopies)
+	assert.Equal(t, string(data), readObject(t, f, "dst"))
+	assert.Equal(t, wantKeyMD5, fake.objects["dst"].header.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5"))
+
+	// Copy with a single copy
+	f.opt.CopyCutoff = maxSizeForCopy
+	_, err = f.Copy(ctx, o, "dst2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.copies)
+	assert.Equal(t, string(data), readObject(t, f, "dst2"))
+
+	// An Fs without the key can't read the object
+	f2, cleanup2 := newFakeFs(t, fake, nil)
+	defer cleanup2()
+	_, err = f2.NewObject(ctx, "src")
+	assert.Error(t, err)
+}
+
+func TestSSEKMS(t *testing.T) {
+	fake := newFakeS3()
+	f, cleanup := newFakeFs(t, fake, configmap.Simple{
+		"server_side_encryption": "aws:kms",
+		"sse_kms_key_id":         "arn:aws:kms:us-east-1:key",
+	})
+	defer cleanup()
+	o := putObject(t, f, "src", []byte("kms"))
+	stored := fake.objects["src"]
+	require.NotNil(t, stored)
+	assert.Equal(t, "aws:kms", stored.header.Get("X-Amz-Server-Side-Encryption"))
+	assert.Equal(t, "arn:aws:kms:us-east-1:key", stored.header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+	assert.NotEqual(t, "", stored.header.Get("X-Amz-Meta-Md5chksum"))
+
+	_, err := f.Copy(context.Background(), o, "dst")
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:kms:us-east-1:key", fake.objects["dst"].header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+}
+
+func TestSSEOptionErrors(t *testing.T) {
+	for _, test := range []struct {
+		opt configmap.Simple
+		err string
+	}{
+		{
+			opt: configmap.Simple{"sse_customer_algorithm": "AES256"},
+			err: "sse-customer-key must be set",
+		}, {
+			opt: configmap.Simple{"sse_customer_key": testSSECustomerKey},
+			err: "sse-customer-algorithm must be set",
+		}, {
+			opt: configmap.Simple{"sse_customer_algorithm": "AES256", "sse_customer_key": testSSECustomerKey, "server_side_encryption": "AES256"},
+			err: "at the same time",
+		}, {
+			opt: configmap.Simple{"link_expiry": "0s"},
+			err: "link expiry",
+		},
+	} {
+		test.opt["chunk_size"] = "5M"
+		_, err := NewFs("fakes3", "bucket", test.opt)
+		require.Error(t, err, "%v", test.opt)
+		assert.Contains(t, err.Error(), test.err, "%v", test.opt)
+	}
+}
+
+func TestPublicLink(t *testing.T) {
+	fake := newFakeS3()
+	makeSource(fake, "dir/src file", 100)
+	for _, test := range []struct {
+		expiry string
+		want   string
+	}{
+		{expiry: "168h", want: "604800"},
+		{expiry: "1h", want: "3600"},
+	} {
+		f, cleanup := newFakeFs(t, fake, configmap.Simple{"link_expiry": test.expiry})
+		link, err := f.PublicLink("dir/src file")
+		cleanup()
+		require.NoError(t, err)
+		u, err := url.Parse(link)
+		require.NoError(t, err)
+		assert.Equal(t, "/bucket/dir/src file", u.Path)
+		assert.Equal(t, test.want, u.Query().Get("X-Amz-Expires"))
+		assert.NotEqual(t, "", u.Query().Get("X-Amz-Signature"))
+	}
+
+	f, cleanup := newFakeFs(t, fake, nil)
+	defer cleanup()
+	_, err := f.PublicLink("potato")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+
+	// Links to SSE-C objects would need the key
+	fSSEC, cleanupSSEC := newFakeFs(t, fake, configmap.Simple{
+		"sse_customer_algorithm": "AES256",
+		"sse_customer_key":       testSSECustomerKey,
+	})
+	defer cleanupSSEC()
+	_, err = fSSEC.PublicLink("dir/src file")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sse-customer-key")
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeS3()
+	for _, key := range []string{"dir/a", "dir/b", "dir/sub/c", "other"} {
+		fake.objects[key] = &fakeObject{data: []byte(key), header: http.Header{}}
+	}
+
+	// Everything under the root is restored
+	f, cleanup := newFakeFs(t, fake, nil)
+	defer cleanup()
+	out, err := f.Command(ctx, "restore", nil, map[string]string{"priority": "Bulk"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, len(out.([]commandStatus)))
+	sort.Strings(fake.restores)
+	assert.Equal(t, []string{"dir/a", "dir/b", "dir/sub/c", "other"}, fake.restores)
+
+	// Only the objects named are restored
+	fake.restores = nil
+	out, err = f.Command(ctx, "restore", []string{"dir/b"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []commandStatus{{Remote: "dir/b", Status: "OK"}}, out)
+	assert.Equal(t, []string{"dir/b"}, fake.restores)
+
+	// A remote pointing to a file is set up as the parent
+	// directory with a filter for the file as cmd.NewFsSrc does
+	// and only that file should be restored
+	oldActive := filter.Active
+	defer func() {
+		filter.Active = oldActive
+	}()
+	filter.Active, err = filter.NewFilter(nil)
+	require.NoError(t, err)
+	require.NoError(t, filter.Active.AddFile("a"))
+	fake.restores = nil
+	fileF, fileCleanup, err := newFakeFsRoot(t, fake, "bucket/dir/a", nil)
+	defer fileCleanup()
+	require.Equal(t, fs.ErrorIsFile, err)
+	out, err = fileF.Command(ctx, "restore", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []commandStatus{{Remote: "a", Status: "OK"}}, out)
+	assert.Equal(t, []string{"dir/a"}, fake.restores)
+}
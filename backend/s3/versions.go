@@ -0,0 +1,339 @@
+// Support for versioned buckets
+
+package s3
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/lib/version"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/errors"
+)
+
+// Globals
+var (
+	errNotWithVersions = errors.New("can't modify or delete files in --s3-versions or --s3-version-at mode")
+)
+
+// versioned returns true if the Fs is showing object versions with
+// --s3-versions or --s3-version-at, in which case it is read only
+func (f *Fs) versioned() bool {
+	return f.opt.Versions || !f.versionAt.IsZero()
+}
+
+// parseVersionAt parses the --s3-version-at parameter which may be a
+// date, a date and time or a duration before now
+func parseVersionAt(s string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	} {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err == nil {
+			return t, nil
+		}
+	}
+	d, err := fs.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, errors.Errorf("couldn't parse %q as a date, date time or duration", s)
+	}
+	return now.Add(-d), nil
+}
+
+// versionKey returns the key in the bucket for remote
+//
+// Old versions listed with --s3-versions have a version string in
+// their remote which is removed here.
+func (f *Fs) versionKey(remote string, versionID *string) string {
+	if f.opt.Versions && versionID != nil {
+		if t, baseRemote := version.Remove(remote); !t.IsZero() {
+			remote = baseRemote
+		}
+	}
+	return f.root + remote
+}
+
+// bucketKey returns the key of the object in the bucket
+func (o *Object) bucketKey() string {
+	return o.fs.versionKey(o.remote, o.versionID)
+}
+
+// versionEntry is an object version or a delete marker as returned
+// by ListObjectVersions
+type versionEntry struct {
+	object       *s3.Object
+	versionID    *string
+	isLatest     bool
+	deleteMarker bool
+}
+
+// mergeVersions merges the versions and delete markers from one page
+// of a ListObjectVersions call into key order, newest first
+func mergeVersions(versions []*s3.ObjectVersion, deleteMarkers []*s3.DeleteMarkerEntry) []versionEntry {
+	entries := make([]versionEntry, 0, len(versions)+len(deleteMarkers))
+	for _, v := range versions {
+		entries = append(entries, versionEntry{
+			object: &s3.Object{
+				ETag:         v.ETag,
+				Key:          v.Key,
+				LastModified: v.LastModified,
+				Owner:        v.Owner,
+				Size:         v.Size,
+				StorageClass: v.StorageClass,
+			},
+			versionID: v.VersionId,
+			isLatest:  aws.BoolValue(v.IsLatest),
+		})
+	}
+	for _, d := range deleteMarkers {
+		entries = append(entries, versionEntry{
+			object: &s3.Object{
+				Key:          d.Key,
+				LastModified: d.LastModified,
+				Owner:        d.Owner,
+			},
+			versionID:    d.VersionId,
+			isLatest:     aws.BoolValue(d.IsLatest),
+			deleteMarker: true,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		aKey, bKey := aws.StringValue(a.object.Key), aws.StringValue(b.object.Key)
+		if aKey != bKey {
+			return aKey < bKey
+		}
+		if a.isLatest != b.isLatest {
+			return a.isLatest
+		}
+		return aws.TimeValue(a.object.LastModified).After(aws.TimeValue(b.object.LastModified))
+	})
+	return entries
+}
+
+// versionFilter chooses which object versions to show in a listing
+//
+// The versions of a key may be split over more than one page of the
+// listing so this keeps state between pages.
+type versionFilter struct {
+	all     bool      // show all versions as with --s3-versions
+	at      time.Time // otherwise show the versions current at this time
+	lastKey string    // the last key seen
+	keyDone bool      // set if a version of lastKey has been chosen
+}
+
+// newVersionFilter makes a versionFilter
+func newVersionFilter(all bool, at time.Time) *versionFilter {
+	return &versionFilter{
+		all: all,
+		at:  at,
+	}
+}
+
+// filter chooses the versions to show from one page of a
+// ListObjectVersions call, returning the objects and their version IDs
+//
+// If all is set then every version which isn't a delete marker is
+// returned with the version ID set to nil for the latest version.
+//
+// Otherwise the newest version of each key at or before at is
+// returned, unless that is a delete marker.
+func (v *versionFilter) filter(versions []*s3.ObjectVersion, deleteMarkers []*s3.DeleteMarkerEntry) (objects []*s3.Object, versionIDs []*string) {
+	for _, entry := range mergeVersions(versions, deleteMarkers) {
+		if v.all {
+			if entry.deleteMarker {
+				continue
+			}
+			versionID := entry.versionID
+			if entry.isLatest {
+				versionID = nil
+			}
+			objects = append(objects, entry.object)
+			versionIDs = append(versionIDs, versionID)
+			continue
+		}
+		key := aws.StringValue(entry.object.Key)
+		if key != v.lastKey {
+			v.lastKey = key
+			v.keyDone = false
+		}
+		if v.keyDone || aws.TimeValue(entry.object.LastModified).After(v.at) {
+			continue
+		}
+		v.keyDone = true
+		if entry.deleteMarker {
+			continue
+		}
+		objects = append(objects, entry.object)
+		versionIDs = append(versionIDs, entry.versionID)
+	}
+	return objects, versionIDs
+}
+
+// listKeyVersions calls fn for each page of versions of keys starting
+// with prefix
+//
+// If fn returns true then the listing stops.
+func (f *Fs) listKeyVersions(ctx context.Context, prefix string, fn func(resp *s3.ListObjectVersionsOutput) bool) error {
+	var keyMarker, versionIDMarker *string
+	for {
+		req := s3.ListObjectVersionsInput{
+			Bucket:          &f.bucket,
+			Prefix:          &prefix,
+			KeyMarker:       keyMarker,
+			VersionIdMarker: versionIDMarker,
+		}
+		resp, err := f.c.ListObjectVersionsWithContext(ctx, &req)
+		if err != nil {
+			return err
+		}
+		if fn(resp) || !aws.BoolValue(resp.IsTruncated) {
+			return nil
+		}
+		keyMarker, versionIDMarker = resp.NextKeyMarker, resp.NextVersionIdMarker
+	}
+}
+
+// findVersion finds the version of remote to use
+//
+// If t is set it finds the version last modified at t, to the
+// millisecond, as named by --s3-versions. Otherwise it finds the
+// version current at --s3-version-at.
+//
+// It returns fs.ErrorObjectNotFound if there is no such version.
+func (f *Fs) findVersion(ctx context.Context, remote string, t time.Time) (info *s3.Object, versionID *string, err error) {
+	key := f.root + remote
+	filter := newVersionFilter(false, f.versionAt)
+	err = f.listKeyVersions(ctx, key, func(resp *s3.ListObjectVersionsOutput) bool {
+		if t.IsZero() {
+			objects, versionIDs := filter.filter(resp.Versions, resp.DeleteMarkers)
+			for i, object := range objects {
+				if aws.StringValue(object.Key) == key {
+					info, versionID = object, versionIDs[i]
+					return true
+				}
+			}
+		} else {
+			for _, entry := range mergeVersions(resp.Versions, nil) {
+				if aws.StringValue(entry.object.Key) == key && aws.TimeValue(entry.object.LastModified).Truncate(time.Millisecond).Equal(t) {
+					info, versionID = entry.object, entry.versionID
+					return true
+				}
+			}
+		}
+		// stop once we have listed past key
+		return aws.StringValue(resp.NextKeyMarker) > key
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if info == nil {
+		return nil, nil, fs.ErrorObjectNotFound
+	}
+	return info, versionID, nil
+}
+
+// newVersionedObject finds the Object at remote when --s3-versions or
+// --s3-version-at are in use
+func (f *Fs) newVersionedObject(ctx context.Context, remote string) (fs.Object, error) {
+	if f.opt.Versions {
+		t, baseRemote := version.Remove(remote)
+		if !t.IsZero() {
+			info, versionID, err := f.findVersion(ctx, baseRemote, t)
+			if err == nil {
+				return f.newObjectWithInfo(ctx, remote, info, versionID)
+			}
+			if err != fs.ErrorObjectNotFound {
+				return nil, err
+			}
+			// not a version so look for the remote as named
+		}
+		return f.newObjectWithInfo(ctx, remote, nil, nil)
+	}
+	info, versionID, err := f.findVersion(ctx, remote, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return f.newObjectWithInfo(ctx, remote, info, versionID)
+}
+
+// versionInfo describes a version of an object for the versions
+// command
+type versionInfo struct {
+	Remote       string
+	VersionID    string
+	LastModified time.Time
+	Size         int64
+	IsLatest     bool
+	DeleteMarker bool `json:",omitempty"`
+}
+
+// listVersions lists all the versions of the keys under dir
+func (f *Fs) listVersions(ctx context.Context, dir string) (out []versionInfo, err error) {
+	out = []versionInfo{}
+	err = f.listKeyVersions(ctx, f.root+dir, func(resp *s3.ListObjectVersionsOutput) bool {
+		for _, entry := range mergeVersions(resp.Versions, resp.DeleteMarkers) {
+			out = append(out, versionInfo{
+				Remote:       aws.StringValue(entry.object.Key)[len(f.root):],
+				VersionID:    aws.StringValue(entry.versionID),
+				LastModified: aws.TimeValue(entry.object.LastModified),
+				Size:         aws.Int64Value(entry.object.Size),
+				IsLatest:     entry.isLatest,
+				DeleteMarker: entry.deleteMarker,
+			})
+		}
+		return false
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "versions: failed to list versions")
+	}
+	return out, nil
+}
+
+// restoreVersion makes versionID the current version of remote by
+// copying it over the top of the current version
+func (f *Fs) restoreVersion(ctx context.Context, remote, versionID string) (out commandStatus, err error) {
+	src := &Object{
+		fs:        f,
+		remote:    remote,
+		versionID: &versionID,
+	}
+	req := s3.CopyObjectInput{
+		MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+	}
+	err = f.copy(ctx, &req, src.bucketKey(), src)
+	if err != nil {
+		return out, errors.Wrapf(err, "version-restore: failed to restore %q version %q", remote, versionID)
+	}
+	fs.Infof(remote, "Restored version %q", versionID)
+	return commandStatus{Remote: remote, VersionID: versionID, Status: "OK"}, nil
+}
+
+// deleteVersions permanently deletes the versionIDs of remote
+func (f *Fs) deleteVersions(ctx context.Context, remote string, versionIDs []string) (out []commandStatus, err error) {
+	key := f.versionKey(remote, nil)
+	out = make([]commandStatus, 0, len(versionIDs))
+	for _, versionID := range versionIDs {
+		req := s3.DeleteObjectInput{
+			Bucket:    &f.bucket,
+			Key:       &key,
+			VersionId: aws.String(versionID),
+		}
+		st := commandStatus{Remote: remote, VersionID: versionID, Status: "OK"}
+		_, err := f.c.DeleteObjectWithContext(ctx, &req)
+		if err != nil {
+			st.Status = err.Error()
+			fs.Errorf(remote, "Failed to delete version %q: %v", versionID, err)
+		} else {
+			fs.Infof(remote, "Deleted version %q", versionID)
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
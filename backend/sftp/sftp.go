@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/user"
 	"path"
@@ -30,6 +31,7 @@ import (
 	"github.com/pkg/sftp"
 	"github.com/xanzy/ssh-agent"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 	"golang.org/x/time/rate"
 )
 
@@ -46,6 +48,12 @@ func init() {
 		Name:        "sftp",
 		Description: "SSH/SFTP Connection",
 		NewFs:       NewFs,
+		Config: func(name string, m configmap.Mapper) {
+			err := configHostKey(m)
+			if err != nil {
+				fs.Errorf(nil, "Couldn't read the host key fingerprint: %v", err)
+			}
+		},
 		Options: []fs.Option{{
 			Name:     "host",
 			Help:     "SSH host to connect to",
@@ -99,6 +107,37 @@ func init() {
 			Default:  true,
 			Help:     "Set the modified time on the remote if set.",
 			Advanced: true,
+		}, {
+			Name: "known_hosts_file",
+			Help: `Optional path to known_hosts file to validate the server's host key.
+
+Set this to an OpenSSH style known_hosts file to check the host key
+the server presents against it.  A leading ~ and any environment
+variables in the path will be expanded.`,
+			Advanced: true,
+			Examples: []fs.OptionExample{{
+				Value: "~/.ssh/known_hosts",
+				Help:  "Use OpenSSH's known_hosts file",
+			}},
+		}, {
+			Name: "host_key_fingerprint",
+			Help: `SHA256 fingerprint of the server's host key.
+
+This is read from the server and stored by "rclone config" when the
+remote is set up.  If set the host key the server presents must have
+this fingerprint.  Clear it and run "rclone config" again if the
+server's host key has legitimately changed.`,
+			Advanced: true,
+		}, {
+			Name:    "strict_host_key_checking",
+			Default: false,
+			Help: `Refuse to connect if the server's host key can't be verified.
+
+If set rclone won't connect to a server unless its host key is found
+in known_hosts_file or matches host_key_fingerprint.  If not set host
+keys which are unknown are accepted with a warning.  A host key which
+doesn't match is always refused.`,
+			Advanced: true,
 		}},
 	}
 	fs.Register(fsi)
@@ -106,16 +145,19 @@ func init() {
 
 // Options defines the configuration for this backend
 type Options struct {
-	Host              string `config:"host"`
-	User              string `config:"user"`
-	Port              string `config:"port"`
-	Pass              string `config:"pass"`
-	KeyFile           string `config:"key_file"`
-	UseInsecureCipher bool   `config:"use_insecure_cipher"`
-	DisableHashCheck  bool   `config:"disable_hashcheck"`
-	AskPassword       bool   `config:"ask_password"`
-	PathOverride      string `config:"path_override"`
-	SetModTime        bool   `config:"set_modtime"`
+	Host                  string `config:"host"`
+	User                  string `config:"user"`
+	Port                  string `config:"port"`
+	Pass                  string `config:"pass"`
+	KeyFile               string `config:"key_file"`
+	UseInsecureCipher     bool   `config:"use_insecure_cipher"`
+	DisableHashCheck      bool   `config:"disable_hashcheck"`
+	AskPassword           bool   `config:"ask_password"`
+	PathOverride          string `config:"path_override"`
+	SetModTime            bool   `config:"set_modtime"`
+	KnownHostsFile        string `config:"known_hosts_file"`
+	HostKeyFingerprint    string `config:"host_key_fingerprint"`
+	StrictHostKeyChecking bool   `config:"strict_host_key_checking"`
 }
 
 // Fs stores the interface to the remote SFTP files
@@ -128,6 +170,8 @@ type Fs struct {
 	url          string
 	mkdirLock    *stringLock
 	cachedHashes *hash.Set
+	shellMu      sync.Mutex // protects canCopy
+	canCopy      *bool      // set if cp can be run on the remote
 	poolMu       sync.Mutex
 	pool         []*conn
 	connLimit    *rate.Limiter // for limiting number of connections per second
@@ -158,6 +202,156 @@ func readCurrentUser() (userName string) {
 	return os.Getenv("LOGNAME")
 }
 
+// expandPath expands a leading ~ and any environment variables in
+// the path
+func expandPath(p string) string {
+	p = os.ExpandEnv(p)
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if usr, err := user.Current(); err == nil {
+			p = usr.HomeDir + p[1:]
+		} else if home := os.Getenv("HOME"); home != "" {
+			p = home + p[1:]
+		}
+	}
+	return p
+}
+
+// hostKeyCallback makes the ssh.HostKeyCallback which checks the
+// host key the server presents according to the options
+//
+// Warnings about unchecked host keys are logged at NOTICE the first
+// time warnOnce is used and at DEBUG after that so they aren't
+// repeated for every connection.
+func hostKeyCallback(opt *Options, warnOnce *sync.Once) (ssh.HostKeyCallback, error) {
+	warn := func(format string, args ...interface{}) {
+		level := fs.LogLevelDebug
+		warnOnce.Do(func() { level = fs.LogLevelNotice })
+		fs.LogLevelPrintf(level, nil, format, args...)
+	}
+	var knownHostsCallback ssh.HostKeyCallback
+	if opt.KnownHostsFile != "" {
+		var err error
+		knownHostsCallback, err = knownhosts.New(expandPath(opt.KnownHostsFile))
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't read known_hosts_file")
+		}
+	}
+	if knownHostsCallback == nil && opt.HostKeyFingerprint == "" {
+		if opt.StrictHostKeyChecking {
+			return nil, errors.New("strict_host_key_checking is set but neither known_hosts_file nor host_key_fingerprint is configured")
+		}
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			warn("Host key %s for %s not checked as neither known_hosts_file nor host_key_fingerprint is set - accepting it as strict_host_key_checking is not set", ssh.FingerprintSHA256(key), hostname)
+			return nil
+		}, nil
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		fingerprint := ssh.FingerprintSHA256(key)
+		if opt.HostKeyFingerprint != "" && fingerprint != opt.HostKeyFingerprint {
+			return errors.Errorf("host key fingerprint for %s is %s but expecting %s - possible man in the middle attack", hostname, fingerprint, opt.HostKeyFingerprint)
+		}
+		if knownHostsCallback != nil {
+			err := knownHostsCallback(hostname, remote, key)
+			keyErr, isKeyErr := err.(*knownhosts.KeyError)
+			if !isKeyErr || len(keyErr.Want) != 0 {
+				// matched, mismatched or some other error
+				return err
+			}
+			// The host isn't in the known_hosts file
+			if opt.HostKeyFingerprint == "" {
+				if opt.StrictHostKeyChecking {
+					return errors.Errorf("host key %s for %s not found in %q", fingerprint, hostname, opt.KnownHostsFile)
+				}
+				warn("Host key %s for %s not found in %q - accepting it as strict_host_key_checking is not set", fingerprint, hostname, opt.KnownHostsFile)
+			}
+		}
+		return nil
+	}, nil
+}
+
+// errHostKeyRead is used to abort the connection once the host key
+// has been read
+var errHostKeyRead = errors.New("host key read")
+
+// readHostKey connects to the server and returns the host key it
+// presents without authenticating
+func readHostKey(opt *Options) (ssh.PublicKey, error) {
+	var hostKey ssh.PublicKey
+	sshConfig := &ssh.ClientConfig{
+		User: opt.User,
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			return errHostKeyRead
+		},
+		Timeout: fs.Config.ConnectTimeout,
+	}
+	if opt.UseInsecureCipher {
+		sshConfig.Config.SetDefaults()
+		sshConfig.Config.Ciphers = append(sshConfig.Config.Ciphers, "aes128-cbc")
+	}
+	c, err := Dial("tcp", opt.Host+":"+opt.Port, sshConfig)
+	if err == nil {
+		_ = c.Close()
+	}
+	if hostKey == nil {
+		if err == nil {
+			err = errors.New("server didn't present a host key")
+		}
+		return nil, errors.Wrap(err, "couldn't read host key")
+	}
+	return hostKey, nil
+}
+
+// configHostKey reads the host key from the server and stores its
+// fingerprint in the config if it isn't set already
+func configHostKey(m configmap.Mapper) error {
+	opt, err := parseOptions(m)
+	if err != nil {
+		return err
+	}
+	if opt.HostKeyFingerprint != "" {
+		return nil
+	}
+	key, err := readHostKey(opt)
+	if err != nil {
+		return err
+	}
+	fingerprint := ssh.FingerprintSHA256(key)
+	if opt.KnownHostsFile != "" {
+		// Check the key against the known_hosts file before trusting it
+		checkOpt := *opt
+		checkOpt.StrictHostKeyChecking = false
+		callback, err := hostKeyCallback(&checkOpt, new(sync.Once))
+		if err != nil {
+			return err
+		}
+		err = callback(opt.Host+":"+opt.Port, &net.TCPAddr{}, key)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Host key fingerprint for %s is %s (%s)\n", opt.Host, fingerprint, key.Type())
+	m.Set("host_key_fingerprint", fingerprint)
+	return nil
+}
+
+// parseOptions parses the config into an Options struct and fills
+// in the defaults
+func parseOptions(m configmap.Mapper) (*Options, error) {
+	opt := new(Options)
+	err := configstruct.Set(m, opt)
+	if err != nil {
+		return nil, err
+	}
+	if opt.User == "" {
+		opt.User = currentUser
+	}
+	if opt.Port == "" {
+		opt.Port = "22"
+	}
+	return opt, nil
+}
+
 // Dial starts a client connection to the given SSH server. It is a
 // convenience function that connects to the given network address,
 // initiates the SSH handshake, and then sets up a Client.
@@ -290,22 +484,21 @@ func (f *Fs) putSftpConnection(pc **conn, err error) {
 // NewFs creates a new Fs object from the name and root. It connects to
 // the host specified in the config file.
 func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
+	ctx := context.Background()
 	// Parse config into Options struct
-	opt := new(Options)
-	err := configstruct.Set(m, opt)
+	opt, err := parseOptions(m)
 	if err != nil {
 		return nil, err
 	}
-	if opt.User == "" {
-		opt.User = currentUser
-	}
-	if opt.Port == "" {
-		opt.Port = "22"
+	// Only warn about the host key once for this Fs
+	keyCallback, err := hostKeyCallback(opt, new(sync.Once))
+	if err != nil {
+		return nil, err
 	}
 	sshConfig := &ssh.ClientConfig{
 		User:            opt.User,
 		Auth:            []ssh.AuthMethod{},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		HostKeyCallback: keyCallback,
 		Timeout:         fs.Config.ConnectTimeout,
 	}
 
@@ -381,7 +574,7 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 		if f.root == "." {
 			f.root = ""
 		}
-		_, err := f.NewObject(remote)
+		_, err := f.NewObject(ctx, remote)
 		if err != nil {
 			if err == fs.ErrorObjectNotFound || errors.Cause(err) == fs.ErrorNotAFile {
 				// File doesn't exist so return old f
@@ -422,7 +615,7 @@ func (f *Fs) Precision() time.Duration {
 }
 
 // NewObject creates a new remote sftp file object
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	o := &Object{
 		fs:     f,
 		remote: remote,
@@ -467,7 +660,7 @@ func (f *Fs) dirExists(dir string) (bool, error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	root := path.Join(f.root, dir)
 	ok, err := f.dirExists(root)
 	if err != nil {
@@ -515,7 +708,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 }
 
 // Put data from <in> into a new remote sftp file object described by <src.Remote()> and <src.ModTime()>
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	err := f.mkParentDir(src.Remote())
 	if err != nil {
 		return nil, errors.Wrap(err, "Put mkParentDir failed")
@@ -525,7 +718,7 @@ func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.
 		fs:     f,
 		remote: src.Remote(),
 	}
-	err = o.Update(in, src, options...)
+	err = o.Update(ctx, in, src, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -533,8 +726,8 @@ func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // mkParentDir makes the parent of remote if necessary and any
@@ -576,13 +769,13 @@ func (f *Fs) mkdir(dirPath string) error {
 }
 
 // Mkdir makes the root directory of the Fs object
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	root := path.Join(f.root, dir)
 	return f.mkdir(root)
 }
 
 // Rmdir removes the root directory of the Fs object
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	root := path.Join(f.root, dir)
 	c, err := f.getSftpConnection()
 	if err != nil {
@@ -594,7 +787,7 @@ func (f *Fs) Rmdir(dir string) error {
 }
 
 // Move renames a remote sftp file object
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	srcObj, ok := src.(*Object)
 	if !ok {
 		fs.Debugf(src, "Can't move - not same remote type")
@@ -616,13 +809,94 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "Move Rename failed")
 	}
-	dstObj, err := f.NewObject(remote)
+	dstObj, err := f.NewObject(ctx, remote)
 	if err != nil {
 		return nil, errors.Wrap(err, "Move NewObject failed")
 	}
 	return dstObj, nil
 }
 
+// Copy src to this remote using server side copy operations.
+//
+// This runs cp on the remote end so needs shell access.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantCopy
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	srcObj, ok := src.(*Object)
+	if !ok {
+		fs.Debugf(src, "Can't copy - not same remote type")
+		return nil, fs.ErrorCantCopy
+	}
+	if !f.shellCanCopy() {
+		fs.Debugf(src, "Can't copy - can't run cp on the remote")
+		return nil, fs.ErrorCantCopy
+	}
+	err := f.mkParentDir(remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "Copy mkParentDir failed")
+	}
+	c, err := f.getSftpConnection()
+	if err != nil {
+		return nil, errors.Wrap(err, "Copy")
+	}
+	session, err := c.sshClient.NewSession()
+	f.putSftpConnection(&c, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "Copy NewSession failed")
+	}
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+	srcPath := shellEscape(srcObj.fs.shellPath(srcObj.remote))
+	dstPath := shellEscape(f.shellPath(remote))
+	err = session.Run("cp -p -- " + srcPath + " " + dstPath)
+	_ = session.Close()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Copy cp failed: %s", bytes.TrimSpace(stderr.Bytes()))
+	}
+	dstObj, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "Copy NewObject failed")
+	}
+	return dstObj, nil
+}
+
+// shellCanCopy returns whether cp can be run on the remote for server
+// side copies.
+//
+// The result is cached unless the connection fails.
+func (f *Fs) shellCanCopy() bool {
+	f.shellMu.Lock()
+	defer f.shellMu.Unlock()
+	if f.canCopy != nil {
+		return *f.canCopy
+	}
+	c, err := f.getSftpConnection()
+	if err != nil {
+		fs.Errorf(f, "Couldn't get SSH connection to check for cp: %v", err)
+		return false
+	}
+	session, err := c.sshClient.NewSession()
+	f.putSftpConnection(&c, err)
+	if err != nil {
+		fs.Errorf(f, "Couldn't make SSH session to check for cp: %v", err)
+		return false
+	}
+	output, err := session.Output("command -v cp")
+	_ = session.Close()
+	canCopy := err == nil && len(bytes.TrimSpace(output)) != 0
+	if !canCopy {
+		fs.Debugf(f, "Server side copy disabled as cp can't be run on the remote: %v", err)
+	}
+	f.canCopy = &canCopy
+	return canCopy
+}
+
 // DirMove moves src, srcRemote to this remote at dstRemote
 // using server side move operations.
 //
@@ -631,7 +905,7 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	srcFs, ok := src.(*Fs)
 	if !ok {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
@@ -771,10 +1045,7 @@ func (o *Object) Hash(r hash.Type) (string, error) {
 	var stdout, stderr bytes.Buffer
 	session.Stdout = &stdout
 	session.Stderr = &stderr
-	escapedPath := shellEscape(o.path())
-	if o.fs.opt.PathOverride != "" {
-		escapedPath = shellEscape(path.Join(o.fs.opt.PathOverride, o.remote))
-	}
+	escapedPath := shellEscape(o.fs.shellPath(o.remote))
 	err = session.Run(hashCmd + " " + escapedPath)
 	if err != nil {
 		_ = session.Close()
@@ -823,6 +1094,15 @@ func (o *Object) path() string {
 	return path.Join(o.fs.root, o.remote)
 }
 
+// shellPath returns the path of remote for use in commands run over
+// SSH
+func (f *Fs) shellPath(remote string) string {
+	if f.opt.PathOverride != "" {
+		return path.Join(f.opt.PathOverride, remote)
+	}
+	return path.Join(f.root, remote)
+}
+
 // setMetadata updates the info in the object from the stat result passed in
 func (o *Object) setMetadata(info os.FileInfo) {
 	o.modTime = info.ModTime()
@@ -932,7 +1212,7 @@ func (file *objectReader) Close() (err error) {
 }
 
 // Open a remote sftp file object for reading. Seek is supported
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	var offset, limit int64 = 0, -1
 	for _, option := range options {
 		switch x := option.(type) {
@@ -966,7 +1246,7 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 }
 
 // Update a remote sftp file using the data <in> and ModTime from <src>
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
 	// Clear the hash cache since we are about to update the object
 	o.md5sum = nil
 	o.sha1sum = nil
@@ -1012,7 +1292,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 }
 
 // Remove a remote sftp file object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	c, err := o.fs.getSftpConnection()
 	if err != nil {
 		return errors.Wrap(err, "Remove")
@@ -1026,6 +1306,7 @@ func (o *Object) Remove() error {
 var (
 	_ fs.Fs          = &Fs{}
 	_ fs.PutStreamer = &Fs{}
+	_ fs.Copier      = &Fs{}
 	_ fs.Mover       = &Fs{}
 	_ fs.DirMover    = &Fs{}
 	_ fs.Object      = &Object{}
@@ -3,10 +3,24 @@
 package sftp
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
 	"testing"
 
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/configmap"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 func TestShellEscape(t *testing.T) {
@@ -35,3 +49,179 @@ func TestParseHash(t *testing.T) {
 		assert.Equal(t, test.checksum, got, fmt.Sprintf("Test %d sshOutput = %q", i, test.sshOutput))
 	}
 }
+
+func TestShellPath(t *testing.T) {
+	for i, test := range []struct {
+		root, pathOverride, remote, want string
+	}{
+		{"", "", "file.txt", "file.txt"},
+		{"/home/user", "", "dir/file.txt", "/home/user/dir/file.txt"},
+		{"home/user", "/volume1/homes/user", "dir/file.txt", "/volume1/homes/user/dir/file.txt"},
+	} {
+		f := &Fs{root: test.root, opt: Options{PathOverride: test.pathOverride}}
+		got := f.shellPath(test.remote)
+		assert.Equal(t, test.want, got, fmt.Sprintf("Test %d root=%q pathOverride=%q", i, test.root, test.pathOverride))
+	}
+}
+
+// makeHostKey makes a new host key for testing
+func makeHostKey(t *testing.T) ssh.Signer {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	require.NoError(t, err)
+	return signer
+}
+
+// writeKnownHosts writes a known_hosts file with key for address
+func writeKnownHosts(t *testing.T, dir, address string, key ssh.PublicKey) string {
+	knownHostsFile := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(address)}, key)
+	require.NoError(t, ioutil.WriteFile(knownHostsFile, []byte(line+"\n"), 0600))
+	return knownHostsFile
+}
+
+func TestHostKeyCallback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-sftp-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	const address = "example.com:22"
+	remoteAddr := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 22}
+	key := makeHostKey(t).PublicKey()
+	otherKey := makeHostKey(t).PublicKey()
+	knownHostsFile := writeKnownHosts(t, dir, address, key)
+
+	for _, test := range []struct {
+		name    string
+		opt     Options
+		key     ssh.PublicKey
+		wantErr string
+	}{
+		{name: "NoChecks", opt: Options{}, key: key},
+		{name: "NoChecksStrict", opt: Options{StrictHostKeyChecking: true}, wantErr: "neither known_hosts_file nor host_key_fingerprint"},
+		{name: "KnownHostsOK", opt: Options{KnownHostsFile: knownHostsFile}, key: key},
+		{name: "KnownHostsMismatch", opt: Options{KnownHostsFile: knownHostsFile}, key: otherKey, wantErr: "key mismatch"},
+		{name: "FingerprintOK", opt: Options{HostKeyFingerprint: ssh.FingerprintSHA256(key)}, key: key},
+		{name: "FingerprintMismatch", opt: Options{HostKeyFingerprint: ssh.FingerprintSHA256(key)}, key: otherKey, wantErr: "possible man in the middle attack"},
+		{name: "MissingKnownHostsFile", opt: Options{KnownHostsFile: filepath.Join(dir, "notfound")}, wantErr: "couldn't read known_hosts_file"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			callback, err := hostKeyCallback(&test.opt, new(sync.Once))
+			if test.key == nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			err = callback(address, remoteAddr, test.key)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+			}
+		})
+	}
+
+	// A host which isn't in the known_hosts file
+	const unknownAddress = "unknown.example.com:22"
+	callback, err := hostKeyCallback(&Options{KnownHostsFile: knownHostsFile}, new(sync.Once))
+	require.NoError(t, err)
+	assert.NoError(t, callback(unknownAddress, remoteAddr, otherKey))
+
+	callback, err = hostKeyCallback(&Options{KnownHostsFile: knownHostsFile, StrictHostKeyChecking: true}, new(sync.Once))
+	require.NoError(t, err)
+	err = callback(unknownAddress, remoteAddr, otherKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found in")
+
+	// ...but is known by its fingerprint
+	callback, err = hostKeyCallback(&Options{KnownHostsFile: knownHostsFile, StrictHostKeyChecking: true, HostKeyFingerprint: ssh.FingerprintSHA256(otherKey)}, new(sync.Once))
+	require.NoError(t, err)
+	assert.NoError(t, callback(unknownAddress, remoteAddr, otherKey))
+}
+
+func TestHostKeyCallbackWarning(t *testing.T) {
+	var logged []string
+	var levels []fs.LogLevel
+	oldLogPrint, oldLogLevel := fs.LogPrint, fs.Config.LogLevel
+	fs.LogPrint = func(level fs.LogLevel, text string) {
+		logged = append(logged, text)
+		levels = append(levels, level)
+	}
+	fs.Config.LogLevel = fs.LogLevelDebug
+	defer func() {
+		fs.LogPrint, fs.Config.LogLevel = oldLogPrint, oldLogLevel
+	}()
+
+	key := makeHostKey(t).PublicKey()
+	callback, err := hostKeyCallback(&Options{}, new(sync.Once))
+	require.NoError(t, err)
+	require.NoError(t, callback("example.com:22", &net.TCPAddr{}, key))
+	require.Equal(t, 1, len(logged))
+	assert.Contains(t, logged[0], ssh.FingerprintSHA256(key))
+	assert.Contains(t, logged[0], "not checked")
+	assert.Equal(t, fs.LogLevelNotice, levels[0])
+
+	// Later connections only warn at debug
+	require.NoError(t, callback("example.com:22", &net.TCPAddr{}, key))
+	require.Equal(t, 2, len(logged))
+	assert.Equal(t, logged[0], logged[1])
+	assert.Equal(t, fs.LogLevelDebug, levels[1])
+}
+
+func TestExpandPath(t *testing.T) {
+	usr, err := user.Current()
+	require.NoError(t, err)
+	assert.Equal(t, "/path/to/file", expandPath("/path/to/file"))
+	assert.Equal(t, usr.HomeDir+"/.ssh/known_hosts", expandPath("~/.ssh/known_hosts"))
+	assert.Equal(t, "~user/file", expandPath("~user/file"))
+	require.NoError(t, os.Setenv("RCLONE_SFTP_TEST_DIR", "/test/dir"))
+	defer func() {
+		_ = os.Unsetenv("RCLONE_SFTP_TEST_DIR")
+	}()
+	assert.Equal(t, "/test/dir/known_hosts", expandPath("$RCLONE_SFTP_TEST_DIR/known_hosts"))
+}
+
+// serveHostKey runs an SSH server which presents hostKey to a single
+// client then closes, returning the port it is listening on
+func serveHostKey(t *testing.T, hostKey ssh.Signer) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(hostKey)
+	go func() {
+		defer func() {
+			_ = listener.Close()
+		}()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		_, _, _, _ = ssh.NewServerConn(conn, config)
+		_ = conn.Close()
+	}()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return port
+}
+
+func TestConfigHostKey(t *testing.T) {
+	hostKey := makeHostKey(t)
+	port := serveHostKey(t, hostKey)
+
+	m := configmap.Simple{
+		"host": "127.0.0.1",
+		"port": port,
+	}
+	require.NoError(t, configHostKey(m))
+	assert.Equal(t, ssh.FingerprintSHA256(hostKey.PublicKey()), m["host_key_fingerprint"])
+
+	// Doesn't connect if already set
+	m["host_key_fingerprint"] = "potato"
+	require.NoError(t, configHostKey(m))
+	assert.Equal(t, "potato", m["host_key_fingerprint"])
+}
@@ -0,0 +1,104 @@
+package union
+
+import (
+	"context"
+	"strings"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+// errNoWritable is returned when there are no upstreams to create
+// anything on
+var errNoWritable = errors.New("no writable remotes in union")
+
+// create chooses the upstream to create remote on using the create
+// policy
+func (f *Fs) create(ctx context.Context, remote string) (*upstream, error) {
+	switch f.opt.CreatePolicy {
+	case "mfs":
+		return f.mostFreeSpace()
+	case "epff":
+		return f.existingPathFirstFound(ctx, remote)
+	}
+	return f.firstFound()
+}
+
+// firstFound returns the first writable upstream
+func (f *Fs) firstFound() (*upstream, error) {
+	writable := f.writable()
+	if len(writable) == 0 {
+		return nil, errNoWritable
+	}
+	return writable[0], nil
+}
+
+// mostFreeSpace returns the writable upstream with the most free
+// space.  Upstreams which can't report their free space are ignored
+// unless none of them can.
+func (f *Fs) mostFreeSpace() (*upstream, error) {
+	var (
+		best     *upstream
+		bestFree int64
+	)
+	for _, u := range f.writable() {
+		do := u.Features().About
+		if do == nil {
+			continue
+		}
+		usage, err := do()
+		if err != nil {
+			fs.Debugf(u, "Ignoring for create policy: failed to read free space: %v", err)
+			continue
+		}
+		if usage.Free == nil {
+			continue
+		}
+		if best == nil || *usage.Free > bestFree {
+			best = u
+			bestFree = *usage.Free
+		}
+	}
+	if best == nil {
+		return f.firstFound()
+	}
+	return best, nil
+}
+
+// existingDepth returns the number of levels of dir which exist on u,
+// 0 if only the root exists or -1 if not even the root exists
+func existingDepth(ctx context.Context, u *upstream, dir string) int {
+	for {
+		if dirExists(ctx, u, dir) {
+			if dir == "" {
+				return 0
+			}
+			return strings.Count(dir, "/") + 1
+		}
+		if dir == "" {
+			return -1
+		}
+		dir = parentDir(dir)
+	}
+}
+
+// existingPathFirstFound returns the first writable upstream which
+// has the most of the parent directories of remote already.
+func (f *Fs) existingPathFirstFound(ctx context.Context, remote string) (*upstream, error) {
+	var (
+		best      *upstream
+		bestDepth = -1
+	)
+	dir := parentDir(remote)
+	for _, u := range f.writable() {
+		depth := existingDepth(ctx, u, dir)
+		if depth > bestDepth {
+			best = u
+			bestDepth = depth
+		}
+	}
+	if best == nil {
+		return f.firstFound()
+	}
+	return best, nil
+}
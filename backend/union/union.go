@@ -0,0 +1,708 @@
+// Package union implements a virtual provider to join existing remotes.
+package union
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/configmap"
+	"github.com/artpar/rclone/fs/config/configstruct"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/pkg/errors"
+)
+
+// Register with Fs
+func init() {
+	fsi := &fs.RegInfo{
+		Name:        "union",
+		Description: "Union merges the contents of several remotes",
+		NewFs:       NewFs,
+		Options: []fs.Option{{
+			Name:     "remotes",
+			Help:     "List of space separated remotes.\nCan be 'remotea:test/dir remoteb:', '\"remotea:test/space dir\" remoteb:', etc.\nAdd ':ro' to the end of a remote to make it read only.\nReads are from the first remote which has the file.",
+			Required: true,
+		}, {
+			Name:    "create_policy",
+			Help:    "Policy to choose which remote new files and directories are created on.",
+			Default: "epff",
+			Examples: []fs.OptionExample{{
+				Value: "ff",
+				Help:  "First found - the first writable remote.",
+			}, {
+				Value: "mfs",
+				Help:  "Most free space - the writable remote with the most free space.",
+			}, {
+				Value: "epff",
+				Help:  "Existing path, first found - the first writable remote with the most of the path already.",
+			}},
+		}},
+	}
+	fs.Register(fsi)
+}
+
+// Options defines the configuration for this backend
+type Options struct {
+	Remotes      string `config:"remotes"`
+	CreatePolicy string `config:"create_policy"`
+}
+
+// upstream is one of the remotes making up the union
+type upstream struct {
+	fs.Fs
+	readOnly bool // set if nothing should be written to this remote
+}
+
+// Fs represents a union of remotes
+type Fs struct {
+	name      string       // name of this remote
+	root      string       // the path we are working on
+	opt       Options      // options for this Fs
+	features  *fs.Features // optional features
+	upstreams []*upstream  // the remotes in priority order
+	hashSet   hash.Set     // intersection of hash types
+	precision time.Duration
+}
+
+// parseRemotes splits the remotes option up into its parts, allowing
+// double quotes around remotes with spaces in
+func parseRemotes(remotes string) (out []string, err error) {
+	var (
+		cur     []rune
+		inQuote bool
+		started bool
+	)
+	for _, c := range remotes {
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+			started = true
+		case !inQuote && (c == ' ' || c == '\t' || c == '\n'):
+			if started {
+				out = append(out, string(cur))
+			}
+			cur, started = nil, false
+		default:
+			cur = append(cur, c)
+			started = true
+		}
+	}
+	if inQuote {
+		return nil, errors.Errorf("unterminated quote in remotes %q", remotes)
+	}
+	if started {
+		out = append(out, string(cur))
+	}
+	return out, nil
+}
+
+// newUpstream makes the upstream for remote with root appended
+//
+// It may return fs.ErrorIsFile along with a valid upstream
+func newUpstream(name, remote, root string) (*upstream, error) {
+	u := &upstream{}
+	if strings.HasSuffix(remote, ":ro") {
+		u.readOnly = true
+		remote = strings.TrimSuffix(remote, ":ro")
+	}
+	if strings.HasPrefix(remote, name+":") {
+		return nil, errors.New("can't point union remote at itself - check the value of the remotes setting")
+	}
+	_, configName, fsPath, err := fs.ParseRemote(remote)
+	if err != nil {
+		return nil, err
+	}
+	rootPath := path.Join(fsPath, root)
+	if configName != "local" {
+		rootPath = configName + ":" + rootPath
+	}
+	u.Fs, err = fs.NewFs(rootPath)
+	if err != nil && err != fs.ErrorIsFile {
+		return nil, errors.Wrapf(err, "failed to make remote %q to union", remote)
+	}
+	return u, err
+}
+
+// NewFs constructs an Fs from the path.
+//
+// The returned Fs is the actual Fs, referenced by remote in the config
+func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
+	// Parse config into Options struct
+	opt := new(Options)
+	err := configstruct.Set(m, opt)
+	if err != nil {
+		return nil, err
+	}
+	remotes, err := parseRemotes(opt.Remotes)
+	if err != nil {
+		return nil, err
+	}
+	if len(remotes) == 0 {
+		return nil, errors.New("union can't point to an empty list of remotes - check the value of the remotes setting")
+	}
+	switch opt.CreatePolicy {
+	case "ff", "mfs", "epff":
+	default:
+		return nil, errors.Errorf("unknown create_policy %q", opt.CreatePolicy)
+	}
+
+	f := &Fs{
+		name: name,
+		root: root,
+		opt:  *opt,
+	}
+
+	// Make the upstreams, starting again from the parent if the
+	// root turns out to be a file on any of them
+	var isFile bool
+	for {
+		f.upstreams = f.upstreams[:0]
+		for _, remote := range remotes {
+			u, err := newUpstream(name, remote, f.root)
+			if err == fs.ErrorIsFile {
+				isFile = true
+			} else if err != nil {
+				return nil, err
+			}
+			f.upstreams = append(f.upstreams, u)
+		}
+		if !isFile || f.root != root {
+			break
+		}
+		f.root = parentDir(root)
+	}
+
+	f.hashSet = hash.Supported
+	for _, u := range f.upstreams {
+		f.hashSet = f.hashSet.Overlap(u.Hashes())
+		if u.Precision() > f.precision {
+			f.precision = u.Precision()
+		}
+	}
+
+	// the features here are ones we could support, and they are
+	// ANDed with the ones from the upstreams
+	f.features = (&fs.Features{
+		CaseInsensitive:         true,
+		DuplicateFiles:          false,
+		ReadMimeType:            true,
+		WriteMimeType:           true,
+		CanHaveEmptyDirectories: true,
+		BucketBased:             true,
+	}).Fill(f)
+	for _, u := range f.upstreams {
+		f.features = f.features.Mask(u)
+	}
+	// About is added up over the upstreams so only one needs it
+	for _, u := range f.upstreams {
+		if u.Features().About != nil {
+			f.features.About = f.About
+			break
+		}
+	}
+
+	if isFile {
+		return f, fs.ErrorIsFile
+	}
+	return f, nil
+}
+
+// parentDir returns the parent directory of remote or "" for the root
+func parentDir(remote string) string {
+	parent := path.Dir(remote)
+	if parent == "." || parent == "/" {
+		parent = ""
+	}
+	return parent
+}
+
+// dirExists returns whether dir exists on u
+func dirExists(ctx context.Context, u *upstream, dir string) bool {
+	_, err := u.List(ctx, dir)
+	return err == nil
+}
+
+// writable returns the upstreams which can be written to
+func (f *Fs) writable() (out []*upstream) {
+	for _, u := range f.upstreams {
+		if !u.readOnly {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// matchUpstream returns the upstream of f which corresponds to u in
+// srcFs, which should be made from the same config as f, or nil if
+// not found
+func (f *Fs) matchUpstream(srcFs *Fs, u *upstream) *upstream {
+	if len(srcFs.upstreams) != len(f.upstreams) {
+		return nil
+	}
+	for i := range srcFs.upstreams {
+		if srcFs.upstreams[i] == u {
+			return f.upstreams[i]
+		}
+	}
+	return nil
+}
+
+// Name of the remote (as passed into NewFs)
+func (f *Fs) Name() string {
+	return f.name
+}
+
+// Root of the remote (as passed into NewFs)
+func (f *Fs) Root() string {
+	return f.root
+}
+
+// String converts this Fs to a string
+func (f *Fs) String() string {
+	return fmt.Sprintf("union root '%s'", f.root)
+}
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features {
+	return f.features
+}
+
+// Precision is the coarsest precision of the upstreams
+func (f *Fs) Precision() time.Duration {
+	return f.precision
+}
+
+// Hashes returns the hash types supported by all the upstreams
+func (f *Fs) Hashes() hash.Set {
+	return f.hashSet
+}
+
+// List the objects and directories in dir into entries.  The
+// entries can be returned in any order but should be for a
+// complete directory.
+//
+// dir should be "" to list the root, and should not have
+// trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+//
+// Entries in earlier upstreams hide entries with the same name in
+// later ones.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	found := false
+	seen := make(map[string]struct{})
+	for _, u := range f.upstreams {
+		uEntries, err := u.List(ctx, dir)
+		if err == fs.ErrorDirNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		for _, entry := range uEntries {
+			remote := entry.Remote()
+			if _, ok := seen[remote]; ok {
+				continue
+			}
+			seen[remote] = struct{}{}
+			switch x := entry.(type) {
+			case fs.Object:
+				entries = append(entries, f.newObject(x, u))
+			case fs.Directory:
+				entries = append(entries, x)
+			default:
+				return nil, errors.Errorf("unknown object type %T", entry)
+			}
+		}
+	}
+	if !found {
+		return nil, fs.ErrorDirNotFound
+	}
+	return entries, nil
+}
+
+// NewObject finds the Object at remote in the first upstream which
+// has it.  If it can't be found it returns the error
+// fs.ErrorObjectNotFound.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	for _, u := range f.upstreams {
+		o, err := u.NewObject(ctx, remote)
+		if err == fs.ErrorObjectNotFound || err == fs.ErrorNotAFile {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return f.newObject(o, u), nil
+	}
+	return nil, fs.ErrorObjectNotFound
+}
+
+// putUpstream chooses the upstream to write remote to.  This is the
+// upstream the object is on already if it exists, otherwise it is
+// chosen by the create policy.
+func (f *Fs) putUpstream(ctx context.Context, remote string) (*upstream, error) {
+	o, err := f.NewObject(ctx, remote)
+	if err == nil {
+		u := o.(*Object).u
+		if u.readOnly {
+			return nil, errors.Errorf("can't overwrite %q: it is on read only remote %v", remote, u)
+		}
+		return u, nil
+	}
+	if err != fs.ErrorObjectNotFound {
+		return nil, err
+	}
+	return f.create(ctx, remote)
+}
+
+// Put in to the remote path with the modTime given of the given size
+//
+// May create the object even if it returns an error - if so
+// will return the object and the error, otherwise will return
+// nil and the error
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	u, err := f.putUpstream(ctx, src.Remote())
+	if err != nil {
+		return nil, err
+	}
+	o, err := u.Put(ctx, in, src, options...)
+	if o == nil {
+		return nil, err
+	}
+	return f.newObject(o, u), err
+}
+
+// PutStream uploads to the remote path with the modTime given of indeterminate size
+//
+// May create the object even if it returns an error - if so
+// will return the object and the error, otherwise will return
+// nil and the error
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	u, err := f.putUpstream(ctx, src.Remote())
+	if err != nil {
+		return nil, err
+	}
+	do := u.Features().PutStream
+	if do == nil {
+		return nil, errors.Errorf("remote %v can't stream uploads", u)
+	}
+	o, err := do(ctx, in, src, options...)
+	if o == nil {
+		return nil, err
+	}
+	return f.newObject(o, u), err
+}
+
+// Mkdir makes the directory on the upstream chosen by the create
+// policy, unless it exists already.  The root is made on all the
+// writable upstreams.
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	if dir == "" {
+		for _, u := range f.writable() {
+			err := u.Mkdir(ctx, dir)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, u := range f.upstreams {
+		if dirExists(ctx, u, dir) {
+			return nil
+		}
+	}
+	u, err := f.create(ctx, dir)
+	if err != nil {
+		return err
+	}
+	return u.Mkdir(ctx, dir)
+}
+
+// Rmdir removes the directory from all the upstreams which have it
+//
+// Return an error if it doesn't exist or isn't empty
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	var found []*upstream
+	for _, u := range f.upstreams {
+		if !dirExists(ctx, u, dir) {
+			continue
+		}
+		if u.readOnly {
+			return errors.Errorf("can't remove directory %q: it is on read only remote %v", dir, u)
+		}
+		found = append(found, u)
+	}
+	if len(found) == 0 {
+		return fs.ErrorDirNotFound
+	}
+	for _, u := range found {
+		err := u.Rmdir(ctx, dir)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Purge all files in the root and the root directory of all the
+// writable upstreams which have it
+//
+// Return an error if it doesn't exist
+func (f *Fs) Purge(ctx context.Context) error {
+	var found []*upstream
+	for _, u := range f.writable() {
+		if u.Features().Purge == nil {
+			return fs.ErrorCantPurge
+		}
+		if dirExists(ctx, u, "") {
+			found = append(found, u)
+		}
+	}
+	if len(found) == 0 {
+		return fs.ErrorDirNotFound
+	}
+	for _, u := range found {
+		err := u.Features().Purge(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy src to this remote using server side copy operations.
+//
+// The copy is done on the upstream the source is on.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantCopy
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	srcObj, ok := src.(*Object)
+	if !ok {
+		fs.Debugf(src, "Can't copy - not same remote type")
+		return nil, fs.ErrorCantCopy
+	}
+	u := f.matchUpstream(srcObj.f, srcObj.u)
+	if u == nil || u.readOnly {
+		return nil, fs.ErrorCantCopy
+	}
+	do := u.Features().Copy
+	if do == nil {
+		return nil, fs.ErrorCantCopy
+	}
+	o, err := do(ctx, srcObj.Object, remote)
+	if err != nil {
+		return nil, err
+	}
+	return f.newObject(o, u), nil
+}
+
+// Move src to this remote using server side move operations.
+//
+// The move is done on the upstream the source is on.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantMove
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	srcObj, ok := src.(*Object)
+	if !ok {
+		fs.Debugf(src, "Can't move - not same remote type")
+		return nil, fs.ErrorCantMove
+	}
+	u := f.matchUpstream(srcObj.f, srcObj.u)
+	if u == nil || u.readOnly {
+		return nil, fs.ErrorCantMove
+	}
+	do := u.Features().Move
+	if do == nil {
+		return nil, fs.ErrorCantMove
+	}
+	o, err := do(ctx, srcObj.Object, remote)
+	if err != nil {
+		return nil, err
+	}
+	return f.newObject(o, u), nil
+}
+
+// DirMove moves src, srcRemote to this remote at dstRemote
+// using server side move operations on each upstream which has it.
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantDirMove
+//
+// If destination exists then return fs.ErrorDirExists
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
+	srcFs, ok := src.(*Fs)
+	if !ok || len(srcFs.upstreams) != len(f.upstreams) {
+		fs.Debugf(srcFs, "Can't move directory - not same remote type")
+		return fs.ErrorCantDirMove
+	}
+	// Check everything first so we don't leave a partial move
+	var moves []int
+	for i, srcU := range srcFs.upstreams {
+		dstU := f.upstreams[i]
+		if dirExists(ctx, dstU, dstRemote) {
+			return fs.ErrorDirExists
+		}
+		if !dirExists(ctx, srcU, srcRemote) {
+			continue
+		}
+		if srcU.readOnly || dstU.Features().DirMove == nil {
+			return fs.ErrorCantDirMove
+		}
+		moves = append(moves, i)
+	}
+	if len(moves) == 0 {
+		return fs.ErrorDirNotFound
+	}
+	for _, i := range moves {
+		err := f.upstreams[i].Features().DirMove(ctx, srcFs.upstreams[i].Fs, srcRemote, dstRemote)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// About gets quota information from the Fs by adding up the usage
+// of all the upstreams which support it
+func (f *Fs) About() (*fs.Usage, error) {
+	usage := &fs.Usage{}
+	found := false
+	add := func(total **int64, value *int64) {
+		if value == nil {
+			return
+		}
+		if *total == nil {
+			*total = new(int64)
+		}
+		**total += *value
+	}
+	for _, u := range f.upstreams {
+		do := u.Features().About
+		if do == nil {
+			continue
+		}
+		uUsage, err := do()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read usage of %v", u)
+		}
+		found = true
+		add(&usage.Total, uUsage.Total)
+		add(&usage.Used, uUsage.Used)
+		add(&usage.Trashed, uUsage.Trashed)
+		add(&usage.Other, uUsage.Other)
+		add(&usage.Free, uUsage.Free)
+		add(&usage.Objects, uUsage.Objects)
+	}
+	if !found {
+		return nil, errors.New("About not supported")
+	}
+	return usage, nil
+}
+
+// Object describes an object in one of the upstreams
+type Object struct {
+	fs.Object
+	f *Fs
+	u *upstream // the upstream the object is on
+}
+
+// newObject wraps o which was found on u
+func (f *Fs) newObject(o fs.Object, u *upstream) *Object {
+	return &Object{
+		Object: o,
+		f:      f,
+		u:      u,
+	}
+}
+
+// Fs returns the union Fs this object is part of
+func (o *Object) Fs() fs.Info {
+	return o.f
+}
+
+// String returns a description of the Object
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.Object.String()
+}
+
+// UnWrap returns the Object that this Object is wrapping
+func (o *Object) UnWrap() fs.Object {
+	return o.Object
+}
+
+// MimeType returns the content type of the Object if known, or ""
+// if not
+func (o *Object) MimeType() string {
+	return fs.MimeType(o.Object)
+}
+
+// Update in to the object with the modTime given of the given size
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	if o.u.readOnly {
+		return errors.Errorf("can't update %q: it is on read only remote %v", o.Remote(), o.u)
+	}
+	return o.Object.Update(ctx, in, src, options...)
+}
+
+// Remove the object and any copies of it in later writable upstreams
+// which it was hiding
+func (o *Object) Remove(ctx context.Context) error {
+	if o.u.readOnly {
+		return errors.Errorf("can't remove %q: it is on read only remote %v", o.Remote(), o.u)
+	}
+	err := o.Object.Remove(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range o.f.writable() {
+		if u == o.u {
+			continue
+		}
+		other, err := u.NewObject(ctx, o.Remote())
+		if err != nil {
+			continue
+		}
+		err = other.Remove(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs              = (*Fs)(nil)
+	_ fs.Purger          = (*Fs)(nil)
+	_ fs.Copier          = (*Fs)(nil)
+	_ fs.Mover           = (*Fs)(nil)
+	_ fs.DirMover        = (*Fs)(nil)
+	_ fs.PutStreamer     = (*Fs)(nil)
+	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.Object          = (*Object)(nil)
+	_ fs.ObjectUnWrapper = (*Object)(nil)
+	_ fs.MimeTyper       = (*Object)(nil)
+)
@@ -0,0 +1,252 @@
+package union
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	_ "github.com/artpar/rclone/backend/local" // pull in test backend
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/configmap"
+	"github.com/artpar/rclone/fs/object"
+	"github.com/artpar/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeUnion makes n temporary directories and a union of them with
+// the create policy given.  Upstreams whose index is in readOnly are
+// made read only.
+func makeUnion(t *testing.T, n int, policy string, readOnly ...int) (f *Fs, dirs []string, cleanup func()) {
+	remotes := ""
+	var cleanups []func()
+	for i := 0; i < n; i++ {
+		dir, cleanup := fstest.TempDir(t, "rclone-union-internal")
+		dirs = append(dirs, dir)
+		cleanups = append(cleanups, cleanup)
+		remote := `"` + dir + `"`
+		for _, ro := range readOnly {
+			if ro == i {
+				remote = `"` + dir + `:ro"`
+			}
+		}
+		remotes += remote + " "
+	}
+	cleanup = func() {
+		for _, cleanup := range cleanups {
+			cleanup()
+		}
+	}
+	fi, err := NewFs("TestUnionInternal", "", configmap.Simple{
+		"remotes":       remotes,
+		"create_policy": policy,
+	})
+	require.NoError(t, err)
+	return fi.(*Fs), dirs, cleanup
+}
+
+// writeFile makes a file with contents in dir
+func writeFile(t *testing.T, dir, name, contents string) {
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0777))
+	require.NoError(t, ioutil.WriteFile(p, []byte(contents), 0666))
+}
+
+// exists returns whether name is in dir
+func exists(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+	return err == nil
+}
+
+func TestParseRemotes(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"a: b:dir", []string{"a:", "b:dir"}, false},
+		{"  a:\t/tmp/x:ro \n", []string{"a:", "/tmp/x:ro"}, false},
+		{`"a:space dir" b:`, []string{"a:space dir", "b:"}, false},
+		{`a:"x y"z`, []string{"a:x yz"}, false},
+		{`"a:unterminated`, nil, true},
+	} {
+		got, err := parseRemotes(test.in)
+		assert.Equal(t, test.wantErr, err != nil, test.in)
+		assert.Equal(t, test.want, got, test.in)
+	}
+}
+
+func TestNewFsErrors(t *testing.T) {
+	_, err := NewFs("TestUnionInternal", "", configmap.Simple{"remotes": " ", "create_policy": "ff"})
+	assert.Error(t, err)
+	_, err = NewFs("TestUnionInternal", "", configmap.Simple{"remotes": "/tmp", "create_policy": "potato"})
+	assert.Error(t, err)
+	_, err = NewFs("TestUnionInternal", "", configmap.Simple{"remotes": "TestUnionInternal:dir", "create_policy": "ff"})
+	assert.Error(t, err)
+}
+
+func TestListAndRead(t *testing.T) {
+	ctx := context.Background()
+	f, dirs, cleanup := makeUnion(t, 2, "ff")
+	defer cleanup()
+
+	writeFile(t, dirs[0], "both.txt", "first")
+	writeFile(t, dirs[1], "both.txt", "second")
+	writeFile(t, dirs[1], "only2.txt", "only in second")
+	writeFile(t, dirs[0], "dir/a.txt", "a")
+	writeFile(t, dirs[1], "dir/b.txt", "b")
+
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Remote())
+		if o, ok := entry.(fs.Object); ok {
+			assert.Equal(t, f, o.Fs())
+		}
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"both.txt", "dir", "only2.txt"}, names)
+
+	// Directories are merged
+	entries, err = f.List(ctx, "dir")
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(entries))
+
+	// Reads come from the first upstream with the file
+	o, err := f.NewObject(ctx, "both.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("first")), o.Size())
+	o, err = f.NewObject(ctx, "only2.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("only in second")), o.Size())
+
+	_, err = f.NewObject(ctx, "potato")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+	_, err = f.List(ctx, "potato")
+	assert.Equal(t, fs.ErrorDirNotFound, err)
+
+	// Removing removes the hidden copies too
+	o, err = f.NewObject(ctx, "both.txt")
+	require.NoError(t, err)
+	require.NoError(t, o.Remove(ctx))
+	assert.False(t, exists(dirs[0], "both.txt"))
+	assert.False(t, exists(dirs[1], "both.txt"))
+}
+
+func TestCreatePolicyFirstFound(t *testing.T) {
+	f, dirs, cleanup := makeUnion(t, 3, "ff", 0)
+	defer cleanup()
+
+	// The first upstream is read only so the second is used
+	fstest.PutString(t, f, "file.txt", "hello")
+	assert.False(t, exists(dirs[0], "file.txt"))
+	assert.True(t, exists(dirs[1], "file.txt"))
+	assert.False(t, exists(dirs[2], "file.txt"))
+
+	// Updates go to the upstream with the file
+	writeFile(t, dirs[2], "three.txt", "3")
+	fstest.PutString(t, f, "three.txt", "three")
+	assert.False(t, exists(dirs[1], "three.txt"))
+	assert.True(t, exists(dirs[2], "three.txt"))
+}
+
+func TestCreatePolicyExistingPath(t *testing.T) {
+	ctx := context.Background()
+	f, dirs, cleanup := makeUnion(t, 3, "epff")
+	defer cleanup()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dirs[1], "a"), 0777))
+	require.NoError(t, os.MkdirAll(filepath.Join(dirs[2], "a", "b"), 0777))
+
+	// The deepest existing path wins
+	fstest.PutString(t, f, "a/b/c/file.txt", "hello")
+	assert.True(t, exists(dirs[2], "a/b/c/file.txt"))
+	fstest.PutString(t, f, "a/file.txt", "hello")
+	assert.True(t, exists(dirs[1], "a/file.txt"))
+
+	// Otherwise the first is used
+	fstest.PutString(t, f, "z/file.txt", "hello")
+	assert.True(t, exists(dirs[0], "z/file.txt"))
+
+	// Mkdir follows the policy too
+	require.NoError(t, f.Mkdir(ctx, "a/b/new"))
+	assert.True(t, exists(dirs[2], "a/b/new"))
+}
+
+func TestCreatePolicyMostFreeSpace(t *testing.T) {
+	f, dirs, cleanup := makeUnion(t, 2, "mfs", 0)
+	defer cleanup()
+
+	// Only one writable upstream so it must be chosen
+	fstest.PutString(t, f, "file.txt", "hello")
+	assert.True(t, exists(dirs[1], "file.txt"))
+}
+
+func TestReadOnly(t *testing.T) {
+	ctx := context.Background()
+	f, dirs, cleanup := makeUnion(t, 2, "ff", 0)
+	defer cleanup()
+
+	writeFile(t, dirs[0], "ro.txt", "read only")
+	o, err := f.NewObject(ctx, "ro.txt")
+	require.NoError(t, err)
+	assert.Error(t, o.Remove(ctx))
+	src := object.NewStaticObjectInfo("ro.txt", time.Now(), 1, true, nil, nil)
+	assert.Error(t, o.Update(ctx, bytes.NewBufferString("x"), src))
+	_, err = f.Put(ctx, bytes.NewBufferString("x"), src)
+	assert.Error(t, err)
+	assert.True(t, exists(dirs[0], "ro.txt"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dirs[0], "rodir"), 0777))
+	assert.Error(t, f.Rmdir(ctx, "rodir"))
+}
+
+func TestRmdirAndPurge(t *testing.T) {
+	ctx := context.Background()
+	f, dirs, cleanup := makeUnion(t, 2, "ff")
+	defer cleanup()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dirs[0], "dir"), 0777))
+	require.NoError(t, os.MkdirAll(filepath.Join(dirs[1], "dir"), 0777))
+	require.NoError(t, f.Rmdir(ctx, "dir"))
+	assert.False(t, exists(dirs[0], "dir"))
+	assert.False(t, exists(dirs[1], "dir"))
+	assert.Equal(t, fs.ErrorDirNotFound, f.Rmdir(ctx, "dir"))
+
+	if f.Features().Purge == nil {
+		t.Skip("Purge not supported")
+	}
+	writeFile(t, dirs[0], "sub/a.txt", "a")
+	writeFile(t, dirs[1], "sub/b.txt", "b")
+	fSub, err := NewFs("TestUnionInternal", "sub", configmap.Simple{
+		"remotes":       dirs[0] + " " + dirs[1],
+		"create_policy": "ff",
+	})
+	require.NoError(t, err)
+	require.NoError(t, fSub.Features().Purge(ctx))
+	assert.False(t, exists(dirs[0], "sub"))
+	assert.False(t, exists(dirs[1], "sub"))
+}
+
+func TestAbout(t *testing.T) {
+	f, _, cleanup := makeUnion(t, 2, "ff")
+	defer cleanup()
+	if f.Features().About == nil {
+		t.Skip("About not supported")
+	}
+	usage, err := f.About()
+	require.NoError(t, err)
+	// Both upstreams are on the same disk so should be double
+	u, err := f.upstreams[0].Features().About()
+	require.NoError(t, err)
+	require.NotNil(t, usage.Total)
+	require.NotNil(t, u.Total)
+	assert.Equal(t, 2*(*u.Total), *usage.Total)
+}
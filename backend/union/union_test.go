@@ -0,0 +1,28 @@
+// Test Union filesystem interface
+package union_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/artpar/rclone/backend/local"
+	"github.com/artpar/rclone/backend/union"
+	"github.com/artpar/rclone/fstest/fstests"
+)
+
+// TestIntegration runs integration tests against the remote
+func TestIntegration(t *testing.T) {
+	tempdir1 := filepath.Join(os.TempDir(), "rclone-union-test-1")
+	tempdir2 := filepath.Join(os.TempDir(), "rclone-union-test-2")
+	tempdir3 := filepath.Join(os.TempDir(), "rclone-union-test-3")
+	name := "TestUnion"
+	fstests.Run(t, &fstests.Opt{
+		RemoteName: name + ":",
+		NilObject:  (*union.Object)(nil),
+		ExtraConfig: []fstests.ExtraConfigItem{
+			{Name: name, Key: "type", Value: "union"},
+			{Name: name, Key: "remotes", Value: tempdir1 + " " + tempdir2 + " " + tempdir3},
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package src
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Copy will copy the file/folder from to path on Yandex Disk
+//
+// If Yandex Disk carries out the copy asynchronously this waits for
+// it to finish.
+func (c *Client) Copy(ctx context.Context, from, path string, overwrite bool) error {
+
+	values := url.Values{}
+	values.Add("from", from)
+	values.Add("path", path)
+	values.Add("overwrite", strconv.FormatBool(overwrite))
+	urlPath := "/v1/disk/resources/copy?" + values.Encode()
+	fullURL := RootAddr
+	if urlPath[:1] != "/" {
+		fullURL += "/" + urlPath
+	} else {
+		fullURL += urlPath
+	}
+
+	return c.PerformCopy(ctx, fullURL)
+}
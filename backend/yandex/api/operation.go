@@ -0,0 +1,102 @@
+package src
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// operationPollInterval is how often the status of an asynchronous
+// operation is checked
+const operationPollInterval = time.Second
+
+// operationTimeout is the longest to wait for an asynchronous
+// operation to finish
+const operationTimeout = time.Hour
+
+// Link is returned by the API for operations which finish
+// asynchronously.
+type Link struct {
+	Href      string `json:"href"`
+	Method    string `json:"method"`
+	Templated bool   `json:"templated"`
+}
+
+// OperationStatusResponse is returned by the API for operation status
+// requests.
+type OperationStatusResponse struct {
+	Status string `json:"status"` // success, failed or in-progress
+}
+
+// OperationStatus reads the status of the asynchronous operation at
+// href
+func (c *Client) OperationStatus(ctx context.Context, href string) (status string, err error) {
+	req, err := http.NewRequest("GET", href, nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+
+	//set access token and headers
+	c.setRequestScope(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer CheckClose(resp.Body, &err)
+
+	if resp.StatusCode != 200 {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		return "", errors.Errorf("operation status error [%d]: %s", resp.StatusCode, string(body))
+	}
+	var response OperationStatusResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return "", err
+	}
+	return response.Status, nil
+}
+
+// WaitForOperation polls the asynchronous operation at href until it
+// has finished, ctx is cancelled or operationTimeout has passed
+func (c *Client) WaitForOperation(ctx context.Context, href string) error {
+	return c.waitForOperation(ctx, href, operationPollInterval, operationTimeout)
+}
+
+func (c *Client) waitForOperation(ctx context.Context, href string, pollInterval, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		status, err := c.OperationStatus(ctx, href)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return errors.Errorf("timed out after %v waiting for operation %q", timeout, href)
+			}
+			return err
+		}
+		switch status {
+		case "success":
+			return nil
+		case "failed":
+			return errors.Errorf("operation %q failed", href)
+		}
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return errors.Errorf("timed out after %v waiting for operation %q", timeout, href)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
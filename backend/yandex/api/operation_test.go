@@ -0,0 +1,58 @@
+package src
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startOperationServer starts a server which reports an operation
+// as in-progress for the first inProgress polls and status after
+func startOperationServer(inProgress int32, status string) (*httptest.Server, *int32) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply := status
+		if atomic.AddInt32(&polls, 1) <= inProgress {
+			reply = "in-progress"
+		}
+		_, _ = fmt.Fprintf(w, `{"status":%q}`, reply)
+	}))
+	return server, &polls
+}
+
+func TestWaitForOperation(t *testing.T) {
+	ctx := context.Background()
+
+	server, polls := startOperationServer(2, "success")
+	defer server.Close()
+	c := NewClient("token", server.Client())
+	require.NoError(t, c.waitForOperation(ctx, server.URL, time.Millisecond, time.Minute))
+	assert.Equal(t, int32(3), atomic.LoadInt32(polls))
+
+	server, _ = startOperationServer(0, "failed")
+	defer server.Close()
+	c = NewClient("token", server.Client())
+	err := c.waitForOperation(ctx, server.URL, time.Millisecond, time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed")
+
+	server, _ = startOperationServer(1<<30, "success")
+	defer server.Close()
+	c = NewClient("token", server.Client())
+	err = c.waitForOperation(ctx, server.URL, time.Millisecond, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = c.waitForOperation(cancelCtx, server.URL, time.Millisecond, time.Minute)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "timed out")
+}
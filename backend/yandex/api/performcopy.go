@@ -0,0 +1,47 @@
+package src
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// PerformCopy does the actual copy via POST request.
+func (c *Client) PerformCopy(ctx context.Context, url string) (err error) {
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	//set access token and headers
+	c.setRequestScope(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer CheckClose(resp.Body, &err)
+
+	//201 - resource copied.
+	//202 - resource will be copied soon (async copy).
+	switch resp.StatusCode {
+	case 201:
+		return nil
+	case 202:
+		var link Link
+		err = json.NewDecoder(resp.Body).Decode(&link)
+		if err != nil {
+			return errors.Wrap(err, "copy error: failed to decode operation link")
+		}
+		return c.WaitForOperation(ctx, link.Href)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return errors.Errorf("copy error [%d]: %s", resp.StatusCode, string(body))
+}
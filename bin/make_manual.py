@@ -28,11 +28,14 @@ docs = [
     "b2.md",
     "box.md",
     "cache.md",
+    "chunker.md",
+    "compress.md",
     "crypt.md",
     "dropbox.md",
     "ftp.md",
     "googlecloudstorage.md",
     "drive.md",
+    "hasher.md",
     "http.md",
     "hubic.md",
     "jottacloud.md",
@@ -44,6 +47,7 @@ docs = [
     "swift.md",
     "pcloud.md",
     "sftp.md",
+    "union.md",
     "webdav.md",
     "yandex.md",
 
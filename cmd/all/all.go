@@ -6,6 +6,8 @@ import (
 	_ "github.com/ncw/rclone/cmd"
 	_ "github.com/ncw/rclone/cmd/about"
 	_ "github.com/ncw/rclone/cmd/authorize"
+	_ "github.com/ncw/rclone/cmd/backend"
+	_ "github.com/ncw/rclone/cmd/bisync"
 	_ "github.com/ncw/rclone/cmd/cachestats"
 	_ "github.com/ncw/rclone/cmd/cat"
 	_ "github.com/ncw/rclone/cmd/check"
@@ -22,6 +24,7 @@ import (
 	_ "github.com/ncw/rclone/cmd/deletefile"
 	_ "github.com/ncw/rclone/cmd/genautocomplete"
 	_ "github.com/ncw/rclone/cmd/gendocs"
+	_ "github.com/ncw/rclone/cmd/hasherimport"
 	_ "github.com/ncw/rclone/cmd/hashsum"
 	_ "github.com/ncw/rclone/cmd/info"
 	_ "github.com/ncw/rclone/cmd/link"
@@ -42,6 +45,7 @@ import (
 	_ "github.com/ncw/rclone/cmd/purge"
 	_ "github.com/ncw/rclone/cmd/rc"
 	_ "github.com/ncw/rclone/cmd/rcat"
+	_ "github.com/ncw/rclone/cmd/rcd"
 	_ "github.com/ncw/rclone/cmd/reveal"
 	_ "github.com/ncw/rclone/cmd/rmdir"
 	_ "github.com/ncw/rclone/cmd/rmdirs"
@@ -0,0 +1,181 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/artpar/rclone/cmd"
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/flags"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	options []string
+	useJSON bool
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefinition)
+	cmdFlags := commandDefinition.Flags()
+	flags.StringArrayVarP(cmdFlags, &options, "option", "o", options, "Option in the form name=value or name.")
+	flags.BoolVarP(cmdFlags, &useJSON, "json", "", useJSON, "Always output in JSON format.")
+}
+
+var commandDefinition = &cobra.Command{
+	Use:   "backend <command> remote:path [opts] <args>",
+	Short: `Run a backend specific command.`,
+	Long: `
+This runs a backend specific command. The commands themselves (except
+for "help") are defined by the backends and you should see the backend
+docs for definitions.
+
+You can discover what commands a backend implements by using
+
+    rclone backend help remote:
+    rclone backend help <backendname>
+
+You can also discover information about the backend using (see
+[operations/fsinfo](/rc/#operations/fsinfo) in the remote control docs
+for more info).
+
+Pass options to the backend command with -o. This should be key=value
+or key, eg:
+
+    rclone backend stats remote:path stats -o format=json -o long
+
+Pass arguments to the backend by placing them on the end of the line
+
+    rclone backend cleanup remote:path file1 file2 file3
+
+Note to run these commands on a running backend then see
+[backend/command](/rc/#backend/command) in the rc docs.
+
+If the command returns a string or a list of strings they are printed
+one per line, otherwise the result is printed as JSON.  Use --json to
+always print JSON.
+`,
+	RunE: func(command *cobra.Command, args []string) error {
+		cmd.CheckArgs(2, 1E6, command, args)
+		name, remote := args[0], args[1]
+		cmd.Run(false, false, command, func() error {
+			if name == "help" {
+				return showHelp(remote)
+			}
+			f := cmd.NewFsSrc(args[1:2])
+			doCommand := f.Features().Command
+			if doCommand == nil {
+				return errors.Errorf("%v: doesn't support backend commands", f)
+			}
+			opt := parseOptions(options)
+			out, err := doCommand(context.Background(), name, args[2:], opt)
+			if err != nil {
+				return errors.Wrapf(err, "command %q failed", name)
+			}
+			return writeOutput(out)
+		})
+		return nil
+	},
+}
+
+// parseOptions parses the -o options into a map
+//
+// Options without an = are given the value "true"
+func parseOptions(options []string) map[string]string {
+	opt := make(map[string]string, len(options))
+	for _, option := range options {
+		equals := strings.IndexRune(option, '=')
+		if equals < 0 {
+			opt[option] = "true"
+		} else {
+			opt[option[:equals]] = option[equals+1:]
+		}
+	}
+	return opt
+}
+
+// writeOutput writes the result of a command to stdout
+//
+// strings and lists of strings are written one per line unless
+// --json is set, anything else is written as JSON.
+func writeOutput(out interface{}) error {
+	if !useJSON {
+		switch x := out.(type) {
+		case nil:
+			return nil
+		case string:
+			fmt.Println(x)
+			return nil
+		case []string:
+			for _, line := range x {
+				fmt.Println(line)
+			}
+			return nil
+		}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	err := enc.Encode(out)
+	if err != nil {
+		return errors.Wrap(err, "failed to write JSON")
+	}
+	return nil
+}
+
+// showHelp shows help for the backend commands of the backend
+// named, which can either be a backend name or a remote
+func showHelp(remote string) error {
+	fsInfo, err := fs.Find(strings.TrimSuffix(remote, ":"))
+	if err != nil {
+		fsInfo, _, _, _, err = fs.ConfigFs(remote)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find backend for %q", remote)
+		}
+	}
+	fmt.Print(CommandsHelp(fsInfo))
+	return nil
+}
+
+// CommandsHelp returns the help for the backend commands of fsInfo
+// in markdown format
+func CommandsHelp(fsInfo *fs.RegInfo) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "### Backend commands\n\n")
+	if len(fsInfo.CommandHelp) == 0 {
+		fmt.Fprintf(&out, "The %s backend has no backend specific commands.\n", fsInfo.Name)
+		return out.String()
+	}
+	fmt.Fprintf(&out, "Here are the commands specific to the %s backend.\n\n", fsInfo.Name)
+	fmt.Fprintf(&out, "Run them with\n\n    rclone backend COMMAND remote:\n\n")
+	fmt.Fprintf(&out, "The help below will explain what arguments each command takes.\n\n")
+	fmt.Fprintf(&out, "See [the \"rclone backend\" command](/commands/rclone_backend/) for more\n")
+	fmt.Fprintf(&out, "info on how to pass options and arguments.\n\n")
+	fmt.Fprintf(&out, "These can be run on a running backend using the rc command\n")
+	fmt.Fprintf(&out, "[backend/command](/rc/#backend/command).\n\n")
+	for _, cmd := range fsInfo.CommandHelp {
+		fmt.Fprintf(&out, "#### %s\n\n", cmd.Name)
+		fmt.Fprintf(&out, "%s\n\n", cmd.Short)
+		fmt.Fprintf(&out, "    rclone backend %s remote: [options] [<arguments>+]\n\n", cmd.Name)
+		if cmd.Long != "" {
+			fmt.Fprintf(&out, "%s\n\n", strings.TrimSpace(cmd.Long))
+		}
+		if len(cmd.Opts) != 0 {
+			fmt.Fprintf(&out, "Options:\n\n")
+			keys := make([]string, 0, len(cmd.Opts))
+			for key := range cmd.Opts {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Fprintf(&out, "- %q: %s\n", key, cmd.Opts[key])
+			}
+			fmt.Fprintf(&out, "\n")
+		}
+	}
+	return out.String()
+}
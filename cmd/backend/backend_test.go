@@ -0,0 +1,39 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOptions(t *testing.T) {
+	assert.Equal(t, map[string]string{}, parseOptions(nil))
+	assert.Equal(t, map[string]string{
+		"echo":   "true",
+		"format": "json",
+		"empty":  "",
+		"eq":     "a=b",
+	}, parseOptions([]string{"echo", "format=json", "empty=", "eq=a=b"}))
+}
+
+func TestCommandsHelp(t *testing.T) {
+	help := CommandsHelp(&fs.RegInfo{Name: "potato"})
+	assert.Contains(t, help, "The potato backend has no backend specific commands.")
+
+	help = CommandsHelp(&fs.RegInfo{
+		Name: "potato",
+		CommandHelp: []fs.CommandHelp{{
+			Name:  "peel",
+			Short: "Peel the potato",
+			Long:  "Removes the skin.\n",
+			Opts: map[string]string{
+				"thin":  "peel thinly",
+				"knife": "use a knife",
+			},
+		}},
+	})
+	assert.Contains(t, help, "#### peel\n\nPeel the potato\n\n")
+	assert.Contains(t, help, "Removes the skin.\n\n")
+	assert.Contains(t, help, "- \"knife\": use a knife\n- \"thin\": peel thinly\n")
+}
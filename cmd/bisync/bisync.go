@@ -0,0 +1,71 @@
+package bisync
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/artpar/rclone/cmd"
+	"github.com/artpar/rclone/fs/bisync"
+	"github.com/artpar/rclone/fs/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	opt = bisync.DefaultOptions(filepath.Join(config.CacheDir, "bisync"))
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+	flags := commandDefintion.Flags()
+	flags.BoolVarP(&opt.Resync, "resync", "", opt.Resync, "Make the listings from scratch, copying missing files both ways.")
+	flags.IntVarP(&opt.MaxDeletePercent, "max-delete-percent", "", opt.MaxDeletePercent, "Abort if more than this percentage of the files on either path would be deleted.")
+	flags.BoolVarP(&opt.Force, "force", "", opt.Force, "Ignore --max-delete-percent.")
+	flags.StringVarP(&opt.StateDir, "state-dir", "", opt.StateDir, "Directory to keep the listings in between runs.")
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "bisync path1:path path2:path",
+	Short: `Make path1 and path2 identical, propagating changes both ways.`,
+	Long: `
+Bidirectional sync between two paths.  The listings of both paths are
+stored at the end of each run and the next run compares the paths
+with them to find out which files were created, changed or deleted on
+each path since, and makes the same changes on the other path.
+Unchanged files aren't transferred.
+
+Files are compared with the listings by size and modification time,
+and by hash too with ` + "`" + `--checksum` + "`" + `.
+
+If a file was changed on both paths then the newer copy wins and the
+older copy is renamed by adding ` + "`" + `.conflict` + "`" + ` before the extension, eg
+` + "`" + `file.conflict.txt` + "`" + `, so both copies end up on both paths.  A file
+changed on one path and deleted on the other is copied back.
+
+The first run needs ` + "`" + `--resync` + "`" + ` to make the listings.  This copies
+the files missing from each path to it, and files which differ from
+path1 to path2, so use it with care.  It can also be used to start
+again if the listings are lost or the paths have been changed by
+something else.
+
+As a safety check the sync is aborted without changing anything if
+more than ` + "`" + `--max-delete-percent` + "`" + ` (default 50) of the files on either path
+would be deleted, for example if one of the paths was unmounted or
+emptied by mistake.  Use ` + "`" + `--force` + "`" + ` to delete them anyway.
+
+The listings are only updated if the run succeeds, so after an error
+the next run tries again.  They are stored in ` + "`" + `--state-dir` + "`" + ` which is
+in the rclone cache directory by default.
+
+Empty directories aren't synced.
+
+**Important**: Since this can cause data loss, test first with the
+` + "`" + `--dry-run` + "`" + ` flag to see exactly what would be copied and deleted.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		f1, f2 := cmd.NewFsDir(args[0:1]), cmd.NewFsDir(args[1:2])
+		cmd.Run(true, true, command, func() error {
+			return bisync.Bisync(context.Background(), f1, f2, &opt)
+		})
+	},
+}
@@ -337,8 +337,8 @@ func Run(Retry bool, showStats bool, cmd *cobra.Command, f func() error) {
 		close(stopStats)
 	}
 	if err != nil {
-		log.Printf("Failed to %s: %v", err)
-		//resolveExitCode(err)
+		log.Printf("Failed to %s: %v", cmd.Name(), err)
+		resolveExitCode(err)
 	}
 	if showStats && (accounting.Stats.Errored() || *statsInterval > 0) {
 		accounting.Stats.Log()
@@ -365,7 +365,7 @@ func Run(Retry bool, showStats bool, cmd *cobra.Command, f func() error) {
 	}
 
 	if accounting.Stats.Errored() {
-		//resolveExitCode(accounting.Stats.GetLastError())
+		resolveExitCode(accounting.Stats.GetLastError())
 	}
 }
 
@@ -425,7 +425,10 @@ func initConfig() {
 	fs.Debugf("rclone", "Version %q starting with parameters %q", fs.Version, os.Args)
 
 	// Start the remote control if configured
-	rc.Start(&rcflags.Opt)
+	_, err = rc.Start(&rcflags.Opt)
+	if err != nil {
+		log.Fatalf("Failed to start remote control: %v", err)
+	}
 
 	// Setup CPU profiling if desired
 	if *cpuProfile != "" {
@@ -477,29 +480,34 @@ func initConfig() {
 
 func resolveExitCode(err error) {
 	atexit.Run()
+	os.Exit(exitCode(err))
+}
+
+// exitCode returns the exit code rclone should exit with for err
+func exitCode(err error) int {
 	if err == nil {
-		os.Exit(exitCodeSuccess)
+		return exitCodeSuccess
 	}
 
 	_, unwrapped := fserrors.Cause(err)
 
 	switch {
 	case unwrapped == fs.ErrorDirNotFound:
-		os.Exit(exitCodeDirNotFound)
+		return exitCodeDirNotFound
 	case unwrapped == fs.ErrorObjectNotFound:
-		os.Exit(exitCodeFileNotFound)
+		return exitCodeFileNotFound
 	case unwrapped == errorUncategorized:
-		os.Exit(exitCodeUncategorizedError)
-	case unwrapped == accounting.ErrorMaxTransferLimitReached:
-		os.Exit(exitCodeTransferExceeded)
+		return exitCodeUncategorizedError
+	case accounting.IsCutoffError(unwrapped):
+		return exitCodeTransferExceeded
 	case fserrors.ShouldRetry(err):
-		os.Exit(exitCodeRetryError)
+		return exitCodeRetryError
 	case fserrors.IsNoRetryError(err):
-		os.Exit(exitCodeNoRetryError)
+		return exitCodeNoRetryError
 	case fserrors.IsFatalError(err):
-		os.Exit(exitCodeFatalError)
+		return exitCodeFatalError
 	default:
-		os.Exit(exitCodeUsageError)
+		return exitCodeUsageError
 	}
 }
 
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"io"
+	"testing"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/accounting"
+	"github.com/artpar/rclone/fs/fserrors"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	errPotato := errors.New("potato")
+	for _, test := range []struct {
+		err  error
+		want int
+	}{
+		{nil, exitCodeSuccess},
+		{fs.ErrorDirNotFound, exitCodeDirNotFound},
+		{errors.Wrap(fs.ErrorObjectNotFound, "wrapped"), exitCodeFileNotFound},
+		{errorUncategorized, exitCodeUncategorizedError},
+		{accounting.ErrorMaxTransferLimitReached, exitCodeTransferExceeded},
+		{accounting.ErrorMaxTransferLimitReachedGraceful, exitCodeTransferExceeded},
+		{accounting.ErrorMaxDurationReached, exitCodeTransferExceeded},
+		{errors.Wrap(accounting.ErrorMaxDurationReachedGraceful, "sync"), exitCodeTransferExceeded},
+		{io.EOF, exitCodeRetryError},
+		{fserrors.NoRetryError(errPotato), exitCodeNoRetryError},
+		{fserrors.FatalError(errPotato), exitCodeFatalError},
+		{errPotato, exitCodeUsageError},
+	} {
+		assert.Equal(t, test.want, exitCode(test.err), "%v", test.err)
+	}
+}
@@ -1,6 +1,8 @@
 package delete
 
 import (
+	"context"
+
 	"github.com/artpar/rclone/cmd"
 	"github.com/artpar/rclone/fs/operations"
 	"github.com/spf13/cobra"
@@ -35,7 +37,7 @@ delete all files bigger than 100MBytes.
 		cmd.CheckArgs(1, 1, command, args)
 		fsrc := cmd.NewFsSrc(args)
 		cmd.Run(true, false, command, func() error {
-			return operations.Delete(fsrc)
+			return operations.Delete(context.Background(), fsrc)
 		})
 	},
 }
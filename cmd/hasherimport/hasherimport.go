@@ -0,0 +1,64 @@
+// +build !plan9
+
+package hasherimport
+
+import (
+	"context"
+	"os"
+
+	"github.com/artpar/rclone/backend/hasher"
+	"github.com/artpar/rclone/cmd"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefinition)
+}
+
+var commandDefinition = &cobra.Command{
+	Use:   "hasherimport <hash> remote:path sumfile",
+	Short: `Import checksums from a SUM file into a hasher remote.`,
+	Long: `
+Reads a checksum file in the format produced by md5sum, sha1sum or
+rclone hashsum and stores the checksums in the database of the hasher
+remote given, so they don't need to be computed again.
+
+The paths in the file should be relative to remote:path.  The
+checksums are stored against the current size and modification time
+of each file, so make sure the file is up to date.  Use "-" to read
+the checksums from standard input.
+
+For example
+
+    rclone hasherimport MD5 hashed:backup backup.md5
+
+Run "rclone hashsum" to see the list of supported hashes.
+`,
+	RunE: func(command *cobra.Command, args []string) error {
+		cmd.CheckArgs(3, 3, command, args)
+		var ht hash.Type
+		err := ht.Set(args[0])
+		if err != nil {
+			return err
+		}
+		fsrc := cmd.NewFsSrc(args[1:2])
+		cmd.Run(false, false, command, func() error {
+			fsHasher, ok := fsrc.(*hasher.Fs)
+			if !ok {
+				return errors.Errorf("%s: is not a hasher remote", fsrc.Name())
+			}
+			in := os.Stdin
+			if args[2] != "-" {
+				in, err = os.Open(args[2])
+				if err != nil {
+					return err
+				}
+				defer func() { _ = in.Close() }()
+			}
+			return fsHasher.Import(context.Background(), ht, in)
+		})
+		return nil
+	},
+}
@@ -5,6 +5,7 @@ package info
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"sort"
@@ -50,13 +51,14 @@ a bit of go code for each one.
 		for i := range args {
 			f := cmd.NewFsDir(args[i : i+1])
 			cmd.Run(false, false, command, func() error {
-				return readInfo(f)
+				return readInfo(context.Background(), f)
 			})
 		}
 	},
 }
 
 type results struct {
+	ctx                  context.Context
 	f                    fs.Fs
 	mu                   sync.Mutex
 	charNeedsEscaping    map[rune]bool
@@ -67,8 +69,9 @@ type results struct {
 	canStream            bool
 }
 
-func newResults(f fs.Fs) *results {
+func newResults(ctx context.Context, f fs.Fs) *results {
 	return &results{
+		ctx:               ctx,
 		f:                 f,
 		charNeedsEscaping: make(map[rune]bool),
 	}
@@ -106,7 +109,7 @@ func (r *results) Print() {
 func (r *results) writeFile(path string) (fs.Object, error) {
 	contents := fstest.RandomString(50)
 	src := object.NewStaticObjectInfo(path, time.Now(), int64(len(contents)), true, nil, r.f)
-	return r.f.Put(bytes.NewBufferString(contents), src)
+	return r.f.Put(r.ctx, bytes.NewBufferString(contents), src)
 }
 
 // check whether normalization is enforced and check whether it is
@@ -120,11 +123,11 @@ func (r *results) checkUTF8Normalization() {
 		return
 	}
 	r.canWriteUnnormalized = true
-	_, err = r.f.NewObject(unnormalized)
+	_, err = r.f.NewObject(r.ctx, unnormalized)
 	if err == nil {
 		r.canReadUnnormalized = true
 	}
-	_, err = r.f.NewObject(normalized)
+	_, err = r.f.NewObject(r.ctx, normalized)
 	if err == nil {
 		r.canReadRenormalized = true
 	}
@@ -216,7 +219,7 @@ func (r *results) checkStreaming() {
 	in := io.TeeReader(buf, hashIn)
 
 	objIn := object.NewStaticObjectInfo("checkStreamingTest", time.Now(), -1, true, nil, r.f)
-	objR, err := putter(in, objIn)
+	objR, err := putter(r.ctx, in, objIn)
 	if err != nil {
 		fs.Infof(r.f, "Streamed file failed to upload (%v)", err)
 		r.canStream = false
@@ -246,12 +249,12 @@ func (r *results) checkStreaming() {
 	r.canStream = true
 }
 
-func readInfo(f fs.Fs) error {
-	err := f.Mkdir("")
+func readInfo(ctx context.Context, f fs.Fs) error {
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		return errors.Wrap(err, "couldn't mkdir")
 	}
-	r := newResults(f)
+	r := newResults(ctx, f)
 	if checkControl {
 		r.checkControls()
 	}
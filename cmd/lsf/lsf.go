@@ -1,6 +1,7 @@
 package lsf
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -148,14 +149,14 @@ those only (without traversing the whole directory structure):
 			if csv && !separatorFlagSupplied {
 				separator = ","
 			}
-			return Lsf(fsrc, os.Stdout)
+			return Lsf(context.Background(), fsrc, os.Stdout)
 		})
 	},
 }
 
 // Lsf lists all the objects in the path with modification time, size
 // and path in specific format.
-func Lsf(fsrc fs.Fs, out io.Writer) error {
+func Lsf(ctx context.Context, fsrc fs.Fs, out io.Writer) error {
 	var list operations.ListFormat
 	list.SetSeparator(separator)
 	list.SetCSV(csv)
@@ -181,7 +182,7 @@ func Lsf(fsrc fs.Fs, out io.Writer) error {
 		}
 	}
 
-	return walk.Walk(fsrc, "", false, operations.ConfigMaxDepth(recurse), func(path string, entries fs.DirEntries, err error) error {
+	return walk.Walk(ctx, fsrc, "", false, operations.ConfigMaxDepth(recurse), func(path string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			fs.CountError(err)
 			fs.Errorf(path, "error listing: %v", err)
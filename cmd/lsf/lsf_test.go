@@ -2,6 +2,7 @@ package lsf
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/artpar/rclone/fs"
@@ -14,13 +15,14 @@ import (
 )
 
 func TestDefaultLsf(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	buf := new(bytes.Buffer)
 
 	f, err := fs.NewFs("testfiles")
 	require.NoError(t, err)
 
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -30,6 +32,7 @@ subdir/
 }
 
 func TestRecurseFlag(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	buf := new(bytes.Buffer)
 
@@ -37,7 +40,7 @@ func TestRecurseFlag(t *testing.T) {
 	require.NoError(t, err)
 
 	recurse = true
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -51,6 +54,7 @@ subdir/file3
 }
 
 func TestDirSlashFlag(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	buf := new(bytes.Buffer)
 
@@ -59,7 +63,7 @@ func TestDirSlashFlag(t *testing.T) {
 
 	dirSlash = true
 	format = "p"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -69,7 +73,7 @@ subdir/
 
 	buf = new(bytes.Buffer)
 	dirSlash = false
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -79,13 +83,14 @@ subdir
 }
 
 func TestFormat(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	f, err := fs.NewFs("testfiles")
 	require.NoError(t, err)
 
 	buf := new(bytes.Buffer)
 	format = "p"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -95,7 +100,7 @@ subdir
 
 	buf = new(bytes.Buffer)
 	format = "s"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `0
 321
@@ -105,7 +110,7 @@ subdir
 
 	buf = new(bytes.Buffer)
 	format = "hp"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `d41d8cd98f00b204e9800998ecf8427e;file1
 409d6c19451dd39d4a94e42d2ff2c834;file2
@@ -116,7 +121,7 @@ subdir
 	buf = new(bytes.Buffer)
 	format = "p"
 	filesOnly = true
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -127,7 +132,7 @@ file3
 	buf = new(bytes.Buffer)
 	format = "p"
 	dirsOnly = true
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `subdir
 `, buf.String())
@@ -135,10 +140,10 @@ file3
 
 	buf = new(bytes.Buffer)
 	format = "t"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 
-	items, _ := list.DirSorted(f, true, "")
+	items, _ := list.DirSorted(ctx, f, true, "")
 	var expectedOutput string
 	for _, item := range items {
 		expectedOutput += item.ModTime().Format("2006-01-02 15:04:05") + "\n"
@@ -148,7 +153,7 @@ file3
 
 	buf = new(bytes.Buffer)
 	format = "sp"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `0;file1
 321;file2
@@ -159,13 +164,14 @@ file3
 }
 
 func TestSeparator(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	f, err := fs.NewFs("testfiles")
 	require.NoError(t, err)
 	format = "ps"
 
 	buf := new(bytes.Buffer)
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1;0
 file2;321
@@ -175,7 +181,7 @@ subdir;-1
 
 	separator = "__SEP__"
 	buf = new(bytes.Buffer)
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1__SEP__0
 file2__SEP__321
@@ -187,6 +193,7 @@ subdir__SEP__-1
 }
 
 func TestWholeLsf(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	f, err := fs.NewFs("testfiles")
 	require.NoError(t, err)
@@ -196,11 +203,11 @@ func TestWholeLsf(t *testing.T) {
 	dirSlash = true
 
 	buf := new(bytes.Buffer)
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 
-	items, _ := list.DirSorted(f, true, "")
-	itemsInSubdir, _ := list.DirSorted(f, true, "subdir")
+	items, _ := list.DirSorted(ctx, f, true, "")
+	itemsInSubdir, _ := list.DirSorted(ctx, f, true, "subdir")
 	var expectedOutput []string
 	for _, item := range items {
 		expectedOutput = append(expectedOutput, item.ModTime().Format("2006-01-02 15:04:05"))
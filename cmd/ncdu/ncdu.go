@@ -5,6 +5,7 @@
 package ncdu
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"path"
@@ -50,7 +51,7 @@ importantly deleting files, but is useful as it stands.
 		cmd.CheckArgs(1, 1, command, args)
 		fsrc := cmd.NewFsSrc(args)
 		cmd.Run(false, false, command, func() error {
-			return NewUI(fsrc).Show()
+			return NewUI(fsrc).Show(context.Background())
 		})
 	},
 }
@@ -463,7 +464,7 @@ func NewUI(f fs.Fs) *UI {
 }
 
 // Show shows the user interface
-func (u *UI) Show() error {
+func (u *UI) Show(ctx context.Context) error {
 	err := termbox.Init()
 	if err != nil {
 		log.Print(err)
@@ -472,7 +473,7 @@ func (u *UI) Show() error {
 
 	// scan the disk in the background
 	u.listing = true
-	rootChan, errChan, updated := scan.Scan(u.f)
+	rootChan, errChan, updated := scan.Scan(ctx, u.f)
 
 	// Poll the events into a channel
 	events := make(chan termbox.Event)
@@ -0,0 +1,63 @@
+package rcd
+
+import (
+	"log"
+
+	"github.com/artpar/rclone/cmd"
+	"github.com/artpar/rclone/fs/rc"
+	"github.com/artpar/rclone/fs/rc/rcflags"
+	_ "github.com/artpar/rclone/fs/sync" // register the sync/* rc calls
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "rcd <path to files to serve>?",
+	Short: `Run rclone listening to remote control commands only.`,
+	Long: `
+This runs rclone so that it only listens to remote control commands.
+
+This is useful if you are controlling rclone via the rc API.
+
+If you pass in a path to a directory, rclone will serve that directory
+for GET requests on the URL passed in.  This can be used to serve a
+web based user interface for rclone.  This is the same as using the
+--rc-files flag.
+
+The server is configured with the --rc-* flags, eg --rc-addr,
+--rc-cert, --rc-key, --rc-client-ca, --rc-htpasswd, --rc-user and
+--rc-pass.
+
+Commands which can change or read data on remotes (eg those in the
+operations/* and sync/* groups) require authentication to be set up
+with --rc-htpasswd, --rc-user and --rc-pass or --rc-client-ca.  Use
+--rc-no-auth to allow them without authentication.
+
+See the [rc documentation](/rc/) for more info on the rc flags.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(0, 1, command, args)
+		if rcflags.Opt.Enabled {
+			log.Fatalf("Don't supply --rc flag when using rcd")
+		}
+
+		// Start the rc
+		rcflags.Opt.Enabled = true
+		if len(args) > 0 {
+			rcflags.Opt.Files = args[0]
+		}
+
+		s, err := rc.Start(&rcflags.Opt)
+		if err != nil {
+			log.Fatalf("Failed to start remote control: %v", err)
+		}
+		if s == nil {
+			log.Fatal("rc server not configured")
+		}
+
+		s.Wait()
+	},
+}
@@ -1,6 +1,8 @@
 package rmdir
 
 import (
+	"context"
+
 	"github.com/artpar/rclone/cmd"
 	"github.com/artpar/rclone/fs/operations"
 	"github.com/spf13/cobra"
@@ -20,7 +22,7 @@ objects in it, use purge for that.`,
 		cmd.CheckArgs(1, 1, command, args)
 		fdst := cmd.NewFsDir(args)
 		cmd.Run(true, false, command, func() error {
-			return operations.Rmdir(fdst, "")
+			return operations.Rmdir(context.Background(), fdst, "")
 		})
 	},
 }
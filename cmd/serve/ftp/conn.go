@@ -0,0 +1,808 @@
+package ftp
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/vfs"
+	"github.com/pkg/errors"
+)
+
+// dataTimeout is how long to wait for the client to connect to a
+// passive data port
+const dataTimeout = 30 * time.Second
+
+// idleTimeout is how long to wait for the client to send the next
+// command before closing the control connection
+const idleTimeout = 5 * time.Minute
+
+// maxLineLength is the longest command line accepted from the client
+const maxLineLength = 8192
+
+// command describes how to run an FTP command
+type command struct {
+	fn        func(c *conn, param string)
+	needLogin bool // command needs a logged in user
+	write     bool // command modifies the VFS so is refused if read only
+}
+
+// commands is the FTP commands the server understands
+var commands = map[string]command{
+	"ABOR": {fn: (*conn).cmdAbor},
+	"AUTH": {fn: (*conn).cmdAuth},
+	"CDUP": {fn: (*conn).cmdCdup, needLogin: true},
+	"CWD":  {fn: (*conn).cmdCwd, needLogin: true},
+	"DELE": {fn: (*conn).cmdDele, needLogin: true, write: true},
+	"EPSV": {fn: (*conn).cmdEpsv, needLogin: true},
+	"FEAT": {fn: (*conn).cmdFeat},
+	"LIST": {fn: (*conn).cmdList, needLogin: true},
+	"MDTM": {fn: (*conn).cmdMdtm, needLogin: true},
+	"MFMT": {fn: (*conn).cmdMfmt, needLogin: true, write: true},
+	"MKD":  {fn: (*conn).cmdMkd, needLogin: true, write: true},
+	"MLSD": {fn: (*conn).cmdMlsd, needLogin: true},
+	"MLST": {fn: (*conn).cmdMlst, needLogin: true},
+	"MODE": {fn: (*conn).cmdMode},
+	"NLST": {fn: (*conn).cmdNlst, needLogin: true},
+	"NOOP": {fn: (*conn).cmdNoop},
+	"OPTS": {fn: (*conn).cmdOpts},
+	"PASS": {fn: (*conn).cmdPass},
+	"PASV": {fn: (*conn).cmdPasv, needLogin: true},
+	"PBSZ": {fn: (*conn).cmdPbsz},
+	"PORT": {fn: (*conn).cmdPort, needLogin: true},
+	"PROT": {fn: (*conn).cmdProt},
+	"PWD":  {fn: (*conn).cmdPwd, needLogin: true},
+	"QUIT": {fn: (*conn).cmdQuit},
+	"REST": {fn: (*conn).cmdRest, needLogin: true},
+	"RETR": {fn: (*conn).cmdRetr, needLogin: true},
+	"RMD":  {fn: (*conn).cmdRmd, needLogin: true, write: true},
+	"RNFR": {fn: (*conn).cmdRnfr, needLogin: true, write: true},
+	"RNTO": {fn: (*conn).cmdRnto, needLogin: true, write: true},
+	"SIZE": {fn: (*conn).cmdSize, needLogin: true},
+	"STOR": {fn: (*conn).cmdStor, needLogin: true, write: true},
+	"STRU": {fn: (*conn).cmdStru},
+	"SYST": {fn: (*conn).cmdSyst},
+	"TYPE": {fn: (*conn).cmdType},
+	"USER": {fn: (*conn).cmdUser},
+	"XCUP": {fn: (*conn).cmdCdup, needLogin: true},
+	"XCWD": {fn: (*conn).cmdCwd, needLogin: true},
+	"XMKD": {fn: (*conn).cmdMkd, needLogin: true, write: true},
+	"XPWD": {fn: (*conn).cmdPwd, needLogin: true},
+	"XRMD": {fn: (*conn).cmdRmd, needLogin: true, write: true},
+}
+
+// conn is a single FTP control connection
+type conn struct {
+	s         *server
+	what      string
+	vfs       *vfs.VFS
+	ctrl      net.Conn
+	r         *bufio.Scanner
+	w         *bufio.Writer
+	user      string       // user name given by USER
+	loggedIn  bool         // set once PASS succeeds
+	readOnly  bool         // set if this user can't modify the VFS
+	cwd       string       // current working directory
+	pasv      net.Listener // listener for the next data connection
+	protected bool         // set to use TLS on data connections
+	restart   int64        // offset given by REST for the next transfer
+	renameSrc string       // path given by RNFR
+	quit      bool         // set to close the connection
+}
+
+// newConn makes a new conn to serve the connection passed in
+func newConn(s *server, nConn net.Conn) *conn {
+	c := &conn{
+		s:    s,
+		what: describeConn(nConn),
+		vfs:  s.vfs,
+		cwd:  "/",
+	}
+	c.setCtrl(nConn)
+	return c
+}
+
+// setCtrl sets the control connection, eg after upgrading to TLS
+func (c *conn) setCtrl(nConn net.Conn) {
+	c.ctrl = nConn
+	c.r = bufio.NewScanner(nConn)
+	c.r.Buffer(make([]byte, 512), maxLineLength)
+	c.w = bufio.NewWriter(nConn)
+}
+
+// reply sends a single line reply to the client
+func (c *conn) reply(code int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	fs.Debugf(c.what, "< %d %s", code, msg)
+	_, err := fmt.Fprintf(c.w, "%d %s\r\n", code, msg)
+	if err == nil {
+		err = c.w.Flush()
+	}
+	if err != nil {
+		fs.Debugf(c.what, "Failed to send reply: %v", err)
+		c.quit = true
+	}
+}
+
+// replyLines sends a multi line reply to the client
+func (c *conn) replyLines(code int, first string, lines []string, last string) {
+	fmt.Fprintf(c.w, "%d-%s\r\n", code, first)
+	for _, line := range lines {
+		fmt.Fprintf(c.w, " %s\r\n", line)
+	}
+	c.reply(code, "%s", last)
+}
+
+// replyError sends an error reply for a file system error
+func (c *conn) replyError(err error) {
+	c.reply(550, "%v", err)
+}
+
+// serve reads commands from the client and runs them until the
+// connection is closed
+func (c *conn) serve() {
+	fs.Infof(c.what, "Connection opened")
+	defer func() {
+		c.closePassive()
+		_ = c.ctrl.Close()
+		fs.Infof(c.what, "Connection closed")
+	}()
+	c.reply(220, "Welcome to rclone %s", fs.Version)
+	for !c.quit {
+		_ = c.ctrl.SetReadDeadline(time.Now().Add(c.s.idleTimeout))
+		if !c.r.Scan() {
+			err := c.r.Err()
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				c.reply(421, "Timeout waiting for command")
+			} else if err == bufio.ErrTooLong {
+				c.reply(500, "Command line too long")
+			} else if err != nil {
+				fs.Debugf(c.what, "Failed to read command: %v", err)
+			}
+			return
+		}
+		line := c.r.Text()
+		name, param := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			name, param = line[:i], line[i+1:]
+		}
+		name = strings.ToUpper(name)
+		if name == "PASS" {
+			fs.Debugf(c.what, "> PASS XXXX")
+		} else {
+			fs.Debugf(c.what, "> %s", line)
+		}
+		cmd, ok := commands[name]
+		switch {
+		case !ok:
+			c.reply(502, "Command %q not implemented", name)
+		case cmd.needLogin && !c.loggedIn:
+			c.reply(530, "Not logged in")
+		case cmd.write && c.readOnly:
+			c.reply(550, "Permission denied: server is read only")
+		default:
+			cmd.fn(c, param)
+		}
+		// Forget the start of a rename unless this was it
+		if name != "RNFR" {
+			c.renameSrc = ""
+		}
+	}
+}
+
+// buildPath returns the absolute FTP path for p relative to the cwd
+func (c *conn) buildPath(p string) string {
+	if !path.IsAbs(p) {
+		p = path.Join(c.cwd, p)
+	}
+	return path.Clean(p)
+}
+
+// stat returns the VFS node for the FTP path p
+func (c *conn) stat(p string) (vfs.Node, error) {
+	return c.vfs.Stat(c.buildPath(p))
+}
+
+// listArg strips any ls style options off a listing argument
+func listArg(param string) string {
+	for strings.HasPrefix(param, "-") {
+		i := strings.IndexByte(param, ' ')
+		if i < 0 {
+			return ""
+		}
+		param = strings.TrimLeft(param[i:], " ")
+	}
+	return param
+}
+
+func (c *conn) cmdUser(param string) {
+	c.user = param
+	c.loggedIn = false
+	c.reply(331, "User name OK, password required")
+}
+
+func (c *conn) cmdPass(param string) {
+	if c.user == "" {
+		c.reply(503, "Login with USER first")
+		return
+	}
+	if !c.s.checkLogin(c.user, param) {
+		fs.Infof(c.what, "Login failed for user %q", c.user)
+		c.reply(530, "Login incorrect")
+		return
+	}
+	c.loggedIn = true
+	c.readOnly = c.s.anonymous
+	fs.Infof(c.what, "Logged in as %q", c.user)
+	c.reply(230, "User logged in")
+}
+
+func (c *conn) cmdAuth(param string) {
+	if c.s.tlsConfig == nil {
+		c.reply(502, "TLS not configured")
+		return
+	}
+	if _, ok := c.ctrl.(*tls.Conn); ok {
+		c.reply(503, "Already using TLS")
+		return
+	}
+	switch strings.ToUpper(param) {
+	case "TLS", "TLS-C", "SSL":
+	default:
+		c.reply(504, "AUTH %s not supported", param)
+		return
+	}
+	c.reply(234, "AUTH %s successful", param)
+	tlsConn := tls.Server(c.ctrl, c.s.tlsConfig)
+	err := tlsConn.Handshake()
+	if err != nil {
+		fs.Errorf(c.what, "TLS handshake failed: %v", err)
+		c.quit = true
+		return
+	}
+	c.setCtrl(tlsConn)
+}
+
+func (c *conn) cmdPbsz(param string) {
+	if _, ok := c.ctrl.(*tls.Conn); !ok {
+		c.reply(503, "Use AUTH first")
+		return
+	}
+	c.reply(200, "PBSZ=0")
+}
+
+func (c *conn) cmdProt(param string) {
+	if _, ok := c.ctrl.(*tls.Conn); !ok {
+		c.reply(503, "Use AUTH first")
+		return
+	}
+	switch strings.ToUpper(param) {
+	case "C":
+		c.protected = false
+	case "P":
+		c.protected = true
+	default:
+		c.reply(504, "PROT %s not supported", param)
+		return
+	}
+	c.reply(200, "PROT %s OK", param)
+}
+
+func (c *conn) cmdFeat(param string) {
+	features := []string{
+		"EPSV",
+		"MDTM",
+		"MFMT",
+		"MLST type*;size*;modify*;",
+		"PASV",
+		"REST STREAM",
+		"SIZE",
+		"UTF8",
+	}
+	if c.s.tlsConfig != nil {
+		features = append(features, "AUTH TLS", "PBSZ", "PROT")
+	}
+	c.replyLines(211, "Features:", features, "End")
+}
+
+func (c *conn) cmdOpts(param string) {
+	if strings.ToUpper(param) == "UTF8 ON" {
+		c.reply(200, "UTF8 mode enabled")
+		return
+	}
+	c.reply(501, "Option not understood")
+}
+
+func (c *conn) cmdSyst(param string) {
+	c.reply(215, "UNIX Type: L8")
+}
+
+func (c *conn) cmdNoop(param string) {
+	c.reply(200, "OK")
+}
+
+func (c *conn) cmdQuit(param string) {
+	c.reply(221, "Goodbye")
+	c.quit = true
+}
+
+func (c *conn) cmdType(param string) {
+	// Everything is transferred as binary
+	switch strings.ToUpper(param) {
+	case "A", "A N", "I", "L 8":
+		c.reply(200, "Type set to %s", param)
+	default:
+		c.reply(504, "Type %s not supported", param)
+	}
+}
+
+func (c *conn) cmdMode(param string) {
+	if strings.ToUpper(param) != "S" {
+		c.reply(504, "Only stream mode is supported")
+		return
+	}
+	c.reply(200, "Mode set to S")
+}
+
+func (c *conn) cmdStru(param string) {
+	if strings.ToUpper(param) != "F" {
+		c.reply(504, "Only file structure is supported")
+		return
+	}
+	c.reply(200, "Structure set to F")
+}
+
+func (c *conn) cmdPwd(param string) {
+	c.reply(257, "%q is the current directory", c.cwd)
+}
+
+func (c *conn) cmdCwd(param string) {
+	p := c.buildPath(param)
+	node, err := c.vfs.Stat(p)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	if !node.IsDir() {
+		c.reply(550, "%q is not a directory", p)
+		return
+	}
+	c.cwd = p
+	c.reply(250, "Directory changed to %q", p)
+}
+
+func (c *conn) cmdCdup(param string) {
+	c.cmdCwd("..")
+}
+
+func (c *conn) cmdMkd(param string) {
+	p := c.buildPath(param)
+	dir, leaf, err := c.vfs.StatParent(p)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	if leaf == "" {
+		c.reply(550, "Directory %q already exists", p)
+		return
+	}
+	_, err = dir.Mkdir(leaf)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	c.reply(257, "%q created", p)
+}
+
+func (c *conn) cmdRmd(param string) {
+	node, err := c.stat(param)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	if !node.IsDir() {
+		c.reply(550, "%q is not a directory", c.buildPath(param))
+		return
+	}
+	err = node.Remove()
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	c.reply(250, "Directory removed")
+}
+
+func (c *conn) cmdDele(param string) {
+	node, err := c.stat(param)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	if node.IsDir() {
+		c.reply(550, "%q is a directory", c.buildPath(param))
+		return
+	}
+	err = node.Remove()
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	c.reply(250, "File removed")
+}
+
+func (c *conn) cmdRnfr(param string) {
+	p := c.buildPath(param)
+	_, err := c.vfs.Stat(p)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	c.renameSrc = p
+	c.reply(350, "Ready for RNTO")
+}
+
+func (c *conn) cmdRnto(param string) {
+	if c.renameSrc == "" {
+		c.reply(503, "Use RNFR first")
+		return
+	}
+	err := c.vfs.Rename(c.renameSrc, c.buildPath(param))
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	c.reply(250, "Rename successful")
+}
+
+func (c *conn) cmdSize(param string) {
+	node, err := c.stat(param)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	if node.IsDir() {
+		c.reply(550, "%q is a directory", c.buildPath(param))
+		return
+	}
+	c.reply(213, "%d", node.Size())
+}
+
+// mdtmFormat is the time format used by MDTM, MFMT and MLSD
+const mdtmFormat = "20060102150405"
+
+func (c *conn) cmdMdtm(param string) {
+	node, err := c.stat(param)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	c.reply(213, "%s", node.ModTime().UTC().Format(mdtmFormat))
+}
+
+func (c *conn) cmdMfmt(param string) {
+	i := strings.IndexByte(param, ' ')
+	if i < 0 {
+		c.reply(501, "Syntax: MFMT YYYYMMDDHHMMSS path")
+		return
+	}
+	modTime, err := time.ParseInLocation(mdtmFormat, param[:i], time.UTC)
+	if err != nil {
+		c.reply(501, "Bad time: %v", err)
+		return
+	}
+	node, err := c.stat(param[i+1:])
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	err = node.SetModTime(modTime)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	c.reply(213, "Modify=%s; %s", param[:i], param[i+1:])
+}
+
+func (c *conn) cmdRest(param string) {
+	offset, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || offset < 0 {
+		c.reply(501, "Bad restart offset %q", param)
+		return
+	}
+	c.restart = offset
+	c.reply(350, "Restarting at %d", offset)
+}
+
+func (c *conn) cmdAbor(param string) {
+	// Transfers are synchronous so there is never one to abort
+	c.closePassive()
+	c.reply(226, "No transfer to abort")
+}
+
+func (c *conn) cmdPort(param string) {
+	c.reply(502, "Active mode not supported - use PASV or EPSV")
+}
+
+// closePassive closes any pending passive listener
+func (c *conn) closePassive() {
+	if c.pasv != nil {
+		_ = c.pasv.Close()
+		c.pasv = nil
+	}
+}
+
+// listenPassive opens a new passive listener on the IP the client
+// connected to
+func (c *conn) listenPassive() error {
+	c.closePassive()
+	ip := c.ctrl.LocalAddr().(*net.TCPAddr).IP
+	ln, err := c.s.listenPassive(ip)
+	if err != nil {
+		return err
+	}
+	c.pasv = ln
+	return nil
+}
+
+func (c *conn) cmdPasv(param string) {
+	ip := c.ctrl.LocalAddr().(*net.TCPAddr).IP.To4()
+	if c.s.opt.PublicIP != "" {
+		ip = net.ParseIP(c.s.opt.PublicIP).To4()
+	}
+	if ip == nil {
+		c.reply(425, "PASV needs an IPv4 address - use EPSV")
+		return
+	}
+	err := c.listenPassive()
+	if err != nil {
+		c.reply(425, "Can't open passive connection: %v", err)
+		return
+	}
+	port := c.pasv.Addr().(*net.TCPAddr).Port
+	c.reply(227, "Entering Passive Mode (%d,%d,%d,%d,%d,%d)", ip[0], ip[1], ip[2], ip[3], port>>8, port&0xFF)
+}
+
+func (c *conn) cmdEpsv(param string) {
+	if strings.ToUpper(param) == "ALL" {
+		c.reply(200, "EPSV ALL OK")
+		return
+	}
+	err := c.listenPassive()
+	if err != nil {
+		c.reply(425, "Can't open passive connection: %v", err)
+		return
+	}
+	port := c.pasv.Addr().(*net.TCPAddr).Port
+	c.reply(229, "Entering Extended Passive Mode (|||%d|)", port)
+}
+
+// openData waits for the client to connect to the passive listener
+// and returns the data connection
+func (c *conn) openData() (net.Conn, error) {
+	if c.pasv == nil {
+		return nil, errors.New("use PASV or EPSV first")
+	}
+	ln := c.pasv
+	c.pasv = nil
+	defer func() {
+		_ = ln.Close()
+	}()
+	if tcpLn, ok := ln.(*net.TCPListener); ok {
+		_ = tcpLn.SetDeadline(time.Now().Add(dataTimeout))
+	}
+	dataConn, err := ln.Accept()
+	if err != nil {
+		return nil, err
+	}
+	// Only accept data connections from the client's address
+	// to stop other people stealing the transfer
+	ctrlIP := c.ctrl.RemoteAddr().(*net.TCPAddr).IP
+	dataIP := dataConn.RemoteAddr().(*net.TCPAddr).IP
+	if !ctrlIP.Equal(dataIP) {
+		_ = dataConn.Close()
+		return nil, errors.Errorf("data connection from %v doesn't match control connection from %v", dataIP, ctrlIP)
+	}
+	if c.protected {
+		dataConn = tls.Server(dataConn, c.s.tlsConfig)
+	}
+	return dataConn, nil
+}
+
+// transfer opens the data connection and calls fn with it, sending
+// the preliminary and completion replies
+func (c *conn) transfer(fn func(dataConn net.Conn) error) {
+	dataConn, err := c.openData()
+	if err != nil {
+		c.reply(425, "Can't open data connection: %v", err)
+		return
+	}
+	c.reply(150, "Opening data connection")
+	err = fn(dataConn)
+	closeErr := dataConn.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fs.Debugf(c.what, "Transfer failed: %v", err)
+		c.reply(426, "Transfer aborted: %v", err)
+		return
+	}
+	c.reply(226, "Transfer complete")
+}
+
+// takeRestart returns the REST offset and clears it
+func (c *conn) takeRestart() int64 {
+	offset := c.restart
+	c.restart = 0
+	return offset
+}
+
+func (c *conn) cmdRetr(param string) {
+	offset := c.takeRestart()
+	node, err := c.stat(param)
+	if err != nil {
+		c.closePassive()
+		c.replyError(err)
+		return
+	}
+	if node.IsDir() {
+		c.closePassive()
+		c.reply(550, "%q is a directory", c.buildPath(param))
+		return
+	}
+	handle, err := node.Open(os.O_RDONLY)
+	if err != nil {
+		c.closePassive()
+		c.replyError(err)
+		return
+	}
+	defer func() {
+		closeErr := handle.Close()
+		if closeErr != nil {
+			fs.Debugf(c.what, "Failed to close %q: %v", node.Path(), closeErr)
+		}
+	}()
+	if offset > 0 {
+		_, err = handle.Seek(offset, io.SeekStart)
+		if err != nil {
+			c.closePassive()
+			c.replyError(err)
+			return
+		}
+	}
+	c.transfer(func(dataConn net.Conn) error {
+		_, err := io.Copy(dataConn, handle)
+		return err
+	})
+}
+
+func (c *conn) cmdStor(param string) {
+	offset := c.takeRestart()
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	handle, err := c.vfs.OpenFile(c.buildPath(param), flags, 0777)
+	if err != nil {
+		c.closePassive()
+		c.replyError(err)
+		return
+	}
+	if offset > 0 {
+		_, err = handle.Seek(offset, io.SeekStart)
+		if err != nil {
+			_ = handle.Close()
+			c.closePassive()
+			c.replyError(err)
+			return
+		}
+	}
+	c.transfer(func(dataConn net.Conn) error {
+		_, err := io.Copy(handle, dataConn)
+		closeErr := handle.Close()
+		if err == nil {
+			err = closeErr
+		}
+		return err
+	})
+}
+
+// readDir returns the nodes in the directory p
+func (c *conn) readDir(p string) (nodes vfs.Nodes, err error) {
+	node, err := c.stat(p)
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := node.(*vfs.Dir)
+	if !ok {
+		return vfs.Nodes{node}, nil
+	}
+	return dir.ReadDirAll()
+}
+
+// listTransfer sends the listing of the directory in param to the
+// client formatting each node with format
+func (c *conn) listTransfer(param string, dirOnly bool, format func(node vfs.Node) string) {
+	p := listArg(param)
+	if dirOnly {
+		node, err := c.stat(p)
+		if err == nil && !node.IsDir() {
+			c.closePassive()
+			c.reply(550, "%q is not a directory", c.buildPath(p))
+			return
+		}
+	}
+	nodes, err := c.readDir(p)
+	if err != nil {
+		c.closePassive()
+		c.replyError(err)
+		return
+	}
+	c.transfer(func(dataConn net.Conn) error {
+		w := bufio.NewWriter(dataConn)
+		for _, node := range nodes {
+			_, err := fmt.Fprintf(w, "%s\r\n", format(node))
+			if err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	})
+}
+
+// lsFormat formats the node like the output of ls -l
+func lsFormat(node vfs.Node) string {
+	mode := "-rw-r--r--"
+	if node.IsDir() {
+		mode = "drwxr-xr-x"
+	}
+	modTime := node.ModTime()
+	timeFormat := "Jan _2 15:04"
+	if time.Since(modTime) > 180*24*time.Hour || modTime.After(time.Now()) {
+		timeFormat = "Jan _2  2006"
+	}
+	return fmt.Sprintf("%s 1 ftp ftp %12d %s %s", mode, node.Size(), modTime.Format(timeFormat), node.Name())
+}
+
+// mlsxFacts formats the facts about the node as used by MLSD and MLST
+func mlsxFacts(node vfs.Node) string {
+	kind := "file"
+	if node.IsDir() {
+		kind = "dir"
+	}
+	return fmt.Sprintf("type=%s;size=%d;modify=%s;", kind, node.Size(), node.ModTime().UTC().Format(mdtmFormat))
+}
+
+func (c *conn) cmdList(param string) {
+	c.listTransfer(param, false, lsFormat)
+}
+
+func (c *conn) cmdNlst(param string) {
+	c.listTransfer(param, false, func(node vfs.Node) string {
+		return node.Name()
+	})
+}
+
+func (c *conn) cmdMlsd(param string) {
+	c.listTransfer(param, true, func(node vfs.Node) string {
+		return mlsxFacts(node) + " " + node.Name()
+	})
+}
+
+func (c *conn) cmdMlst(param string) {
+	p := c.buildPath(param)
+	node, err := c.vfs.Stat(p)
+	if err != nil {
+		c.replyError(err)
+		return
+	}
+	c.replyLines(250, "Listing "+p, []string{mlsxFacts(node) + " " + p}, "End")
+}
@@ -0,0 +1,121 @@
+// Package ftp implements an FTP server to serve an rclone VFS
+package ftp
+
+import (
+	"github.com/artpar/rclone/cmd"
+	"github.com/artpar/rclone/cmd/serve/httplib"
+	"github.com/artpar/rclone/cmd/serve/httplib/httpflags"
+	"github.com/artpar/rclone/fs/config/flags"
+	"github.com/artpar/rclone/vfs"
+	"github.com/artpar/rclone/vfs/vfsflags"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Options contains options for the FTP server
+type Options struct {
+	ListenAddr   string          // Port to listen on
+	PublicIP     string          // Public IP address to advertise for passive connections
+	PassivePorts string          // Range of ports to use for passive connections
+	BasicUser    string          // single username for authentication
+	BasicPass    string          // password for BasicUser - if blank allow anonymous read only access
+	TLS          httplib.Options // only the SslCert, SslKey and ClientCA TLS options are used
+}
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{
+	ListenAddr:   "localhost:2121",
+	PassivePorts: "30000-32000",
+	BasicUser:    "anonymous",
+	TLS:          httplib.DefaultOpt,
+}
+
+// Opt is options set by command line flags
+var Opt = DefaultOpt
+
+// AddFlags adds flags for the ftp
+func AddFlags(flagSet *pflag.FlagSet, Opt *Options) {
+	flags.StringVarP(flagSet, &Opt.ListenAddr, "addr", "", Opt.ListenAddr, "IPaddress:Port or :Port to bind server to.")
+	flags.StringVarP(flagSet, &Opt.PublicIP, "public-ip", "", Opt.PublicIP, "Public IP address to advertise for passive connections.")
+	flags.StringVarP(flagSet, &Opt.PassivePorts, "passive-port", "", Opt.PassivePorts, "Passive port range to use.")
+	flags.StringVarP(flagSet, &Opt.BasicUser, "user", "", Opt.BasicUser, "User name for authentication.")
+	flags.StringVarP(flagSet, &Opt.BasicPass, "pass", "", Opt.BasicPass, "Password for authentication (leave blank for anonymous read only access).")
+	httpflags.AddTLSFlagsPrefix(flagSet, "", &Opt.TLS)
+}
+
+func init() {
+	vfsflags.AddFlags(Command.Flags())
+	AddFlags(Command.Flags(), &Opt)
+}
+
+// Command definition for cobra
+var Command = &cobra.Command{
+	Use:   "ftp remote:path",
+	Short: `Serve remote:path over FTP.`,
+	Long: `rclone serve ftp implements a basic ftp server to serve the
+remote over FTP protocol. This can be viewed with a ftp client
+or you can make a remote of type ftp to read and write it.
+
+You can use the filter flags (eg --include, --exclude) to control what
+is served.
+
+The server will log errors.  Use -v to see access logs.
+
+--bwlimit will be respected for file transfers.  Use --stats to
+control the stats printing.
+
+### Server options
+
+Use --addr to specify which IP address and port the server should
+listen on, eg --addr 1.2.3.4:8000 or --addr :8080 to listen to all
+IPs.  By default it only listens on localhost.  You can use port
+:0 to let the OS choose an available port.
+
+If you set --addr to listen on a public or LAN accessible IP address
+then using Authentication is advised - see the next section for info.
+
+Only passive mode transfers are supported.  Use --passive-port to
+set the range of ports the server will listen on for data
+connections, eg --passive-port 30000-32000, and make sure they are
+reachable by the clients.  Use --public-ip to set the IP address the
+server advertises in its PASV replies if it is behind NAT.
+
+#### Authentication
+
+Use --user and --pass to set a single username and password which
+clients must log in with.  These give full read/write access.
+
+If --pass is not set then the server runs in anonymous mode.  Clients
+must log in with the --user name ("anonymous" by default) but any
+password will be accepted, and the server will be read only.
+
+#### TLS
+
+By default this will serve over plain FTP.  If you supply the --cert
+and --key flags then clients may upgrade the connection with explicit
+TLS (AUTH TLS) and protect the data connections with PROT P.
+
+--cert should be a either a PEM encoded certificate or a concatenation
+of that with the CA certificate.  --key should be the PEM encoded
+private key.  If you wish to do client side certificate validation
+then supply --client-ca as the PEM encoded client certificate
+authority certificate.  These are the same flags as used by the http
+based servers.
+` + vfs.Help,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(1, 1, command, args)
+		f := cmd.NewFsSrc(args)
+		cmd.Run(false, true, command, func() error {
+			s, err := newServer(f, &Opt)
+			if err != nil {
+				return err
+			}
+			err = s.Serve()
+			if err != nil {
+				return err
+			}
+			s.Wait()
+			return nil
+		})
+	},
+}
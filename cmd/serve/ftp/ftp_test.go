@@ -0,0 +1,360 @@
+// Serve ftp tests set up a server and run the integration tests
+// for the ftp remote against it.
+//
+// We skip tests on platforms with troublesome character mappings
+
+//+build !windows,!darwin
+
+package ftp
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/textproto"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/artpar/rclone/backend/local"
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/obscure"
+	"github.com/artpar/rclone/fstest"
+	ftpclient "github.com/jlaffaye/ftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBindAddress = "localhost:0"
+	testUser        = "testuser"
+	testPass        = "testpass"
+)
+
+// TestFTP runs the ftp server then runs the unit tests for the
+// ftp remote against it.
+func TestFTP(t *testing.T) {
+	ctx := context.Background()
+	opt := DefaultOpt
+	opt.ListenAddr = testBindAddress
+	opt.PassivePorts = ""
+	opt.BasicUser = testUser
+	opt.BasicPass = testPass
+
+	fstest.Initialise()
+
+	fremote, _, clean, err := fstest.RandomRemote(*fstest.RemoteName, *fstest.SubDir)
+	assert.NoError(t, err)
+	defer clean()
+
+	err = fremote.Mkdir(ctx, "")
+	assert.NoError(t, err)
+
+	// Start the server
+	w, err := newServer(fremote, &opt)
+	require.NoError(t, err)
+	require.NoError(t, w.Serve())
+	defer w.Close()
+
+	// Change directory to run the tests
+	err = os.Chdir("../../../backend/ftp")
+	assert.NoError(t, err, "failed to cd to ftp remote")
+
+	// Run the ftp tests with an on the fly remote
+	args := []string{"test"}
+	if testing.Verbose() {
+		args = append(args, "-v")
+	}
+	if *fstest.Verbose {
+		args = append(args, "-verbose")
+	}
+	args = append(args, "-remote", "ftptest:")
+	cmd := exec.Command("go", args...)
+	addr := w.Addr()
+	colon := strings.LastIndex(addr, ":")
+	cmd.Env = append(os.Environ(),
+		"RCLONE_CONFIG_FTPTEST_TYPE=ftp",
+		"RCLONE_CONFIG_FTPTEST_HOST="+addr[:colon],
+		"RCLONE_CONFIG_FTPTEST_PORT="+addr[colon+1:],
+		"RCLONE_CONFIG_FTPTEST_USER="+testUser,
+		"RCLONE_CONFIG_FTPTEST_PASS="+obscure.MustObscure(testPass),
+	)
+	out, err := cmd.CombinedOutput()
+	if len(out) != 0 {
+		t.Logf("\n----------\n%s----------\n", string(out))
+	}
+	assert.NoError(t, err, "Running ftp integration tests")
+}
+
+// startLocalServer starts a server serving a temporary local
+// directory containing one file
+func startLocalServer(t *testing.T, opt Options) (s *server, dir string, cleanup func()) {
+	s, dir, cleanup = newLocalServer(t, opt)
+	require.NoError(t, s.Serve())
+	return s, dir, cleanup
+}
+
+// newLocalServer makes a server for a temporary local directory
+// without starting it
+func newLocalServer(t *testing.T, opt Options) (s *server, dir string, cleanup func()) {
+	fstest.Initialise()
+	dir, err := ioutil.TempDir("", "rclone-serve-ftp")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0600))
+	f, err := fs.NewFs(dir)
+	require.NoError(t, err)
+	opt.ListenAddr = testBindAddress
+	opt.PassivePorts = ""
+	s, err = newServer(f, &opt)
+	require.NoError(t, err)
+	return s, dir, func() {
+		s.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestAnonymousReadOnly(t *testing.T) {
+	s, dir, cleanup := startLocalServer(t, DefaultOpt)
+	defer cleanup()
+
+	c, err := ftpclient.Dial(s.Addr())
+	require.NoError(t, err)
+	defer func() { _ = c.Quit() }()
+
+	// Wrong user name is refused
+	assert.Error(t, c.Login("bob", "whatever"))
+
+	// Any password is OK for the anonymous user
+	require.NoError(t, c.Login("anonymous", "me@example.com"))
+
+	entries, err := c.List("/")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, "file.txt", entries[0].Name)
+	assert.Equal(t, uint64(5), entries[0].Size)
+
+	r, err := c.Retr("file.txt")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "hello", string(data))
+
+	// Writes are refused
+	assert.Error(t, c.Stor("new.txt", bytes.NewBufferString("data")))
+	assert.Error(t, c.MakeDir("newdir"))
+	assert.Error(t, c.Delete("file.txt"))
+	assert.Error(t, c.Rename("file.txt", "renamed.txt"))
+
+	_, err = os.Stat(filepath.Join(dir, "file.txt"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "new.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLogin(t *testing.T) {
+	opt := DefaultOpt
+	opt.BasicUser = testUser
+	opt.BasicPass = testPass
+	s, _, cleanup := startLocalServer(t, opt)
+	defer cleanup()
+
+	c, err := ftpclient.Dial(s.Addr())
+	require.NoError(t, err)
+	defer func() { _ = c.Quit() }()
+
+	assert.Error(t, c.Login(testUser, "wrong"))
+	_, err = c.List("/")
+	assert.Error(t, err)
+	require.NoError(t, c.Login(testUser, testPass))
+	_, err = c.List("/")
+	assert.NoError(t, err)
+}
+
+// writeTestCert writes a self signed certificate and key for
+// localhost into dir
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func TestExplicitTLS(t *testing.T) {
+	certDir, err := ioutil.TempDir("", "rclone-serve-ftp-cert")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(certDir) }()
+	opt := DefaultOpt
+	opt.TLS.SslCert, opt.TLS.SslKey = writeTestCert(t, certDir)
+	s, _, cleanup := startLocalServer(t, opt)
+	defer cleanup()
+
+	rawConn, err := net.Dial("tcp", s.Addr())
+	require.NoError(t, err)
+	defer func() { _ = rawConn.Close() }()
+	c := textproto.NewConn(rawConn)
+	_, _, err = c.ReadResponse(220)
+	require.NoError(t, err)
+
+	cmd := func(expectCode int, format string, args ...interface{}) string {
+		_, err := c.Cmd(format, args...)
+		require.NoError(t, err)
+		_, msg, err := c.ReadResponse(expectCode)
+		require.NoError(t, err, format)
+		return msg
+	}
+
+	// AUTH TLS is advertised
+	assert.Contains(t, cmd(211, "FEAT"), "AUTH TLS")
+
+	// PROT needs AUTH first
+	_, err = c.Cmd("PROT P")
+	require.NoError(t, err)
+	_, _, err = c.ReadResponse(200)
+	assert.Error(t, err)
+
+	// Upgrade the connection and carry on over TLS
+	cmd(234, "AUTH TLS")
+	tlsConn := tls.Client(rawConn, &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, tlsConn.Handshake())
+	c = textproto.NewConn(tlsConn)
+	cmd(331, "USER anonymous")
+	cmd(230, "PASS guest")
+	cmd(200, "PBSZ 0")
+	cmd(200, "PROT P")
+
+	// Fetch a listing over a protected data connection
+	msg := cmd(229, "EPSV")
+	start, end := strings.Index(msg, "|||"), strings.LastIndex(msg, "|")
+	require.True(t, start >= 0 && end > start, msg)
+	host, _, err := net.SplitHostPort(s.Addr())
+	require.NoError(t, err)
+	dataConn, err := net.Dial("tcp", net.JoinHostPort(host, msg[start+3:end]))
+	require.NoError(t, err)
+	_, err = c.Cmd("NLST")
+	require.NoError(t, err)
+	_, _, err = c.ReadResponse(150)
+	require.NoError(t, err)
+	tlsData := tls.Client(dataConn, &tls.Config{InsecureSkipVerify: true})
+	listing, err := ioutil.ReadAll(tlsData)
+	require.NoError(t, err)
+	_ = tlsData.Close()
+	_, _, err = c.ReadResponse(226)
+	require.NoError(t, err)
+	assert.Equal(t, "file.txt\r\n", string(listing))
+}
+
+func TestLongLine(t *testing.T) {
+	s, _, cleanup := startLocalServer(t, DefaultOpt)
+	defer cleanup()
+
+	rawConn, err := net.Dial("tcp", s.Addr())
+	require.NoError(t, err)
+	defer func() { _ = rawConn.Close() }()
+	c := textproto.NewConn(rawConn)
+	_, _, err = c.ReadResponse(220)
+	require.NoError(t, err)
+
+	_, err = c.Cmd("USER %s", strings.Repeat("a", 2*maxLineLength))
+	require.NoError(t, err)
+	_, _, err = c.ReadResponse(500)
+	require.NoError(t, err)
+
+	// The server closes the connection
+	_, err = c.ReadLine()
+	assert.Error(t, err)
+}
+
+func TestIdleTimeout(t *testing.T) {
+	s, _, cleanup := newLocalServer(t, DefaultOpt)
+	defer cleanup()
+	s.idleTimeout = 100 * time.Millisecond
+	require.NoError(t, s.Serve())
+
+	rawConn, err := net.Dial("tcp", s.Addr())
+	require.NoError(t, err)
+	defer func() { _ = rawConn.Close() }()
+	c := textproto.NewConn(rawConn)
+	_, _, err = c.ReadResponse(220)
+	require.NoError(t, err)
+
+	// Say nothing and the server should hang up
+	_ = rawConn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	_, _, err = c.ReadResponse(421)
+	require.NoError(t, err)
+	_, err = c.ReadLine()
+	assert.Error(t, err)
+	assert.False(t, isTimeout(err), "server didn't close the connection")
+}
+
+// isTimeout returns true if err is a network timeout
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+func TestParsePassivePorts(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		min     int
+		max     int
+		wantErr bool
+	}{
+		{"", 0, 0, false},
+		{"30000-32000", 30000, 32000, false},
+		{"2121", 2121, 2121, false},
+		{" 1 - 2 ", 1, 2, false},
+		{"32000-30000", 0, 0, true},
+		{"0-10", 0, 0, true},
+		{"1-70000", 0, 0, true},
+		{"potato", 0, 0, true},
+		{"1-potato", 0, 0, true},
+	} {
+		min, max, err := parsePassivePorts(test.in)
+		assert.Equal(t, test.wantErr, err != nil, test.in)
+		assert.Equal(t, test.min, min, test.in)
+		assert.Equal(t, test.max, max, test.in)
+	}
+}
+
+func TestListArg(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"dir", "dir"},
+		{"-la", ""},
+		{"-la dir", "dir"},
+		{"-a -l dir/sub dir", "dir/sub dir"},
+	} {
+		assert.Equal(t, test.want, listArg(test.in), test.in)
+	}
+}
@@ -0,0 +1,175 @@
+package ftp
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artpar/rclone/cmd/serve/httplib"
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/vfs"
+	"github.com/artpar/rclone/vfs/vfsflags"
+	"github.com/pkg/errors"
+)
+
+// server contains everything to run the server
+type server struct {
+	f           fs.Fs
+	opt         Options
+	vfs         *vfs.VFS
+	tlsConfig   *tls.Config // set if TLS is available
+	anonymous   bool        // set if allowing anonymous read only access
+	portMin     int         // lowest passive port to use - 0 to let the OS choose
+	portMax     int         // highest passive port to use
+	listener    net.Listener
+	idleTimeout time.Duration // close control connections idle for longer than this
+	waitChan    chan struct{}
+}
+
+// newServer makes a new server from the options, checking they are
+// valid
+func newServer(f fs.Fs, opt *Options) (*server, error) {
+	s := &server{
+		f:           f,
+		vfs:         vfs.New(f, &vfsflags.Opt),
+		opt:         *opt,
+		anonymous:   opt.BasicPass == "",
+		idleTimeout: idleTimeout,
+		waitChan:    make(chan struct{}),
+	}
+	var err error
+	s.portMin, s.portMax, err = parsePassivePorts(opt.PassivePorts)
+	if err != nil {
+		return nil, err
+	}
+	if opt.PublicIP != "" && net.ParseIP(opt.PublicIP).To4() == nil {
+		return nil, errors.Errorf("invalid --public-ip %q: must be an IPv4 address", opt.PublicIP)
+	}
+	if opt.TLS.SslCert != "" || opt.TLS.SslKey != "" {
+		s.tlsConfig, err = httplib.NewTLSConfig(opt.TLS.SslCert, opt.TLS.SslKey, opt.TLS.ClientCA)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// parsePassivePorts parses a port range like "30000-32000" or a
+// single port.  An empty range means let the OS choose.
+func parsePassivePorts(ports string) (portMin, portMax int, err error) {
+	if ports == "" {
+		return 0, 0, nil
+	}
+	parts := strings.SplitN(ports, "-", 2)
+	portMin, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid passive port range %q", ports)
+	}
+	portMax = portMin
+	if len(parts) == 2 {
+		portMax, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return 0, 0, errors.Errorf("invalid passive port range %q", ports)
+		}
+	}
+	if portMin <= 0 || portMax > 65535 || portMin > portMax {
+		return 0, 0, errors.Errorf("invalid passive port range %q", ports)
+	}
+	return portMin, portMax, nil
+}
+
+// listenPassive opens a listener for a passive data connection on
+// the ip passed in, using a port from the configured range.
+func (s *server) listenPassive(ip net.IP) (net.Listener, error) {
+	if s.portMin == 0 {
+		return net.Listen("tcp", net.JoinHostPort(ip.String(), "0"))
+	}
+	// Start at a random port in the range so concurrent
+	// connections don't all fight over the same ports
+	n := s.portMax - s.portMin + 1
+	start := rand.Intn(n)
+	for i := 0; i < n; i++ {
+		port := s.portMin + (start+i)%n
+		ln, err := net.Listen("tcp", net.JoinHostPort(ip.String(), strconv.Itoa(port)))
+		if err == nil {
+			return ln, nil
+		}
+	}
+	return nil, errors.Errorf("no free passive ports in range %d-%d", s.portMin, s.portMax)
+}
+
+// checkLogin returns whether the user and password are allowed to log
+// in
+func (s *server) checkLogin(user, pass string) bool {
+	if user != s.opt.BasicUser {
+		return false
+	}
+	return s.anonymous || subtle.ConstantTimeCompare([]byte(pass), []byte(s.opt.BasicPass)) == 1
+}
+
+// acceptConnections accepts new connections until the listener is
+// closed
+func (s *server) acceptConnections() {
+	for {
+		nConn, err := s.listener.Accept()
+		if err != nil {
+			if strings.Contains(err.Error(), "use of closed network connection") {
+				return
+			}
+			fs.Errorf(nil, "Failed to accept incoming connection: %v", err)
+			continue
+		}
+		c := newConn(s, nConn)
+		go c.serve()
+	}
+}
+
+// Serve starts the ftp server listening in the background.
+//
+// Use s.Close() and s.Wait() to shutdown server
+func (s *server) Serve() (err error) {
+	s.listener, err = net.Listen("tcp", s.opt.ListenAddr)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen for connection")
+	}
+	if s.anonymous {
+		fs.Logf(nil, "Serving FTP on %s read only to anonymous user %q", s.Addr(), s.opt.BasicUser)
+	} else {
+		fs.Logf(nil, "Serving FTP on %s to user %q", s.Addr(), s.opt.BasicUser)
+	}
+	go s.acceptConnections()
+	return nil
+}
+
+// Addr returns the address the server is listening on
+func (s *server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Wait blocks while the listener is open.
+func (s *server) Wait() {
+	<-s.waitChan
+}
+
+// Close shuts the running server down
+func (s *server) Close() {
+	err := s.listener.Close()
+	if err != nil {
+		fs.Errorf(nil, "Error on closing FTP server: %v", err)
+		return
+	}
+	close(s.waitChan)
+}
+
+// describeConn returns a description of the connection for logging
+func describeConn(c interface {
+	RemoteAddr() net.Addr
+	LocalAddr() net.Addr
+}) string {
+	return fmt.Sprintf("serve ftp %s->%s", c.RemoteAddr(), c.LocalAddr())
+}
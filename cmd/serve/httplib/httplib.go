@@ -13,6 +13,7 @@ import (
 
 	auth "github.com/abbot/go-http-auth"
 	"github.com/artpar/rclone/fs"
+	"github.com/pkg/errors"
 )
 
 // Globals
@@ -96,6 +97,36 @@ var DefaultOpt = Options{
 	MaxHeaderBytes:     4096,
 }
 
+// NewTLSConfig makes a TLS configuration for serving with the PEM
+// encoded certificate and private key files passed in.  If clientCA
+// is set then clients must present a certificate signed by it.
+func NewTLSConfig(certFile, keyFile, clientCA string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("need both --cert and --key to use TLS")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load key pair")
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS10, // disable SSL v3.0 and earlier
+		Certificates: []tls.Certificate{cert},
+	}
+	if clientCA != "" {
+		certpool := x509.NewCertPool()
+		pem, err := ioutil.ReadFile(clientCA)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read client certificate authority")
+		}
+		if !certpool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("can't parse client certificate authority")
+		}
+		tlsConfig.ClientCAs = certpool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
 // Server contains info about the running http server
 type Server struct {
 	Opt             Options
@@ -240,6 +271,11 @@ func (s *Server) Close() {
 	close(s.waitChan)
 }
 
+// UsingAuth returns true if authentication is required
+func (s *Server) UsingAuth() bool {
+	return s.Opt.HtPasswd != "" || s.Opt.BasicUser != "" || s.Opt.ClientCA != ""
+}
+
 // URL returns the serving address of this server
 func (s *Server) URL() string {
 	proto := "http"
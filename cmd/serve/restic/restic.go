@@ -2,6 +2,7 @@
 package restic
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -198,6 +199,7 @@ func makeRemote(path string) string {
 
 // handler reads incoming requests and dispatches them
 func (s *server) handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	w.Header().Set("Accept-Ranges", "bytes")
 	w.Header().Set("Server", "rclone/"+fs.Version)
 
@@ -209,22 +211,22 @@ func (s *server) handler(w http.ResponseWriter, r *http.Request) {
 	if strings.HasSuffix(path, "/") {
 		switch r.Method {
 		case "GET":
-			s.listObjects(w, r, remote)
+			s.listObjects(ctx, w, r, remote)
 		case "POST":
-			s.createRepo(w, r, remote)
+			s.createRepo(ctx, w, r, remote)
 		default:
 			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
 		}
 	} else {
 		switch r.Method {
 		case "GET":
-			s.getObject(w, r, remote)
+			s.getObject(ctx, w, r, remote)
 		case "HEAD":
-			s.headObject(w, r, remote)
+			s.headObject(ctx, w, r, remote)
 		case "POST":
-			s.postObject(w, r, remote)
+			s.postObject(ctx, w, r, remote)
 		case "DELETE":
-			s.deleteObject(w, r, remote)
+			s.deleteObject(ctx, w, r, remote)
 		default:
 			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
 		}
@@ -232,8 +234,8 @@ func (s *server) handler(w http.ResponseWriter, r *http.Request) {
 }
 
 // head request the remote
-func (s *server) headObject(w http.ResponseWriter, r *http.Request, remote string) {
-	o, err := s.f.NewObject(remote)
+func (s *server) headObject(ctx context.Context, w http.ResponseWriter, r *http.Request, remote string) {
+	o, err := s.f.NewObject(ctx, remote)
 	if err != nil {
 		fs.Debugf(remote, "Head request error: %v", err)
 		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
@@ -245,8 +247,8 @@ func (s *server) headObject(w http.ResponseWriter, r *http.Request, remote strin
 }
 
 // get the remote
-func (s *server) getObject(w http.ResponseWriter, r *http.Request, remote string) {
-	o, err := s.f.NewObject(remote)
+func (s *server) getObject(ctx context.Context, w http.ResponseWriter, r *http.Request, remote string) {
+	o, err := s.f.NewObject(ctx, remote)
 	if err != nil {
 		fs.Debugf(remote, "Get request error: %v", err)
 		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
@@ -286,7 +288,7 @@ func (s *server) getObject(w http.ResponseWriter, r *http.Request, remote string
 	}
 	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
 
-	file, err := o.Open(options...)
+	file, err := o.Open(ctx, options...)
 	if err != nil {
 		fs.Debugf(remote, "Get request open error: %v", err)
 		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
@@ -319,10 +321,10 @@ func (s *server) getObject(w http.ResponseWriter, r *http.Request, remote string
 }
 
 // postObject posts an object to the repository
-func (s *server) postObject(w http.ResponseWriter, r *http.Request, remote string) {
+func (s *server) postObject(ctx context.Context, w http.ResponseWriter, r *http.Request, remote string) {
 	if appendOnly {
 		// make sure the file does not exist yet
-		_, err := s.f.NewObject(remote)
+		_, err := s.f.NewObject(ctx, remote)
 		if err == nil {
 			fs.Errorf(remote, "Post request: file already exists, refusing to overwrite in append-only mode")
 			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
@@ -352,7 +354,7 @@ func (s *server) postObject(w http.ResponseWriter, r *http.Request, remote strin
 			}
 		}()
 		info := object.NewStaticObjectInfo(remote, time.Now(), r.ContentLength, true, nil, s.f)
-		_, err = s.f.Put(in, info)
+		_, err = s.f.Put(ctx, in, info)
 		if err != nil {
 			fs.Errorf(remote, "Post request put error: %v", err)
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -360,7 +362,7 @@ func (s *server) postObject(w http.ResponseWriter, r *http.Request, remote strin
 		}
 	} else {
 		// Size unknown use Rcat
-		_, err := operations.Rcat(s.f, remote, r.Body, time.Now())
+		_, err := operations.Rcat(ctx, s.f, remote, r.Body, time.Now())
 		if err != nil {
 			fs.Errorf(remote, "Post request rcat error: %v", err)
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -370,7 +372,7 @@ func (s *server) postObject(w http.ResponseWriter, r *http.Request, remote strin
 }
 
 // delete the remote
-func (s *server) deleteObject(w http.ResponseWriter, r *http.Request, remote string) {
+func (s *server) deleteObject(ctx context.Context, w http.ResponseWriter, r *http.Request, remote string) {
 	if appendOnly {
 		parts := strings.Split(r.URL.Path, "/")
 
@@ -381,14 +383,14 @@ func (s *server) deleteObject(w http.ResponseWriter, r *http.Request, remote str
 		}
 	}
 
-	o, err := s.f.NewObject(remote)
+	o, err := s.f.NewObject(ctx, remote)
 	if err != nil {
 		fs.Debugf(remote, "Delete request error: %v", err)
 		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 		return
 	}
 
-	if err := o.Remove(); err != nil {
+	if err := o.Remove(ctx); err != nil {
 		fs.Errorf(remote, "Delete request remove error: %v", err)
 		if err == fs.ErrorObjectNotFound {
 			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
@@ -419,7 +421,7 @@ func (ls *listItems) add(entry fs.DirEntry) {
 }
 
 // listObjects lists all Objects of a given type in an arbitrary order.
-func (s *server) listObjects(w http.ResponseWriter, r *http.Request, remote string) {
+func (s *server) listObjects(ctx context.Context, w http.ResponseWriter, r *http.Request, remote string) {
 	fs.Debugf(remote, "list request")
 
 	if r.Header.Get("Accept") != resticAPIV2 {
@@ -434,14 +436,14 @@ func (s *server) listObjects(w http.ResponseWriter, r *http.Request, remote stri
 	// if remote supports ListR use that directly, otherwise use recursive Walk
 	var err error
 	if ListR := s.f.Features().ListR; ListR != nil {
-		err = ListR(remote, func(entries fs.DirEntries) error {
+		err = ListR(ctx, remote, func(entries fs.DirEntries) error {
 			for _, entry := range entries {
 				ls.add(entry)
 			}
 			return nil
 		})
 	} else {
-		err = walk.Walk(s.f, remote, true, -1, func(path string, entries fs.DirEntries, err error) error {
+		err = walk.Walk(ctx, s.f, remote, true, -1, func(path string, entries fs.DirEntries, err error) error {
 			if err == nil {
 				for _, entry := range entries {
 					ls.add(entry)
@@ -473,7 +475,7 @@ func (s *server) listObjects(w http.ResponseWriter, r *http.Request, remote stri
 // createRepo creates repository directories.
 //
 // We don't bother creating the data dirs as rclone will create them on the fly
-func (s *server) createRepo(w http.ResponseWriter, r *http.Request, remote string) {
+func (s *server) createRepo(ctx context.Context, w http.ResponseWriter, r *http.Request, remote string) {
 	fs.Infof(remote, "Creating repository")
 
 	if r.URL.Query().Get("create") != "true" {
@@ -481,7 +483,7 @@ func (s *server) createRepo(w http.ResponseWriter, r *http.Request, remote strin
 		return
 	}
 
-	err := s.f.Mkdir(remote)
+	err := s.f.Mkdir(ctx, remote)
 	if err != nil {
 		fs.Errorf(remote, "Create repo failed to Mkdir: %v", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -490,7 +492,7 @@ func (s *server) createRepo(w http.ResponseWriter, r *http.Request, remote strin
 
 	for _, name := range []string{"data", "index", "keys", "locks", "snapshots"} {
 		dirRemote := path.Join(remote, name)
-		err := s.f.Mkdir(dirRemote)
+		err := s.f.Mkdir(ctx, dirRemote)
 		if err != nil {
 			fs.Errorf(dirRemote, "Create repo failed to Mkdir: %v", err)
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -4,8 +4,10 @@ import (
 	"errors"
 
 	"github.com/artpar/rclone/cmd"
+	"github.com/artpar/rclone/cmd/serve/ftp"
 	"github.com/artpar/rclone/cmd/serve/http"
 	"github.com/artpar/rclone/cmd/serve/restic"
+	"github.com/artpar/rclone/cmd/serve/sftp"
 	"github.com/artpar/rclone/cmd/serve/webdav"
 	"github.com/spf13/cobra"
 )
@@ -14,6 +16,10 @@ func init() {
 	Command.AddCommand(http.Command)
 	Command.AddCommand(webdav.Command)
 	Command.AddCommand(restic.Command)
+	Command.AddCommand(ftp.Command)
+	if sftp.Command != nil {
+		Command.AddCommand(sftp.Command)
+	}
 	cmd.Root.AddCommand(Command)
 }
 
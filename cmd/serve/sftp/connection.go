@@ -0,0 +1,264 @@
+// +build !plan9
+
+package sftp
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/vfs"
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// Hashes of "abc\n" which the sftp backend uses to see whether the
+// server supports md5sum/sha1sum
+const (
+	abcMD5  = "0bee89b07a248e27c83fc3d5951213c1"
+	abcSHA1 = "03cfd743661f07975fa2f1220c5194cbaff48451"
+)
+
+// shellUnescape removes the shell escaping the sftp backend applies
+// to paths, ie backslash escapes and single quoted strings
+func shellUnescape(str string) string {
+	var out strings.Builder
+	inQuote := false
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		switch {
+		case inQuote && c == '\'':
+			inQuote = false
+		case inQuote:
+			out.WriteByte(c)
+		case c == '\'':
+			inQuote = true
+		case c == '\\' && i+1 < len(str):
+			i++
+			out.WriteByte(str[i])
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// conn encapsulates the state of a single SSH connection
+type conn struct {
+	what             string
+	vfs              *vfs.VFS
+	handlers         sftp.Handlers
+	handshakeTimeout time.Duration // time allowed to ask for sftp or exec
+}
+
+// execCommand implements an extremely limited number of commands to
+// interoperate with the rclone sftp backend
+func (c *conn) execCommand(out io.Writer, command string) (err error) {
+	binary, args := command, ""
+	space := strings.Index(command, " ")
+	if space >= 0 {
+		binary = command[:space]
+		args = strings.TrimLeft(command[space+1:], " ")
+	}
+	fs.Debugf(c.what, "exec command: binary = %q, args = %q", binary, args)
+	switch binary {
+	case "md5sum", "sha1sum":
+		ht := hash.MD5
+		if binary == "sha1sum" {
+			ht = hash.SHA1
+		}
+		if args == "" {
+			return errors.Errorf("%s needs a file name", binary)
+		}
+		args = shellUnescape(args)
+		node, err := c.vfs.Stat(args)
+		if err != nil {
+			return errors.Wrapf(err, "hash failed finding file %q", args)
+		}
+		if node.IsDir() {
+			return errors.New("can't hash directory")
+		}
+		o, ok := node.DirEntry().(fs.ObjectInfo)
+		if !ok {
+			return errors.New("unexpected non file")
+		}
+		hashSum, err := o.Hash(ht)
+		if err != nil {
+			return errors.Wrap(err, "hash failed")
+		}
+		if hashSum == "" {
+			return errors.Errorf("%v hash not supported", ht)
+		}
+		_, err = fmt.Fprintf(out, "%s  %s", hashSum, args)
+		if err != nil {
+			return errors.Wrap(err, "send output failed")
+		}
+	case "echo":
+		// special cases for rclone command detection
+		var hashSum string
+		switch args {
+		case "'abc' | md5sum":
+			if !c.vfs.Fs().Hashes().Contains(hash.MD5) {
+				return errors.New("md5 hash not supported")
+			}
+			hashSum = abcMD5
+		case "'abc' | sha1sum":
+			if !c.vfs.Fs().Hashes().Contains(hash.SHA1) {
+				return errors.New("sha1 hash not supported")
+			}
+			hashSum = abcSHA1
+		default:
+			_, err = fmt.Fprintf(out, "%s", shellUnescape(args))
+			if err != nil {
+				return errors.Wrap(err, "send output failed")
+			}
+			return nil
+		}
+		_, err = fmt.Fprintf(out, "%s  -", hashSum)
+		if err != nil {
+			return errors.Wrap(err, "send output failed")
+		}
+	default:
+		return errors.Errorf("%q not implemented", command)
+	}
+	return nil
+}
+
+// handle a new incoming channel request
+func (c *conn) handleChannel(newChannel ssh.NewChannel) {
+	fs.Debugf(c.what, "Incoming channel: %s", newChannel.ChannelType())
+	if newChannel.ChannelType() != "session" {
+		err := newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+		fs.Debugf(c.what, "Unknown channel type: %s", newChannel.ChannelType())
+		if err != nil {
+			fs.Errorf(c.what, "Failed to reject unknown channel: %v", err)
+		}
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		fs.Errorf(c.what, "could not accept channel: %v", err)
+		return
+	}
+	defer func() {
+		err := channel.Close()
+		if err != nil && err != io.EOF {
+			fs.Debugf(c.what, "Failed to close channel: %v", err)
+		}
+	}()
+	fs.Debugf(c.what, "Channel accepted")
+
+	isSFTP := make(chan bool, 1)
+	closed := make(chan struct{})
+	var command execCommand
+
+	// Handle out-of-band requests
+	go func(in <-chan *ssh.Request) {
+		defer close(closed)
+		for req := range in {
+			fs.Debugf(c.what, "Request: %v", req.Type)
+			ok := false
+			var subSystemIsSFTP bool
+			var reply []byte
+			switch req.Type {
+			case "subsystem":
+				// The payload is the subsystem name preceded by its length
+				if len(req.Payload) < 4 {
+					fs.Debugf(c.what, "ignoring short subsystem request")
+					break
+				}
+				fs.Debugf(c.what, "Subsystem: %s", req.Payload[4:])
+				if string(req.Payload[4:]) == "sftp" {
+					ok = true
+					subSystemIsSFTP = true
+				}
+			case "exec":
+				err := ssh.Unmarshal(req.Payload, &command)
+				if err != nil {
+					fs.Errorf(c.what, "ignoring bad exec command: %v", err)
+				} else {
+					ok = true
+					subSystemIsSFTP = false
+				}
+			}
+			fs.Debugf(c.what, " - accepted: %v", ok)
+			err = req.Reply(ok, reply)
+			if err != nil {
+				fs.Errorf(c.what, "Failed to Reply to request: %v", err)
+				return
+			}
+			if ok {
+				// Wake up main routine after we have responded
+				// unless it has been woken already
+				select {
+				case isSFTP <- subSystemIsSFTP:
+				default:
+				}
+			}
+		}
+	}(requests)
+
+	// Wait for either subsystem "sftp" or "exec" request
+	timer := time.NewTimer(c.handshakeTimeout)
+	defer timer.Stop()
+	var startSFTP bool
+	select {
+	case startSFTP = <-isSFTP:
+	case <-closed:
+		fs.Debugf(c.what, "Channel closed before sftp or exec request")
+		return
+	case <-timer.C:
+		fs.Debugf(c.what, "Timed out waiting for sftp or exec request")
+		return
+	}
+	if startSFTP {
+		fs.Debugf(c.what, "Starting SFTP server")
+		server := sftp.NewRequestServer(channel, c.handlers)
+		defer func() {
+			err := server.Close()
+			if err != nil && err != io.EOF {
+				fs.Debugf(c.what, "Failed to close server: %v", err)
+			}
+		}()
+		err = server.Serve()
+		if err == io.EOF || err == nil {
+			fs.Debugf(c.what, "exited session")
+		} else {
+			fs.Errorf(c.what, "completed with error: %v", err)
+		}
+	} else {
+		var rc = uint32(0)
+		err := c.execCommand(channel, command.Command)
+		if err != nil {
+			rc = 1
+			_, errPrint := fmt.Fprintf(channel.Stderr(), "%v", err)
+			if errPrint != nil {
+				fs.Errorf(c.what, "Failed to write to stderr: %v", errPrint)
+			}
+			fs.Debugf(c.what, "command %q failed with error: %v", command.Command, err)
+		}
+		_, err = channel.SendRequest("exit-status", false, ssh.Marshal(exitStatus{RC: rc}))
+		if err != nil {
+			fs.Errorf(c.what, "Failed to send exit status: %v", err)
+		}
+	}
+}
+
+// Service the incoming Channel channel in go routine
+func (c *conn) handleChannels(chans <-chan ssh.NewChannel) {
+	for newChannel := range chans {
+		go c.handleChannel(newChannel)
+	}
+}
+
+type exitStatus struct {
+	RC uint32
+}
+
+type execCommand struct {
+	Command string
+}
@@ -0,0 +1,160 @@
+// +build !plan9
+
+package sftp
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/artpar/rclone/vfs"
+	"github.com/pkg/sftp"
+)
+
+// vfsHandler converts the VFS to be served by SFTP
+type vfsHandler struct {
+	*vfs.VFS
+}
+
+// newVFSHandler returns the Handlers to serve the VFS over SFTP
+func newVFSHandler(VFS *vfs.VFS) sftp.Handlers {
+	v := vfsHandler{VFS: VFS}
+	return sftp.Handlers{
+		FileGet:  v,
+		FilePut:  v,
+		FileCmd:  v,
+		FileList: v,
+	}
+}
+
+// Fileread opens the file for reading
+func (v vfsHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	file, err := v.OpenFile(r.Filepath, os.O_RDONLY, 0777)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Filewrite opens the file for writing
+func (v vfsHandler) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	file, err := v.OpenFile(r.Filepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Filecmd runs the commands which don't need a file handle
+func (v vfsHandler) Filecmd(r *sftp.Request) error {
+	switch r.Method {
+	case "Setstat":
+		node, err := v.Stat(r.Filepath)
+		if err != nil {
+			return err
+		}
+		attr := r.Attributes()
+		if r.AttrFlags().Acmodtime {
+			modTime := time.Unix(int64(attr.Mtime), 0)
+			err := node.SetModTime(modTime)
+			if err != nil {
+				return err
+			}
+		}
+		if r.AttrFlags().Size {
+			err := node.Truncate(int64(attr.Size))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "Rename":
+		err := v.Rename(r.Filepath, r.Target)
+		if err != nil {
+			return err
+		}
+	case "Rmdir", "Remove":
+		node, err := v.Stat(r.Filepath)
+		if err != nil {
+			return err
+		}
+		err = node.Remove()
+		if err != nil {
+			return err
+		}
+	case "Mkdir":
+		dir, leaf, err := v.StatParent(r.Filepath)
+		if err != nil {
+			return err
+		}
+		_, err = dir.Mkdir(leaf)
+		if err != nil {
+			return err
+		}
+	case "Symlink":
+		// The VFS doesn't support symlinks
+		return sftp.ErrSshFxOpUnsupported
+	}
+	return nil
+}
+
+type listerat []os.FileInfo
+
+// Modeled after strings.Reader's ReadAt() implementation
+func (f listerat) ListAt(ls []os.FileInfo, offset int64) (int, error) {
+	var n int
+	if offset >= int64(len(f)) {
+		return 0, io.EOF
+	}
+	n = copy(ls, f[offset:])
+	if n < len(ls) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Filelist lists or stats the file
+func (v vfsHandler) Filelist(r *sftp.Request) (l sftp.ListerAt, err error) {
+	var node vfs.Node
+	var handle vfs.Handle
+	switch r.Method {
+	case "List":
+		node, err = v.Stat(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+		if !node.IsDir() {
+			return nil, syscall.ENOTDIR
+		}
+		handle, err = node.Open(os.O_RDONLY)
+		if err != nil {
+			return nil, err
+		}
+		defer closeHandle(handle, &err)
+		var fileInfos []os.FileInfo
+		fileInfos, err = handle.Readdir(-1)
+		if err != nil {
+			return nil, err
+		}
+		return listerat(fileInfos), nil
+	case "Stat":
+		node, err = v.Stat(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+		return listerat([]os.FileInfo{node}), nil
+	case "Readlink":
+		// The VFS doesn't support symlinks
+	}
+	return nil, sftp.ErrSshFxOpUnsupported
+}
+
+// closeHandle closes the handle, setting *perr to the error if it
+// isn't already set
+func closeHandle(handle vfs.Handle, perr *error) {
+	err := handle.Close()
+	if *perr == nil {
+		*perr = err
+	}
+}
@@ -0,0 +1,202 @@
+// Serve sftp tests set up a server and run the integration tests
+// for the sftp remote against it.
+//
+// We skip tests on platforms with troublesome character mappings
+
+//+build !windows,!darwin,!plan9
+
+package sftp
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/artpar/rclone/backend/local"
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/config/obscure"
+	"github.com/artpar/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	testBindAddress = "localhost:0"
+	testUser        = "testuser"
+	testPass        = "testpass"
+)
+
+// TestSftp runs the sftp server then runs the unit tests for the
+// sftp remote against it.
+func TestSftp(t *testing.T) {
+	ctx := context.Background()
+	opt := DefaultOpt
+	opt.ListenAddr = testBindAddress
+	opt.User = testUser
+	opt.Pass = testPass
+
+	fstest.Initialise()
+
+	fremote, _, clean, err := fstest.RandomRemote(*fstest.RemoteName, *fstest.SubDir)
+	assert.NoError(t, err)
+	defer clean()
+
+	err = fremote.Mkdir(ctx, "")
+	assert.NoError(t, err)
+
+	// Start the server
+	w := newServer(fremote, &opt)
+	require.NoError(t, w.Serve())
+	defer w.Close()
+
+	// Change directory to run the tests
+	err = os.Chdir("../../../backend/sftp")
+	assert.NoError(t, err, "failed to cd to sftp remote")
+
+	// Run the sftp tests with an on the fly remote
+	args := []string{"test"}
+	if testing.Verbose() {
+		args = append(args, "-v")
+	}
+	if *fstest.Verbose {
+		args = append(args, "-verbose")
+	}
+	args = append(args, "-remote", "sftptest:")
+	cmd := exec.Command("go", args...)
+	addr := w.Addr()
+	colon := strings.LastIndex(addr, ":")
+	cmd.Env = append(os.Environ(),
+		"RCLONE_CONFIG_SFTPTEST_TYPE=sftp",
+		"RCLONE_CONFIG_SFTPTEST_HOST="+addr[:colon],
+		"RCLONE_CONFIG_SFTPTEST_PORT="+addr[colon+1:],
+		"RCLONE_CONFIG_SFTPTEST_USER="+testUser,
+		"RCLONE_CONFIG_SFTPTEST_PASS="+obscure.MustObscure(testPass),
+	)
+	out, err := cmd.CombinedOutput()
+	if len(out) != 0 {
+		t.Logf("\n----------\n%s----------\n", string(out))
+	}
+	assert.NoError(t, err, "Running sftp integration tests")
+}
+
+// TestSlowHandshake checks a client which doesn't complete the
+// handshake doesn't stop other clients logging in and is disconnected
+// after the handshake timeout
+func TestSlowHandshake(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-serve-sftp")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	keyPath := filepath.Join(dir, "id_rsa")
+	require.NoError(t, makeSSHKeyPair(2048, keyPath+".pub", keyPath))
+	f, err := fs.NewFs(dir)
+	require.NoError(t, err)
+
+	opt := DefaultOpt
+	opt.ListenAddr = testBindAddress
+	opt.NoAuth = true
+	opt.HostKeys = []string{keyPath}
+	w := newServer(f, &opt)
+	w.handshakeTimeout = time.Second
+	require.NoError(t, w.Serve())
+	defer w.Close()
+
+	// A client which connects but never sends anything
+	slow, err := net.Dial("tcp", w.Addr())
+	require.NoError(t, err)
+	defer func() {
+		_ = slow.Close()
+	}()
+
+	// Another client can still log in
+	client, err := ssh.Dial("tcp", w.Addr(), &ssh.ClientConfig{
+		User:            testUser,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         10 * time.Second,
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	// The slow client is disconnected after the timeout
+	require.NoError(t, slow.SetReadDeadline(time.Now().Add(10*time.Second)))
+	_, err = ioutil.ReadAll(slow)
+	assert.NoError(t, err, "expecting the server to close the connection")
+}
+
+// TestBadChannelRequests checks a malformed subsystem request is
+// refused and a session which never asks for sftp or exec is closed
+// after the handshake timeout
+func TestBadChannelRequests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-serve-sftp")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	keyPath := filepath.Join(dir, "id_rsa")
+	require.NoError(t, makeSSHKeyPair(2048, keyPath+".pub", keyPath))
+	f, err := fs.NewFs(dir)
+	require.NoError(t, err)
+
+	opt := DefaultOpt
+	opt.ListenAddr = testBindAddress
+	opt.NoAuth = true
+	opt.HostKeys = []string{keyPath}
+	w := newServer(f, &opt)
+	w.handshakeTimeout = time.Second
+	require.NoError(t, w.Serve())
+	defer w.Close()
+
+	client, err := ssh.Dial("tcp", w.Addr(), &ssh.ClientConfig{
+		User:            testUser,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         10 * time.Second,
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = client.Close()
+	}()
+	channel, reqs, err := client.OpenChannel("session", nil)
+	require.NoError(t, err)
+	go ssh.DiscardRequests(reqs)
+
+	// A subsystem request too short to hold a name is refused
+	ok, err := channel.SendRequest("subsystem", true, []byte{0, 0})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The session is closed after the timeout
+	done := make(chan error, 1)
+	go func() {
+		_, err := ioutil.ReadAll(channel)
+		done <- err
+	}()
+	select {
+	case err = <-done:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("expecting the server to close the channel")
+	}
+}
+
+func TestShellUnescape(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"/this/is/harmless", "/this/is/harmless"},
+		{"\\$\\(rm\\ -rf\\ /\\)", "$(rm -rf /)"},
+		{"/test/'\n'", "/test/\n"},
+		{":\\\"\\'", ":\"'"},
+		{"'quoted string'", "quoted string"},
+	} {
+		assert.Equal(t, test.want, shellUnescape(test.in), test.in)
+	}
+}
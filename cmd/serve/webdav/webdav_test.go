@@ -8,6 +8,7 @@
 package webdav
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"testing"
@@ -26,6 +27,7 @@ const (
 // TestWebDav runs the webdav server then runs the unit tests for the
 // webdav remote against it.
 func TestWebDav(t *testing.T) {
+	ctx := context.Background()
 	opt := httplib.DefaultOpt
 	opt.ListenAddr = testBindAddress
 
@@ -35,7 +37,7 @@ func TestWebDav(t *testing.T) {
 	assert.NoError(t, err)
 	defer clean()
 
-	err = fremote.Mkdir("")
+	err = fremote.Mkdir(ctx, "")
 	assert.NoError(t, err)
 
 	// Start the server
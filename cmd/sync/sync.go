@@ -1,6 +1,8 @@
 package sync
 
 import (
+	"context"
+
 	"github.com/artpar/rclone/cmd"
 	"github.com/artpar/rclone/fs/sync"
 	"github.com/spf13/cobra"
@@ -37,7 +39,7 @@ go there.
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, fdst := cmd.NewFsSrcDst(args)
 		cmd.Run(true, true, command, func() error {
-			return sync.Sync(fdst, fsrc)
+			return sync.Sync(context.Background(), fdst, fsrc)
 		})
 	},
 }
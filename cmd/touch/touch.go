@@ -2,6 +2,7 @@ package touch
 
 import (
 	"bytes"
+	"context"
 	"time"
 
 	"github.com/artpar/rclone/cmd"
@@ -33,13 +34,13 @@ var commandDefintion = &cobra.Command{
 		cmd.CheckArgs(1, 1, command, args)
 		fsrc, srcFileName := cmd.NewFsDstFile(args)
 		cmd.Run(true, false, command, func() error {
-			return Touch(fsrc, srcFileName)
+			return Touch(context.Background(), fsrc, srcFileName)
 		})
 	},
 }
 
 //Touch create new file or change file modification time.
-func Touch(fsrc fs.Fs, srcFileName string) error {
+func Touch(ctx context.Context, fsrc fs.Fs, srcFileName string) error {
 	timeAtr := time.Now()
 	if timeAsArgument != "" {
 		layout := defaultLayout
@@ -52,12 +53,12 @@ func Touch(fsrc fs.Fs, srcFileName string) error {
 		}
 		timeAtr = timeAtrFromFlags
 	}
-	file, err := fsrc.NewObject(srcFileName)
+	file, err := fsrc.NewObject(ctx, srcFileName)
 	if err != nil {
 		if !notCreateNewFile {
 			var buffer []byte
 			src := object.NewStaticObjectInfo(srcFileName, timeAtr, int64(len(buffer)), true, nil, fsrc)
-			_, err = fsrc.Put(bytes.NewBuffer(buffer), src)
+			_, err = fsrc.Put(ctx, bytes.NewBuffer(buffer), src)
 			if err != nil {
 				return err
 			}
@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -109,15 +110,15 @@ short options as they conflict with rclone's short options.
 			opts.DeepLevel = fs.Config.MaxDepth
 		}
 		cmd.Run(false, false, command, func() error {
-			return Tree(fsrc, outFile, &opts)
+			return Tree(context.Background(), fsrc, outFile, &opts)
 		})
 		return nil
 	},
 }
 
 // Tree lists fsrc to outFile using the Options passed in
-func Tree(fsrc fs.Fs, outFile io.Writer, opts *tree.Options) error {
-	dirs, err := walk.NewDirTree(fsrc, "", false, opts.DeepLevel)
+func Tree(ctx context.Context, fsrc fs.Fs, outFile io.Writer, opts *tree.Options) error {
+	dirs, err := walk.NewDirTree(ctx, fsrc, "", false, opts.DeepLevel)
 	if err != nil {
 		return err
 	}
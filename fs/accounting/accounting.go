@@ -18,6 +18,32 @@ import (
 // transfer limit is reached.
 var ErrorMaxTransferLimitReached = fserrors.FatalError(errors.New("Max transfer limit reached as set by --max-transfer"))
 
+// ErrorMaxTransferLimitReachedGraceful is returned when the max
+// transfer limit is reached with --cutoff-mode soft or cautious. The
+// transfers in progress are allowed to finish.
+var ErrorMaxTransferLimitReachedGraceful = fserrors.NoRetryError(errors.New("Max transfer limit reached as set by --max-transfer"))
+
+// ErrorMaxDurationReached is returned when the max duration is
+// reached with --cutoff-mode hard.
+var ErrorMaxDurationReached = fserrors.FatalError(errors.New("Max transfer duration reached as set by --max-duration"))
+
+// ErrorMaxDurationReachedGraceful is returned when the max duration
+// is reached with --cutoff-mode soft or cautious. The transfers in
+// progress are allowed to finish.
+var ErrorMaxDurationReachedGraceful = fserrors.NoRetryError(errors.New("Max transfer duration reached as set by --max-duration"))
+
+// IsCutoffError returns true if err was caused by reaching the limits
+// set with --max-transfer or --max-duration
+func IsCutoffError(err error) bool {
+	_, err = fserrors.Cause(err)
+	switch err {
+	case ErrorMaxTransferLimitReached, ErrorMaxTransferLimitReachedGraceful,
+		ErrorMaxDurationReached, ErrorMaxDurationReachedGraceful:
+		return true
+	}
+	return false
+}
+
 // Account limits and accounts for one transfer
 type Account struct {
 	// The mutex is to make sure Read() and Close() aren't called
@@ -137,21 +163,28 @@ func (acc *Account) averageLoop() {
 	}
 }
 
-// read bytes from the io.Reader passed in and account them
-func (acc *Account) read(in io.Reader, p []byte) (n int, err error) {
+// checkRead checks the transfer limit and starts the clock before a
+// read
+//
+// The limit is only enforced here with --cutoff-mode hard - the other
+// modes check it with Stats.ReserveTransfer before the transfer is
+// started, which operations.Copy and operations.Rcat do.
+func (acc *Account) checkRead() error {
 	acc.statmu.Lock()
-	if acc.max >= 0 && Stats.GetBytes() >= acc.max {
-		acc.statmu.Unlock()
-		return 0, ErrorMaxTransferLimitReached
+	defer acc.statmu.Unlock()
+	if acc.max >= 0 && fs.Config.CutoffMode == fs.CutoffModeHard && Stats.GetBytes() >= acc.max {
+		return ErrorMaxTransferLimitReached
 	}
 	// Set start time.
 	if acc.start.IsZero() {
 		acc.start = time.Now()
 	}
-	acc.statmu.Unlock()
-
-	n, err = in.Read(p)
+	return nil
+}
 
+// accountRead updates the stats with n bytes read and limits the
+// bandwidth
+func (acc *Account) accountRead(n int) {
 	// Update Stats
 	acc.statmu.Lock()
 	acc.lpBytes += n
@@ -161,6 +194,16 @@ func (acc *Account) read(in io.Reader, p []byte) (n int, err error) {
 	Stats.Bytes(int64(n))
 
 	limitBandwidth(n)
+}
+
+// read bytes from the io.Reader passed in and account them
+func (acc *Account) read(in io.Reader, p []byte) (n int, err error) {
+	err = acc.checkRead()
+	if err != nil {
+		return 0, err
+	}
+	n, err = in.Read(p)
+	acc.accountRead(n)
 	return
 }
 
@@ -171,6 +214,20 @@ func (acc *Account) Read(p []byte) (n int, err error) {
 	return acc.read(acc.in, p)
 }
 
+// AccountRead accounts for n bytes read by some other means, for
+// example by several streams reading parts of the same file.
+//
+// It returns ErrorMaxTransferLimitReached if --max-transfer has been
+// reached.
+func (acc *Account) AccountRead(n int) error {
+	err := acc.checkRead()
+	if err != nil {
+		return err
+	}
+	acc.accountRead(n)
+	return nil
+}
+
 // Close the object
 func (acc *Account) Close() error {
 	acc.mu.Lock()
@@ -2,6 +2,7 @@ package accounting
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"strings"
@@ -108,6 +109,93 @@ func TestAccountRead(t *testing.T) {
 	assert.NoError(t, acc.Close())
 }
 
+func TestAccountAccountRead(t *testing.T) {
+	old := fs.Config.MaxTransfer
+	fs.Config.MaxTransfer = 15
+	defer func() {
+		fs.Config.MaxTransfer = old
+	}()
+	Stats.ResetCounters()
+
+	acc := NewAccountSizeName(ioutil.NopCloser(bytes.NewBuffer(nil)), 20, "test")
+	assert.True(t, acc.start.IsZero())
+
+	assert.NoError(t, acc.AccountRead(10))
+	assert.False(t, acc.start.IsZero())
+	assert.Equal(t, 10, acc.lpBytes)
+	assert.Equal(t, int64(10), acc.bytes)
+	assert.Equal(t, int64(10), Stats.bytes)
+
+	assert.NoError(t, acc.AccountRead(10))
+	assert.Equal(t, int64(20), acc.bytes)
+	assert.Equal(t, ErrorMaxTransferLimitReached, acc.AccountRead(1))
+	assert.Equal(t, int64(20), acc.bytes)
+
+	assert.NoError(t, acc.Close())
+}
+
+func TestAccountAccountReadSoft(t *testing.T) {
+	oldMaxTransfer, oldCutoffMode := fs.Config.MaxTransfer, fs.Config.CutoffMode
+	fs.Config.MaxTransfer = 15
+	fs.Config.CutoffMode = fs.CutoffModeSoft
+	defer func() {
+		fs.Config.MaxTransfer, fs.Config.CutoffMode = oldMaxTransfer, oldCutoffMode
+	}()
+	Stats.ResetCounters()
+
+	acc := NewAccountSizeName(ioutil.NopCloser(bytes.NewBuffer(nil)), 20, "test")
+	assert.NoError(t, acc.AccountRead(10))
+	assert.NoError(t, acc.AccountRead(10))
+	// Transfers in progress may continue past the limit
+	assert.NoError(t, acc.AccountRead(1))
+	assert.Equal(t, int64(21), acc.bytes)
+
+	assert.NoError(t, acc.Close())
+}
+
+func TestStatsReserveTransfer(t *testing.T) {
+	oldMaxTransfer, oldCutoffMode := fs.Config.MaxTransfer, fs.Config.CutoffMode
+	defer func() {
+		fs.Config.MaxTransfer, fs.Config.CutoffMode = oldMaxTransfer, oldCutoffMode
+	}()
+	fs.Config.MaxTransfer = 100
+
+	for _, test := range []struct {
+		mode  fs.CutoffMode
+		bytes int64
+		sizes []int64
+		want  []error
+	}{
+		{fs.CutoffModeHard, 200, []int64{150}, []error{nil}},
+		{fs.CutoffModeSoft, 0, []int64{150, 10}, []error{nil, nil}},
+		{fs.CutoffModeSoft, 100, []int64{10}, []error{ErrorMaxTransferLimitReachedGraceful}},
+		{fs.CutoffModeCautious, 0, []int64{150, 60, 40, 1}, []error{ErrorMaxTransferLimitReachedGraceful, nil, nil, ErrorMaxTransferLimitReachedGraceful}},
+		{fs.CutoffModeCautious, 0, []int64{-1, 100, -1}, []error{nil, nil, nil}},
+		{fs.CutoffModeCautious, 100, []int64{-1}, []error{ErrorMaxTransferLimitReachedGraceful}},
+	} {
+		fs.Config.CutoffMode = test.mode
+		s := NewStats()
+		s.Bytes(test.bytes)
+		for i, size := range test.sizes {
+			assert.Equal(t, test.want[i], s.ReserveTransfer(size), fmt.Sprintf("mode=%v bytes=%d size[%d]=%d", test.mode, test.bytes, i, size))
+		}
+	}
+
+	// No limit
+	fs.Config.MaxTransfer = -1
+	fs.Config.CutoffMode = fs.CutoffModeCautious
+	assert.NoError(t, NewStats().ReserveTransfer(1<<40))
+}
+
+func TestIsCutoffError(t *testing.T) {
+	assert.True(t, IsCutoffError(ErrorMaxTransferLimitReached))
+	assert.True(t, IsCutoffError(ErrorMaxTransferLimitReachedGraceful))
+	assert.True(t, IsCutoffError(ErrorMaxDurationReached))
+	assert.True(t, IsCutoffError(ErrorMaxDurationReachedGraceful))
+	assert.False(t, IsCutoffError(nil))
+	assert.False(t, IsCutoffError(io.EOF))
+}
+
 func TestAccountString(t *testing.T) {
 	in := ioutil.NopCloser(bytes.NewBuffer([]byte{1, 2, 3}))
 	acc := NewAccountSizeName(in, 3, "test")
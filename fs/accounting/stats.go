@@ -2,13 +2,14 @@ package accounting
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/ncw/rclone/fs"
-	"github.com/ncw/rclone/fs/rc"
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/rc"
 )
 
 var (
@@ -76,6 +77,7 @@ type StatsInfo struct {
 	deletes      int64
 	start        time.Time
 	inProgress   *inProgress
+	reserved     int64 // bytes of transfers started with --cutoff-mode cautious
 }
 
 // NewStats cretates an initialised StatsInfo
@@ -89,7 +91,7 @@ func NewStats() *StatsInfo {
 }
 
 // RemoteStats returns stats for rc
-func (s *StatsInfo) RemoteStats(in rc.Params) (out rc.Params, err error) {
+func (s *StatsInfo) RemoteStats(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	out = make(rc.Params)
 	s.mu.RLock()
 	dt := time.Now().Sub(s.start)
@@ -196,6 +198,49 @@ func (s *StatsInfo) GetBytes() int64 {
 	return s.bytes
 }
 
+// Speed returns the average transfer speed in bytes/s since the
+// stats were started
+func (s *StatsInfo) Speed() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	dt := time.Now().Sub(s.start)
+	if dt <= 0 {
+		return 0
+	}
+	return float64(s.bytes) / dt.Seconds()
+}
+
+// ReserveTransfer checks whether a transfer of size bytes may be
+// started under the --max-transfer limit.
+//
+// With --cutoff-mode hard this always succeeds as the limit is
+// enforced while reading. With --cutoff-mode soft no new transfers
+// are started once the limit has been reached. With --cutoff-mode
+// cautious the size of each transfer is reserved in advance and a
+// transfer which would take the total over the limit is refused.
+// Transfers of unknown size are treated as in soft mode.
+//
+// It returns ErrorMaxTransferLimitReachedGraceful if the transfer
+// should not be started.
+func (s *StatsInfo) ReserveTransfer(size int64) error {
+	max := int64(fs.Config.MaxTransfer)
+	if max < 0 || fs.Config.CutoffMode == fs.CutoffModeHard {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.bytes >= max {
+		return ErrorMaxTransferLimitReachedGraceful
+	}
+	if fs.Config.CutoffMode == fs.CutoffModeCautious && size >= 0 {
+		if s.reserved+size > max {
+			return ErrorMaxTransferLimitReachedGraceful
+		}
+		s.reserved += size
+	}
+	return nil
+}
+
 // Errors updates the stats for errors
 func (s *StatsInfo) Errors(errors int64) {
 	s.mu.Lock()
@@ -234,6 +279,7 @@ func (s *StatsInfo) ResetCounters() {
 	s.checks = 0
 	s.transfers = 0
 	s.deletes = 0
+	s.reserved = 0
 }
 
 // ResetErrors sets the errors count to 0
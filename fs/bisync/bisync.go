@@ -0,0 +1,483 @@
+// Package bisync keeps two paths in step by propagating the changes
+// made to either of them since the last run to the other
+package bisync
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/accounting"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/fs/march"
+	"github.com/artpar/rclone/fs/operations"
+	"github.com/pkg/errors"
+)
+
+// ErrTooManyDeletes is returned when more files would be deleted than
+// Options.MaxDeletePercent allows
+var ErrTooManyDeletes = errors.New("too many deletes")
+
+// Options controls Bisync
+type Options struct {
+	Resync           bool   // make the listings from scratch by copying everything both ways
+	MaxDeletePercent int    // abort if more than this percentage of either path would be deleted
+	Force            bool   // ignore MaxDeletePercent
+	StateDir         string // directory the listings are kept in between runs
+}
+
+// DefaultOptions returns the default options for Bisync keeping the
+// listings in stateDir
+func DefaultOptions(stateDir string) Options {
+	return Options{
+		MaxDeletePercent: 50,
+		StateDir:         stateDir,
+	}
+}
+
+// change describes what happened to a file on one path since the
+// last run
+type change int
+
+const (
+	unchanged change = iota
+	created
+	modified
+	deleted
+)
+
+// side is one of the two paths being synced
+type side struct {
+	f    fs.Fs
+	name string    // path1 or path2 for the logs
+	ht   hash.Type // hash stored in the listings or hash.None
+	prev listing   // listing from the last run
+	cur  map[string]fs.Object
+
+	mu   sync.Mutex
+	next listing // listing to store at the end of this run
+}
+
+func newSide(f fs.Fs, name string) *side {
+	s := &side{
+		f:    f,
+		name: name,
+		ht:   hash.None,
+		prev: listing{},
+		cur:  make(map[string]fs.Object),
+	}
+	if fs.Config.CheckSum {
+		s.ht = f.Hashes().GetOne()
+	}
+	return s
+}
+
+// set records dst as the new state of remote. If dst is nil the
+// state is made from src instead.
+func (s *side) set(remote string, dst, src fs.Object) {
+	var rec record
+	if dst != nil {
+		rec = newRecord(dst, s.ht)
+	} else {
+		rec = newRecord(src, hash.None)
+	}
+	s.mu.Lock()
+	s.next[remote] = rec
+	s.mu.Unlock()
+}
+
+// remove records remote as not existing any more
+func (s *side) remove(remote string) {
+	s.mu.Lock()
+	delete(s.next, remote)
+	s.mu.Unlock()
+}
+
+// changeOf works out what happened to remote since the last run
+func (s *side) changeOf(remote string) change {
+	prev, hadPrev := s.prev[remote]
+	o := s.cur[remote]
+	switch {
+	case !hadPrev && o == nil:
+		return unchanged
+	case !hadPrev:
+		return created
+	case o == nil:
+		return deleted
+	case s.differs(prev, o):
+		return modified
+	}
+	return unchanged
+}
+
+// differs returns true if o doesn't match the record from the last run
+func (s *side) differs(prev record, o fs.Object) bool {
+	if prev.Size != o.Size() {
+		return true
+	}
+	if window := fs.GetModifyWindow(s.f); window != fs.ModTimeNotSupported {
+		dt := o.ModTime().Sub(prev.ModTime)
+		if dt < -window || dt > window {
+			return true
+		}
+	}
+	if prev.Hash != "" && s.ht != hash.None {
+		sum, err := o.Hash(s.ht)
+		if err == nil && sum != "" && sum != prev.Hash {
+			return true
+		}
+	}
+	return false
+}
+
+// task is an action planned for one file
+type task func(ctx context.Context) error
+
+// bisync holds the state of a run
+type bisync struct {
+	opt      *Options
+	s1, s2   *side
+	mu       sync.Mutex // protects the march callbacks
+	tasks    []task
+	deletes1 int             // files to be deleted from path1
+	deletes2 int             // files to be deleted from path2
+	reserved map[string]bool // names allocated for conflicts
+}
+
+// Bisync propagates the changes made to f1 and f2 since the last run
+// to the other path, so they end up the same.
+//
+// New, changed and deleted files are propagated. If a file was
+// changed on both paths the older copy is renamed so both are kept.
+//
+// If opt.Resync is set then the listings from the last run aren't
+// needed - the files missing from each path are copied to it and if
+// a file differs the copy on f1 wins.
+func Bisync(ctx context.Context, f1, f2 fs.Fs, opt *Options) error {
+	b := &bisync{
+		opt:      opt,
+		s1:       newSide(f1, "path1"),
+		s2:       newSide(f2, "path2"),
+		reserved: make(map[string]bool),
+	}
+	stateFile := filepath.Join(opt.StateDir, stateFileName(f1, f2))
+	if !opt.Resync {
+		st, err := loadState(stateFile)
+		if os.IsNotExist(errors.Cause(err)) {
+			return errors.Errorf("no listings from a previous run found in %q - run with --resync first", stateFile)
+		} else if err != nil {
+			return err
+		}
+		b.s1.prev, b.s2.prev = st.Path1, st.Path2
+	}
+	err := b.list(ctx)
+	if err != nil {
+		return err
+	}
+	b.plan()
+	err = b.checkDeletes()
+	if err != nil {
+		return err
+	}
+	err = b.run(ctx)
+	if err != nil {
+		return err
+	}
+	if fs.Config.DryRun {
+		return nil
+	}
+	return saveState(stateFile, &state{
+		Path1: b.s1.next,
+		Path2: b.s2.next,
+	})
+}
+
+// list reads the files on both paths in lock step
+func (b *bisync) list(ctx context.Context) error {
+	errorsBefore := accounting.Stats.GetErrors()
+	march.New(ctx, b.s2.f, b.s1.f, "", b).Run()
+	if accounting.Stats.GetErrors() != errorsBefore {
+		return errors.New("failed to list both paths - not syncing")
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	for _, s := range []*side{b.s1, b.s2} {
+		s.next = make(listing, len(s.cur))
+		for remote, o := range s.cur {
+			s.next[remote] = newRecord(o, s.ht)
+		}
+	}
+	return nil
+}
+
+// add o to the current files of s
+func (b *bisync) add(s *side, entry fs.DirEntry) (recurse bool) {
+	o, ok := entry.(fs.Object)
+	if !ok {
+		return true
+	}
+	b.mu.Lock()
+	s.cur[o.Remote()] = o
+	b.mu.Unlock()
+	return false
+}
+
+// SrcOnly is called for a DirEntry found only in path1
+func (b *bisync) SrcOnly(src fs.DirEntry) (recurse bool) {
+	return b.add(b.s1, src)
+}
+
+// DstOnly is called for a DirEntry found only in path2
+func (b *bisync) DstOnly(dst fs.DirEntry) (recurse bool) {
+	return b.add(b.s2, dst)
+}
+
+// Match is called for a DirEntry found on both paths
+func (b *bisync) Match(dst, src fs.DirEntry) (recurse bool) {
+	recurse = b.add(b.s1, src)
+	b.add(b.s2, dst)
+	return recurse
+}
+
+// plan works out the tasks needed for each file
+func (b *bisync) plan() {
+	seen := make(map[string]bool)
+	var remotes []string
+	for _, s := range []*side{b.s1, b.s2} {
+		for remote := range s.prev {
+			if !seen[remote] {
+				seen[remote] = true
+				remotes = append(remotes, remote)
+			}
+		}
+		for remote := range s.cur {
+			if !seen[remote] {
+				seen[remote] = true
+				remotes = append(remotes, remote)
+			}
+		}
+	}
+	sort.Strings(remotes)
+	for _, remote := range remotes {
+		var t task
+		if b.opt.Resync {
+			t = b.planResync(remote)
+		} else {
+			t = b.planFile(remote)
+		}
+		if t != nil {
+			b.tasks = append(b.tasks, t)
+		}
+	}
+}
+
+// planResync returns the task to make remote the same on both paths
+// without using the listings
+func (b *bisync) planResync(remote string) task {
+	o1, o2 := b.s1.cur[remote], b.s2.cur[remote]
+	switch {
+	case o1 == nil && o2 == nil:
+		return nil
+	case o2 == nil:
+		return b.copyTask(b.s1, b.s2, remote)
+	case o1 == nil:
+		return b.copyTask(b.s2, b.s1, remote)
+	}
+	return b.equalOr(remote, b.copyTask(b.s1, b.s2, remote))
+}
+
+// planFile returns the task to propagate the changes to remote since
+// the last run or nil if there is nothing to do
+func (b *bisync) planFile(remote string) task {
+	c1, c2 := b.s1.changeOf(remote), b.s2.changeOf(remote)
+	switch {
+	case c1 == unchanged && c2 == unchanged, c1 == deleted && c2 == deleted:
+		return nil
+	case c1 == deleted && c2 == unchanged:
+		return b.deleteTask(b.s2, remote)
+	case c2 == deleted && c1 == unchanged:
+		return b.deleteTask(b.s1, remote)
+	case c2 == unchanged || c2 == deleted:
+		// changes win over deletes
+		return b.copyTask(b.s1, b.s2, remote)
+	case c1 == unchanged || c1 == deleted:
+		return b.copyTask(b.s2, b.s1, remote)
+	}
+	// changed on both paths
+	return b.equalOr(remote, b.conflictTask(remote))
+}
+
+// equalOr returns a task which does nothing if remote is the same on
+// both paths, or runs t otherwise
+func (b *bisync) equalOr(remote string, t task) task {
+	o1, o2 := b.s1.cur[remote], b.s2.cur[remote]
+	return func(ctx context.Context) error {
+		if operations.Equal(ctx, o1, o2) {
+			fs.Debugf(o1, "Same on both paths")
+			return nil
+		}
+		return t(ctx)
+	}
+}
+
+// copyTask returns a task copying remote from src to dst
+func (b *bisync) copyTask(src, dst *side, remote string) task {
+	o, old := src.cur[remote], dst.cur[remote]
+	return func(ctx context.Context) error {
+		newO, err := operations.Copy(ctx, dst.f, old, remote, o)
+		if err != nil {
+			return err
+		}
+		dst.set(remote, newO, o)
+		return nil
+	}
+}
+
+// deleteTask returns a task deleting remote from s if it exists
+func (b *bisync) deleteTask(s *side, remote string) task {
+	o := s.cur[remote]
+	if o == nil {
+		return nil
+	}
+	if s == b.s1 {
+		b.deletes1++
+	} else {
+		b.deletes2++
+	}
+	return func(ctx context.Context) error {
+		err := operations.DeleteFile(ctx, o)
+		if err != nil {
+			return err
+		}
+		s.remove(remote)
+		return nil
+	}
+}
+
+// conflictName returns an unused name for the losing copy of remote
+// in a conflict, eg "dir/file.conflict.txt"
+func (b *bisync) conflictName(remote string) string {
+	ext := path.Ext(remote)
+	base := strings.TrimSuffix(remote, ext)
+	for i := 1; ; i++ {
+		suffix := ".conflict"
+		if i > 1 {
+			suffix += strconv.Itoa(i)
+		}
+		name := base + suffix + ext
+		_, in1 := b.s1.cur[name]
+		_, in2 := b.s2.cur[name]
+		if !in1 && !in2 && !b.reserved[name] {
+			b.reserved[name] = true
+			return name
+		}
+	}
+}
+
+// conflictTask returns a task resolving a file changed on both paths
+//
+// The newer copy wins. The losing copy is renamed and then both
+// files are copied to the other path.
+func (b *bisync) conflictTask(remote string) task {
+	winner, loser := b.s1, b.s2
+	if b.s2.cur[remote].ModTime().After(b.s1.cur[remote].ModTime()) {
+		winner, loser = b.s2, b.s1
+	}
+	oWinner, oLoser := winner.cur[remote], loser.cur[remote]
+	name := b.conflictName(remote)
+	return func(ctx context.Context) error {
+		fs.Logf(oLoser, "Changed on both paths - keeping the newer copy from %s and renaming the copy on %s to %q", winner.name, loser.name, name)
+		if fs.Config.DryRun {
+			return nil
+		}
+		renamed, err := operations.Move(ctx, loser.f, nil, name, oLoser)
+		if err != nil {
+			return err
+		}
+		if renamed == nil {
+			return errors.Errorf("failed to rename %q to %q", remote, name)
+		}
+		loser.remove(remote)
+		loser.set(name, renamed, oLoser)
+		copied, err := operations.Copy(ctx, winner.f, nil, name, renamed)
+		if err != nil {
+			return err
+		}
+		winner.set(name, copied, renamed)
+		newO, err := operations.Copy(ctx, loser.f, nil, remote, oWinner)
+		if err != nil {
+			return err
+		}
+		loser.set(remote, newO, oWinner)
+		return nil
+	}
+}
+
+// checkDeletes returns ErrTooManyDeletes if more than the allowed
+// percentage of the files on either path would be deleted
+func (b *bisync) checkDeletes() error {
+	if b.opt.Force || b.opt.MaxDeletePercent < 0 {
+		return nil
+	}
+	for _, x := range []struct {
+		s       *side
+		deletes int
+	}{{b.s1, b.deletes1}, {b.s2, b.deletes2}} {
+		total := len(x.s.prev)
+		if total > 0 && x.deletes*100 > b.opt.MaxDeletePercent*total {
+			fs.Errorf(x.s.f, "Would delete %d of %d files which is more than --max-delete-percent %d%% - use --force to delete anyway", x.deletes, total, b.opt.MaxDeletePercent)
+			return errors.Wrapf(ErrTooManyDeletes, "%d of %d files on %s", x.deletes, total, x.s.name)
+		}
+	}
+	return nil
+}
+
+// run the tasks using --transfers go routines
+func (b *bisync) run(ctx context.Context) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		nErrors  int
+	)
+	in := make(chan task, len(b.tasks))
+	for _, t := range b.tasks {
+		in <- t
+	}
+	close(in)
+	for i := 0; i < fs.Config.Transfers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range in {
+				if ctx.Err() != nil {
+					return
+				}
+				err := t(ctx)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					nErrors++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return errors.Wrapf(firstErr, "%d files failed to sync - listings not updated", nErrors)
+	}
+	return ctx.Err()
+}
+
+// Check the interfaces are satisfied
+var _ march.Marcher = (*bisync)(nil)
@@ -0,0 +1,214 @@
+// Test bidirectional sync
+
+package bisync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/artpar/rclone/backend/all" // import all backends
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fstest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Some times used in the tests
+var (
+	t1 = fstest.Time("2001-02-03T04:05:06.499999999Z")
+	t2 = fstest.Time("2011-12-25T12:59:59.123456789Z")
+	t3 = fstest.Time("2011-12-30T12:59:59.000000000Z")
+)
+
+// TestMain drives the tests
+func TestMain(m *testing.M) {
+	fstest.TestMain(m)
+}
+
+// newOpt makes options with a temporary state directory
+func newOpt(t *testing.T) (opt *Options, cleanup func()) {
+	dir, err := ioutil.TempDir("", "rclone-bisync")
+	require.NoError(t, err)
+	o := DefaultOptions(dir)
+	return &o, func() { _ = os.RemoveAll(dir) }
+}
+
+// removeLocal removes item from the local Fs
+func removeLocal(t *testing.T, r *fstest.Run, item fstest.Item) {
+	require.NoError(t, os.Remove(filepath.Join(r.LocalName, item.Path)))
+}
+
+// removeRemote removes item from the remote Fs
+func removeRemote(ctx context.Context, t *testing.T, r *fstest.Run, item fstest.Item) {
+	o, err := r.Fremote.NewObject(ctx, item.Path)
+	require.NoError(t, err)
+	require.NoError(t, o.Remove(ctx))
+}
+
+func TestStateFileName(t *testing.T) {
+	assert.Equal(t, "remote_path_to_dir", sanitize("remote:path/to/dir"))
+	assert.Equal(t, "local__tmp_a_b-c.d", sanitize("local:/tmp/a b-c.d"))
+}
+
+func TestNeedsResync(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+	r.Mkdir(ctx, r.Fremote)
+
+	err := Bisync(ctx, r.Flocal, r.Fremote, opt)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--resync")
+}
+
+func TestResync(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+
+	local := r.WriteFile("local only", "local", t1)
+	remote := r.WriteObject(ctx, "sub dir/remote only", "remote", t1)
+	both := r.WriteFile("both", "path1 wins", t1)
+	r.WriteObject(ctx, "both", "path2 loses", t2)
+
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, local, remote, both)
+	fstest.CheckItems(t, r.Fremote, local, remote, both)
+
+	// Nothing to do now
+	opt.Resync = false
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, local, remote, both)
+	fstest.CheckItems(t, r.Fremote, local, remote, both)
+}
+
+func TestPropagate(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+
+	file1 := r.WriteBoth(ctx, "one", "one", t1)
+	file2 := r.WriteBoth(ctx, "two", "two", t1)
+	file3 := r.WriteBoth(ctx, "sub dir/three", "three", t1)
+	file4 := r.WriteBoth(ctx, "four", "four", t1)
+	file5 := r.WriteBoth(ctx, "five", "five", t1)
+	file6 := r.WriteBoth(ctx, "six", "six", t1)
+	file7 := r.WriteBoth(ctx, "seven", "seven", t1)
+	file8 := r.WriteBoth(ctx, "eight", "eight", t1)
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	opt.Resync = false
+
+	// Changes on path1
+	new1 := r.WriteFile("new on path1", "new", t2)
+	file1 = r.WriteFile("one", "one changed", t2)
+	removeLocal(t, r, file2)
+	// Changes on path2
+	new2 := r.WriteObject(ctx, "sub dir/new on path2", "new2", t2)
+	file3 = r.WriteObject(ctx, "sub dir/three", "three changed", t2)
+	removeRemote(ctx, t, r, file4)
+	// Changed on one path and deleted on the other
+	file5 = r.WriteFile("five", "five changed", t2)
+	removeRemote(ctx, t, r, file5)
+
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, new1, new2, file1, file3, file5, file6, file7, file8)
+	fstest.CheckItems(t, r.Fremote, new1, new2, file1, file3, file5, file6, file7, file8)
+
+	// The listings were updated so a second run does nothing
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, new1, new2, file1, file3, file5, file6, file7, file8)
+	fstest.CheckItems(t, r.Fremote, new1, new2, file1, file3, file5, file6, file7, file8)
+}
+
+func TestConflict(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+
+	r.WriteBoth(ctx, "file.txt", "original", t1)
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	opt.Resync = false
+
+	// The newer copy on path2 wins
+	older := r.WriteFile("file.txt", "changed on path1", t2)
+	newer := r.WriteObject(ctx, "file.txt", "changed on path2", t3)
+	same := r.WriteFile("same.txt", "created on both", t2)
+	r.WriteObject(ctx, "same.txt", "created on both", t2)
+
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	older.Path = "file.conflict.txt"
+	fstest.CheckItems(t, r.Flocal, newer, older, same)
+	fstest.CheckItems(t, r.Fremote, newer, older, same)
+
+	// Another conflict gets a different name
+	older2 := r.WriteFile("file.txt", "path1 again", t3)
+	newer2 := r.WriteObject(ctx, "file.txt", "path2 again and newer", fstest.Time("2012-01-01T00:00:00Z"))
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	older2.Path = "file.conflict2.txt"
+	fstest.CheckItems(t, r.Flocal, newer2, older, older2, same)
+	fstest.CheckItems(t, r.Fremote, newer2, older, older2, same)
+}
+
+func TestTooManyDeletes(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+
+	file1 := r.WriteBoth(ctx, "one", "one", t1)
+	file2 := r.WriteBoth(ctx, "two", "two", t1)
+	file3 := r.WriteBoth(ctx, "three", "three", t1)
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	opt.Resync = false
+
+	removeLocal(t, r, file1)
+	removeLocal(t, r, file2)
+	err := Bisync(ctx, r.Flocal, r.Fremote, opt)
+	assert.Equal(t, ErrTooManyDeletes, errors.Cause(err))
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+
+	opt.Force = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Fremote, file3)
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+
+	file1 := r.WriteBoth(ctx, "one", "one", t1)
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	opt.Resync = false
+
+	file2 := r.WriteFile("two", "two", t2)
+	fs.Config.DryRun = true
+	err := Bisync(ctx, r.Flocal, r.Fremote, opt)
+	fs.Config.DryRun = false
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	// The listings weren't updated so the change is still found
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+}
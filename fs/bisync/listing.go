@@ -0,0 +1,120 @@
+package bisync
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/hash"
+	"github.com/pkg/errors"
+)
+
+// stateVersion is the version of the state file written
+const stateVersion = 1
+
+// record is the state of a file at the end of a run
+type record struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modtime"`
+	Hash    string    `json:"hash,omitempty"`
+}
+
+// newRecord makes a record for o, reading its hash of type ht unless
+// ht is hash.None
+func newRecord(o fs.ObjectInfo, ht hash.Type) record {
+	rec := record{
+		Size:    o.Size(),
+		ModTime: o.ModTime(),
+	}
+	if ht != hash.None {
+		sum, err := o.Hash(ht)
+		if err != nil {
+			fs.Debugf(o, "Failed to read %v hash: %v", ht, err)
+		}
+		rec.Hash = sum
+	}
+	return rec
+}
+
+// listing is the state of all the files on one path keyed by their
+// path
+type listing map[string]record
+
+// state is stored between runs
+type state struct {
+	Version int     `json:"version"`
+	Path1   listing `json:"path1"`
+	Path2   listing `json:"path2"`
+}
+
+// stateFileName returns the name of the file the state of syncing f1
+// and f2 is kept in
+func stateFileName(f1, f2 fs.Fs) string {
+	return sanitize(f1.Name()+":"+f1.Root()) + ".." + sanitize(f2.Name()+":"+f2.Root()) + ".json"
+}
+
+// sanitize replaces the characters in name which might not be
+// allowed in a file name with _
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// loadState reads the state from file
+//
+// If the file doesn't exist the error will satisfy os.IsNotExist
+// after errors.Cause.
+func loadState(file string) (*state, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	st := new(state)
+	err = json.Unmarshal(data, st)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode listings %q", file)
+	}
+	if st.Version != stateVersion {
+		return nil, errors.Errorf("unsupported version %d of listings %q", st.Version, file)
+	}
+	if st.Path1 == nil {
+		st.Path1 = listing{}
+	}
+	if st.Path2 == nil {
+		st.Path2 = listing{}
+	}
+	return st, nil
+}
+
+// saveState writes st to file, replacing it atomically
+func saveState(file string, st *state) error {
+	st.Version = stateVersion
+	data, err := json.Marshal(st)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode listings")
+	}
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return errors.Wrap(err, "failed to make directory for listings")
+	}
+	tmp := file + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to write listings")
+	}
+	err = os.Rename(tmp, file)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return errors.Wrap(err, "failed to write listings")
+	}
+	return nil
+}
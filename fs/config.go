@@ -38,49 +38,60 @@ var (
 
 // ConfigInfo is filesystem config options
 type ConfigInfo struct {
-	LogLevel              LogLevel
-	StatsLogLevel         LogLevel
-	DryRun                bool
-	CheckSum              bool
-	SizeOnly              bool
-	IgnoreTimes           bool
-	IgnoreExisting        bool
-	IgnoreErrors          bool
-	ModifyWindow          time.Duration
-	Checkers              int
-	Transfers             int
-	ConnectTimeout        time.Duration // Connect timeout
-	Timeout               time.Duration // Data channel timeout
-	Dump                  DumpFlags
-	InsecureSkipVerify    bool // Skip server certificate verification
-	DeleteMode            DeleteMode
-	MaxDelete             int64
-	TrackRenames          bool // Track file renames.
-	LowLevelRetries       int
-	UpdateOlder           bool // Skip files that are newer on the destination
-	NoGzip                bool // Disable compression
-	MaxDepth              int
-	IgnoreSize            bool
-	IgnoreChecksum        bool
-	NoUpdateModTime       bool
-	DataRateUnit          string
-	BackupDir             string
-	Suffix                string
-	UseListR              bool
-	BufferSize            SizeSuffix
-	BwLimit               BwTimetable
-	TPSLimit              float64
-	TPSLimitBurst         int
-	BindAddr              net.IP
-	DisableFeatures       []string
-	UserAgent             string
-	Immutable             bool
-	AutoConfirm           bool
-	StreamingUploadCutoff SizeSuffix
-	StatsFileNameLength   int
-	AskPassword           bool
-	UseServerModTime      bool
-	MaxTransfer           SizeSuffix
+	LogLevel                LogLevel
+	StatsLogLevel           LogLevel
+	DryRun                  bool
+	CheckSum                bool
+	SizeOnly                bool
+	IgnoreTimes             bool
+	IgnoreExisting          bool
+	IgnoreErrors            bool
+	ModifyWindow            time.Duration
+	Checkers                int
+	Transfers               int
+	ConnectTimeout          time.Duration // Connect timeout
+	Timeout                 time.Duration // Data channel timeout
+	Dump                    DumpFlags
+	InsecureSkipVerify      bool // Skip server certificate verification
+	DeleteMode              DeleteMode
+	MaxDelete               int64
+	TrackRenames            bool // Track file renames.
+	LowLevelRetries         int
+	UpdateOlder             bool // Skip files that are newer on the destination
+	NoGzip                  bool // Disable compression
+	MaxDepth                int
+	IgnoreSize              bool
+	IgnoreChecksum          bool
+	NoUpdateModTime         bool
+	DataRateUnit            string
+	BackupDir               string
+	Suffix                  string
+	CompareDest             string
+	CopyDest                string
+	UseListR                bool
+	BufferSize              SizeSuffix
+	BwLimit                 BwTimetable
+	TPSLimit                float64
+	TPSLimitBurst           int
+	BindAddr                net.IP
+	DisableFeatures         []string
+	UserAgent               string
+	Immutable               bool
+	AutoConfirm             bool
+	StreamingUploadCutoff   SizeSuffix
+	StatsFileNameLength     int
+	AskPassword             bool
+	UseServerModTime        bool
+	MaxTransfer             SizeSuffix
+	MaxDuration             time.Duration
+	CutoffMode              CutoffMode
+	MultiThreadCutoff       SizeSuffix
+	MultiThreadStreams      int
+	ServerSideAcrossConfigs bool
+	OrderBy                 string
+	MaxBacklog              int
+	Metadata                bool
+	MetadataSet             Metadata // extra metadata to write when uploading
 }
 
 // NewConfig creates a new config with everything set to the default
@@ -109,6 +120,10 @@ func NewConfig() *ConfigInfo {
 	c.AskPassword = true
 	c.TPSLimitBurst = 1
 	c.MaxTransfer = -1
+	c.CutoffMode = CutoffModeDefault
+	c.MultiThreadCutoff = SizeSuffix(250 * 1024 * 1024)
+	c.MultiThreadStreams = 4
+	c.MaxBacklog = 10000
 
 	return c
 }
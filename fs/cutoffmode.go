@@ -0,0 +1,50 @@
+package fs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CutoffMode describes what rclone does when it reaches a limit such
+// as --max-transfer or --max-duration
+type CutoffMode byte
+
+// CutoffMode constants
+const (
+	CutoffModeHard     CutoffMode = iota // stop immediately
+	CutoffModeSoft                       // stop starting new transfers
+	CutoffModeCautious                   // don't start transfers which would exceed the limit
+	CutoffModeDefault  = CutoffModeHard
+)
+
+var cutoffModeToString = []string{
+	CutoffModeHard:     "HARD",
+	CutoffModeSoft:     "SOFT",
+	CutoffModeCautious: "CAUTIOUS",
+}
+
+// String turns a CutoffMode into a string
+func (m CutoffMode) String() string {
+	if m >= CutoffMode(len(cutoffModeToString)) {
+		return fmt.Sprintf("CutoffMode(%d)", m)
+	}
+	return cutoffModeToString[m]
+}
+
+// Set a CutoffMode
+func (m *CutoffMode) Set(s string) error {
+	for n, name := range cutoffModeToString {
+		if s != "" && name == strings.ToUpper(s) {
+			*m = CutoffMode(n)
+			return nil
+		}
+	}
+	return errors.Errorf("Unknown cutoff mode %q", s)
+}
+
+// Type of the value
+func (m *CutoffMode) Type() string {
+	return "string"
+}
@@ -0,0 +1,49 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Check it satisfies the interface
+var _ pflag.Value = (*CutoffMode)(nil)
+
+func TestCutoffModeString(t *testing.T) {
+	for _, test := range []struct {
+		in   CutoffMode
+		want string
+	}{
+		{CutoffModeHard, "HARD"},
+		{CutoffModeSoft, "SOFT"},
+		{CutoffModeCautious, "CAUTIOUS"},
+		{CutoffMode(99), "CutoffMode(99)"},
+	} {
+		assert.Equal(t, test.want, test.in.String())
+	}
+}
+
+func TestCutoffModeSet(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want CutoffMode
+		err  bool
+	}{
+		{"hard", CutoffModeHard, false},
+		{"SOFT", CutoffModeSoft, false},
+		{"Cautious", CutoffModeCautious, false},
+		{"", CutoffModeHard, true},
+		{"potato", CutoffModeHard, true},
+	} {
+		m := CutoffModeHard
+		err := m.Set(test.in)
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+		}
+		assert.Equal(t, test.want, m, test.in)
+	}
+}
@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -10,7 +11,7 @@ import (
 
 func TestFeaturesDisable(t *testing.T) {
 	ft := new(Features)
-	ft.Copy = func(src Object, remote string) (Object, error) {
+	ft.Copy = func(ctx context.Context, src Object, remote string) (Object, error) {
 		return nil, nil
 	}
 	ft.CaseInsensitive = true
@@ -36,7 +37,7 @@ func TestFeaturesList(t *testing.T) {
 
 func TestFeaturesDisableList(t *testing.T) {
 	ft := new(Features)
-	ft.Copy = func(src Object, remote string) (Object, error) {
+	ft.Copy = func(ctx context.Context, src Object, remote string) (Object, error) {
 		return nil, nil
 	}
 	ft.CaseInsensitive = true
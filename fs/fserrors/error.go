@@ -2,6 +2,7 @@
 package fserrors
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -252,6 +253,11 @@ func ShouldRetry(err error) bool {
 		return false
 	}
 
+	// Never retry if the context was cancelled
+	if IsContextError(err) {
+		return false
+	}
+
 	// Find root cause if available
 	retriable, err := Cause(err)
 	if retriable {
@@ -290,3 +296,31 @@ func ShouldRetryHTTP(resp *http.Response, retryErrorCodes []int) bool {
 	}
 	return false
 }
+
+// ContextError checks to see if ctx is in error.
+//
+// If it is in error then it overwrites *perr with the context error
+// if *perr was nil and returns true.
+//
+// Otherwise it returns false.
+func ContextError(ctx context.Context, perr *error) bool {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if *perr == nil {
+			*perr = ctxErr
+		}
+		return true
+	}
+	return false
+}
+
+// IsContextError returns true if the root cause of err is a
+// cancelled context or a context whose deadline has been exceeded.
+//
+// Operations which fail for this reason should not be retried.
+func IsContextError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, err = Cause(err)
+	return err == context.Canceled || err == context.DeadlineExceeded
+}
@@ -1,6 +1,7 @@
 package fserrors
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net"
@@ -143,3 +144,24 @@ func TestShouldRetry(t *testing.T) {
 		assert.Equal(t, test.want, got, fmt.Sprintf("test #%d: %v", i, test.err))
 	}
 }
+
+func TestIsContextError(t *testing.T) {
+	for i, test := range []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("potato"), false},
+		{context.Canceled, true},
+		{context.DeadlineExceeded, true},
+		{errors.Wrap(context.Canceled, "listing"), true},
+		{&url.Error{Op: "get", URL: "/", Err: context.Canceled}, true},
+		{&url.Error{Op: "get", URL: "/", Err: context.DeadlineExceeded}, true},
+	} {
+		got := IsContextError(test.err)
+		assert.Equal(t, test.want, got, fmt.Sprintf("test #%d: %v", i, test.err))
+		if test.want {
+			assert.False(t, ShouldRetry(test.err), fmt.Sprintf("test #%d: %v", i, test.err))
+		}
+	}
+}
@@ -73,7 +73,7 @@ type listDirFn func(dir string) (entries fs.DirEntries, err error)
 func (m *March) makeListDir(f fs.Fs, includeAll bool) listDirFn {
 	if !fs.Config.UseListR || f.Features().ListR == nil {
 		return func(dir string) (entries fs.DirEntries, err error) {
-			return list.DirSorted(f, includeAll, dir)
+			return list.DirSorted(m.ctx, f, includeAll, dir)
 		}
 	}
 	var (
@@ -86,7 +86,7 @@ func (m *March) makeListDir(f fs.Fs, includeAll bool) listDirFn {
 		mu.Lock()
 		defer mu.Unlock()
 		if !started {
-			dirs, dirsErr = walk.NewDirTree(f, m.dir, includeAll, fs.Config.MaxDepth)
+			dirs, dirsErr = walk.NewDirTree(m.ctx, f, m.dir, includeAll, fs.Config.MaxDepth)
 			started = true
 		}
 		if dirsErr != nil {
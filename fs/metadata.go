@@ -0,0 +1,110 @@
+package fs
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Metadata represents Object metadata in a standardised form
+//
+// See the Metadata section of docs/content/docs.md for an explanation
+// of the keys
+type Metadata map[string]string
+
+// MetadataHelp represents help for a bit of system metadata
+type MetadataHelp struct {
+	Help     string
+	Type     string
+	Example  string
+	ReadOnly bool
+}
+
+// MetadataInfo is help for the metadata a backend reads and writes
+type MetadataInfo struct {
+	System map[string]MetadataHelp // keys the backend translates into its own metadata
+	Help   string                  // general help on the metadata for the backend
+}
+
+// Metadataer is an optional interface for Object
+type Metadataer interface {
+	// Metadata returns metadata for an object
+	//
+	// It should return nil if there is no Metadata
+	Metadata() (Metadata, error)
+}
+
+// Set k to v on m
+//
+// If m is nil, then it will get made
+func (m *Metadata) Set(k, v string) {
+	if *m == nil {
+		*m = make(Metadata, 1)
+	}
+	(*m)[k] = v
+}
+
+// Merge other into m
+//
+// If m is nil, then it will get made
+func (m *Metadata) Merge(other Metadata) {
+	for k, v := range other {
+		if *m == nil {
+			*m = make(Metadata, len(other))
+		}
+		(*m)[k] = v
+	}
+}
+
+// MergeOptions gets any Metadata from the options passed in and
+// stores it in m (which may be nil).
+//
+// If there is no m then metadata will be nil
+func (m *Metadata) MergeOptions(options []OpenOption) {
+	for _, opt := range options {
+		if metadataOption, ok := opt.(MetadataOption); ok {
+			m.Merge(Metadata(metadataOption))
+		}
+	}
+}
+
+// GetMetadata from an ObjectInfo
+//
+// If the object has no metadata then metadata will be nil
+func GetMetadata(o ObjectInfo) (metadata Metadata, err error) {
+	do, ok := o.(Metadataer)
+	if !ok {
+		return nil, nil
+	}
+	return do.Metadata()
+}
+
+// GetMetadataOptions from an ObjectInfo and merge it with any in options
+//
+// If --metadata isn't in use it will return nil
+//
+// If the object has no metadata then metadata will be nil
+func GetMetadataOptions(o ObjectInfo, options []OpenOption) (metadata Metadata, err error) {
+	if !Config.Metadata {
+		return nil, nil
+	}
+	metadata, err = GetMetadata(o)
+	if err != nil {
+		return nil, err
+	}
+	metadata.MergeOptions(options)
+	return metadata, nil
+}
+
+// ParseMetadata parses a list of key=value strings as passed to
+// --metadata-set into Metadata
+func ParseMetadata(kvs []string) (metadata Metadata, err error) {
+	for _, kv := range kvs {
+		equal := strings.IndexRune(kv, '=')
+		if equal < 0 {
+			return nil, errors.Errorf("failed to parse metadata %q: missing =", kv)
+		}
+		metadata.Set(strings.ToLower(kv[:equal]), kv[equal+1:])
+	}
+	return metadata, nil
+}
@@ -0,0 +1,89 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataSet(t *testing.T) {
+	var m Metadata
+	assert.Nil(t, m)
+	m.Set("key", "value")
+	assert.NotNil(t, m)
+	assert.Equal(t, "value", m["key"])
+	m.Set("key", "value2")
+	assert.Equal(t, "value2", m["key"])
+}
+
+func TestMetadataMerge(t *testing.T) {
+	for _, test := range []struct {
+		in    Metadata
+		merge Metadata
+		want  Metadata
+	}{
+		{
+			in:    Metadata{},
+			merge: Metadata{},
+			want:  Metadata{},
+		}, {
+			in:    nil,
+			merge: nil,
+			want:  nil,
+		}, {
+			in:    nil,
+			merge: Metadata{},
+			want:  nil,
+		}, {
+			in:    nil,
+			merge: Metadata{"a": "1", "b": "2"},
+			want:  Metadata{"a": "1", "b": "2"},
+		}, {
+			in:    Metadata{"a": "1", "b": "2"},
+			merge: nil,
+			want:  Metadata{"a": "1", "b": "2"},
+		}, {
+			in:    Metadata{"a": "1", "b": "2"},
+			merge: Metadata{"b": "B", "c": "3"},
+			want:  Metadata{"a": "1", "b": "B", "c": "3"},
+		},
+	} {
+		what := test.in
+		test.in.Merge(test.merge)
+		assert.Equal(t, test.want, test.in, what)
+	}
+}
+
+func TestMetadataMergeOptions(t *testing.T) {
+	var m Metadata
+	m.MergeOptions([]OpenOption{
+		&HTTPOption{Key: "a", Value: "x"},
+		MetadataOption{"a": "1"},
+		MetadataOption{"b": "2", "a": "A"},
+	})
+	assert.Equal(t, Metadata{"a": "A", "b": "2"}, m)
+}
+
+func TestParseMetadata(t *testing.T) {
+	for _, test := range []struct {
+		in   []string
+		want Metadata
+		err  string
+	}{
+		{in: nil, want: nil},
+		{in: []string{"a=1"}, want: Metadata{"a": "1"}},
+		{in: []string{"A=1", "b=x=y", "c="}, want: Metadata{"a": "1", "b": "x=y", "c": ""}},
+		{in: []string{"a=1", "potato"}, err: "missing ="},
+	} {
+		got, err := ParseMetadata(test.in)
+		if test.err != "" {
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+			assert.Nil(t, got)
+		} else {
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package object_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"testing"
@@ -47,6 +48,7 @@ func TestStaticObject(t *testing.T) {
 }
 
 func TestMemoryFs(t *testing.T) {
+	ctx := context.Background()
 	f := object.MemoryFs
 	assert.Equal(t, "memory", f.Name())
 	assert.Equal(t, "", f.Root())
@@ -55,31 +57,32 @@ func TestMemoryFs(t *testing.T) {
 	assert.Equal(t, hash.Supported, f.Hashes())
 	assert.Equal(t, &fs.Features{}, f.Features())
 
-	entries, err := f.List("")
+	entries, err := f.List(ctx, "")
 	assert.NoError(t, err)
 	assert.Nil(t, entries)
 
-	o, err := f.NewObject("obj")
+	o, err := f.NewObject(ctx, "obj")
 	assert.Equal(t, fs.ErrorObjectNotFound, err)
 	assert.Nil(t, o)
 
 	buf := bytes.NewBufferString("potato")
 	now := time.Now()
 	src := object.NewStaticObjectInfo("remote", now, int64(buf.Len()), true, nil, nil)
-	o, err = f.Put(buf, src)
+	o, err = f.Put(ctx, buf, src)
 	assert.NoError(t, err)
 	hash, err := o.Hash(hash.SHA1)
 	assert.NoError(t, err)
 	assert.Equal(t, "3e2e95f5ad970eadfa7e17eaf73da97024aa5359", hash)
 
-	err = f.Mkdir("dir")
+	err = f.Mkdir(ctx, "dir")
 	assert.Error(t, err)
 
-	err = f.Rmdir("dir")
+	err = f.Rmdir(ctx, "dir")
 	assert.Equal(t, fs.ErrorDirNotFound, err)
 }
 
 func TestMemoryObject(t *testing.T) {
+	ctx := context.Background()
 	remote := "path/to/object"
 	now := time.Now()
 	content := []byte("potatoXXXXXXXXXXXXX")
@@ -117,18 +120,18 @@ func TestMemoryObject(t *testing.T) {
 	}
 
 	checkContent := func(o fs.Object, expected string) {
-		rc, err := o.Open()
+		rc, err := o.Open(ctx)
 		assert.NoError(t, err)
 		checkOpen(rc, expected)
 	}
 
 	checkContent(o, string(content))
 
-	rc, err := o.Open(&fs.RangeOption{Start: 1, End: 3})
+	rc, err := o.Open(ctx, &fs.RangeOption{Start: 1, End: 3})
 	assert.NoError(t, err)
 	checkOpen(rc, "ot")
 
-	rc, err = o.Open(&fs.SeekOption{Offset: 3})
+	rc, err = o.Open(ctx, &fs.SeekOption{Offset: 3})
 	assert.NoError(t, err)
 	checkOpen(rc, "ato")
 
@@ -137,7 +140,7 @@ func TestMemoryObject(t *testing.T) {
 	newContent := bytes.NewBufferString("Rutabaga")
 	assert.True(t, newContent.Len() < cap(content)) // fits within cap(content)
 	src := object.NewStaticObjectInfo(remote, newNow, int64(newContent.Len()), true, nil, nil)
-	err = o.Update(newContent, src)
+	err = o.Update(ctx, newContent, src)
 	assert.NoError(t, err)
 	checkContent(o, "Rutabaga")
 	assert.Equal(t, newNow, o.ModTime())
@@ -149,7 +152,7 @@ func TestMemoryObject(t *testing.T) {
 	newContent = bytes.NewBufferString(newStr)
 	assert.True(t, newContent.Len() > cap(content)) // does not fit within cap(content)
 	src = object.NewStaticObjectInfo(remote, newNow, int64(newContent.Len()), true, nil, nil)
-	err = o.Update(newContent, src)
+	err = o.Update(ctx, newContent, src)
 	assert.NoError(t, err)
 	checkContent(o, newStr)
 	assert.Equal(t, "Rutaba", string(content)) // check we didn't re-use the buffer
@@ -158,7 +161,7 @@ func TestMemoryObject(t *testing.T) {
 	newStr = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	newContent = bytes.NewBufferString(newStr)
 	src = object.NewStaticObjectInfo(remote, newNow, -1, true, nil, nil)
-	err = o.Update(newContent, src)
+	err = o.Update(ctx, newContent, src)
 	assert.NoError(t, err)
 	checkContent(o, newStr)
 
@@ -166,10 +169,10 @@ func TestMemoryObject(t *testing.T) {
 	newStr = ""
 	newContent = bytes.NewBufferString(newStr)
 	src = object.NewStaticObjectInfo(remote, newNow, 0, true, nil, nil)
-	err = o.Update(newContent, src)
+	err = o.Update(ctx, newContent, src)
 	assert.NoError(t, err)
 	checkContent(o, newStr)
 
-	err = o.Remove()
+	err = o.Remove(ctx)
 	assert.Error(t, err)
 }
@@ -1,6 +1,7 @@
 package operations_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -32,61 +33,64 @@ func skipIfNoHash(t *testing.T, f fs.Fs) {
 }
 
 func TestDeduplicateInteractive(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
 	skipIfNoHash(t, r.Fremote)
 
-	file1 := r.WriteUncheckedObject("one", "This is one", t1)
-	file2 := r.WriteUncheckedObject("one", "This is one", t1)
-	file3 := r.WriteUncheckedObject("one", "This is one", t1)
+	file1 := r.WriteUncheckedObject(ctx, "one", "This is one", t1)
+	file2 := r.WriteUncheckedObject(ctx, "one", "This is one", t1)
+	file3 := r.WriteUncheckedObject(ctx, "one", "This is one", t1)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateInteractive)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateInteractive)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file1)
 }
 
 func TestDeduplicateSkip(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
 	haveHash := r.Fremote.Hashes().GetOne() != hash.None
 
-	file1 := r.WriteUncheckedObject("one", "This is one", t1)
+	file1 := r.WriteUncheckedObject(ctx, "one", "This is one", t1)
 	files := []fstest.Item{file1}
 	if haveHash {
-		file2 := r.WriteUncheckedObject("one", "This is one", t1)
+		file2 := r.WriteUncheckedObject(ctx, "one", "This is one", t1)
 		files = append(files, file2)
 	}
-	file3 := r.WriteUncheckedObject("one", "This is another one", t1)
+	file3 := r.WriteUncheckedObject(ctx, "one", "This is another one", t1)
 	files = append(files, file3)
 	r.CheckWithDuplicates(t, files...)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateSkip)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateSkip)
 	require.NoError(t, err)
 
 	r.CheckWithDuplicates(t, file1, file3)
 }
 
 func TestDeduplicateFirst(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
 
-	file1 := r.WriteUncheckedObject("one", "This is one", t1)
-	file2 := r.WriteUncheckedObject("one", "This is one A", t1)
-	file3 := r.WriteUncheckedObject("one", "This is one BB", t1)
+	file1 := r.WriteUncheckedObject(ctx, "one", "This is one", t1)
+	file2 := r.WriteUncheckedObject(ctx, "one", "This is one A", t1)
+	file3 := r.WriteUncheckedObject(ctx, "one", "This is one BB", t1)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateFirst)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateFirst)
 	require.NoError(t, err)
 
 	// list until we get one object
 	var objects, size int64
 	for try := 1; try <= *fstest.ListRetries; try++ {
-		objects, size, err = operations.Count(r.Fremote)
+		objects, size, err = operations.Count(ctx, r.Fremote)
 		require.NoError(t, err)
 		if objects == 1 {
 			break
@@ -100,67 +104,71 @@ func TestDeduplicateFirst(t *testing.T) {
 }
 
 func TestDeduplicateNewest(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
 
-	file1 := r.WriteUncheckedObject("one", "This is one", t1)
-	file2 := r.WriteUncheckedObject("one", "This is one too", t2)
-	file3 := r.WriteUncheckedObject("one", "This is another one", t3)
+	file1 := r.WriteUncheckedObject(ctx, "one", "This is one", t1)
+	file2 := r.WriteUncheckedObject(ctx, "one", "This is one too", t2)
+	file3 := r.WriteUncheckedObject(ctx, "one", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateNewest)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateNewest)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file3)
 }
 
 func TestDeduplicateOldest(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
 
-	file1 := r.WriteUncheckedObject("one", "This is one", t1)
-	file2 := r.WriteUncheckedObject("one", "This is one too", t2)
-	file3 := r.WriteUncheckedObject("one", "This is another one", t3)
+	file1 := r.WriteUncheckedObject(ctx, "one", "This is one", t1)
+	file2 := r.WriteUncheckedObject(ctx, "one", "This is one too", t2)
+	file3 := r.WriteUncheckedObject(ctx, "one", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateOldest)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateOldest)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file1)
 }
 
 func TestDeduplicateLargest(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
 
-	file1 := r.WriteUncheckedObject("one", "This is one", t1)
-	file2 := r.WriteUncheckedObject("one", "This is one too", t2)
-	file3 := r.WriteUncheckedObject("one", "This is another one", t3)
+	file1 := r.WriteUncheckedObject(ctx, "one", "This is one", t1)
+	file2 := r.WriteUncheckedObject(ctx, "one", "This is one too", t2)
+	file3 := r.WriteUncheckedObject(ctx, "one", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateLargest)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateLargest)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file3)
 }
 
 func TestDeduplicateRename(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
 
-	file1 := r.WriteUncheckedObject("one.txt", "This is one", t1)
-	file2 := r.WriteUncheckedObject("one.txt", "This is one too", t2)
-	file3 := r.WriteUncheckedObject("one.txt", "This is another one", t3)
+	file1 := r.WriteUncheckedObject(ctx, "one.txt", "This is one", t1)
+	file2 := r.WriteUncheckedObject(ctx, "one.txt", "This is one too", t2)
+	file3 := r.WriteUncheckedObject(ctx, "one.txt", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateRename)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateRename)
 	require.NoError(t, err)
 
-	require.NoError(t, walk.Walk(r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
+	require.NoError(t, walk.Walk(ctx, r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			return err
 		}
@@ -183,6 +191,7 @@ func TestDeduplicateRename(t *testing.T) {
 // This should really be a unit test, but the test framework there
 // doesn't have enough tools to make it easy
 func TestMergeDirs(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -191,11 +200,11 @@ func TestMergeDirs(t *testing.T) {
 		t.Skip("Can't merge directories")
 	}
 
-	file1 := r.WriteObject("dupe1/one.txt", "This is one", t1)
-	file2 := r.WriteObject("dupe2/two.txt", "This is one too", t2)
-	file3 := r.WriteObject("dupe3/three.txt", "This is another one", t3)
+	file1 := r.WriteObject(ctx, "dupe1/one.txt", "This is one", t1)
+	file2 := r.WriteObject(ctx, "dupe2/two.txt", "This is one too", t2)
+	file3 := r.WriteObject(ctx, "dupe3/three.txt", "This is another one", t3)
 
-	objs, dirs, err := walk.GetAll(r.Fremote, "", true, 1)
+	objs, dirs, err := walk.GetAll(ctx, r.Fremote, "", true, 1)
 	require.NoError(t, err)
 	assert.Equal(t, 3, len(dirs))
 	assert.Equal(t, 0, len(objs))
@@ -207,7 +216,7 @@ func TestMergeDirs(t *testing.T) {
 	file3.Path = "dupe1/three.txt"
 	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
 
-	objs, dirs, err = walk.GetAll(r.Fremote, "", true, 1)
+	objs, dirs, err = walk.GetAll(ctx, r.Fremote, "", true, 1)
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(dirs))
 	assert.Equal(t, 0, len(objs))
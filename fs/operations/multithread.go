@@ -0,0 +1,215 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/accounting"
+	"github.com/pkg/errors"
+)
+
+const (
+	multithreadChunkSize  = 64 << 10 // parts are a multiple of this
+	multithreadBufferSize = 32 * 1024
+)
+
+// doMultiThreadCopy returns whether src should be copied to f with
+// multiple streams
+//
+// This needs f to support random access writes and the file to be
+// bigger than --multi-thread-cutoff.
+func doMultiThreadCopy(f fs.Fs, src fs.Object) bool {
+	if fs.Config.MultiThreadStreams <= 1 {
+		return false
+	}
+	if src.Size() < int64(fs.Config.MultiThreadCutoff) {
+		return false
+	}
+	// OpenWriterAt has no way of passing the metadata on
+	if fs.Config.Metadata && f.Features().WriteMetadata {
+		return false
+	}
+	return f.Features().OpenWriterAt != nil
+}
+
+// state for a multi-thread copy
+type multiThreadCopyState struct {
+	ctx      context.Context
+	partSize int64
+	size     int64
+	wc       fs.WriterAtCloser
+	src      fs.Object
+	acc      *accounting.Account
+	streams  int
+}
+
+// calculateParts works out the part size and number of streams to
+// copy size bytes with at most streams streams
+//
+// The parts are rounded up to a multiple of multithreadChunkSize so
+// the writes stay aligned.
+func calculateParts(size int64, streams int) (partSize int64, n int) {
+	partSize = (size + int64(streams) - 1) / int64(streams)
+	if remainder := partSize % multithreadChunkSize; remainder != 0 {
+		partSize += multithreadChunkSize - remainder
+	}
+	if partSize == 0 {
+		partSize = multithreadChunkSize
+	}
+	n = int((size + partSize - 1) / partSize)
+	return partSize, n
+}
+
+// copyStream copies a single stream into place
+func (mc *multiThreadCopyState) copyStream(stream int) (err error) {
+	start := int64(stream) * mc.partSize
+	if start >= mc.size {
+		return nil
+	}
+	end := start + mc.partSize
+	if end > mc.size {
+		end = mc.size
+	}
+
+	fs.Debugf(mc.src, "multi-thread copy: stream %d/%d (%d-%d) size %v starting", stream+1, mc.streams, start, end, fs.SizeSuffix(end-start))
+
+	rc, err := mc.src.Open(mc.ctx, &fs.RangeOption{Start: start, End: end - 1})
+	if err != nil {
+		return errors.Wrap(err, "multi-thread copy: failed to open source")
+	}
+	defer fs.CheckClose(rc, &err)
+
+	// Read no more than the part in case the source ignores the range
+	in := io.LimitReader(rc, end-start)
+	buf := make([]byte, multithreadBufferSize)
+	offset := start
+	for {
+		nr, er := in.Read(buf)
+		if nr > 0 {
+			err = mc.acc.AccountRead(nr)
+			if err != nil {
+				return errors.Wrap(err, "multi-thread copy: accounting failed")
+			}
+			nw, ew := mc.wc.WriteAt(buf[0:nr], offset)
+			if nw > 0 {
+				offset += int64(nw)
+			}
+			if ew != nil {
+				return errors.Wrap(ew, "multi-thread copy: write failed")
+			}
+			if nr != nw {
+				return errors.Wrap(io.ErrShortWrite, "multi-thread copy")
+			}
+		}
+		if er != nil {
+			if er != io.EOF {
+				return errors.Wrap(er, "multi-thread copy: read failed")
+			}
+			break
+		}
+		if mc.ctx.Err() != nil {
+			return mc.ctx.Err()
+		}
+	}
+
+	if offset != end {
+		return errors.Errorf("multi-thread copy: stream %d/%d: expecting %d bytes but read %d", stream+1, mc.streams, end-start, offset-start)
+	}
+
+	fs.Debugf(mc.src, "multi-thread copy: stream %d/%d (%d-%d) size %v finished", stream+1, mc.streams, start, end, fs.SizeSuffix(end-start))
+	return nil
+}
+
+// multiThreadCopy copies src to remote in f using up to streams
+// concurrent ranged reads written into place with WriteAt
+//
+// The modification time is set afterwards. The hash is checked by
+// the caller.
+func multiThreadCopy(ctx context.Context, f fs.Fs, remote string, src fs.Object, streams int) (newDst fs.Object, err error) {
+	openWriterAt := f.Features().OpenWriterAt
+	if openWriterAt == nil {
+		return nil, errors.New("multi-thread copy: OpenWriterAt not supported")
+	}
+	if src.Size() < 0 {
+		return nil, errors.New("multi-thread copy: can't copy unknown sized file")
+	}
+	if src.Size() == 0 {
+		return nil, errors.New("multi-thread copy: can't copy zero sized file")
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	mc := &multiThreadCopyState{
+		ctx:  streamCtx,
+		size: src.Size(),
+		src:  src,
+	}
+	mc.partSize, mc.streams = calculateParts(mc.size, streams)
+
+	// Make accounting
+	mc.acc = accounting.NewAccountSizeName(ioutil.NopCloser(bytes.NewReader(nil)), src.Size(), src.Remote())
+	defer fs.CheckClose(mc.acc, &err)
+
+	// create write file handle
+	mc.wc, err = openWriterAt(ctx, remote, mc.size)
+	if err != nil {
+		return nil, errors.Wrap(err, "multi-thread copy: failed to open destination")
+	}
+
+	fs.Debugf(src, "Starting multi-thread copy with %d parts of size %v", mc.streams, fs.SizeSuffix(mc.partSize))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for stream := 0; stream < mc.streams; stream++ {
+		wg.Add(1)
+		go func(stream int) {
+			defer wg.Done()
+			err := mc.copyStream(stream)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}(stream)
+	}
+	wg.Wait()
+	closeErr := mc.wc.Close()
+	err = firstErr
+	if err == nil && closeErr != nil {
+		err = errors.Wrap(closeErr, "multi-thread copy: failed to close object after copy")
+	}
+	if err != nil {
+		if obj, findErr := f.NewObject(ctx, remote); findErr == nil {
+			fs.Logf(obj, "Removing partially written file on error: %v", err)
+			if removeErr := obj.Remove(ctx); removeErr != nil {
+				fs.Errorf(obj, "Failed to remove partially written file: %v", removeErr)
+			}
+		}
+		return nil, err
+	}
+
+	obj, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "multi-thread copy: failed to find object after copy")
+	}
+
+	err = obj.SetModTime(src.ModTime())
+	switch err {
+	case nil, fs.ErrorCantSetModTime, fs.ErrorCantSetModTimeWithoutDelete:
+	default:
+		return nil, errors.Wrap(err, "multi-thread copy: failed to set modification time")
+	}
+
+	fs.Debugf(src, "Finished multi-thread copy with %d parts of size %v", mc.streams, fs.SizeSuffix(mc.partSize))
+	return obj, nil
+}
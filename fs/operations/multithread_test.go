@@ -0,0 +1,125 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Time used in the tests
+var t1 = fstest.Time("2001-02-03T04:05:06.499999999Z")
+
+func TestCalculateParts(t *testing.T) {
+	const chunk = multithreadChunkSize
+	for _, test := range []struct {
+		size         int64
+		streams      int
+		wantPartSize int64
+		wantStreams  int
+	}{
+		{1, 4, chunk, 1},
+		{chunk, 4, chunk, 1},
+		{chunk + 1, 4, chunk, 2},
+		{4 * chunk, 4, chunk, 4},
+		{4*chunk + 1, 4, 2 * chunk, 3},
+		{10 * chunk, 3, 4 * chunk, 3},
+		{10 * chunk, 1, 10 * chunk, 1},
+	} {
+		what := fmt.Sprintf("size=%d streams=%d", test.size, test.streams)
+		partSize, streams := calculateParts(test.size, test.streams)
+		assert.Equal(t, test.wantPartSize, partSize, what)
+		assert.Equal(t, test.wantStreams, streams, what)
+		assert.True(t, int64(streams)*partSize >= test.size, what)
+	}
+}
+
+func TestDoMultiThreadCopy(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	oldStreams, oldCutoff := fs.Config.MultiThreadStreams, fs.Config.MultiThreadCutoff
+	defer func() {
+		fs.Config.MultiThreadStreams, fs.Config.MultiThreadCutoff = oldStreams, oldCutoff
+	}()
+	fs.Config.MultiThreadStreams, fs.Config.MultiThreadCutoff = 4, 50
+
+	small := r.WriteObject(ctx, "small", "too small", t1)
+	big := r.WriteObject(ctx, "big", fstest.RandomString(100), t1)
+	fstest.CheckItems(t, r.Fremote, small, big)
+	srcSmall, err := r.Fremote.NewObject(ctx, "small")
+	require.NoError(t, err)
+	srcBig, err := r.Fremote.NewObject(ctx, "big")
+	require.NoError(t, err)
+
+	assert.False(t, doMultiThreadCopy(r.Flocal, srcSmall))
+	assert.True(t, doMultiThreadCopy(r.Flocal, srcBig))
+	fs.Config.MultiThreadStreams = 1
+	assert.False(t, doMultiThreadCopy(r.Flocal, srcBig))
+}
+
+func TestMultithreadCopy(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	for _, test := range []struct {
+		size    int
+		streams int
+	}{
+		{1, 4},
+		{multithreadChunkSize - 1, 2},
+		{multithreadChunkSize + 1, 2},
+		{4*multithreadChunkSize + 17, 4},
+		{1<<20 + 3, 7},
+	} {
+		t.Run(fmt.Sprintf("%+v", test), func(t *testing.T) {
+			contents := fstest.RandomString(test.size)
+			file1 := r.WriteObject(ctx, "file1", contents, t1)
+			fstest.CheckItems(t, r.Fremote, file1)
+			fstest.CheckItems(t, r.Flocal)
+
+			src, err := r.Fremote.NewObject(ctx, "file1")
+			require.NoError(t, err)
+
+			dst, err := multiThreadCopy(ctx, r.Flocal, "file1", src, test.streams)
+			require.NoError(t, err)
+			assert.Equal(t, src.Size(), dst.Size())
+			assert.Equal(t, "file1", dst.Remote())
+
+			fstest.CheckListingWithPrecision(t, r.Flocal, []fstest.Item{file1}, nil, fs.GetModifyWindow(r.Flocal, r.Fremote))
+			require.NoError(t, dst.Remove(ctx))
+		})
+	}
+}
+
+func TestCopyMultiThread(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	oldStreams, oldCutoff := fs.Config.MultiThreadStreams, fs.Config.MultiThreadCutoff
+	defer func() {
+		fs.Config.MultiThreadStreams, fs.Config.MultiThreadCutoff = oldStreams, oldCutoff
+	}()
+	fs.Config.MultiThreadStreams, fs.Config.MultiThreadCutoff = 3, 1024
+
+	file1 := r.WriteObject(ctx, "sub dir/file1", fstest.RandomString(3*multithreadChunkSize+5), t1)
+	fstest.CheckItems(t, r.Fremote, file1)
+	src, err := r.Fremote.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+
+	// New file then replacing it
+	for i := 0; i < 2; i++ {
+		dst, _ := r.Flocal.NewObject(ctx, file1.Path)
+		newDst, err := Copy(ctx, r.Flocal, dst, file1.Path, src)
+		require.NoError(t, err)
+		require.NotNil(t, newDst)
+		fstest.CheckItems(t, r.Flocal, file1)
+	}
+}
@@ -90,8 +90,8 @@ func CheckHashes(src fs.ObjectInfo, dst fs.Object) (equal bool, ht hash.Type, er
 //
 // Otherwise the file is considered to be not equal including if there
 // were errors reading info.
-func Equal(src fs.ObjectInfo, dst fs.Object) bool {
-	return equal(src, dst, fs.Config.SizeOnly, fs.Config.CheckSum)
+func Equal(ctx context.Context, src fs.ObjectInfo, dst fs.Object) bool {
+	return equal(ctx, src, dst, fs.Config.SizeOnly, fs.Config.CheckSum)
 }
 
 // sizeDiffers compare the size of src and dst taking into account the
@@ -103,7 +103,7 @@ func sizeDiffers(src, dst fs.ObjectInfo) bool {
 	return src.Size() != dst.Size()
 }
 
-func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
+func equal(ctx context.Context, src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
 	if sizeDiffers(src, dst) {
 		fs.Debugf(src, "Sizes differ (src %d vs dst %d)", src.Size(), dst.Size())
 		return false
@@ -179,7 +179,7 @@ func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
 				// Remove the file if BackupDir isn't set.  If BackupDir is set we would rather have the old file
 				// put in the BackupDir than deleted which is what will happen if we don't delete it.
 				if fs.Config.BackupDir == "" {
-					err = dst.Remove()
+					err = dst.Remove(ctx)
 					if err != nil {
 						fs.Errorf(dst, "failed to delete before re-upload: %v", err)
 					}
@@ -199,12 +199,12 @@ func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
 // Used to remove a failed copy
 //
 // Returns whether the file was succesfully removed or not
-func removeFailedCopy(dst fs.Object) bool {
+func removeFailedCopy(ctx context.Context, dst fs.Object) bool {
 	if dst == nil {
 		return false
 	}
 	fs.Infof(dst, "Removing failed copy")
-	removeErr := dst.Remove()
+	removeErr := dst.Remove(ctx)
 	if removeErr != nil {
 		fs.Infof(dst, "Failed to remove failed copy: %s", removeErr)
 		return false
@@ -232,20 +232,35 @@ func (o *overrideRemoteObject) MimeType() string {
 	return ""
 }
 
+// Metadata returns the metadata of the underlying object or nil if
+// it doesn't have any
+func (o *overrideRemoteObject) Metadata() (fs.Metadata, error) {
+	return fs.GetMetadata(o.Object)
+}
+
 // Check interface is satisfied
-var _ fs.MimeTyper = (*overrideRemoteObject)(nil)
+var (
+	_ fs.MimeTyper  = (*overrideRemoteObject)(nil)
+	_ fs.Metadataer = (*overrideRemoteObject)(nil)
+)
 
 // Copy src object to dst or f if nil.  If dst is nil then it uses
 // remote as the name of the new object.
 //
 // It returns the destination object if possible.  Note that this may
 // be nil.
-func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
+func Copy(ctx context.Context, f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
 	newDst = dst
 	if fs.Config.DryRun {
 		fs.Logf(src, "Not copying as --dry-run")
 		return newDst, nil
 	}
+	// Check --max-transfer with --cutoff-mode soft or cautious
+	err = accounting.Stats.ReserveTransfer(src.Size())
+	if err != nil {
+		fs.Debugf(src, "Not copying: %v", err)
+		return newDst, err
+	}
 	maxTries := fs.Config.LowLevelRetries
 	tries := 0
 	doUpdate := dst != nil
@@ -260,23 +275,42 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 		}
 	}
 	hashOption := &fs.HashesOption{Hashes: common}
+	options := []fs.OpenOption{hashOption}
+	if fs.Config.Metadata && len(fs.Config.MetadataSet) != 0 {
+		options = append(options, fs.MetadataOption(fs.Config.MetadataSet))
+	}
 	var actionTaken string
 	for {
 		// Try server side copy first - if has optional interface and
 		// is same underlying remote
 		actionTaken = "Copied (server side copy)"
-		if doCopy := f.Features().Copy; doCopy != nil && SameConfig(src.Fs(), f) {
-			newDst, err = doCopy(src, remote)
+		acrossConfigs := !SameConfig(src.Fs(), f) && ServerSideAcrossConfigs(f, src.Fs())
+		if doCopy := f.Features().Copy; doCopy != nil && (SameConfig(src.Fs(), f) || acrossConfigs) {
+			newDst, err = doCopy(ctx, src, remote)
 			if err == nil {
 				dst = newDst
+			} else if acrossConfigs && err != fs.ErrorCantCopy {
+				fs.Debugf(src, "Server side copy across configs failed, falling back to copy: %v", err)
+				err = fs.ErrorCantCopy
 			}
 		} else {
 			err = fs.ErrorCantCopy
 		}
 		// If can't server side copy, do it manually
-		if err == fs.ErrorCantCopy {
+		if err == fs.ErrorCantCopy && doMultiThreadCopy(f, src) {
+			// Copy with multiple streams writing into place
+			dst, err = multiThreadCopy(ctx, f, remote, src, fs.Config.MultiThreadStreams)
+			if doUpdate {
+				actionTaken = "Multi-thread Copied (replaced existing)"
+			} else {
+				actionTaken = "Multi-thread Copied (new)"
+			}
+			if err == nil {
+				newDst = dst
+			}
+		} else if err == fs.ErrorCantCopy {
 			var in0 io.ReadCloser
-			in0, err = src.Open(hashOption)
+			in0, err = src.Open(ctx, hashOption)
 			if err != nil {
 				err = errors.Wrap(err, "failed to open source object")
 			} else {
@@ -288,10 +322,10 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 				}
 				if doUpdate {
 					actionTaken = "Copied (replaced existing)"
-					err = dst.Update(in, wrappedSrc, hashOption)
+					err = dst.Update(ctx, in, wrappedSrc, options...)
 				} else {
 					actionTaken = "Copied (new)"
-					dst, err = f.Put(in, wrappedSrc, hashOption)
+					dst, err = f.Put(ctx, in, wrappedSrc, options...)
 				}
 				closeErr := in.Close()
 				if err == nil {
@@ -323,7 +357,7 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 		err = errors.Errorf("corrupted on transfer: sizes differ %d vs %d", src.Size(), dst.Size())
 		fs.Errorf(dst, "%v", err)
 		fs.CountError(err)
-		removeFailedCopy(dst)
+		removeFailedCopy(ctx, dst)
 		return newDst, err
 	}
 
@@ -346,7 +380,7 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 				err = errors.Errorf("corrupted on transfer: %v hash differ %q vs %q", hashType, srcSum, dstSum)
 				fs.Errorf(dst, "%v", err)
 				fs.CountError(err)
-				removeFailedCopy(dst)
+				removeFailedCopy(ctx, dst)
 				return newDst, err
 			}
 		}
@@ -361,23 +395,26 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 //
 // It returns the destination object if possible.  Note that this may
 // be nil.
-func Move(fdst fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
+func Move(ctx context.Context, fdst fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
 	newDst = dst
 	if fs.Config.DryRun {
 		fs.Logf(src, "Not moving as --dry-run")
 		return newDst, nil
 	}
 	// See if we have Move available
-	if doMove := fdst.Features().Move; doMove != nil && SameConfig(src.Fs(), fdst) {
+	acrossConfigs := !SameConfig(src.Fs(), fdst) && ServerSideAcrossConfigs(fdst, src.Fs())
+	if doMove := fdst.Features().Move; doMove != nil && (SameConfig(src.Fs(), fdst) || acrossConfigs) {
 		// Delete destination if it exists
 		if dst != nil {
-			err = DeleteFile(dst)
+			err = DeleteFile(ctx, dst)
 			if err != nil {
 				return newDst, err
 			}
+			// dst is gone so don't update it if falling back to copy
+			dst, newDst = nil, nil
 		}
 		// Move dst <- src
-		newDst, err = doMove(src, remote)
+		newDst, err = doMove(ctx, src, remote)
 		switch err {
 		case nil:
 			fs.Infof(src, "Moved (server side)")
@@ -385,19 +422,23 @@ func Move(fdst fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Ob
 		case fs.ErrorCantMove:
 			fs.Debugf(src, "Can't move, switching to copy")
 		default:
+			if acrossConfigs {
+				fs.Debugf(src, "Server side move across configs failed, switching to copy: %v", err)
+				break
+			}
 			fs.CountError(err)
 			fs.Errorf(src, "Couldn't move: %v", err)
 			return newDst, err
 		}
 	}
 	// Move not found or didn't work so copy dst <- src
-	newDst, err = Copy(fdst, dst, remote, src)
+	newDst, err = Copy(ctx, fdst, dst, remote, src)
 	if err != nil {
 		fs.Errorf(src, "Not deleting source as copy failed: %v", err)
 		return newDst, err
 	}
 	// Delete src if no error on copy
-	return newDst, DeleteFile(src)
+	return newDst, DeleteFile(ctx, src)
 }
 
 // CanServerSideMove returns true if fdst support server side moves or
@@ -416,7 +457,7 @@ func CanServerSideMove(fdst fs.Fs) bool {
 //
 // If backupDir is set then it moves the file to there instead of
 // deleting
-func DeleteFileWithBackupDir(dst fs.Object, backupDir fs.Fs) (err error) {
+func DeleteFileWithBackupDir(ctx context.Context, dst fs.Object, backupDir fs.Fs) (err error) {
 	accounting.Stats.Checking(dst.Remote())
 	numDeletes := accounting.Stats.Deletes(1)
 	if fs.Config.MaxDelete != -1 && numDeletes > fs.Config.MaxDelete {
@@ -433,11 +474,11 @@ func DeleteFileWithBackupDir(dst fs.Object, backupDir fs.Fs) (err error) {
 			err = errors.New("parameter to --backup-dir has to be on the same remote as destination")
 		} else {
 			remoteWithSuffix := dst.Remote() + fs.Config.Suffix
-			overwritten, _ := backupDir.NewObject(remoteWithSuffix)
-			_, err = Move(backupDir, overwritten, remoteWithSuffix, dst)
+			overwritten, _ := backupDir.NewObject(ctx, remoteWithSuffix)
+			_, err = Move(ctx, backupDir, overwritten, remoteWithSuffix, dst)
 		}
 	} else {
-		err = dst.Remove()
+		err = dst.Remove(ctx)
 	}
 	if err != nil {
 		fs.CountError(err)
@@ -453,8 +494,8 @@ func DeleteFileWithBackupDir(dst fs.Object, backupDir fs.Fs) (err error) {
 //
 // If useBackupDir is set and --backup-dir is in effect then it moves
 // the file to there instead of deleting
-func DeleteFile(dst fs.Object) (err error) {
-	return DeleteFileWithBackupDir(dst, nil)
+func DeleteFile(ctx context.Context, dst fs.Object) (err error) {
+	return DeleteFileWithBackupDir(ctx, dst, nil)
 }
 
 // DeleteFilesWithBackupDir removes all the files passed in the
@@ -462,7 +503,7 @@ func DeleteFile(dst fs.Object) (err error) {
 //
 // If backupDir is set the files will be placed into that directory
 // instead of being deleted.
-func DeleteFilesWithBackupDir(toBeDeleted fs.ObjectsChan, backupDir fs.Fs) error {
+func DeleteFilesWithBackupDir(ctx context.Context, toBeDeleted fs.ObjectsChan, backupDir fs.Fs) error {
 	var wg sync.WaitGroup
 	wg.Add(fs.Config.Transfers)
 	var errorCount int32
@@ -472,7 +513,7 @@ func DeleteFilesWithBackupDir(toBeDeleted fs.ObjectsChan, backupDir fs.Fs) error
 		go func() {
 			defer wg.Done()
 			for dst := range toBeDeleted {
-				err := DeleteFileWithBackupDir(dst, backupDir)
+				err := DeleteFileWithBackupDir(ctx, dst, backupDir)
 				if err != nil {
 					atomic.AddInt32(&errorCount, 1)
 					if fserrors.IsFatalError(err) {
@@ -497,8 +538,8 @@ func DeleteFilesWithBackupDir(toBeDeleted fs.ObjectsChan, backupDir fs.Fs) error
 }
 
 // DeleteFiles removes all the files passed in the channel
-func DeleteFiles(toBeDeleted fs.ObjectsChan) error {
-	return DeleteFilesWithBackupDir(toBeDeleted, nil)
+func DeleteFiles(ctx context.Context, toBeDeleted fs.ObjectsChan) error {
+	return DeleteFilesWithBackupDir(ctx, toBeDeleted, nil)
 }
 
 // SameConfig returns true if fdst and fsrc are using the same config
@@ -507,6 +548,21 @@ func SameConfig(fdst, fsrc fs.Info) bool {
 	return fdst.Name() == fsrc.Name()
 }
 
+// SameRemoteType returns true if fdst and fsrc are the same type
+func SameRemoteType(fdst, fsrc fs.Info) bool {
+	return fmt.Sprintf("%T", fdst) == fmt.Sprintf("%T", fsrc)
+}
+
+// ServerSideAcrossConfigs returns true if server side operations
+// should be attempted from fsrc to fdst even though they don't use
+// the same config file entry.
+//
+// This needs --server-side-across-configs, fdst to support it and
+// fsrc to be the same type of remote.
+func ServerSideAcrossConfigs(fdst, fsrc fs.Info) bool {
+	return fs.Config.ServerSideAcrossConfigs && fdst.Features().ServerSideAcrossConfigs && SameRemoteType(fdst, fsrc)
+}
+
 // Same returns true if fdst and fsrc point to the same underlying Fs
 func Same(fdst, fsrc fs.Info) bool {
 	return SameConfig(fdst, fsrc) && fdst.Root() == fsrc.Root()
@@ -535,7 +591,7 @@ func Overlapping(fdst, fsrc fs.Info) bool {
 //
 // it returns true if differences were found
 // it also returns whether it couldn't be hashed
-func checkIdentical(dst, src fs.Object) (differ bool, noHash bool) {
+func checkIdentical(ctx context.Context, dst, src fs.Object) (differ bool, noHash bool) {
 	same, ht, err := CheckHashes(src, dst)
 	if err != nil {
 		// CheckHashes will log and count errors
@@ -554,11 +610,12 @@ func checkIdentical(dst, src fs.Object) (differ bool, noHash bool) {
 }
 
 // checkFn is the the type of the checking function used in CheckFn()
-type checkFn func(a, b fs.Object) (differ bool, noHash bool)
+type checkFn func(ctx context.Context, a, b fs.Object) (differ bool, noHash bool)
 
 // checkMarch is used to march over two Fses in the same way as
 // sync/copy
 type checkMarch struct {
+	ctx             context.Context
 	fdst, fsrc      fs.Fs
 	check           checkFn
 	oneway          bool
@@ -608,7 +665,7 @@ func (c *checkMarch) SrcOnly(src fs.DirEntry) (recurse bool) {
 }
 
 // check to see if two objects are identical using the check function
-func (c *checkMarch) checkIdentical(dst, src fs.Object) (differ bool, noHash bool) {
+func (c *checkMarch) checkIdentical(ctx context.Context, dst, src fs.Object) (differ bool, noHash bool) {
 	accounting.Stats.Checking(src.Remote())
 	defer accounting.Stats.DoneChecking(src.Remote())
 	if sizeDiffers(src, dst) {
@@ -620,7 +677,7 @@ func (c *checkMarch) checkIdentical(dst, src fs.Object) (differ bool, noHash boo
 	if fs.Config.SizeOnly {
 		return false, false
 	}
-	return c.check(dst, src)
+	return c.check(ctx, dst, src)
 }
 
 // Match is called when src and dst are present, so sync src to dst
@@ -629,7 +686,7 @@ func (c *checkMarch) Match(dst, src fs.DirEntry) (recurse bool) {
 	case fs.Object:
 		dstX, ok := dst.(fs.Object)
 		if ok {
-			differ, noHash := c.checkIdentical(dstX, srcX)
+			differ, noHash := c.checkIdentical(c.ctx, dstX, srcX)
 			if differ {
 				atomic.AddInt32(&c.differences, 1)
 			} else {
@@ -670,8 +727,9 @@ func (c *checkMarch) Match(dst, src fs.DirEntry) (recurse bool) {
 //
 // it returns true if differences were found
 // it also returns whether it couldn't be hashed
-func CheckFn(fdst, fsrc fs.Fs, check checkFn, oneway bool) error {
+func CheckFn(ctx context.Context, fdst, fsrc fs.Fs, check checkFn, oneway bool) error {
 	c := &checkMarch{
+		ctx:    ctx,
 		fdst:   fdst,
 		fsrc:   fsrc,
 		check:  check,
@@ -679,7 +737,7 @@ func CheckFn(fdst, fsrc fs.Fs, check checkFn, oneway bool) error {
 	}
 
 	// set up a march over fdst and fsrc
-	m := march.New(context.Background(), fdst, fsrc, "", c)
+	m := march.New(ctx, fdst, fsrc, "", c)
 	fs.Infof(fdst, "Waiting for checks to finish")
 	m.Run()
 
@@ -701,8 +759,8 @@ func CheckFn(fdst, fsrc fs.Fs, check checkFn, oneway bool) error {
 }
 
 // Check the files in fsrc and fdst according to Size and hash
-func Check(fdst, fsrc fs.Fs, oneway bool) error {
-	return CheckFn(fdst, fsrc, checkIdentical, oneway)
+func Check(ctx context.Context, fdst, fsrc fs.Fs, oneway bool) error {
+	return CheckFn(ctx, fdst, fsrc, checkIdentical, oneway)
 }
 
 // CheckEqualReaders checks to see if in1 and in2 have the same
@@ -739,15 +797,15 @@ func CheckEqualReaders(in1, in2 io.Reader) (differ bool, err error) {
 // reading all their bytes if necessary.
 //
 // it returns true if differences were found
-func CheckIdentical(dst, src fs.Object) (differ bool, err error) {
-	in1, err := dst.Open()
+func CheckIdentical(ctx context.Context, dst, src fs.Object) (differ bool, err error) {
+	in1, err := dst.Open(ctx)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to open %q", dst)
 	}
 	in1 = accounting.NewAccount(in1, dst).WithBuffer() // account and buffer the transfer
 	defer fs.CheckClose(in1, &err)
 
-	in2, err := src.Open()
+	in2, err := src.Open(ctx)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to open %q", src)
 	}
@@ -759,9 +817,9 @@ func CheckIdentical(dst, src fs.Object) (differ bool, err error) {
 
 // CheckDownload checks the files in fsrc and fdst according to Size
 // and the actual contents of the files.
-func CheckDownload(fdst, fsrc fs.Fs, oneway bool) error {
-	check := func(a, b fs.Object) (differ bool, noHash bool) {
-		differ, err := CheckIdentical(a, b)
+func CheckDownload(ctx context.Context, fdst, fsrc fs.Fs, oneway bool) error {
+	check := func(ctx context.Context, a, b fs.Object) (differ bool, noHash bool) {
+		differ, err := CheckIdentical(ctx, a, b)
 		if err != nil {
 			fs.CountError(err)
 			fs.Errorf(a, "Failed to download: %v", err)
@@ -769,14 +827,14 @@ func CheckDownload(fdst, fsrc fs.Fs, oneway bool) error {
 		}
 		return differ, false
 	}
-	return CheckFn(fdst, fsrc, check, oneway)
+	return CheckFn(ctx, fdst, fsrc, check, oneway)
 }
 
 // ListFn lists the Fs to the supplied function
 //
 // Lists in parallel which may get them out of order
-func ListFn(f fs.Fs, fn func(fs.Object)) error {
-	return walk.Walk(f, "", false, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
+func ListFn(ctx context.Context, f fs.Fs, fn func(fs.Object)) error {
+	return walk.Walk(ctx, f, "", false, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			// FIXME count errors and carry on for listing
 			return err
@@ -803,8 +861,8 @@ func syncFprintf(w io.Writer, format string, a ...interface{}) {
 // Shows size and path - obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func List(f fs.Fs, w io.Writer) error {
-	return ListFn(f, func(o fs.Object) {
+func List(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return ListFn(ctx, f, func(o fs.Object) {
 		syncFprintf(w, "%9d %s\n", o.Size(), o.Remote())
 	})
 }
@@ -814,8 +872,8 @@ func List(f fs.Fs, w io.Writer) error {
 // Shows size, mod time and path - obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func ListLong(f fs.Fs, w io.Writer) error {
-	return ListFn(f, func(o fs.Object) {
+func ListLong(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return ListFn(ctx, f, func(o fs.Object) {
 		accounting.Stats.Checking(o.Remote())
 		modTime := o.ModTime()
 		accounting.Stats.DoneChecking(o.Remote())
@@ -829,8 +887,8 @@ func ListLong(f fs.Fs, w io.Writer) error {
 // excludes
 //
 // Lists in parallel which may get them out of order
-func Md5sum(f fs.Fs, w io.Writer) error {
-	return HashLister(hash.MD5, f, w)
+func Md5sum(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return HashLister(ctx, hash.MD5, f, w)
 }
 
 // Sha1sum list the Fs to the supplied writer
@@ -838,8 +896,8 @@ func Md5sum(f fs.Fs, w io.Writer) error {
 // Obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func Sha1sum(f fs.Fs, w io.Writer) error {
-	return HashLister(hash.SHA1, f, w)
+func Sha1sum(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return HashLister(ctx, hash.SHA1, f, w)
 }
 
 // DropboxHashSum list the Fs to the supplied writer
@@ -847,8 +905,8 @@ func Sha1sum(f fs.Fs, w io.Writer) error {
 // Obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func DropboxHashSum(f fs.Fs, w io.Writer) error {
-	return HashLister(hash.Dropbox, f, w)
+func DropboxHashSum(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return HashLister(ctx, hash.Dropbox, f, w)
 }
 
 // hashSum returns the human readable hash for ht passed in.  This may
@@ -867,8 +925,8 @@ func hashSum(ht hash.Type, o fs.Object) string {
 }
 
 // HashLister does a md5sum equivalent for the hash type passed in
-func HashLister(ht hash.Type, f fs.Fs, w io.Writer) error {
-	return ListFn(f, func(o fs.Object) {
+func HashLister(ctx context.Context, ht hash.Type, f fs.Fs, w io.Writer) error {
+	return ListFn(ctx, f, func(o fs.Object) {
 		sum := hashSum(ht, o)
 		syncFprintf(w, "%*s  %s\n", hash.Width[ht], sum, o.Remote())
 	})
@@ -877,8 +935,8 @@ func HashLister(ht hash.Type, f fs.Fs, w io.Writer) error {
 // Count counts the objects and their sizes in the Fs
 //
 // Obeys includes and excludes
-func Count(f fs.Fs) (objects int64, size int64, err error) {
-	err = ListFn(f, func(o fs.Object) {
+func Count(ctx context.Context, f fs.Fs) (objects int64, size int64, err error) {
+	err = ListFn(ctx, f, func(o fs.Object) {
 		atomic.AddInt64(&objects, 1)
 		atomic.AddInt64(&size, o.Size())
 	})
@@ -895,8 +953,8 @@ func ConfigMaxDepth(recursive bool) int {
 }
 
 // ListDir lists the directories/buckets/containers in the Fs to the supplied writer
-func ListDir(f fs.Fs, w io.Writer) error {
-	return walk.Walk(f, "", false, ConfigMaxDepth(false), func(dirPath string, entries fs.DirEntries, err error) error {
+func ListDir(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return walk.Walk(ctx, f, "", false, ConfigMaxDepth(false), func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			// FIXME count errors and carry on for listing
 			return err
@@ -911,13 +969,13 @@ func ListDir(f fs.Fs, w io.Writer) error {
 }
 
 // Mkdir makes a destination directory or container
-func Mkdir(f fs.Fs, dir string) error {
+func Mkdir(ctx context.Context, f fs.Fs, dir string) error {
 	if fs.Config.DryRun {
 		fs.Logf(fs.LogDirName(f, dir), "Not making directory as dry run is set")
 		return nil
 	}
 	fs.Debugf(fs.LogDirName(f, dir), "Making directory")
-	err := f.Mkdir(dir)
+	err := f.Mkdir(ctx, dir)
 	if err != nil {
 		fs.CountError(err)
 		return err
@@ -927,18 +985,18 @@ func Mkdir(f fs.Fs, dir string) error {
 
 // TryRmdir removes a container but not if not empty.  It doesn't
 // count errors but may return one.
-func TryRmdir(f fs.Fs, dir string) error {
+func TryRmdir(ctx context.Context, f fs.Fs, dir string) error {
 	if fs.Config.DryRun {
 		fs.Logf(fs.LogDirName(f, dir), "Not deleting as dry run is set")
 		return nil
 	}
 	fs.Debugf(fs.LogDirName(f, dir), "Removing directory")
-	return f.Rmdir(dir)
+	return f.Rmdir(ctx, dir)
 }
 
 // Rmdir removes a container but not if not empty
-func Rmdir(f fs.Fs, dir string) error {
-	err := TryRmdir(f, dir)
+func Rmdir(ctx context.Context, f fs.Fs, dir string) error {
+	err := TryRmdir(ctx, f, dir)
 	if err != nil {
 		fs.CountError(err)
 		return err
@@ -947,7 +1005,7 @@ func Rmdir(f fs.Fs, dir string) error {
 }
 
 // Purge removes a directory and all of its contents
-func Purge(f fs.Fs, dir string) error {
+func Purge(ctx context.Context, f fs.Fs, dir string) error {
 	doFallbackPurge := true
 	var err error
 	if dir == "" {
@@ -957,7 +1015,7 @@ func Purge(f fs.Fs, dir string) error {
 			if fs.Config.DryRun {
 				fs.Logf(f, "Not purging as --dry-run set")
 			} else {
-				err = doPurge()
+				err = doPurge(ctx)
 				if err == fs.ErrorCantPurge {
 					doFallbackPurge = true
 				}
@@ -966,11 +1024,11 @@ func Purge(f fs.Fs, dir string) error {
 	}
 	if doFallbackPurge {
 		// DeleteFiles and Rmdir observe --dry-run
-		err = DeleteFiles(listToChan(f, dir))
+		err = DeleteFiles(ctx, listToChan(ctx, f, dir))
 		if err != nil {
 			return err
 		}
-		err = Rmdirs(f, "", false)
+		err = Rmdirs(ctx, f, "", false)
 	}
 	if err != nil {
 		fs.CountError(err)
@@ -981,13 +1039,13 @@ func Purge(f fs.Fs, dir string) error {
 
 // Delete removes all the contents of a container.  Unlike Purge, it
 // obeys includes and excludes.
-func Delete(f fs.Fs) error {
+func Delete(ctx context.Context, f fs.Fs) error {
 	delChan := make(fs.ObjectsChan, fs.Config.Transfers)
 	delErr := make(chan error, 1)
 	go func() {
-		delErr <- DeleteFiles(delChan)
+		delErr <- DeleteFiles(ctx, delChan)
 	}()
-	err := ListFn(f, func(o fs.Object) {
+	err := ListFn(ctx, f, func(o fs.Object) {
 		delChan <- o
 	})
 	close(delChan)
@@ -1004,11 +1062,11 @@ func Delete(f fs.Fs) error {
 // channel.
 //
 // If the error was ErrorDirNotFound then it will be ignored
-func listToChan(f fs.Fs, dir string) fs.ObjectsChan {
+func listToChan(ctx context.Context, f fs.Fs, dir string) fs.ObjectsChan {
 	o := make(fs.ObjectsChan, fs.Config.Checkers)
 	go func() {
 		defer close(o)
-		_ = walk.Walk(f, dir, true, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
+		_ = walk.Walk(ctx, f, dir, true, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
 			if err != nil {
 				if err == fs.ErrorDirNotFound {
 					return nil
@@ -1054,9 +1112,9 @@ type readCloser struct {
 //
 // if count < 0 then it will be ignored
 // if count >= 0 then only that many characters will be output
-func Cat(f fs.Fs, w io.Writer, offset, count int64) error {
+func Cat(ctx context.Context, f fs.Fs, w io.Writer, offset, count int64) error {
 	var mu sync.Mutex
-	return ListFn(f, func(o fs.Object) {
+	return ListFn(ctx, f, func(o fs.Object) {
 		var err error
 		accounting.Stats.Transferring(o.Remote())
 		defer func() {
@@ -1074,7 +1132,7 @@ func Cat(f fs.Fs, w io.Writer, offset, count int64) error {
 		if opt.Start > 0 || opt.End >= 0 {
 			options = append(options, &opt)
 		}
-		in, err := o.Open(options...)
+		in, err := o.Open(ctx, options...)
 		if err != nil {
 			fs.CountError(err)
 			fs.Errorf(o, "Failed to open: %v", err)
@@ -1107,7 +1165,13 @@ func Cat(f fs.Fs, w io.Writer, offset, count int64) error {
 }
 
 // Rcat reads data from the Reader until EOF and uploads it to a file on remote
-func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst fs.Object, err error) {
+func Rcat(ctx context.Context, fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst fs.Object, err error) {
+	// Check --max-transfer with --cutoff-mode soft or cautious
+	err = accounting.Stats.ReserveTransfer(-1)
+	if err != nil {
+		_ = in.Close()
+		return nil, err
+	}
 	accounting.Stats.Transferring(dstFileName)
 	in = accounting.NewAccountSizeName(in, -1, dstFileName).WithBuffer()
 	defer func() {
@@ -1127,7 +1191,7 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 
 	compare := func(dst fs.Object) error {
 		src := object.NewStaticObjectInfo(dstFileName, modTime, int64(readCounter.BytesRead()), false, hash.Sums(), fdst)
-		if !Equal(src, dst) {
+		if !Equal(ctx, src, dst) {
 			err = errors.Errorf("corrupted on transfer")
 			fs.CountError(err)
 			fs.Errorf(dst, "%v", err)
@@ -1141,7 +1205,7 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 	if n, err := io.ReadFull(trackingIn, buf); err == io.EOF || err == io.ErrUnexpectedEOF {
 		fs.Debugf(fdst, "File to upload is small (%d bytes), uploading instead of streaming", n)
 		src := object.NewMemoryObject(dstFileName, modTime, buf[:n])
-		return Copy(fdst, nil, dstFileName, src)
+		return Copy(ctx, fdst, nil, dstFileName, src)
 	}
 
 	// Make a new ReadCloser with the bits we've already read
@@ -1159,7 +1223,7 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 			return nil, errors.Wrap(err, "Failed to create temporary local FS to spool file")
 		}
 		defer func() {
-			err := Purge(tmpLocalFs, "")
+			err := Purge(ctx, tmpLocalFs, "")
 			if err != nil {
 				fs.Infof(tmpLocalFs, "Failed to cleanup temporary FS: %v", err)
 			}
@@ -1175,7 +1239,7 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 	}
 
 	objInfo := object.NewStaticObjectInfo(dstFileName, modTime, -1, false, nil, nil)
-	if dst, err = fStreamTo.Features().PutStream(in, objInfo, hashOption); err != nil {
+	if dst, err = fStreamTo.Features().PutStream(ctx, in, objInfo, hashOption); err != nil {
 		return dst, err
 	}
 	if err = compare(dst); err != nil {
@@ -1183,7 +1247,7 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 	}
 	if !canStream {
 		// copy dst (which is the local object we have just streamed to) to the remote
-		return Copy(fdst, nil, dstFileName, dst)
+		return Copy(ctx, fdst, nil, dstFileName, dst)
 	}
 	return dst, nil
 }
@@ -1199,10 +1263,10 @@ func PublicLink(f fs.Fs, remote string) (string, error) {
 
 // Rmdirs removes any empty directories (or directories only
 // containing empty directories) under f, including f.
-func Rmdirs(f fs.Fs, dir string, leaveRoot bool) error {
+func Rmdirs(ctx context.Context, f fs.Fs, dir string, leaveRoot bool) error {
 	dirEmpty := make(map[string]bool)
-	dirEmpty[""] = !leaveRoot
-	err := walk.Walk(f, dir, true, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
+	dirEmpty[dir] = !leaveRoot
+	err := walk.Walk(ctx, f, dir, true, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			fs.CountError(err)
 			fs.Errorf(f, "Failed to list %q: %v", dirPath, err)
@@ -1249,7 +1313,7 @@ func Rmdirs(f fs.Fs, dir string, leaveRoot bool) error {
 	sort.Strings(toDelete)
 	for i := len(toDelete) - 1; i >= 0; i-- {
 		dir := toDelete[i]
-		err := TryRmdir(f, dir)
+		err := TryRmdir(ctx, f, dir)
 		if err != nil {
 			fs.CountError(err)
 			fs.Errorf(dir, "Failed to rmdir: %v", err)
@@ -1264,7 +1328,7 @@ func Rmdirs(f fs.Fs, dir string, leaveRoot bool) error {
 //
 // Returns a flag which indicates whether the file needs to be
 // transferred or not.
-func NeedTransfer(dst, src fs.Object) bool {
+func NeedTransfer(ctx context.Context, dst, src fs.Object) bool {
 	if dst == nil {
 		fs.Debugf(src, "Couldn't find file - need to transfer")
 		return true
@@ -1307,7 +1371,7 @@ func NeedTransfer(dst, src fs.Object) bool {
 		}
 	} else {
 		// Check to see if changed or not
-		if Equal(src, dst) {
+		if Equal(ctx, src, dst) {
 			fs.Debugf(src, "Unchanged skipping")
 			return false
 		}
@@ -1315,8 +1379,63 @@ func NeedTransfer(dst, src fs.Object) bool {
 	return true
 }
 
+// CompareOrCopyDest checks --compare-dest and --copy-dest to see if
+// src needs to be transferred to fdst.
+//
+// compareOrCopyDest is the Fs made from whichever of the flags is
+// set. If --copy-dest is in use and an identical file is found there
+// it is server side copied to fdst, first moving any existing dst
+// into backupDir if that isn't nil.
+//
+// Returns true if src doesn't need transferring.
+func CompareOrCopyDest(ctx context.Context, fdst fs.Fs, dst, src fs.Object, compareOrCopyDest fs.Fs, backupDir fs.Fs) (noNeedTransfer bool, err error) {
+	if compareOrCopyDest == nil {
+		return false, nil
+	}
+	remote := src.Remote()
+	if dst != nil {
+		remote = dst.Remote()
+	}
+	reference, err := compareOrCopyDest.NewObject(ctx, remote)
+	switch err {
+	case nil:
+	case fs.ErrorObjectNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+	if !Equal(ctx, src, reference) {
+		return false, nil
+	}
+	if fs.Config.CompareDest != "" {
+		fs.Debugf(src, "Destination found in --compare-dest, skipping")
+		return true, nil
+	}
+	if dst != nil && Equal(ctx, src, dst) {
+		fs.Debugf(src, "Unchanged skipping")
+		return true, nil
+	}
+	// If destination already exists, then we must move it into --backup-dir if required
+	if dst != nil && backupDir != nil {
+		remoteWithSuffix := dst.Remote() + fs.Config.Suffix
+		overwritten, _ := backupDir.NewObject(ctx, remoteWithSuffix)
+		_, err = Move(ctx, backupDir, overwritten, remoteWithSuffix, dst)
+		if err != nil {
+			return false, err
+		}
+		dst = nil
+	}
+	_, err = Copy(ctx, fdst, dst, remote, reference)
+	if err != nil {
+		fs.Errorf(src, "Destination found in --copy-dest but failed to copy it: %v", err)
+		return false, nil
+	}
+	fs.Debugf(src, "Destination found in --copy-dest, using server side copy")
+	return true, nil
+}
+
 // moveOrCopyFile moves or copies a single file possibly to a new name
-func moveOrCopyFile(fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string, cp bool) (err error) {
+func moveOrCopyFile(ctx context.Context, fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string, cp bool) (err error) {
 	dstFilePath := path.Join(fdst.Root(), dstFileName)
 	srcFilePath := path.Join(fsrc.Root(), srcFileName)
 	if fdst.Name() == fsrc.Name() && dstFilePath == srcFilePath {
@@ -1331,27 +1450,27 @@ func moveOrCopyFile(fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName stri
 	}
 
 	// Find src object
-	srcObj, err := fsrc.NewObject(srcFileName)
+	srcObj, err := fsrc.NewObject(ctx, srcFileName)
 	if err != nil {
 		return err
 	}
 
 	// Find dst object if it exists
-	dstObj, err := fdst.NewObject(dstFileName)
+	dstObj, err := fdst.NewObject(ctx, dstFileName)
 	if err == fs.ErrorObjectNotFound {
 		dstObj = nil
 	} else if err != nil {
 		return err
 	}
 
-	if NeedTransfer(dstObj, srcObj) {
+	if NeedTransfer(ctx, dstObj, srcObj) {
 		accounting.Stats.Transferring(srcFileName)
-		_, err = Op(fdst, dstObj, dstFileName, srcObj)
+		_, err = Op(ctx, fdst, dstObj, dstFileName, srcObj)
 		accounting.Stats.DoneTransferring(srcFileName, err == nil)
 	} else {
 		accounting.Stats.Checking(srcFileName)
 		if !cp {
-			err = DeleteFile(srcObj)
+			err = DeleteFile(ctx, srcObj)
 		}
 		defer accounting.Stats.DoneChecking(srcFileName)
 	}
@@ -1359,13 +1478,13 @@ func moveOrCopyFile(fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName stri
 }
 
 // MoveFile moves a single file possibly to a new name
-func MoveFile(fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string) (err error) {
-	return moveOrCopyFile(fdst, fsrc, dstFileName, srcFileName, false)
+func MoveFile(ctx context.Context, fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string) (err error) {
+	return moveOrCopyFile(ctx, fdst, fsrc, dstFileName, srcFileName, false)
 }
 
 // CopyFile moves a single file possibly to a new name
-func CopyFile(fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string) (err error) {
-	return moveOrCopyFile(fdst, fsrc, dstFileName, srcFileName, true)
+func CopyFile(ctx context.Context, fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string) (err error) {
+	return moveOrCopyFile(ctx, fdst, fsrc, dstFileName, srcFileName, true)
 }
 
 // ListFormat defines files information print format
@@ -21,6 +21,7 @@ package operations_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -55,41 +56,44 @@ func TestMain(m *testing.M) {
 }
 
 func TestMkdir(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
-	err := operations.Mkdir(r.Fremote, "")
+	err := operations.Mkdir(ctx, r.Fremote, "")
 	require.NoError(t, err)
 	fstest.CheckListing(t, r.Fremote, []fstest.Item{})
 
-	err = operations.Mkdir(r.Fremote, "")
+	err = operations.Mkdir(ctx, r.Fremote, "")
 	require.NoError(t, err)
 }
 
 func TestLsd(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	file1 := r.WriteObject("sub dir/hello world", "hello world", t1)
+	file1 := r.WriteObject(ctx, "sub dir/hello world", "hello world", t1)
 
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	var buf bytes.Buffer
-	err := operations.ListDir(r.Fremote, &buf)
+	err := operations.ListDir(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	assert.Contains(t, res, "sub dir\n")
 }
 
 func TestLs(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
-	file2 := r.WriteBoth("empty space", "", t2)
+	file1 := r.WriteBoth(ctx, "potato2", "------------------------------------------------------------", t1)
+	file2 := r.WriteBoth(ctx, "empty space", "", t2)
 
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
 	var buf bytes.Buffer
-	err := operations.List(r.Fremote, &buf)
+	err := operations.List(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	assert.Contains(t, res, "        0 empty space\n")
@@ -97,15 +101,16 @@ func TestLs(t *testing.T) {
 }
 
 func TestLsLong(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
-	file2 := r.WriteBoth("empty space", "", t2)
+	file1 := r.WriteBoth(ctx, "potato2", "------------------------------------------------------------", t1)
+	file2 := r.WriteBoth(ctx, "empty space", "", t2)
 
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
 	var buf bytes.Buffer
-	err := operations.ListLong(r.Fremote, &buf)
+	err := operations.ListLong(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	lines := strings.Split(strings.Trim(res, "\n"), "\n")
@@ -142,17 +147,18 @@ func TestLsLong(t *testing.T) {
 }
 
 func TestHashSums(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
-	file2 := r.WriteBoth("empty space", "", t2)
+	file1 := r.WriteBoth(ctx, "potato2", "------------------------------------------------------------", t1)
+	file2 := r.WriteBoth(ctx, "empty space", "", t2)
 
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
 	// MD5 Sum
 
 	var buf bytes.Buffer
-	err := operations.Md5sum(r.Fremote, &buf)
+	err := operations.Md5sum(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	if !strings.Contains(res, "d41d8cd98f00b204e9800998ecf8427e  empty space\n") &&
@@ -169,7 +175,7 @@ func TestHashSums(t *testing.T) {
 	// SHA1 Sum
 
 	buf.Reset()
-	err = operations.Sha1sum(r.Fremote, &buf)
+	err = operations.Sha1sum(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res = buf.String()
 	if !strings.Contains(res, "da39a3ee5e6b4b0d3255bfef95601890afd80709  empty space\n") &&
@@ -186,7 +192,7 @@ func TestHashSums(t *testing.T) {
 	// Dropbox Hash Sum
 
 	buf.Reset()
-	err = operations.DropboxHashSum(r.Fremote, &buf)
+	err = operations.DropboxHashSum(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res = buf.String()
 	if !strings.Contains(res, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  empty space\n") &&
@@ -202,11 +208,12 @@ func TestHashSums(t *testing.T) {
 }
 
 func TestCount(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
-	file2 := r.WriteBoth("empty space", "", t2)
-	file3 := r.WriteBoth("sub dir/potato3", "hello", t2)
+	file1 := r.WriteBoth(ctx, "potato2", "------------------------------------------------------------", t1)
+	file2 := r.WriteBoth(ctx, "empty space", "", t2)
+	file3 := r.WriteBoth(ctx, "sub dir/potato3", "hello", t2)
 
 	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
 
@@ -214,18 +221,19 @@ func TestCount(t *testing.T) {
 	fs.Config.MaxDepth = 1
 	defer func() { fs.Config.MaxDepth = -1 }()
 
-	objects, size, err := operations.Count(r.Fremote)
+	objects, size, err := operations.Count(ctx, r.Fremote)
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), objects)
 	assert.Equal(t, int64(60), size)
 }
 
 func TestDelete(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	file1 := r.WriteObject("small", "1234567890", t2)                                                                                           // 10 bytes
-	file2 := r.WriteObject("medium", "------------------------------------------------------------", t1)                                        // 60 bytes
-	file3 := r.WriteObject("large", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", t1) // 100 bytes
+	file1 := r.WriteObject(ctx, "small", "1234567890", t2)                                                                                           // 10 bytes
+	file2 := r.WriteObject(ctx, "medium", "------------------------------------------------------------", t1)                                        // 60 bytes
+	file3 := r.WriteObject(ctx, "large", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", t1) // 100 bytes
 	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
 
 	filter.Active.Opt.MaxSize = 60
@@ -233,19 +241,20 @@ func TestDelete(t *testing.T) {
 		filter.Active.Opt.MaxSize = -1
 	}()
 
-	err := operations.Delete(r.Fremote)
+	err := operations.Delete(ctx, r.Fremote)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Fremote, file3)
 }
 
-func testCheck(t *testing.T, checkFunction func(fdst, fsrc fs.Fs, oneway bool) error) {
+func testCheck(t *testing.T, checkFunction func(ctx context.Context, fdst, fsrc fs.Fs, oneway bool) error) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
 	check := func(i int, wantErrors int64, oneway bool) {
 		fs.Debugf(r.Fremote, "%d: Starting check test", i)
 		oldErrors := accounting.Stats.GetErrors()
-		err := checkFunction(r.Fremote, r.Flocal, oneway)
+		err := checkFunction(ctx, r.Fremote, r.Flocal, oneway)
 		gotErrors := accounting.Stats.GetErrors() - oldErrors
 		if wantErrors == 0 && err != nil {
 			t.Errorf("%d: Got error when not expecting one: %v", i, err)
@@ -259,7 +268,7 @@ func testCheck(t *testing.T, checkFunction func(fdst, fsrc fs.Fs, oneway bool) e
 		fs.Debugf(r.Fremote, "%d: Ending check test", i)
 	}
 
-	file1 := r.WriteBoth("rutabaga", "is tasty", t3)
+	file1 := r.WriteBoth(ctx, "rutabaga", "is tasty", t3)
 	fstest.CheckItems(t, r.Fremote, file1)
 	fstest.CheckItems(t, r.Flocal, file1)
 	check(1, 0, false)
@@ -268,15 +277,15 @@ func testCheck(t *testing.T, checkFunction func(fdst, fsrc fs.Fs, oneway bool) e
 	fstest.CheckItems(t, r.Flocal, file1, file2)
 	check(2, 1, false)
 
-	file3 := r.WriteObject("empty space", "", t2)
+	file3 := r.WriteObject(ctx, "empty space", "", t2)
 	fstest.CheckItems(t, r.Fremote, file1, file3)
 	check(3, 2, false)
 
 	file2r := file2
 	if fs.Config.SizeOnly {
-		file2r = r.WriteObject("potato2", "--Some-Differences-But-Size-Only-Is-Enabled-----------------", t1)
+		file2r = r.WriteObject(ctx, "potato2", "--Some-Differences-But-Size-Only-Is-Enabled-----------------", t1)
 	} else {
-		r.WriteObject("potato2", "------------------------------------------------------------", t1)
+		r.WriteObject(ctx, "potato2", "------------------------------------------------------------", t1)
 	}
 	fstest.CheckItems(t, r.Fremote, file1, file2r, file3)
 	check(4, 1, false)
@@ -285,7 +294,7 @@ func testCheck(t *testing.T, checkFunction func(fdst, fsrc fs.Fs, oneway bool) e
 	fstest.CheckItems(t, r.Flocal, file1, file2, file3)
 	check(5, 0, false)
 
-	file4 := r.WriteObject("remotepotato", "------------------------------------------------------------", t1)
+	file4 := r.WriteObject(ctx, "remotepotato", "------------------------------------------------------------", t1)
 	fstest.CheckItems(t, r.Fremote, file1, file2r, file3, file4)
 	check(6, 1, false)
 	check(7, 0, true)
@@ -306,10 +315,11 @@ func TestCheckSizeOnly(t *testing.T) {
 }
 
 func TestCat(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	file1 := r.WriteBoth("file1", "ABCDEFGHIJ", t1)
-	file2 := r.WriteBoth("file2", "012345678", t2)
+	file1 := r.WriteBoth(ctx, "file1", "ABCDEFGHIJ", t1)
+	file2 := r.WriteBoth(ctx, "file2", "012345678", t2)
 
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
@@ -325,7 +335,7 @@ func TestCat(t *testing.T) {
 		{1, 3, "BCD", "123"},
 	} {
 		var buf bytes.Buffer
-		err := operations.Cat(r.Fremote, &buf, test.offset, test.count)
+		err := operations.Cat(ctx, r.Fremote, &buf, test.offset, test.count)
 		require.NoError(t, err)
 		res := buf.String()
 
@@ -336,6 +346,7 @@ func TestCat(t *testing.T) {
 }
 
 func TestRcat(t *testing.T) {
+	ctx := context.Background()
 	checkSumBefore := fs.Config.CheckSum
 	defer func() { fs.Config.CheckSum = checkSumBefore }()
 
@@ -358,11 +369,11 @@ func TestRcat(t *testing.T) {
 		path2 := prefix + "big_file_from_pipe"
 
 		in := ioutil.NopCloser(strings.NewReader(data1))
-		_, err := operations.Rcat(r.Fremote, path1, in, t1)
+		_, err := operations.Rcat(ctx, r.Fremote, path1, in, t1)
 		require.NoError(t, err)
 
 		in = ioutil.NopCloser(strings.NewReader(data2))
-		_, err = operations.Rcat(r.Fremote, path2, in, t2)
+		_, err = operations.Rcat(ctx, r.Fremote, path2, in, t2)
 		require.NoError(t, err)
 
 		file1 := fstest.NewItem(path1, data1, t1)
@@ -375,23 +386,24 @@ func TestRcat(t *testing.T) {
 }
 
 func TestRmdirsNoLeaveRoot(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	r.Mkdir(r.Fremote)
+	r.Mkdir(ctx, r.Fremote)
 
 	// Make some files and dirs we expect to keep
-	r.ForceMkdir(r.Fremote)
-	file1 := r.WriteObject("A1/B1/C1/one", "aaa", t1)
+	r.ForceMkdir(ctx, r.Fremote)
+	file1 := r.WriteObject(ctx, "A1/B1/C1/one", "aaa", t1)
 	//..and dirs we expect to delete
-	require.NoError(t, operations.Mkdir(r.Fremote, "A2"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B2"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B2/C2"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B1/C3"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A3"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A3/B3"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A3/B3/C4"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A2"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B2"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B2/C2"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B1/C3"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A3"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A3/B3"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A3/B3/C4"))
 	//..and one more file at the end
-	file2 := r.WriteObject("A1/two", "bbb", t2)
+	file2 := r.WriteObject(ctx, "A1/two", "bbb", t2)
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -414,7 +426,7 @@ func TestRmdirsNoLeaveRoot(t *testing.T) {
 		fs.GetModifyWindow(r.Fremote),
 	)
 
-	require.NoError(t, operations.Rmdirs(r.Fremote, "", false))
+	require.NoError(t, operations.Rmdirs(ctx, r.Fremote, "", false))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -433,15 +445,16 @@ func TestRmdirsNoLeaveRoot(t *testing.T) {
 }
 
 func TestRmdirsLeaveRoot(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	r.Mkdir(r.Fremote)
+	r.Mkdir(ctx, r.Fremote)
 
-	r.ForceMkdir(r.Fremote)
+	r.ForceMkdir(ctx, r.Fremote)
 
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B1"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B1/C1"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B1"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B1/C1"))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -455,7 +468,7 @@ func TestRmdirsLeaveRoot(t *testing.T) {
 		fs.GetModifyWindow(r.Fremote),
 	)
 
-	require.NoError(t, operations.Rmdirs(r.Fremote, "A1", true))
+	require.NoError(t, operations.Rmdirs(ctx, r.Fremote, "A1", true))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -469,6 +482,7 @@ func TestRmdirsLeaveRoot(t *testing.T) {
 }
 
 func TestMoveFile(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -478,7 +492,7 @@ func TestMoveFile(t *testing.T) {
 	file2 := file1
 	file2.Path = "sub/file2"
 
-	err := operations.MoveFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err := operations.MoveFile(ctx, r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file2)
@@ -486,18 +500,65 @@ func TestMoveFile(t *testing.T) {
 	r.WriteFile("file1", "file1 contents", t1)
 	fstest.CheckItems(t, r.Flocal, file1)
 
-	err = operations.MoveFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err = operations.MoveFile(ctx, r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file2)
 
-	err = operations.MoveFile(r.Fremote, r.Fremote, file2.Path, file2.Path)
+	err = operations.MoveFile(ctx, r.Fremote, r.Fremote, file2.Path, file2.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file2)
 }
 
+// removedObject fails Update once it has been removed like an object
+// in the trash would
+type removedObject struct {
+	fs.Object
+	removed bool
+}
+
+func (o *removedObject) Remove(ctx context.Context) error {
+	o.removed = true
+	return o.Object.Remove(ctx)
+}
+
+func (o *removedObject) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	if o.removed {
+		return errors.New("update of removed object")
+	}
+	return o.Object.Update(ctx, in, src, options...)
+}
+
+func TestMoveFallbackToCopyAfterDelete(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	// Make server side moves fail so Move falls back to copy
+	features := r.Fremote.Features()
+	oldFeatures := *features
+	defer func() { *features = oldFeatures }()
+	features.Move = func(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+		return nil, fs.ErrorCantMove
+	}
+
+	file1 := r.WriteObject(ctx, "file1", "file1 contents", t1)
+	file2 := r.WriteObject(ctx, "file2", "file2 contents", t2)
+	src, err := r.Fremote.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+	dst, err := r.Fremote.NewObject(ctx, file2.Path)
+	require.NoError(t, err)
+
+	newDst, err := operations.Move(ctx, r.Fremote, &removedObject{Object: dst}, file2.Path, src)
+	require.NoError(t, err)
+	assert.Equal(t, file2.Path, newDst.Remote())
+	file1.Path = file2.Path
+	fstest.CheckItems(t, r.Fremote, file1)
+}
+
 func TestCopyFile(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -507,22 +568,57 @@ func TestCopyFile(t *testing.T) {
 	file2 := file1
 	file2.Path = "sub/file2"
 
-	err := operations.CopyFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err := operations.CopyFile(ctx, r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file2)
 
-	err = operations.CopyFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err = operations.CopyFile(ctx, r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file2)
 
-	err = operations.CopyFile(r.Fremote, r.Fremote, file2.Path, file2.Path)
+	err = operations.CopyFile(ctx, r.Fremote, r.Fremote, file2.Path, file2.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file2)
 }
 
+// Test copyto with --max-transfer and --cutoff-mode soft finishes the
+// transfer which crosses the limit but doesn't start any more
+func TestCopyFileMaxTransferSoft(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	oldMaxTransfer, oldCutoffMode := fs.Config.MaxTransfer, fs.Config.CutoffMode
+	defer func() {
+		fs.Config.MaxTransfer, fs.Config.CutoffMode = oldMaxTransfer, oldCutoffMode
+		accounting.Stats.ResetCounters()
+	}()
+	fs.Config.MaxTransfer = 10
+	fs.Config.CutoffMode = fs.CutoffModeSoft
+	accounting.Stats.ResetCounters()
+
+	file1 := r.WriteFile("file1", "file1 contents", t1)
+	file2 := r.WriteFile("file2", "file2 contents", t1)
+	fstest.CheckItems(t, r.Flocal, file1, file2)
+
+	err := operations.CopyFile(ctx, r.Fremote, r.Flocal, file1.Path, file1.Path)
+	require.NoError(t, err)
+	err = operations.CopyFile(ctx, r.Fremote, r.Flocal, file2.Path, file2.Path)
+	assert.Equal(t, accounting.ErrorMaxTransferLimitReachedGraceful, err)
+	_, err = operations.Rcat(ctx, r.Fremote, "file3", ioutil.NopCloser(strings.NewReader("file3 contents")), t1)
+	assert.Equal(t, accounting.ErrorMaxTransferLimitReachedGraceful, err)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	// cautious mode won't start a transfer which would exceed the limit
+	fs.Config.CutoffMode = fs.CutoffModeCautious
+	accounting.Stats.ResetCounters()
+	err = operations.CopyFile(ctx, r.Fremote, r.Flocal, file2.Path, file2.Path)
+	assert.Equal(t, accounting.ErrorMaxTransferLimitReachedGraceful, err)
+	fstest.CheckItems(t, r.Fremote, file1)
+}
+
 // testFsInfo is for unit testing fs.Info
 type testFsInfo struct {
 	name      string
@@ -571,6 +667,34 @@ func TestSameConfig(t *testing.T) {
 	}
 }
 
+func TestSameRemoteType(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	a := &testFsInfo{name: "name", root: "root"}
+	b := &testFsInfo{name: "namey", root: "rooty"}
+	assert.True(t, operations.SameRemoteType(a, b))
+	assert.True(t, operations.SameRemoteType(r.Flocal, r.Flocal))
+	assert.False(t, operations.SameRemoteType(a, r.Flocal))
+	assert.False(t, operations.SameRemoteType(r.Flocal, a))
+}
+
+func TestServerSideAcrossConfigs(t *testing.T) {
+	a := &testFsInfo{name: "name", root: "root"}
+	b := &testFsInfo{name: "namey", root: "rooty"}
+	defer func() {
+		fs.Config.ServerSideAcrossConfigs = false
+	}()
+
+	// Needs the flag and the feature
+	fs.Config.ServerSideAcrossConfigs = false
+	a.features.ServerSideAcrossConfigs = true
+	assert.False(t, operations.ServerSideAcrossConfigs(a, b))
+	fs.Config.ServerSideAcrossConfigs = true
+	assert.True(t, operations.ServerSideAcrossConfigs(a, b))
+	a.features.ServerSideAcrossConfigs = false
+	assert.False(t, operations.ServerSideAcrossConfigs(a, b))
+}
+
 func TestSame(t *testing.T) {
 	a := &testFsInfo{name: "name", root: "root"}
 	for _, test := range []struct {
@@ -688,14 +812,15 @@ func TestCheckEqualReaders(t *testing.T) {
 }
 
 func TestListFormat(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	file1 := r.WriteObject("a", "a", t1)
-	file2 := r.WriteObject("subdir/b", "b", t1)
+	file1 := r.WriteObject(ctx, "a", "a", t1)
+	file2 := r.WriteObject(ctx, "subdir/b", "b", t1)
 
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
-	items, _ := list.DirSorted(r.Fremote, true, "")
+	items, _ := list.DirSorted(ctx, r.Fremote, true, "")
 	var list operations.ListFormat
 	list.AddPath()
 	list.SetDirSlash(false)
@@ -0,0 +1,352 @@
+package operations_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/operations"
+	"github.com/artpar/rclone/fs/rc"
+	"github.com/artpar/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rcNewRun(t *testing.T, method string) (*fstest.Run, *rc.Call) {
+	if *fstest.RemoteName != "" {
+		t.Skip("Skipping test on non local remote")
+	}
+	r := fstest.NewRun(t)
+	call := rc.GetCall(method)
+	assert.NotNil(t, call)
+	return r, call
+}
+
+// operations/about: Return the space used on the remote
+func TestRcAbout(t *testing.T) {
+	r, call := rcNewRun(t, "operations/about")
+	defer r.Finalise()
+	r.Mkdir(context.Background(), r.Fremote)
+
+	// Will only work on local remotes
+	in := rc.Params{
+		"fs": r.FremoteName,
+	}
+	out, err := call.Fn(context.Background(), in)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	assert.NotEqual(t, nil, out["total"])
+	assert.NotEqual(t, nil, out["used"])
+}
+
+// operations/copyfile: Copy a file from source remote to destination remote
+func TestRcCopyfile(t *testing.T) {
+	r, call := rcNewRun(t, "operations/copyfile")
+	defer r.Finalise()
+	file1 := r.WriteFile("file1", "file1 contents", t1)
+	r.Mkdir(context.Background(), r.Fremote)
+	fstest.CheckItems(t, r.Flocal, file1)
+	fstest.CheckItems(t, r.Fremote)
+
+	in := rc.Params{
+		"srcFs":     r.LocalName,
+		"srcRemote": "file1",
+		"dstFs":     r.FremoteName,
+		"dstRemote": "file1-renamed",
+	}
+	out, err := call.Fn(context.Background(), in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params(nil), out)
+
+	fstest.CheckItems(t, r.Flocal, file1)
+	file1.Path = "file1-renamed"
+	fstest.CheckItems(t, r.Fremote, file1)
+}
+
+// operations/movefile: Move a file from source remote to destination remote
+func TestRcMovefile(t *testing.T) {
+	r, call := rcNewRun(t, "operations/movefile")
+	defer r.Finalise()
+	file1 := r.WriteFile("file1", "file1 contents", t1)
+	r.Mkdir(context.Background(), r.Fremote)
+	fstest.CheckItems(t, r.Flocal, file1)
+	fstest.CheckItems(t, r.Fremote)
+
+	in := rc.Params{
+		"srcFs":     r.LocalName,
+		"srcRemote": "file1",
+		"dstFs":     r.FremoteName,
+		"dstRemote": "file1-renamed",
+	}
+	out, err := call.Fn(context.Background(), in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params(nil), out)
+
+	fstest.CheckItems(t, r.Flocal)
+	file1.Path = "file1-renamed"
+	fstest.CheckItems(t, r.Fremote, file1)
+}
+
+// operations/delete: Remove files in the path
+func TestRcDelete(t *testing.T) {
+	ctx := context.Background()
+	r, call := rcNewRun(t, "operations/delete")
+	defer r.Finalise()
+
+	file1 := r.WriteObject(ctx, "small", "1234567890", t2)                                                                                                      // 10 bytes
+	file2 := r.WriteObject(ctx, "medium", "------------------------------------------------------------", t1)                                                   // 60 bytes
+	file3 := r.WriteObject(ctx, "large", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", t1) // 100 bytes
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+
+	in := rc.Params{
+		"fs": r.FremoteName,
+	}
+	out, err := call.Fn(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params(nil), out)
+
+	fstest.CheckItems(t, r.Fremote)
+}
+
+// operations/deletefile: Remove the single file pointed to
+func TestRcDeletefile(t *testing.T) {
+	ctx := context.Background()
+	r, call := rcNewRun(t, "operations/deletefile")
+	defer r.Finalise()
+
+	file1 := r.WriteObject(ctx, "small", "1234567890", t2)                                                    // 10 bytes
+	file2 := r.WriteObject(ctx, "medium", "------------------------------------------------------------", t1) // 60 bytes
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+
+	in := rc.Params{
+		"fs":     r.FremoteName,
+		"remote": "small",
+	}
+	out, err := call.Fn(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params(nil), out)
+
+	fstest.CheckItems(t, r.Fremote, file2)
+}
+
+// operations/list: List the given remote and path in JSON format
+func TestRcList(t *testing.T) {
+	ctx := context.Background()
+	r, call := rcNewRun(t, "operations/list")
+	defer r.Finalise()
+
+	file1 := r.WriteObject(ctx, "a", "a", t1)
+	file2 := r.WriteObject(ctx, "subdir/b", "bb", t2)
+
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+
+	in := rc.Params{
+		"fs":     r.FremoteName,
+		"remote": "",
+	}
+	out, err := call.Fn(ctx, in)
+	require.NoError(t, err)
+
+	list := out["list"].([]*operations.ListJSONItem)
+	assert.Equal(t, 2, len(list))
+
+	checkFile1 := func(got *operations.ListJSONItem) {
+		assert.WithinDuration(t, t1, time.Time(got.ModTime), fs.GetModifyWindow(r.Fremote))
+		assert.Equal(t, "a", got.Path)
+		assert.Equal(t, "a", got.Name)
+		assert.Equal(t, int64(1), got.Size)
+		assert.Equal(t, "application/octet-stream", got.MimeType)
+		assert.Equal(t, false, got.IsDir)
+	}
+	checkFile1(list[0])
+
+	checkSubdir := func(got *operations.ListJSONItem) {
+		assert.Equal(t, "subdir", got.Path)
+		assert.Equal(t, "subdir", got.Name)
+		assert.Equal(t, int64(-1), got.Size)
+		assert.Equal(t, "inode/directory", got.MimeType)
+		assert.Equal(t, true, got.IsDir)
+	}
+	checkSubdir(list[1])
+
+	in = rc.Params{
+		"fs":     r.FremoteName,
+		"remote": "",
+		"opt": rc.Params{
+			"recurse": true,
+		},
+	}
+	out, err = call.Fn(ctx, in)
+	require.NoError(t, err)
+
+	list = out["list"].([]*operations.ListJSONItem)
+	assert.Equal(t, 3, len(list))
+	checkFile1(list[0])
+	checkSubdir(list[1])
+
+	checkFile2 := func(got *operations.ListJSONItem) {
+		assert.WithinDuration(t, t2, time.Time(got.ModTime), fs.GetModifyWindow(r.Fremote))
+		assert.Equal(t, "subdir/b", got.Path)
+		assert.Equal(t, "b", got.Name)
+		assert.Equal(t, int64(2), got.Size)
+		assert.Equal(t, "application/octet-stream", got.MimeType)
+		assert.Equal(t, false, got.IsDir)
+	}
+	checkFile2(list[2])
+}
+
+// operations/mkdir: Make a destination directory or container
+func TestRcMkdir(t *testing.T) {
+	ctx := context.Background()
+	r, call := rcNewRun(t, "operations/mkdir")
+	defer r.Finalise()
+	r.Mkdir(ctx, r.Fremote)
+
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{}, fs.GetModifyWindow(r.Fremote))
+
+	in := rc.Params{
+		"fs":     r.FremoteName,
+		"remote": "subdir",
+	}
+	out, err := call.Fn(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params(nil), out)
+
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{"subdir"}, fs.GetModifyWindow(r.Fremote))
+}
+
+// operations/publiclink: Create or retrieve a public link to the given file or folder.
+func TestRcPublicLink(t *testing.T) {
+	r, call := rcNewRun(t, "operations/publiclink")
+	defer r.Finalise()
+	in := rc.Params{
+		"fs":     r.FremoteName,
+		"remote": "",
+	}
+	_, err := call.Fn(context.Background(), in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "doesn't support public links")
+}
+
+// operations/purge: Remove a directory or container and all of its contents
+func TestRcPurge(t *testing.T) {
+	ctx := context.Background()
+	r, call := rcNewRun(t, "operations/purge")
+	defer r.Finalise()
+	file1 := r.WriteObject(ctx, "subdir/file1", "subdir/file1 contents", t1)
+
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{file1}, []string{"subdir"}, fs.GetModifyWindow(r.Fremote))
+
+	in := rc.Params{
+		"fs":     r.FremoteName,
+		"remote": "subdir",
+	}
+	out, err := call.Fn(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params(nil), out)
+
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{}, fs.GetModifyWindow(r.Fremote))
+}
+
+// operations/rmdir: Remove an empty directory or container
+func TestRcRmdir(t *testing.T) {
+	ctx := context.Background()
+	r, call := rcNewRun(t, "operations/rmdir")
+	defer r.Finalise()
+	r.Mkdir(ctx, r.Fremote)
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir"))
+
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{"subdir"}, fs.GetModifyWindow(r.Fremote))
+
+	in := rc.Params{
+		"fs":     r.FremoteName,
+		"remote": "subdir",
+	}
+	out, err := call.Fn(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params(nil), out)
+
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{}, fs.GetModifyWindow(r.Fremote))
+}
+
+// operations/rmdirs: Remove all the empty directories in the path
+func TestRcRmdirs(t *testing.T) {
+	ctx := context.Background()
+	r, call := rcNewRun(t, "operations/rmdirs")
+	defer r.Finalise()
+	r.Mkdir(ctx, r.Fremote)
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir"))
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir/subsubdir"))
+
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{"subdir", "subdir/subsubdir"}, fs.GetModifyWindow(r.Fremote))
+
+	in := rc.Params{
+		"fs":     r.FremoteName,
+		"remote": "subdir",
+	}
+	out, err := call.Fn(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params(nil), out)
+
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{}, fs.GetModifyWindow(r.Fremote))
+
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir"))
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir/subsubdir"))
+
+	in = rc.Params{
+		"fs":        r.FremoteName,
+		"remote":    "subdir",
+		"leaveRoot": true,
+	}
+	out, err = call.Fn(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params(nil), out)
+
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{"subdir"}, fs.GetModifyWindow(r.Fremote))
+}
+
+// backend/command: Runs a backend command
+func TestRcBackendCommand(t *testing.T) {
+	r, call := rcNewRun(t, "backend/command")
+	defer r.Finalise()
+
+	in := rc.Params{
+		"command": "noop",
+		"fs":      r.FremoteName,
+		"arg": []string{
+			"path1",
+			"path2",
+		},
+		"opt": map[string]interface{}{
+			"echo": "true",
+			"blue": "",
+		},
+	}
+	out, err := call.Fn(context.Background(), in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params{
+		"result": map[string]interface{}{
+			"arg": []string{
+				"path1",
+				"path2",
+			},
+			"name": "noop",
+			"opt": map[string]string{
+				"blue": "",
+				"echo": "true",
+			},
+		},
+	}, out)
+
+	in["opt"] = map[string]interface{}{"error": "potato"}
+	_, err = call.Fn(context.Background(), in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "potato")
+
+	in["command"] = "notfound"
+	delete(in, "opt")
+	_, err = call.Fn(context.Background(), in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fs.ErrorCommandNotFound.Error())
+}
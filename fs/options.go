@@ -219,6 +219,25 @@ func (o *HashesOption) Mandatory() bool {
 	return false
 }
 
+// MetadataOption defines an Option used to pass the metadata set with
+// --metadata-set to the Put and Update calls of a backend.
+type MetadataOption Metadata
+
+// Header formats the option as an http header
+func (o MetadataOption) Header() (key string, value string) {
+	return "", ""
+}
+
+// String formats the option into human readable form
+func (o MetadataOption) String() string {
+	return fmt.Sprintf("MetadataOption(%v)", map[string]string(o))
+}
+
+// Mandatory returns whether the option must be parsed or can be ignored
+func (o MetadataOption) Mandatory() bool {
+	return false
+}
+
 // OpenOptionAddHeaders adds each header found in options to the
 // headers map provided the key was non empty.
 func OpenOptionAddHeaders(options []OpenOption, headers map[string]string) {
@@ -259,4 +278,5 @@ var (
 	_ OpenOption = (*RangeOption)(nil)
 	_ OpenOption = (*SeekOption)(nil)
 	_ OpenOption = (*HTTPOption)(nil)
+	_ OpenOption = MetadataOption(nil)
 )
@@ -0,0 +1,38 @@
+// Utilities for making the fs.Fs the rc calls operate on
+
+package rc
+
+import (
+	"github.com/artpar/rclone/fs"
+)
+
+// GetFsNamed gets an fs.Fs named fsName from the input, creating it
+// with fs.NewFs
+func GetFsNamed(in Params, fsName string) (f fs.Fs, err error) {
+	fsString, err := in.GetString(fsName)
+	if err != nil {
+		return nil, err
+	}
+	return fs.NewFs(fsString)
+}
+
+// GetFs gets an fs.Fs named "fs" from the input
+func GetFs(in Params) (f fs.Fs, err error) {
+	return GetFsNamed(in, "fs")
+}
+
+// GetFsAndRemoteNamed gets the fsName parameter from in and makes
+// an fs.Fs from it, then gets the remoteName parameter from in too.
+func GetFsAndRemoteNamed(in Params, fsName, remoteName string) (f fs.Fs, remote string, err error) {
+	remote, err = in.GetString(remoteName)
+	if err != nil {
+		return
+	}
+	f, err = GetFsNamed(in, fsName)
+	return
+}
+
+// GetFsAndRemote gets the "fs" and "remote" parameters from in
+func GetFsAndRemote(in Params) (f fs.Fs, remote string, err error) {
+	return GetFsAndRemoteNamed(in, "fs", "remote")
+}
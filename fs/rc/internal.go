@@ -3,6 +3,7 @@
 package rc
 
 import (
+	"context"
 	"os"
 	"runtime"
 
@@ -72,31 +73,31 @@ memory problems.
 }
 
 // Echo the input to the ouput parameters
-func rcNoop(in Params) (out Params, err error) {
+func rcNoop(ctx context.Context, in Params) (out Params, err error) {
 	return in, nil
 }
 
 // Return an error regardless
-func rcError(in Params) (out Params, err error) {
+func rcError(ctx context.Context, in Params) (out Params, err error) {
 	return nil, errors.Errorf("arbitrary error on input %+v", in)
 }
 
 // List the registered commands
-func rcList(in Params) (out Params, err error) {
+func rcList(ctx context.Context, in Params) (out Params, err error) {
 	out = make(Params)
 	out["commands"] = registry.list()
 	return out, nil
 }
 
 // Return PID of current process
-func rcPid(in Params) (out Params, err error) {
+func rcPid(ctx context.Context, in Params) (out Params, err error) {
 	out = make(Params)
 	out["pid"] = os.Getpid()
 	return out, nil
 }
 
 // Return the memory statistics
-func rcMemStats(in Params) (out Params, err error) {
+func rcMemStats(ctx context.Context, in Params) (out Params, err error) {
 	out = make(Params)
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
@@ -124,7 +125,7 @@ func rcMemStats(in Params) (out Params, err error) {
 }
 
 // Do a garbage collection run
-func rcGc(in Params) (out Params, err error) {
+func rcGc(ctx context.Context, in Params) (out Params, err error) {
 	out = make(Params)
 	runtime.GC()
 	return out, nil
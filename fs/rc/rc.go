@@ -5,6 +5,10 @@
 // error.  Use rc.NewError to wrap an existing error along with an
 // http response type if another response other than 500 internal
 // error is required on error.
+//
+// Any call may be run in the background by passing _async=true in
+// its parameters.  This returns a job id which can be queried with
+// job/status and cancelled with job/stop.
 package rc
 
 import (
@@ -12,6 +16,7 @@ import (
 	"io"
 	"net/http"
 	_ "net/http/pprof" // install the pprof http handlers
+	"time"
 
 	"strings"
 
@@ -22,14 +27,20 @@ import (
 
 // Options contains options for the remote control server
 type Options struct {
-	HTTPOptions httplib.Options
-	Enabled     bool
+	HTTPOptions       httplib.Options
+	Enabled           bool   // set to enable the server
+	NoAuth            bool   // set to disable auth checks on AuthRequired methods
+	Files             string // set to enable serving files locally
+	JobExpireDuration time.Duration
+	JobExpireInterval time.Duration
 }
 
 // DefaultOpt is the default values used for Options
 var DefaultOpt = Options{
-	HTTPOptions: httplib.DefaultOpt,
-	Enabled:     false,
+	HTTPOptions:       httplib.DefaultOpt,
+	Enabled:           false,
+	JobExpireDuration: 60 * time.Second,
+	JobExpireInterval: 10 * time.Second,
 }
 
 func init() {
@@ -37,38 +48,65 @@ func init() {
 }
 
 // Start the remote control server if configured
-func Start(opt *Options) {
+//
+// If the server wasn't configured the *Server returned may be nil
+func Start(opt *Options) (*Server, error) {
+	running.SetOpt(opt)
 	if opt.Enabled {
-		s := newServer(opt)
-		go s.serve()
+		// Serve on the DefaultServeMux so can have global registrations appear
+		s := newServer(opt, http.DefaultServeMux)
+		return s, s.Serve()
 	}
+	return nil, nil
 }
 
-// server contains everything to run the server
-type server struct {
-	srv *httplib.Server
+// Server contains everything to run the server
+type Server struct {
+	srv   *httplib.Server
+	opt   *Options
+	files http.Handler
 }
 
-func newServer(opt *Options) *server {
-	// Serve on the DefaultServeMux so can have global registrations appear
-	mux := http.DefaultServeMux
-	s := &server{
+func newServer(opt *Options, mux *http.ServeMux) *Server {
+	s := &Server{
 		srv: httplib.NewServer(mux, &opt.HTTPOptions),
+		opt: opt,
+	}
+	if opt.Files != "" {
+		fs.Logf(nil, "Serving files from %q", opt.Files)
+		s.files = http.FileServer(http.Dir(opt.Files))
 	}
 	mux.HandleFunc("/", s.handler)
 	return s
 }
 
-// serve runs the http server - doesn't return
-func (s *server) serve() {
+// Serve runs the http server in the background.
+//
+// Use s.Close() and s.Wait() to shutdown server
+func (s *Server) Serve() error {
 	err := s.srv.Serve()
 	if err != nil {
-		fs.Errorf(nil, "Opening listener: %v", err)
+		return errors.Wrap(err, "failed to start remote control server")
 	}
 	fs.Logf(nil, "Serving remote control on %s", s.srv.URL())
+	return nil
+}
+
+// Wait blocks while the server is serving requests
+func (s *Server) Wait() {
 	s.srv.Wait()
 }
 
+// Close shuts the running server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// URL returns the URL the server is serving on
+func (s *Server) URL() string {
+	return s.srv.URL()
+}
+
 // WriteJSON writes JSON in out to w
 func WriteJSON(w io.Writer, out Params) error {
 	enc := json.NewEncoder(w)
@@ -76,40 +114,63 @@ func WriteJSON(w io.Writer, out Params) error {
 	return enc.Encode(out)
 }
 
+// writeError writes a formatted error to the output
+func writeError(path string, in Params, w http.ResponseWriter, err error, status int) {
+	fs.Errorf(nil, "rc: %q: error: %v", path, err)
+	w.WriteHeader(status)
+	err = WriteJSON(w, Params{
+		"error": err.Error(),
+		"input": in,
+	})
+	if err != nil {
+		// can't return the error at this point
+		fs.Errorf(nil, "rc: failed to write JSON output: %v", err)
+	}
+}
+
 // handler reads incoming requests and dispatches them
-func (s *server) handler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handler(w http.ResponseWriter, r *http.Request) {
 	path := strings.Trim(r.URL.Path, "/")
-	in := make(Params)
-
-	writeError := func(err error, status int) {
-		fs.Errorf(nil, "rc: %q: error: %v", path, err)
-		w.WriteHeader(status)
-		err = WriteJSON(w, Params{
-			"error": err.Error(),
-			"input": in,
-		})
-		if err != nil {
-			// can't return the error at this point
-			fs.Errorf(nil, "rc: failed to write JSON output: %v", err)
-		}
+	switch r.Method {
+	case "POST":
+		s.handlePost(w, r, path)
+	case "GET", "HEAD":
+		s.handleGet(w, r, path)
+	default:
+		writeError(path, nil, w, errors.Errorf("method %q not allowed", r.Method), http.StatusMethodNotAllowed)
 	}
+}
 
-	if r.Method != "POST" {
-		writeError(errors.Errorf("method %q not allowed - POST required", r.Method), http.StatusMethodNotAllowed)
+// handleGet serves the static files if configured
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request, path string) {
+	if s.files == nil {
+		writeError(path, nil, w, errors.Errorf("method %q not allowed - POST required", r.Method), http.StatusMethodNotAllowed)
 		return
 	}
+	s.files.ServeHTTP(w, r)
+}
+
+// handlePost reads the parameters of a POST and runs the call
+func (s *Server) handlePost(w http.ResponseWriter, r *http.Request, path string) {
+	in := make(Params)
 
 	// Find the call
 	call := registry.get(path)
 	if call == nil {
-		writeError(errors.Errorf("couldn't find method %q", path), http.StatusMethodNotAllowed)
+		writeError(path, in, w, errors.Errorf("couldn't find method %q", path), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Check to see if it is authorised
+	if call.AuthRequired && !s.opt.NoAuth && !s.srv.UsingAuth() {
+		writeError(path, in, w, errors.Errorf("authentication must be set up on the rc server to use %q or the --rc-no-auth flag must be in use", path), http.StatusForbidden)
 		return
 	}
 
 	// Parse the POST and URL parameters into r.Form
 	err := r.ParseForm()
 	if err != nil {
-		writeError(errors.Wrap(err, "failed to parse form/URL parameters"), http.StatusBadRequest)
+		writeError(path, in, w, errors.Wrap(err, "failed to parse form/URL parameters"), http.StatusBadRequest)
 		return
 	}
 
@@ -125,17 +186,38 @@ func (s *server) handler(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("Content-Type") == "application/json" {
 		err := json.NewDecoder(r.Body).Decode(&in)
 		if err != nil {
-			writeError(errors.Wrap(err, "failed to read input JSON"), http.StatusBadRequest)
+			writeError(path, in, w, errors.Wrap(err, "failed to read input JSON"), http.StatusBadRequest)
 			return
 		}
 	}
 
 	fs.Debugf(nil, "rc: %q: with parameters %+v", path, in)
-	out, err := call.Fn(in)
+
+	// Check to see if this is an asynchronous call
+	isAsync, err := in.GetBool("_async")
+	if NotErrParamNotFound(err) {
+		writeError(path, in, w, err, http.StatusBadRequest)
+		return
+	}
+	delete(in, "_async")
+
+	var out Params
+	if isAsync {
+		out, err = StartJob(call.Fn, in)
+	} else {
+		out, err = call.Fn(r.Context(), in)
+	}
 	if err != nil {
-		writeError(errors.Wrap(err, "remote control command failed"), http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if IsErrParamNotFound(err) || IsErrParamInvalid(err) {
+			status = http.StatusBadRequest
+		}
+		writeError(path, in, w, errors.Wrap(err, "remote control command failed"), status)
 		return
 	}
+	if out == nil {
+		out = make(Params)
+	}
 
 	fs.Debugf(nil, "rc: %q: reply %+v: %v", path, out, err)
 	err = WriteJSON(w, out)
@@ -0,0 +1,226 @@
+package rc
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFs = "testdata/files"
+
+func init() {
+	Add(Call{
+		Path: "rc/test/noauth",
+		Fn: func(ctx context.Context, in Params) (Params, error) {
+			return in, nil
+		},
+		Title: "Test call without auth",
+	})
+	Add(Call{
+		Path:         "rc/test/auth",
+		AuthRequired: true,
+		Fn: func(ctx context.Context, in Params) (Params, error) {
+			return in, nil
+		},
+		Title: "Test call with auth",
+	})
+}
+
+type testRun struct {
+	Name        string
+	URL         string
+	Status      int
+	Method      string
+	Body        string
+	ContentType string
+	Expected    string
+	Contains    string
+}
+
+// Run a suite of tests
+func testServer(t *testing.T, tests []testRun, opt *Options) {
+	s := newServer(opt, http.NewServeMux())
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			method := test.Method
+			if method == "" {
+				method = "GET"
+			}
+			var inBody *strings.Reader
+			if test.Body != "" {
+				inBody = strings.NewReader(test.Body)
+			} else {
+				inBody = strings.NewReader("")
+			}
+			req, err := http.NewRequest(method, "http://1.2.3.4/"+test.URL, inBody)
+			require.NoError(t, err)
+			if test.ContentType != "" {
+				req.Header.Set("Content-Type", test.ContentType)
+			}
+
+			w := httptest.NewRecorder()
+			s.handler(w, req)
+			resp := w.Result()
+
+			assert.Equal(t, test.Status, resp.StatusCode)
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			if test.Contains == "" {
+				assert.Equal(t, test.Expected, string(body))
+			} else {
+				assert.True(t, bytes.Contains(body, []byte(test.Contains)), string(body))
+			}
+		})
+	}
+}
+
+func TestRC(t *testing.T) {
+	tests := []testRun{{
+		Name:     "rc-root",
+		URL:      "",
+		Method:   "POST",
+		Status:   http.StatusMethodNotAllowed,
+		Contains: `couldn't find method \"\"`,
+	}, {
+		Name:   "rc-noop",
+		URL:    "rc/noop",
+		Method: "POST",
+		Status: http.StatusOK,
+		Expected: `{}
+`,
+	}, {
+		Name:   "rc-error",
+		URL:    "rc/error",
+		Method: "POST",
+		Status: http.StatusInternalServerError,
+		Expected: `{
+	"error": "remote control command failed: arbitrary error on input map[]",
+	"input": {}
+}
+`,
+	}, {
+		Name:        "rc-noop-json",
+		URL:         "rc/noop",
+		Method:      "POST",
+		Body:        `{ "param1":"string", "param2":true }`,
+		ContentType: "application/json",
+		Status:      http.StatusOK,
+		Expected: `{
+	"param1": "string",
+	"param2": true
+}
+`,
+	}, {
+		Name:        "rc-async-invalid",
+		URL:         "rc/noop",
+		Method:      "POST",
+		Body:        `_async=potato`,
+		ContentType: "application/x-www-form-urlencoded",
+		Status:      http.StatusBadRequest,
+		Contains:    `couldn't parse key \"_async\"`,
+	}, {
+		Name:     "get-no-files",
+		URL:      "file.txt",
+		Method:   "GET",
+		Status:   http.StatusMethodNotAllowed,
+		Contains: `method \"GET\" not allowed - POST required`,
+	}, {
+		Name:     "put",
+		URL:      "rc/noop",
+		Method:   "PUT",
+		Status:   http.StatusMethodNotAllowed,
+		Contains: `method \"PUT\" not allowed`,
+	}}
+	opt := DefaultOpt
+	testServer(t, tests, &opt)
+}
+
+func TestRCFiles(t *testing.T) {
+	tests := []testRun{{
+		Name:   "get-file",
+		URL:    "file.txt",
+		Method: "GET",
+		Status: http.StatusOK,
+		Expected: `this is file1.txt
+`,
+	}, {
+		Name:     "get-root",
+		URL:      "",
+		Method:   "GET",
+		Status:   http.StatusOK,
+		Contains: `<a href="file.txt">file.txt</a>`,
+	}, {
+		Name:   "get-missing",
+		URL:    "notfound",
+		Method: "GET",
+		Status: http.StatusNotFound,
+		Expected: `404 page not found
+`,
+	}, {
+		Name:   "post-still-works",
+		URL:    "rc/noop",
+		Method: "POST",
+		Status: http.StatusOK,
+		Expected: `{}
+`,
+	}}
+	opt := DefaultOpt
+	opt.Files = testFs
+	testServer(t, tests, &opt)
+}
+
+func TestRCAuthRequired(t *testing.T) {
+	tests := []testRun{{
+		Name:   "noauth",
+		URL:    "rc/test/noauth",
+		Method: "POST",
+		Status: http.StatusOK,
+		Expected: `{}
+`,
+	}, {
+		Name:     "auth",
+		URL:      "rc/test/auth",
+		Method:   "POST",
+		Status:   http.StatusForbidden,
+		Contains: `authentication must be set up on the rc server to use \"rc/test/auth\" or the --rc-no-auth flag must be in use`,
+	}}
+	opt := DefaultOpt
+	testServer(t, tests, &opt)
+}
+
+func TestRCNoAuth(t *testing.T) {
+	tests := []testRun{{
+		Name:   "auth",
+		URL:    "rc/test/auth",
+		Method: "POST",
+		Status: http.StatusOK,
+		Expected: `{}
+`,
+	}}
+	opt := DefaultOpt
+	opt.NoAuth = true
+	testServer(t, tests, &opt)
+}
+
+func TestRCWithAuth(t *testing.T) {
+	tests := []testRun{{
+		Name:   "auth",
+		URL:    "rc/test/auth",
+		Method: "POST",
+		Status: http.StatusOK,
+		Expected: `{}
+`,
+	}}
+	opt := DefaultOpt
+	opt.HTTPOptions.BasicUser = "user"
+	opt.HTTPOptions.BasicPass = "pass"
+	testServer(t, tests, &opt)
+}
@@ -3,6 +3,7 @@
 package rc
 
 import (
+	"context"
 	"sort"
 	"strings"
 	"sync"
@@ -14,15 +15,16 @@ import (
 type Params map[string]interface{}
 
 // Func defines a type for a remote control function
-type Func func(in Params) (out Params, err error)
+type Func func(ctx context.Context, in Params) (out Params, err error)
 
 // Call defines info about a remote control function and is used in
 // the Add function to create new entry points.
 type Call struct {
-	Path  string // path to activate this RC
-	Fn    Func   `json:"-"` // function to call
-	Title string // help for the function
-	Help  string // multi-line markdown formatted help
+	Path         string // path to activate this RC
+	Fn           Func   `json:"-"` // function to call
+	Title        string // help for the function
+	AuthRequired bool   // if set then this call requires authorisation to be set
+	Help         string // multi-line markdown formatted help
 }
 
 // Registry holds the list of all the registered remote control functions
@@ -77,3 +79,9 @@ var registry = NewRegistry()
 func Add(call Call) {
 	registry.add(call)
 }
+
+// GetCall looks up a function in the global registry by path,
+// returning nil if it isn't found
+func GetCall(path string) *Call {
+	return registry.get(strings.Trim(path, "/"))
+}
@@ -0,0 +1,235 @@
+package sync
+
+import (
+	"container/heap"
+	"context"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fs/fserrors"
+	"github.com/pkg/errors"
+)
+
+// compare function for Less
+type lessFn func(a, b fs.ObjectPair) bool
+
+// pipe is a bounded queue of ObjectPairs between the checkers and the
+// transfers.
+//
+// If --order-by is in effect the queue is a priority queue ordered by
+// less, otherwise it is first in first out.
+type pipe struct {
+	mu       sync.Mutex
+	c        chan struct{} // one token for each item in the queue - bounds the backlog
+	queue    []fs.ObjectPair
+	less     lessFn // if set the queue is a heap ordered by this
+	fraction int    // if >= 0 the percentage of transfers which take the largest item
+}
+
+// newPipe makes a new pipe ordered according to orderBy (which may be
+// empty) holding no more than maxBacklog items. If maxBacklog is < 0
+// then the backlog is unlimited.
+func newPipe(orderBy string, maxBacklog int) (*pipe, error) {
+	if maxBacklog < 0 {
+		maxBacklog = (1 << (bits.UintSize - 1)) - 1 // largest positive int
+	}
+	less, fraction, err := newLess(orderBy)
+	if err != nil {
+		return nil, fserrors.FatalError(err)
+	}
+	return &pipe{
+		c:        make(chan struct{}, maxBacklog),
+		less:     less,
+		fraction: fraction,
+	}, nil
+}
+
+// Len satisfies heap.Interface - must be called with lock held
+func (p *pipe) Len() int {
+	return len(p.queue)
+}
+
+// Less satisfies heap.Interface - must be called with lock held
+func (p *pipe) Less(i, j int) bool {
+	return p.less(p.queue[i], p.queue[j])
+}
+
+// Swap satisfies heap.Interface - must be called with lock held
+func (p *pipe) Swap(i, j int) {
+	p.queue[i], p.queue[j] = p.queue[j], p.queue[i]
+}
+
+// Push satisfies heap.Interface - must be called with lock held
+func (p *pipe) Push(item interface{}) {
+	p.queue = append(p.queue, item.(fs.ObjectPair))
+}
+
+// Pop satisfies heap.Interface - must be called with lock held
+func (p *pipe) Pop() interface{} {
+	old := p.queue
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = fs.ObjectPair{} // avoid memory leak
+	p.queue = old[0 : n-1]
+	return item
+}
+
+// maxIndex returns the index of the largest item in the heap - must
+// be called with lock held
+//
+// The largest item in a heap is always one of the leaves.
+func (p *pipe) maxIndex() int {
+	n := len(p.queue)
+	max := n / 2
+	if max >= n {
+		max = 0
+	}
+	for i := max + 1; i < n; i++ {
+		if p.less(p.queue[max], p.queue[i]) {
+			max = i
+		}
+	}
+	return max
+}
+
+// Put a pair into the pipe
+//
+// It blocks while the backlog is full and returns ok = false if the
+// context was cancelled.
+//
+// The pair is queued before waiting for space in the backlog so that
+// a token in p.c always has an item in the queue to go with it.
+//
+// It will panic if you call it after Close()
+func (p *pipe) Put(ctx context.Context, pair fs.ObjectPair) (ok bool) {
+	if ctx.Err() != nil {
+		return false
+	}
+	p.mu.Lock()
+	if p.less == nil {
+		p.queue = append(p.queue, pair)
+	} else {
+		heap.Push(p, pair)
+	}
+	p.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return false
+	case p.c <- struct{}{}:
+	}
+	return true
+}
+
+// GetMax gets a pair from the pipe
+//
+// fraction is the percentage position of the caller among the
+// transfers. If --order-by mixed is in effect and fraction is in the
+// top part reserved for the largest items then the largest item is
+// returned rather than the smallest.
+//
+// It returns ok = false if the context was cancelled or Close() has
+// been called and the pipe is empty.
+func (p *pipe) GetMax(ctx context.Context, fraction int) (pair fs.ObjectPair, ok bool) {
+	if ctx.Err() != nil {
+		return pair, false
+	}
+	select {
+	case <-ctx.Done():
+		return pair, false
+	case _, ok = <-p.c:
+		if !ok {
+			return pair, false
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case p.less == nil:
+		pair = p.queue[0]
+		p.queue[0] = fs.ObjectPair{} // avoid memory leak
+		p.queue = p.queue[1:]
+	case p.fraction >= 0 && fraction >= 100-p.fraction:
+		pair = heap.Remove(p, p.maxIndex()).(fs.ObjectPair)
+	default:
+		pair = heap.Pop(p).(fs.ObjectPair)
+	}
+	return pair, true
+}
+
+// Get gets the next pair from the pipe
+//
+// It returns ok = false if the context was cancelled or Close() has
+// been called and the pipe is empty.
+func (p *pipe) Get(ctx context.Context) (pair fs.ObjectPair, ok bool) {
+	return p.GetMax(ctx, -1)
+}
+
+// Close the pipe
+//
+// Items remaining in the pipe can still be read with Get
+func (p *pipe) Close() {
+	close(p.c)
+}
+
+// newLess returns a less function for the heap comparison or nil if
+// one is not required.
+//
+// orderBy is of the form "key[,direction[,N]]" where key is one of
+// name, size or modtime and direction is one of ascending, asc,
+// descending, desc or mixed. For mixed, N is the percentage of
+// transfers which take the largest items (default 50) - this is
+// returned as fraction which is -1 if mixed isn't in use.
+func newLess(orderBy string) (less lessFn, fraction int, err error) {
+	fraction = -1
+	if orderBy == "" {
+		return nil, fraction, nil
+	}
+	parts := strings.Split(strings.ToLower(orderBy), ",")
+	switch parts[0] {
+	case "name":
+		less = func(a, b fs.ObjectPair) bool {
+			return a.Src.Remote() < b.Src.Remote()
+		}
+	case "size":
+		less = func(a, b fs.ObjectPair) bool {
+			return a.Src.Size() < b.Src.Size()
+		}
+	case "modtime":
+		less = func(a, b fs.ObjectPair) bool {
+			return a.Src.ModTime().Before(b.Src.ModTime())
+		}
+	default:
+		return nil, fraction, errors.Errorf("unknown --order-by comparison %q", parts[0])
+	}
+	descending := false
+	if len(parts) > 1 {
+		switch parts[1] {
+		case "ascending", "asc":
+		case "descending", "desc":
+			descending = true
+		case "mixed":
+			fraction = 50
+			if len(parts) > 2 {
+				fraction, err = strconv.Atoi(parts[2])
+				if err != nil || fraction < 0 || fraction > 100 {
+					return nil, -1, errors.Errorf("bad mixed percentage in --order-by %q", orderBy)
+				}
+			}
+		default:
+			return nil, fraction, errors.Errorf("unknown --order-by sort direction %q", parts[1])
+		}
+	}
+	if (fraction >= 0 && len(parts) > 3) || (fraction < 0 && len(parts) > 2) {
+		return nil, -1, errors.Errorf("bad --order-by string %q", orderBy)
+	}
+	if descending {
+		ascending := less
+		less = func(a, b fs.ObjectPair) bool {
+			return ascending(b, a)
+		}
+	}
+	return less, fraction, nil
+}
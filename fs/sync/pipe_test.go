@@ -0,0 +1,142 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fstest/mockobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sizedObject is a mock object with a size and modtime
+type sizedObject struct {
+	mockobject.Object
+	size    int64
+	modTime time.Time
+}
+
+func (o sizedObject) Size() int64 { return o.size }
+
+func (o sizedObject) ModTime() time.Time { return o.modTime }
+
+func newPair(name string, size int64) fs.ObjectPair {
+	return fs.ObjectPair{Src: sizedObject{
+		Object:  mockobject.New(name),
+		size:    size,
+		modTime: time.Unix(size, 0),
+	}}
+}
+
+func TestPipe(t *testing.T) {
+	ctx := context.Background()
+	p, err := newPipe("", 3)
+	require.NoError(t, err)
+
+	for _, name := range []string{"c", "a", "b"} {
+		assert.True(t, p.Put(ctx, newPair(name, 0)))
+	}
+	p.Close()
+
+	var got []string
+	for {
+		pair, ok := p.Get(ctx)
+		if !ok {
+			break
+		}
+		got = append(got, pair.Src.Remote())
+	}
+	assert.Equal(t, []string{"c", "a", "b"}, got)
+}
+
+func TestPipeCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p, err := newPipe("", 1)
+	require.NoError(t, err)
+
+	assert.True(t, p.Put(ctx, newPair("a", 0)))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// blocks as the backlog is full
+		assert.False(t, p.Put(ctx, newPair("b", 0)))
+	}()
+	cancel()
+	wg.Wait()
+
+	_, ok := p.Get(ctx)
+	assert.False(t, ok)
+}
+
+func TestPipeOrderBy(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		orderBy  string
+		fraction int
+		want     []string
+	}{
+		{"name", -1, []string{"a", "b", "c", "d"}},
+		{"name,desc", -1, []string{"d", "c", "b", "a"}},
+		{"size", -1, []string{"c", "d", "a", "b"}},
+		{"size,ascending", -1, []string{"c", "d", "a", "b"}},
+		{"size,descending", -1, []string{"b", "a", "d", "c"}},
+		{"modtime,asc", -1, []string{"c", "d", "a", "b"}},
+		{"modtime,desc", -1, []string{"b", "a", "d", "c"}},
+		{"size,mixed", 0, []string{"c", "d", "a", "b"}},
+		{"size,mixed", 50, []string{"b", "a", "d", "c"}},
+		{"size,mixed,25", 50, []string{"c", "d", "a", "b"}},
+		{"size,mixed,25", 75, []string{"b", "a", "d", "c"}},
+	} {
+		p, err := newPipe(test.orderBy, 10)
+		require.NoError(t, err, test.orderBy)
+		for i, name := range []string{"a", "b", "c", "d"} {
+			size := []int64{3, 4, 1, 2}[i]
+			require.True(t, p.Put(ctx, newPair(name, size)))
+		}
+		p.Close()
+
+		var got []string
+		for {
+			pair, ok := p.GetMax(ctx, test.fraction)
+			if !ok {
+				break
+			}
+			got = append(got, pair.Src.Remote())
+		}
+		assert.Equal(t, test.want, got, test.orderBy)
+	}
+}
+
+func TestNewLess(t *testing.T) {
+	for _, test := range []struct {
+		orderBy  string
+		isNil    bool
+		fraction int
+		wantErr  bool
+	}{
+		{"", true, -1, false},
+		{"size", false, -1, false},
+		{"Name,Descending", false, -1, false},
+		{"modtime,mixed", false, 50, false},
+		{"size,mixed,25", false, 25, false},
+		{"potato", true, -1, true},
+		{"size,potato", true, -1, true},
+		{"size,asc,25", true, -1, true},
+		{"size,mixed,101", true, -1, true},
+		{"size,mixed,25,1", true, -1, true},
+	} {
+		less, fraction, err := newLess(test.orderBy)
+		if test.wantErr {
+			assert.Error(t, err, test.orderBy)
+		} else {
+			assert.NoError(t, err, test.orderBy)
+		}
+		assert.Equal(t, test.isNil, less == nil, test.orderBy)
+		assert.Equal(t, test.fraction, fraction, test.orderBy)
+	}
+}
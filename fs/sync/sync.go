@@ -7,6 +7,7 @@ import (
 	"path"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/artpar/rclone/fs"
 	"github.com/artpar/rclone/fs/accounting"
@@ -27,42 +28,45 @@ type syncCopyMove struct {
 	deleteEmptySrcDirs bool
 	dir                string
 	// internal state
-	ctx            context.Context        // internal context for controlling go-routines
-	cancel         func()                 // cancel the context
-	deletersWg     sync.WaitGroup         // for delete before go routine
-	deleteFilesCh  chan fs.Object         // channel to receive deletes if delete before
-	trackRenames   bool                   // set if we should do server side renames
-	dstFilesMu     sync.Mutex             // protect dstFiles
-	dstFiles       map[string]fs.Object   // dst files, always filled
-	srcFiles       map[string]fs.Object   // src files, only used if deleteBefore
-	srcFilesChan   chan fs.Object         // passes src objects
-	srcFilesResult chan error             // error result of src listing
-	dstFilesResult chan error             // error result of dst listing
-	dstEmptyDirsMu sync.Mutex             // protect dstEmptyDirs
-	dstEmptyDirs   map[string]fs.DirEntry // potentially empty directories
-	srcEmptyDirsMu sync.Mutex             // protect srcEmptyDirs
-	srcEmptyDirs   map[string]fs.DirEntry // potentially empty directories
-	checkerWg      sync.WaitGroup         // wait for checkers
-	toBeChecked    fs.ObjectPairChan      // checkers channel
-	transfersWg    sync.WaitGroup         // wait for transfers
-	toBeUploaded   fs.ObjectPairChan      // copiers channel
-	errorMu        sync.Mutex             // Mutex covering the errors variables
-	err            error                  // normal error from copy process
-	noRetryErr     error                  // error with NoRetry set
-	fatalErr       error                  // fatal error
-	commonHash     hash.Type              // common hash type between src and dst
-	renameMapMu    sync.Mutex             // mutex to protect the below
-	renameMap      map[string][]fs.Object // dst files by hash - only used by trackRenames
-	renamerWg      sync.WaitGroup         // wait for renamers
-	toBeRenamed    fs.ObjectPairChan      // renamers channel
-	trackRenamesWg sync.WaitGroup         // wg for background track renames
-	trackRenamesCh chan fs.Object         // objects are pumped in here
-	renameCheck    []fs.Object            // accumulate files to check for rename here
-	backupDir      fs.Fs                  // place to store overwrites/deletes
-	suffix         string                 // suffix to add to files placed in backupDir
-}
-
-func newSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, deleteEmptySrcDirs bool) (*syncCopyMove, error) {
+	ctx             context.Context        // internal context for controlling go-routines
+	cancel          func()                 // cancel the context
+	deletersWg      sync.WaitGroup         // for delete before go routine
+	deleteFilesCh   chan fs.Object         // channel to receive deletes if delete before
+	trackRenames    bool                   // set if we should do server side renames
+	dstFilesMu      sync.Mutex             // protect dstFiles
+	dstFiles        map[string]fs.Object   // dst files, always filled
+	srcFiles        map[string]fs.Object   // src files, only used if deleteBefore
+	srcFilesChan    chan fs.Object         // passes src objects
+	srcFilesResult  chan error             // error result of src listing
+	dstFilesResult  chan error             // error result of dst listing
+	dstEmptyDirsMu  sync.Mutex             // protect dstEmptyDirs
+	dstEmptyDirs    map[string]fs.DirEntry // potentially empty directories
+	srcEmptyDirsMu  sync.Mutex             // protect srcEmptyDirs
+	srcEmptyDirs    map[string]fs.DirEntry // potentially empty directories
+	checkerWg       sync.WaitGroup         // wait for checkers
+	toBeChecked     fs.ObjectPairChan      // checkers channel
+	transfersWg     sync.WaitGroup         // wait for transfers
+	toBeUploaded    *pipe                  // copiers queue, ordered by --order-by
+	errorMu         sync.Mutex             // Mutex covering the errors variables
+	err             error                  // normal error from copy process
+	noRetryErr      error                  // error with NoRetry set
+	fatalErr        error                  // fatal error
+	commonHash      hash.Type              // common hash type between src and dst
+	renameMapMu     sync.Mutex             // mutex to protect the below
+	renameMap       map[string][]fs.Object // dst files by hash - only used by trackRenames
+	renamerWg       sync.WaitGroup         // wait for renamers
+	toBeRenamed     fs.ObjectPairChan      // renamers channel
+	trackRenamesWg  sync.WaitGroup         // wg for background track renames
+	trackRenamesCh  chan fs.Object         // objects are pumped in here
+	renameCheck     []fs.Object            // accumulate files to check for rename here
+	backupDir       fs.Fs                  // place to store overwrites/deletes
+	suffix          string                 // suffix to add to files placed in backupDir
+	compareCopyDest fs.Fs                  // place to check for files to server side copy or skip
+	maxDurationEnd  time.Time              // if set, time after which no more transfers are started
+	cutoffOnce      sync.Once              // log the cutoff only once
+}
+
+func newSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, deleteEmptySrcDirs bool) (*syncCopyMove, error) {
 	s := &syncCopyMove{
 		fdst:               fdst,
 		fsrc:               fsrc,
@@ -76,14 +80,21 @@ func newSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, de
 		dstEmptyDirs:       make(map[string]fs.DirEntry),
 		srcEmptyDirs:       make(map[string]fs.DirEntry),
 		toBeChecked:        make(fs.ObjectPairChan, fs.Config.Transfers),
-		toBeUploaded:       make(fs.ObjectPairChan, fs.Config.Transfers),
 		deleteFilesCh:      make(chan fs.Object, fs.Config.Checkers),
 		trackRenames:       fs.Config.TrackRenames,
 		commonHash:         fsrc.Hashes().Overlap(fdst.Hashes()).GetOne(),
 		toBeRenamed:        make(fs.ObjectPairChan, fs.Config.Transfers),
 		trackRenamesCh:     make(chan fs.Object, fs.Config.Checkers),
 	}
-	s.ctx, s.cancel = context.WithCancel(context.Background())
+	var err error
+	s.toBeUploaded, err = newPipe(fs.Config.OrderBy, fs.Config.MaxBacklog)
+	if err != nil {
+		return nil, err
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	if fs.Config.MaxDuration > 0 {
+		s.maxDurationEnd = time.Now().Add(fs.Config.MaxDuration)
+	}
 	if s.trackRenames {
 		// Don't track renames for remotes without server-side move support.
 		if !operations.CanServerSideMove(fdst) {
@@ -126,6 +137,32 @@ func newSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, de
 		}
 		s.suffix = fs.Config.Suffix
 	}
+	// Make Fs for --compare-dest or --copy-dest if required
+	if fs.Config.CompareDest != "" {
+		var err error
+		s.compareCopyDest, err = fs.NewFs(fs.Config.CompareDest)
+		if err != nil {
+			return nil, fserrors.FatalError(errors.Errorf("Failed to make fs for --compare-dest %q: %v", fs.Config.CompareDest, err))
+		}
+		if operations.Overlapping(fdst, s.compareCopyDest) {
+			return nil, fserrors.FatalError(errors.New("destination and parameter to --compare-dest mustn't overlap"))
+		}
+	} else if fs.Config.CopyDest != "" {
+		var err error
+		s.compareCopyDest, err = fs.NewFs(fs.Config.CopyDest)
+		if err != nil {
+			return nil, fserrors.FatalError(errors.Errorf("Failed to make fs for --copy-dest %q: %v", fs.Config.CopyDest, err))
+		}
+		if fdst.Features().Copy == nil {
+			return nil, fserrors.FatalError(errors.New("can't use --copy-dest on a remote which doesn't support server side copy"))
+		}
+		if !operations.SameConfig(fdst, s.compareCopyDest) {
+			return nil, fserrors.FatalError(errors.New("parameter to --copy-dest has to be on the same remote as destination"))
+		}
+		if operations.Overlapping(fdst, s.compareCopyDest) {
+			return nil, fserrors.FatalError(errors.New("destination and parameter to --copy-dest mustn't overlap"))
+		}
+	}
 	return s, nil
 }
 
@@ -197,7 +234,7 @@ func (s *syncCopyMove) currentError() error {
 // pairChecker reads Objects~s on in send to out if they need transferring.
 //
 // FIXME potentially doing lots of hashes at once
-func (s *syncCopyMove) pairChecker(in fs.ObjectPairChan, out fs.ObjectPairChan, wg *sync.WaitGroup) {
+func (s *syncCopyMove) pairChecker(in fs.ObjectPairChan, out *pipe, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for {
 		if s.aborting() {
@@ -212,7 +249,11 @@ func (s *syncCopyMove) pairChecker(in fs.ObjectPairChan, out fs.ObjectPairChan,
 			accounting.Stats.Checking(src.Remote())
 			// Check to see if can store this
 			if src.Storable() {
-				if operations.NeedTransfer(pair.Dst, pair.Src) {
+				noNeedTransfer, err := operations.CompareOrCopyDest(s.ctx, s.fdst, pair.Dst, pair.Src, s.compareCopyDest, s.backupDir)
+				if err != nil {
+					s.processError(err)
+				}
+				if !noNeedTransfer && operations.NeedTransfer(s.ctx, pair.Dst, pair.Src) {
 					// If files are treated as immutable, fail if destination exists and does not match
 					if fs.Config.Immutable && pair.Dst != nil {
 						fs.Errorf(pair.Dst, "Source and destination exist but do not match: immutable file modified")
@@ -221,24 +262,20 @@ func (s *syncCopyMove) pairChecker(in fs.ObjectPairChan, out fs.ObjectPairChan,
 						// If destination already exists, then we must move it into --backup-dir if required
 						if pair.Dst != nil && s.backupDir != nil {
 							remoteWithSuffix := pair.Dst.Remote() + s.suffix
-							overwritten, _ := s.backupDir.NewObject(remoteWithSuffix)
-							_, err := operations.Move(s.backupDir, overwritten, remoteWithSuffix, pair.Dst)
+							overwritten, _ := s.backupDir.NewObject(s.ctx, remoteWithSuffix)
+							_, err := operations.Move(s.ctx, s.backupDir, overwritten, remoteWithSuffix, pair.Dst)
 							if err != nil {
 								s.processError(err)
 							} else {
 								// If successful zero out the dst as it is no longer there and copy the file
 								pair.Dst = nil
-								select {
-								case <-s.ctx.Done():
+								if !out.Put(s.ctx, pair) {
 									return
-								case out <- pair:
 								}
 							}
 						} else {
-							select {
-							case <-s.ctx.Done():
+							if !out.Put(s.ctx, pair) {
 								return
-							case out <- pair:
 							}
 						}
 					}
@@ -246,7 +283,7 @@ func (s *syncCopyMove) pairChecker(in fs.ObjectPairChan, out fs.ObjectPairChan,
 					// If moving need to delete the files we don't need to copy
 					if s.DoMove {
 						// Delete src if no error on copy
-						s.processError(operations.DeleteFile(src))
+						s.processError(operations.DeleteFile(s.ctx, src))
 					}
 				}
 			}
@@ -259,7 +296,7 @@ func (s *syncCopyMove) pairChecker(in fs.ObjectPairChan, out fs.ObjectPairChan,
 
 // pairRenamer reads Objects~s on in and attempts to rename them,
 // otherwise it sends them out if they need transferring.
-func (s *syncCopyMove) pairRenamer(in fs.ObjectPairChan, out fs.ObjectPairChan, wg *sync.WaitGroup) {
+func (s *syncCopyMove) pairRenamer(in fs.ObjectPairChan, out *pipe, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for {
 		if s.aborting() {
@@ -273,10 +310,8 @@ func (s *syncCopyMove) pairRenamer(in fs.ObjectPairChan, out fs.ObjectPairChan,
 			src := pair.Src
 			if !s.tryRename(src) {
 				// pass on if not renamed
-				select {
-				case <-s.ctx.Done():
+				if !out.Put(s.ctx, pair) {
 					return
-				case out <- pair:
 				}
 			}
 		case <-s.ctx.Done():
@@ -286,33 +321,75 @@ func (s *syncCopyMove) pairRenamer(in fs.ObjectPairChan, out fs.ObjectPairChan,
 }
 
 // pairCopyOrMove reads Objects on in and moves or copies them.
-func (s *syncCopyMove) pairCopyOrMove(in fs.ObjectPairChan, fdst fs.Fs, wg *sync.WaitGroup) {
+//
+// fraction is the percentage position of this transfer among all the
+// transfers which is used by --order-by mixed.
+func (s *syncCopyMove) pairCopyOrMove(in *pipe, fdst fs.Fs, fraction int, wg *sync.WaitGroup) {
 	defer wg.Done()
 	var err error
 	for {
 		if s.aborting() {
 			return
 		}
-		select {
-		case pair, ok := <-in:
-			if !ok {
-				return
-			}
-			src := pair.Src
-			accounting.Stats.Transferring(src.Remote())
-			if s.DoMove {
-				_, err = operations.Move(fdst, pair.Dst, src.Remote(), src)
-			} else {
-				_, err = operations.Copy(fdst, pair.Dst, src.Remote(), src)
-			}
-			s.processError(err)
-			accounting.Stats.DoneTransferring(src.Remote(), err == nil)
-		case <-s.ctx.Done():
+		pair, ok := in.GetMax(s.ctx, fraction)
+		if !ok {
 			return
 		}
+		src := pair.Src
+		if err = s.checkCutoff(src); err != nil {
+			s.logCutoff(src, err)
+			s.processError(err)
+			continue
+		}
+		accounting.Stats.Transferring(src.Remote())
+		if s.DoMove {
+			_, err = operations.Move(s.ctx, fdst, pair.Dst, src.Remote(), src)
+		} else {
+			_, err = operations.Copy(s.ctx, fdst, pair.Dst, src.Remote(), src)
+		}
+		if err == accounting.ErrorMaxTransferLimitReachedGraceful {
+			s.logCutoff(src, err)
+		}
+		s.processError(err)
+		accounting.Stats.DoneTransferring(src.Remote(), err == nil)
 	}
 }
 
+// checkCutoff checks the --max-duration limit before src is
+// transferred when using --cutoff-mode soft or cautious.
+// operations.Copy checks the --max-transfer limit.
+//
+// In cautious mode a transfer is refused if it is estimated not to
+// finish before the --max-duration deadline at the current average
+// speed.
+//
+// It returns a graceful (NoRetry) error if src should not be transferred.
+func (s *syncCopyMove) checkCutoff(src fs.Object) (err error) {
+	if fs.Config.CutoffMode == fs.CutoffModeHard {
+		return nil
+	}
+	if !s.maxDurationEnd.IsZero() {
+		remaining := s.maxDurationEnd.Sub(time.Now())
+		if remaining <= 0 {
+			err = accounting.ErrorMaxDurationReachedGraceful
+		} else if fs.Config.CutoffMode == fs.CutoffModeCautious && src.Size() > 0 {
+			if speed := accounting.Stats.Speed(); speed > 0 && float64(src.Size())/speed > remaining.Seconds() {
+				err = accounting.ErrorMaxDurationReachedGraceful
+			}
+		}
+	}
+	return err
+}
+
+// logCutoff logs that src wasn't transferred because of the cutoff
+// error err, logging it as an error only the first time
+func (s *syncCopyMove) logCutoff(src fs.Object, err error) {
+	s.cutoffOnce.Do(func() {
+		fs.Errorf(nil, "%v - not starting any more transfers which exceed it (--cutoff-mode %v)", err, fs.Config.CutoffMode)
+	})
+	fs.Debugf(src, "Not transferring: %v", err)
+}
+
 // This starts the background checkers.
 func (s *syncCopyMove) startCheckers() {
 	s.checkerWg.Add(fs.Config.Checkers)
@@ -332,13 +409,14 @@ func (s *syncCopyMove) stopCheckers() {
 func (s *syncCopyMove) startTransfers() {
 	s.transfersWg.Add(fs.Config.Transfers)
 	for i := 0; i < fs.Config.Transfers; i++ {
-		go s.pairCopyOrMove(s.toBeUploaded, s.fdst, &s.transfersWg)
+		fraction := (100 * i) / fs.Config.Transfers
+		go s.pairCopyOrMove(s.toBeUploaded, s.fdst, fraction, &s.transfersWg)
 	}
 }
 
 // This stops the background transfers
 func (s *syncCopyMove) stopTransfers() {
-	close(s.toBeUploaded)
+	s.toBeUploaded.Close()
 	fs.Infof(s.fdst, "Waiting for transfers to finish")
 	s.transfersWg.Wait()
 }
@@ -395,7 +473,7 @@ func (s *syncCopyMove) startDeleters() {
 	s.deletersWg.Add(1)
 	go func() {
 		defer s.deletersWg.Done()
-		err := operations.DeleteFilesWithBackupDir(s.deleteFilesCh, s.backupDir)
+		err := operations.DeleteFilesWithBackupDir(s.ctx, s.deleteFilesCh, s.backupDir)
 		s.processError(err)
 	}()
 }
@@ -442,12 +520,12 @@ func (s *syncCopyMove) deleteFiles(checkSrcMap bool) error {
 		}
 		close(toDelete)
 	}()
-	return operations.DeleteFilesWithBackupDir(toDelete, s.backupDir)
+	return operations.DeleteFilesWithBackupDir(s.ctx, toDelete, s.backupDir)
 }
 
 // This deletes the empty directories in the slice passed in.  It
 // ignores any errors deleting directories
-func deleteEmptyDirectories(f fs.Fs, entriesMap map[string]fs.DirEntry) error {
+func deleteEmptyDirectories(ctx context.Context, f fs.Fs, entriesMap map[string]fs.DirEntry) error {
 	if len(entriesMap) == 0 {
 		return nil
 	}
@@ -469,7 +547,7 @@ func deleteEmptyDirectories(f fs.Fs, entriesMap map[string]fs.DirEntry) error {
 		dir, ok := entry.(fs.Directory)
 		if ok {
 			// TryRmdir only deletes empty directories
-			err := operations.TryRmdir(f, dir.Remote())
+			err := operations.TryRmdir(ctx, f, dir.Remote())
 			if err != nil {
 				fs.Debugf(fs.LogDirName(f, dir.Remote()), "Failed to Rmdir: %v", err)
 				errorCount++
@@ -491,7 +569,7 @@ func deleteEmptyDirectories(f fs.Fs, entriesMap map[string]fs.DirEntry) error {
 
 // This copies the empty directories in the slice passed in and logs
 // any errors copying the directories
-func copyEmptyDirectories(f fs.Fs, entries map[string]fs.DirEntry) error {
+func copyEmptyDirectories(ctx context.Context, f fs.Fs, entries map[string]fs.DirEntry) error {
 	if len(entries) == 0 {
 		return nil
 	}
@@ -500,7 +578,7 @@ func copyEmptyDirectories(f fs.Fs, entries map[string]fs.DirEntry) error {
 	for _, entry := range entries {
 		dir, ok := entry.(fs.Directory)
 		if ok {
-			err := f.Mkdir(dir.Remote())
+			err := f.Mkdir(ctx, dir.Remote())
 			if err != nil {
 				fs.Errorf(fs.LogDirName(f, dir.Remote()), "Failed to Mkdir: %v", err)
 				accounting.Stats.Error(err)
@@ -635,10 +713,10 @@ func (s *syncCopyMove) tryRename(src fs.Object) bool {
 	}
 
 	// Find dst object we are about to overwrite if it exists
-	dstOverwritten, _ := s.fdst.NewObject(src.Remote())
+	dstOverwritten, _ := s.fdst.NewObject(s.ctx, src.Remote())
 
 	// Rename dst to have name src.Remote()
-	_, err := operations.Move(s.fdst, dstOverwritten, src.Remote(), dst)
+	_, err := operations.Move(s.ctx, s.fdst, dstOverwritten, src.Remote(), dst)
 	if err != nil {
 		fs.Debugf(src, "Failed to rename to %q: %v", dst.Remote(), err)
 		return false
@@ -666,6 +744,14 @@ func (s *syncCopyMove) run() error {
 		return nil
 	}
 
+	// Stop all transfers when --max-duration expires in hard mode
+	if !s.maxDurationEnd.IsZero() && fs.Config.CutoffMode == fs.CutoffModeHard {
+		timer := time.AfterFunc(s.maxDurationEnd.Sub(time.Now()), func() {
+			s.processError(accounting.ErrorMaxDurationReached)
+		})
+		defer timer.Stop()
+	}
+
 	// Start background checking and transferring pipeline
 	s.startCheckers()
 	s.startRenamers()
@@ -699,7 +785,7 @@ func (s *syncCopyMove) run() error {
 	s.stopTransfers()
 	s.stopDeleters()
 
-	s.processError(copyEmptyDirectories(s.fdst, s.srcEmptyDirs))
+	s.processError(copyEmptyDirectories(s.ctx, s.fdst, s.srcEmptyDirs))
 
 	// Delete files after
 	if s.deleteMode == fs.DeleteModeAfter {
@@ -715,7 +801,7 @@ func (s *syncCopyMove) run() error {
 		if s.currentError() != nil && !fs.Config.IgnoreErrors {
 			fs.Errorf(s.fdst, "%v", fs.ErrorNotDeletingDirs)
 		} else {
-			s.processError(deleteEmptyDirectories(s.fdst, s.dstEmptyDirs))
+			s.processError(deleteEmptyDirectories(s.ctx, s.fdst, s.dstEmptyDirs))
 		}
 	}
 
@@ -723,7 +809,7 @@ func (s *syncCopyMove) run() error {
 	// if DoMove and --delete-empty-src-dirs flag is set
 	if s.DoMove && s.deleteEmptySrcDirs {
 		//delete empty subdirectories that were part of the move
-		s.processError(deleteEmptyDirectories(s.fsrc, s.srcEmptyDirs))
+		s.processError(deleteEmptyDirectories(s.ctx, s.fsrc, s.srcEmptyDirs))
 	}
 
 	// cancel the context to free resources
@@ -790,12 +876,17 @@ func (s *syncCopyMove) SrcOnly(src fs.DirEntry) (recurse bool) {
 				return
 			case s.trackRenamesCh <- x:
 			}
-		} else {
-			// No need to check since doesn't exist
+		} else if s.compareCopyDest != nil {
+			// Need to check --compare-dest or --copy-dest first
 			select {
 			case <-s.ctx.Done():
 				return
-			case s.toBeUploaded <- fs.ObjectPair{Src: x, Dst: nil}:
+			case s.toBeChecked <- fs.ObjectPair{Src: x, Dst: nil}:
+			}
+		} else {
+			// No need to check since doesn't exist
+			if !s.toBeUploaded.Put(s.ctx, fs.ObjectPair{Src: x, Dst: nil}) {
+				return
 			}
 		}
 	case fs.Directory:
@@ -864,7 +955,7 @@ func (s *syncCopyMove) Match(dst, src fs.DirEntry) (recurse bool) {
 // If DoMove is true then files will be moved instead of copied
 //
 // dir is the start directory, "" for root
-func runSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, deleteEmptySrcDirs bool) error {
+func runSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, deleteEmptySrcDirs bool) error {
 	if deleteMode != fs.DeleteModeOff && DoMove {
 		return fserrors.FatalError(errors.New("can't delete and move at the same time"))
 	}
@@ -874,7 +965,7 @@ func runSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, de
 			return fserrors.FatalError(errors.New("can't use --delete-before with --track-renames"))
 		}
 		// only delete stuff during in this pass
-		do, err := newSyncCopyMove(fdst, fsrc, fs.DeleteModeOnly, false, deleteEmptySrcDirs)
+		do, err := newSyncCopyMove(ctx, fdst, fsrc, fs.DeleteModeOnly, false, deleteEmptySrcDirs)
 		if err != nil {
 			return err
 		}
@@ -885,7 +976,7 @@ func runSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, de
 		// Next pass does a copy only
 		deleteMode = fs.DeleteModeOff
 	}
-	do, err := newSyncCopyMove(fdst, fsrc, deleteMode, DoMove, deleteEmptySrcDirs)
+	do, err := newSyncCopyMove(ctx, fdst, fsrc, deleteMode, DoMove, deleteEmptySrcDirs)
 	if err != nil {
 		return err
 	}
@@ -893,22 +984,22 @@ func runSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, de
 }
 
 // Sync fsrc into fdst
-func Sync(fdst, fsrc fs.Fs) error {
-	return runSyncCopyMove(fdst, fsrc, fs.Config.DeleteMode, false, false)
+func Sync(ctx context.Context, fdst, fsrc fs.Fs) error {
+	return runSyncCopyMove(ctx, fdst, fsrc, fs.Config.DeleteMode, false, false)
 }
 
 // CopyDir copies fsrc into fdst
-func CopyDir(fdst, fsrc fs.Fs) error {
-	return runSyncCopyMove(fdst, fsrc, fs.DeleteModeOff, false, false)
+func CopyDir(ctx context.Context, fdst, fsrc fs.Fs) error {
+	return runSyncCopyMove(ctx, fdst, fsrc, fs.DeleteModeOff, false, false)
 }
 
 // moveDir moves fsrc into fdst
-func moveDir(fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
-	return runSyncCopyMove(fdst, fsrc, fs.DeleteModeOff, true, deleteEmptySrcDirs)
+func moveDir(ctx context.Context, fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
+	return runSyncCopyMove(ctx, fdst, fsrc, fs.DeleteModeOff, true, deleteEmptySrcDirs)
 }
 
 // MoveDir moves fsrc into fdst
-func MoveDir(fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
+func MoveDir(ctx context.Context, fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
 	if operations.Same(fdst, fsrc) {
 		fs.Errorf(fdst, "Nothing to do as source and destination are the same")
 		return nil
@@ -921,7 +1012,7 @@ func MoveDir(fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
 			return nil
 		}
 		fs.Debugf(fdst, "Using server side directory move")
-		err := fdstDirMove(fsrc, "", "")
+		err := fdstDirMove(ctx, fsrc, "", "")
 		switch err {
 		case fs.ErrorCantDirMove, fs.ErrorDirExists:
 			fs.Infof(fdst, "Server side directory move failed - fallback to file moves: %v", err)
@@ -943,5 +1034,5 @@ func MoveDir(fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
 	}
 
 	// Otherwise move the files one by one
-	return moveDir(fdst, fsrc, deleteEmptySrcDirs)
+	return moveDir(ctx, fdst, fsrc, deleteEmptySrcDirs)
 }
@@ -5,6 +5,7 @@ package fstest
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -25,6 +26,7 @@ import (
 	"github.com/artpar/rclone/fs/accounting"
 	"github.com/artpar/rclone/fs/config"
 	"github.com/artpar/rclone/fs/hash"
+	"github.com/artpar/rclone/fs/object"
 	"github.com/artpar/rclone/fs/walk"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -283,7 +285,7 @@ func CheckListingWithPrecision(t *testing.T, f fs.Fs, items []Item, expectedDirs
 	gotListing := "<unset>"
 	listingOK := false
 	for i := 1; i <= retries; i++ {
-		objs, dirs, err = walk.GetAll(f, "", true, -1)
+		objs, dirs, err = walk.GetAll(context.Background(), f, "", true, -1)
 		if err != nil && err != fs.ErrorDirNotFound {
 			t.Fatalf("Error listing: %v", err)
 		}
@@ -461,21 +463,21 @@ func Purge(f fs.Fs) {
 	if doPurge := f.Features().Purge; doPurge != nil {
 		doFallbackPurge = false
 		fs.Debugf(f, "Purge remote")
-		err = doPurge()
+		err = doPurge(context.Background())
 		if err == fs.ErrorCantPurge {
 			doFallbackPurge = true
 		}
 	}
 	if doFallbackPurge {
 		dirs := []string{""}
-		err = walk.Walk(f, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
+		err = walk.Walk(context.Background(), f, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
 			if err != nil {
 				log.Printf("purge walk returned error: %v", err)
 				return nil
 			}
 			entries.ForObject(func(obj fs.Object) {
 				fs.Debugf(f, "Purge object %q", obj.Remote())
-				err = obj.Remove()
+				err = obj.Remove(context.Background())
 				if err != nil {
 					log.Printf("purge failed to remove %q: %v", obj.Remote(), err)
 				}
@@ -489,7 +491,7 @@ func Purge(f fs.Fs) {
 		for i := len(dirs) - 1; i >= 0; i-- {
 			dir := dirs[i]
 			fs.Debugf(f, "Purge dir %q", dir)
-			err := f.Rmdir(dir)
+			err := f.Rmdir(context.Background(), dir)
 			if err != nil {
 				log.Printf("purge failed to rmdir %q: %v", dir, err)
 			}
@@ -499,3 +501,60 @@ func Purge(f fs.Fs) {
 		log.Printf("purge failed: %v", err)
 	}
 }
+
+// TempDir makes a temporary local directory with the prefix given,
+// returning it and a function to remove it
+func TempDir(t *testing.T, prefix string) (dir string, cleanup func()) {
+	dir, err := ioutil.TempDir("", prefix)
+	require.NoError(t, err)
+	return dir, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// DirNames returns the sorted names of the entries in the local
+// directory dir
+func DirNames(t *testing.T, dir string) (names []string) {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PutString uploads contents to remote in f with the current time as
+// the modification time, returning the new object
+func PutString(t *testing.T, f fs.Fs, remote, contents string) fs.Object {
+	src := object.NewStaticObjectInfo(remote, time.Now(), int64(len(contents)), true, nil, nil)
+	o, err := f.Put(context.Background(), bytes.NewBufferString(contents), src)
+	require.NoError(t, err)
+	return o
+}
+
+// ReadObject reads all of o with the options given
+func ReadObject(t *testing.T, o fs.Object, options ...fs.OpenOption) string {
+	in, err := o.Open(context.Background(), options...)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(in)
+	require.NoError(t, err)
+	require.NoError(t, in.Close())
+	return string(data)
+}
+
+// ObjectHash returns the hash of type ht of o
+func ObjectHash(t *testing.T, o fs.Object, ht hash.Type) string {
+	sum, err := o.Hash(ht)
+	require.NoError(t, err)
+	return sum
+}
+
+// StringHash returns the hash of type ht of s
+func StringHash(t *testing.T, ht hash.Type, s string) string {
+	hasher, err := hash.NewMultiHasherTypes(hash.NewHashSet(ht))
+	require.NoError(t, err)
+	_, err = hasher.Write([]byte(s))
+	require.NoError(t, err)
+	return hasher.Sums()[ht]
+}
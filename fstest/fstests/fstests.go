@@ -4,6 +4,7 @@ package fstests
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -56,11 +57,11 @@ func objsToNames(objs []fs.Object) []string {
 }
 
 // findObject finds the object on the remote
-func findObject(t *testing.T, f fs.Fs, Name string) fs.Object {
+func findObject(ctx context.Context, t *testing.T, f fs.Fs, Name string) fs.Object {
 	var obj fs.Object
 	var err error
 	for i := 1; i <= *fstest.ListRetries; i++ {
-		obj, err = f.NewObject(Name)
+		obj, err = f.NewObject(ctx, Name)
 		if err == nil {
 			break
 		}
@@ -72,7 +73,7 @@ func findObject(t *testing.T, f fs.Fs, Name string) fs.Object {
 }
 
 // testPut puts file to the remote
-func testPut(t *testing.T, f fs.Fs, file *fstest.Item) string {
+func testPut(ctx context.Context, t *testing.T, f fs.Fs, file *fstest.Item) string {
 	tries := 1
 	const maxTries = 10
 again:
@@ -83,7 +84,7 @@ again:
 
 	file.Size = int64(buf.Len())
 	obji := object.NewStaticObjectInfo(file.Path, file.ModTime, file.Size, true, nil, nil)
-	obj, err := f.Put(in, obji)
+	obj, err := f.Put(ctx, in, obji)
 	if err != nil {
 		// Retry if err returned a retry error
 		if fserrors.IsRetryError(err) && tries < maxTries {
@@ -98,7 +99,7 @@ again:
 	file.Hashes = hash.Sums()
 	file.Check(t, obj, f.Precision())
 	// Re-read the object and check again
-	obj = findObject(t, f, file.Path)
+	obj = findObject(ctx, t, f, file.Path)
 	file.Check(t, obj, f.Precision())
 	return contents
 }
@@ -114,9 +115,9 @@ func (er errorReader) Read(p []byte) (n int, err error) {
 }
 
 // read the contents of an object as a string
-func readObject(t *testing.T, obj fs.Object, limit int64, options ...fs.OpenOption) string {
+func readObject(ctx context.Context, t *testing.T, obj fs.Object, limit int64, options ...fs.OpenOption) string {
 	what := fmt.Sprintf("readObject(%q) limit=%d, options=%+v", obj, limit, options)
-	in, err := obj.Open(options...)
+	in, err := obj.Open(ctx, options...)
 	require.NoError(t, err, what)
 	var r io.Reader = in
 	if limit >= 0 {
@@ -145,6 +146,7 @@ type Opt struct {
 // name passed in and the nil object
 func Run(t *testing.T, opt *Opt) {
 	var (
+		ctx           = context.Background()
 		remote        fs.Fs
 		remoteName    = opt.RemoteName
 		subRemoteName string
@@ -229,7 +231,7 @@ func Run(t *testing.T, opt *Opt) {
 
 		skipIfNotOk(t)
 
-		err = remote.Mkdir("")
+		err = remote.Mkdir(ctx, "")
 		require.NoError(t, err)
 		fstest.CheckListing(t, remote, []fstest.Item{})
 	})
@@ -268,14 +270,14 @@ func Run(t *testing.T, opt *Opt) {
 	// TestFsRmdirEmpty tests deleting an empty directory
 	t.Run("TestFsRmdirEmpty", func(t *testing.T) {
 		skipIfNotOk(t)
-		err := remote.Rmdir("")
+		err := remote.Rmdir(ctx, "")
 		require.NoError(t, err)
 	})
 
 	// TestFsRmdirNotFound tests deleting a non existent directory
 	t.Run("TestFsRmdirNotFound", func(t *testing.T) {
 		skipIfNotOk(t)
-		err := remote.Rmdir("")
+		err := remote.Rmdir(ctx, "")
 		assert.Error(t, err, "Expecting error on Rmdir non existent")
 	})
 
@@ -288,11 +290,11 @@ func Run(t *testing.T, opt *Opt) {
 		// (eg azure blob)
 		newFs(t)
 
-		err := remote.Mkdir("")
+		err := remote.Mkdir(ctx, "")
 		require.NoError(t, err)
 		fstest.CheckListing(t, remote, []fstest.Item{})
 
-		err = remote.Mkdir("")
+		err = remote.Mkdir(ctx, "")
 		require.NoError(t, err)
 	})
 
@@ -300,15 +302,15 @@ func Run(t *testing.T, opt *Opt) {
 	t.Run("TestFsMkdirRmdirSubdir", func(t *testing.T) {
 		skipIfNotOk(t)
 		dir := "dir/subdir"
-		err := operations.Mkdir(remote, dir)
+		err := operations.Mkdir(ctx, remote, dir)
 		require.NoError(t, err)
 		fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{"dir", "dir/subdir"}, fs.GetModifyWindow(remote))
 
-		err = operations.Rmdir(remote, dir)
+		err = operations.Rmdir(ctx, remote, dir)
 		require.NoError(t, err)
 		fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{"dir"}, fs.GetModifyWindow(remote))
 
-		err = operations.Rmdir(remote, "dir")
+		err = operations.Rmdir(ctx, remote, "dir")
 		require.NoError(t, err)
 		fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{}, fs.GetModifyWindow(remote))
 	})
@@ -322,7 +324,7 @@ func Run(t *testing.T, opt *Opt) {
 	// TestFsListDirEmpty tests listing the directories from an empty directory
 	TestFsListDirEmpty := func(t *testing.T) {
 		skipIfNotOk(t)
-		objs, dirs, err := walk.GetAll(remote, "", true, 1)
+		objs, dirs, err := walk.GetAll(ctx, remote, "", true, 1)
 		require.NoError(t, err)
 		assert.Equal(t, []string{}, objsToNames(objs))
 		assert.Equal(t, []string{}, dirsToNames(dirs))
@@ -338,7 +340,7 @@ func Run(t *testing.T, opt *Opt) {
 	// TestFsListDirNotFound tests listing the directories from an empty directory
 	TestFsListDirNotFound := func(t *testing.T) {
 		skipIfNotOk(t)
-		objs, dirs, err := walk.GetAll(remote, "does not exist", true, 1)
+		objs, dirs, err := walk.GetAll(ctx, remote, "does not exist", true, 1)
 		if !remote.Features().CanHaveEmptyDirectories {
 			if err != fs.ErrorDirNotFound {
 				assert.NoError(t, err)
@@ -360,11 +362,11 @@ func Run(t *testing.T, opt *Opt) {
 	t.Run("TestFsNewObjectNotFound", func(t *testing.T) {
 		skipIfNotOk(t)
 		// Object in an existing directory
-		o, err := remote.NewObject("potato")
+		o, err := remote.NewObject(ctx, "potato")
 		assert.Nil(t, o)
 		assert.Equal(t, fs.ErrorObjectNotFound, err)
 		// Now try an object in a non existing directory
-		o, err = remote.NewObject("directory/not/found/potato")
+		o, err = remote.NewObject(ctx, "directory/not/found/potato")
 		assert.Nil(t, o)
 		assert.Equal(t, fs.ErrorObjectNotFound, err)
 	})
@@ -372,7 +374,7 @@ func Run(t *testing.T, opt *Opt) {
 	// TestFsPutFile1 tests putting a file
 	t.Run("TestFsPutFile1", func(t *testing.T) {
 		skipIfNotOk(t)
-		file1Contents = testPut(t, remote, &file1)
+		file1Contents = testPut(ctx, t, remote, &file1)
 	})
 
 	// TestFsPutError tests uploading a file where there is an error
@@ -389,11 +391,11 @@ func Run(t *testing.T, opt *Opt) {
 		in := io.MultiReader(buf, er)
 
 		obji := object.NewStaticObjectInfo(file2.Path, file2.ModTime, 100, true, nil, nil)
-		_, err := remote.Put(in, obji)
+		_, err := remote.Put(ctx, in, obji)
 		// assert.Nil(t, obj) - FIXME some remotes return the object even on nil
 		assert.NotNil(t, err)
 
-		obj, err := remote.NewObject(file2.Path)
+		obj, err := remote.NewObject(ctx, file2.Path)
 		assert.Nil(t, obj)
 		assert.Equal(t, fs.ErrorObjectNotFound, err)
 	})
@@ -401,13 +403,13 @@ func Run(t *testing.T, opt *Opt) {
 	// TestFsPutFile2 tests putting a file into a subdirectory
 	t.Run("TestFsPutFile2", func(t *testing.T) {
 		skipIfNotOk(t)
-		/* file2Contents = */ testPut(t, remote, &file2)
+		/* file2Contents = */ testPut(ctx, t, remote, &file2)
 	})
 
 	// TestFsUpdateFile1 tests updating file1 with new contents
 	t.Run("TestFsUpdateFile1", func(t *testing.T) {
 		skipIfNotOk(t)
-		file1Contents = testPut(t, remote, &file1)
+		file1Contents = testPut(ctx, t, remote, &file1)
 		// Note that the next test will check there are no duplicated file names
 	})
 
@@ -418,9 +420,9 @@ func Run(t *testing.T, opt *Opt) {
 		list := func(dir string, expectedDirNames, expectedObjNames []string) {
 			var objNames, dirNames []string
 			for i := 1; i <= *fstest.ListRetries; i++ {
-				objs, dirs, err := walk.GetAll(remote, dir, true, 1)
+				objs, dirs, err := walk.GetAll(ctx, remote, dir, true, 1)
 				if errors.Cause(err) == fs.ErrorDirNotFound {
-					objs, dirs, err = walk.GetAll(remote, fstest.WinPath(dir), true, 1)
+					objs, dirs, err = walk.GetAll(ctx, remote, fstest.WinPath(dir), true, 1)
 				}
 				require.NoError(t, err)
 				objNames = objsToNames(objs)
@@ -468,7 +470,7 @@ func Run(t *testing.T, opt *Opt) {
 		skipIfNotOk(t)
 		rootRemote, err := fs.NewFs(remoteName)
 		require.NoError(t, err)
-		_, dirs, err := walk.GetAll(rootRemote, "", true, 1)
+		_, dirs, err := walk.GetAll(ctx, rootRemote, "", true, 1)
 		require.NoError(t, err)
 		assert.Contains(t, dirsToNames(dirs), subRemoteLeaf, "Remote leaf not found")
 	}
@@ -490,7 +492,7 @@ func Run(t *testing.T, opt *Opt) {
 		for i := 0; i < 2; i++ {
 			dir, _ := path.Split(fileName)
 			dir = dir[:len(dir)-1]
-			objs, dirs, err = walk.GetAll(remote, dir, true, -1)
+			objs, dirs, err = walk.GetAll(ctx, remote, dir, true, -1)
 			if err != fs.ErrorDirNotFound {
 				break
 			}
@@ -512,7 +514,7 @@ func Run(t *testing.T, opt *Opt) {
 	// TestFsListLevel2 tests List works for 2 levels
 	TestFsListLevel2 := func(t *testing.T) {
 		skipIfNotOk(t)
-		objs, dirs, err := walk.GetAll(remote, "", true, 2)
+		objs, dirs, err := walk.GetAll(ctx, remote, "", true, 2)
 		if err == fs.ErrorLevelNotSupported {
 			return
 		}
@@ -537,7 +539,7 @@ func Run(t *testing.T, opt *Opt) {
 	// TestFsNewObject tests NewObject
 	t.Run("TestFsNewObject", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		file1.Check(t, obj, remote.Precision())
 	})
 
@@ -551,7 +553,7 @@ func Run(t *testing.T, opt *Opt) {
 	t.Run("TestFsNewObjectDir", func(t *testing.T) {
 		skipIfNotOk(t)
 		dir := path.Dir(file2.Path)
-		obj, err := remote.NewObject(dir)
+		obj, err := remote.NewObject(ctx, dir)
 		assert.Nil(t, obj)
 		assert.NotNil(t, err)
 	})
@@ -571,8 +573,8 @@ func Run(t *testing.T, opt *Opt) {
 		file2Copy.Path += "-copy"
 
 		// do the copy
-		src := findObject(t, remote, file2.Path)
-		dst, err := doCopy(src, file2Copy.Path)
+		src := findObject(ctx, t, remote, file2.Path)
+		dst, err := doCopy(ctx, src, file2Copy.Path)
 		if err == fs.ErrorCantCopy {
 			t.Skip("FS can't copy")
 		}
@@ -583,9 +585,10 @@ func Run(t *testing.T, opt *Opt) {
 
 		// Check dst lightly - list above has checked ModTime/Hashes
 		assert.Equal(t, file2Copy.Path, dst.Remote())
+		assert.Equal(t, readObject(ctx, t, src, -1), readObject(ctx, t, dst, -1))
 
 		// Delete copy
-		err = dst.Remove()
+		err = dst.Remove(ctx)
 		require.NoError(t, err)
 
 	})
@@ -611,8 +614,8 @@ func Run(t *testing.T, opt *Opt) {
 		// separate operations
 		file2Move.Path = "other.txt"
 		file2Move.WinPath = ""
-		src := findObject(t, remote, file2.Path)
-		dst, err := doMove(src, file2Move.Path)
+		src := findObject(ctx, t, remote, file2.Path)
+		dst, err := doMove(ctx, src, file2Move.Path)
 		if err == fs.ErrorCantMove {
 			t.Skip("FS can't move")
 		}
@@ -626,30 +629,30 @@ func Run(t *testing.T, opt *Opt) {
 
 		// Check conflict on "rename, then move"
 		file1Move.Path = "moveTest/other.txt"
-		src = findObject(t, remote, file1.Path)
-		_, err = doMove(src, file1Move.Path)
+		src = findObject(ctx, t, remote, file1.Path)
+		_, err = doMove(ctx, src, file1Move.Path)
 		require.NoError(t, err)
 		fstest.CheckListing(t, remote, []fstest.Item{file1Move, file2Move})
 		// 1: moveTest/other.txt
 		// 2: other.txt
 
 		// Check conflict on "move, then rename"
-		src = findObject(t, remote, file1Move.Path)
-		_, err = doMove(src, file1.Path)
+		src = findObject(ctx, t, remote, file1Move.Path)
+		_, err = doMove(ctx, src, file1.Path)
 		require.NoError(t, err)
 		fstest.CheckListing(t, remote, []fstest.Item{file1, file2Move})
 		// 1: file name.txt
 		// 2: other.txt
 
-		src = findObject(t, remote, file2Move.Path)
-		_, err = doMove(src, file2.Path)
+		src = findObject(ctx, t, remote, file2Move.Path)
+		_, err = doMove(ctx, src, file2.Path)
 		require.NoError(t, err)
 		fstest.CheckListing(t, remote, []fstest.Item{file1, file2})
 		// 1: file name.txt
 		// 2: hello sausage?/../z.txt
 
 		// Tidy up moveTest directory
-		require.NoError(t, remote.Rmdir("moveTest"))
+		require.NoError(t, remote.Rmdir(ctx, "moveTest"))
 	})
 
 	// Move src to this remote using server side move operations.
@@ -673,7 +676,7 @@ func Run(t *testing.T, opt *Opt) {
 		}
 
 		// Check it can't move onto itself
-		err := doDirMove(remote, "", "")
+		err := doDirMove(ctx, remote, "", "")
 		require.Equal(t, fs.ErrorDirExists, err)
 
 		// new remote
@@ -683,12 +686,12 @@ func Run(t *testing.T, opt *Opt) {
 
 		const newName = "new_name/sub_new_name"
 		// try the move
-		err = newRemote.Features().DirMove(remote, "", newName)
+		err = newRemote.Features().DirMove(ctx, remote, "", newName)
 		require.NoError(t, err)
 
 		// check remotes
 		// remote should not exist here
-		_, err = remote.List("")
+		_, err = remote.List(ctx, "")
 		assert.Equal(t, fs.ErrorDirNotFound, errors.Cause(err))
 		//fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{}, remote.Precision())
 		file1Copy := file1
@@ -706,7 +709,7 @@ func Run(t *testing.T, opt *Opt) {
 		}, newRemote.Precision())
 
 		// move it back
-		err = doDirMove(newRemote, newName, "")
+		err = doDirMove(ctx, newRemote, newName, "")
 		require.NoError(t, err)
 
 		// check remotes
@@ -724,7 +727,7 @@ func Run(t *testing.T, opt *Opt) {
 	// TestFsRmdirFull tests removing a non empty directory
 	t.Run("TestFsRmdirFull", func(t *testing.T) {
 		skipIfNotOk(t)
-		err := remote.Rmdir("")
+		err := remote.Rmdir(ctx, "")
 		require.Error(t, err, "Expecting error on RMdir on non empty remote")
 	})
 
@@ -754,7 +757,7 @@ func Run(t *testing.T, opt *Opt) {
 			t.Skip("FS has no ChangeNotify interface")
 		}
 
-		err := operations.Mkdir(remote, "dir")
+		err := operations.Mkdir(ctx, remote, "dir")
 		require.NoError(t, err)
 
 		dirChanges := []string{}
@@ -776,7 +779,7 @@ func Run(t *testing.T, opt *Opt) {
 		var dirs []string
 		for _, idx := range []int{1, 3, 2} {
 			dir := fmt.Sprintf("dir/subdir%d", idx)
-			err = operations.Mkdir(remote, dir)
+			err = operations.Mkdir(ctx, remote, dir)
 			require.NoError(t, err)
 			dirs = append(dirs, dir)
 		}
@@ -787,7 +790,7 @@ func Run(t *testing.T, opt *Opt) {
 		var objs []fs.Object
 		for _, idx := range []int{2, 4, 3} {
 			obji := object.NewStaticObjectInfo(fmt.Sprintf("dir/file%d", idx), time.Now(), int64(buf.Len()), true, nil, nil)
-			o, err := remote.Put(buf, obji)
+			o, err := remote.Put(ctx, buf, obji)
 			require.NoError(t, err)
 			objs = append(objs, o)
 		}
@@ -799,18 +802,18 @@ func Run(t *testing.T, opt *Opt) {
 
 		// tidy up afterwards
 		for _, o := range objs {
-			assert.NoError(t, o.Remove())
+			assert.NoError(t, o.Remove(ctx))
 		}
 		dirs = append(dirs, "dir")
 		for _, dir := range dirs {
-			assert.NoError(t, remote.Rmdir(dir))
+			assert.NoError(t, remote.Rmdir(ctx, dir))
 		}
 	})
 
 	// TestObjectString tests the Object String method
 	t.Run("TestObjectString", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		assert.Equal(t, file1.Path, obj.String())
 		assert.Equal(t, "<nil>", opt.NilObject.String())
 	})
@@ -818,7 +821,7 @@ func Run(t *testing.T, opt *Opt) {
 	// TestObjectFs tests the object can be found
 	t.Run("TestObjectFs", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		testRemote := remote
 		if obj.Fs() != testRemote {
 			// Check to see if this wraps something else
@@ -832,21 +835,21 @@ func Run(t *testing.T, opt *Opt) {
 	// TestObjectRemote tests the Remote is correct
 	t.Run("TestObjectRemote", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		assert.Equal(t, file1.Path, obj.Remote())
 	})
 
 	// TestObjectHashes checks all the hashes the object supports
 	t.Run("TestObjectHashes", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		file1.CheckHashes(t, obj)
 	})
 
 	// TestObjectModTime tests the ModTime of the object is correct
 	TestObjectModTime := func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		file1.CheckModTime(t, obj, obj.ModTime(), remote.Precision())
 	}
 	t.Run("TestObjectModTime", TestObjectModTime)
@@ -854,7 +857,7 @@ func Run(t *testing.T, opt *Opt) {
 	// TestObjectMimeType tests the MimeType of the object is correct
 	t.Run("TestObjectMimeType", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		do, ok := obj.(fs.MimeTyper)
 		if !ok {
 			t.Skip("MimeType method not supported")
@@ -871,7 +874,7 @@ func Run(t *testing.T, opt *Opt) {
 	t.Run("TestObjectSetModTime", func(t *testing.T) {
 		skipIfNotOk(t)
 		newModTime := fstest.Time("2011-12-13T14:15:16.999999999Z")
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		err := obj.SetModTime(newModTime)
 		if err == fs.ErrorCantSetModTime || err == fs.ErrorCantSetModTimeWithoutDelete {
 			t.Log(err)
@@ -887,22 +890,22 @@ func Run(t *testing.T, opt *Opt) {
 	// TestObjectSize tests that Size works
 	t.Run("TestObjectSize", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		assert.Equal(t, file1.Size, obj.Size())
 	})
 
 	// TestObjectOpen tests that Open works
 	t.Run("TestObjectOpen", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
-		assert.Equal(t, file1Contents, readObject(t, obj, -1), "contents of file1 differ")
+		obj := findObject(ctx, t, remote, file1.Path)
+		assert.Equal(t, file1Contents, readObject(ctx, t, obj, -1), "contents of file1 differ")
 	})
 
 	// TestObjectOpenSeek tests that Open works with SeekOption
 	t.Run("TestObjectOpenSeek", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
-		assert.Equal(t, file1Contents[50:], readObject(t, obj, -1, &fs.SeekOption{Offset: 50}), "contents of file1 differ after seek")
+		obj := findObject(ctx, t, remote, file1.Path)
+		assert.Equal(t, file1Contents[50:], readObject(ctx, t, obj, -1, &fs.SeekOption{Offset: 50}), "contents of file1 differ after seek")
 	})
 
 	// TestObjectOpenRange tests that Open works with RangeOption
@@ -910,7 +913,7 @@ func Run(t *testing.T, opt *Opt) {
 	// go test -v -run 'TestIntegration/Test(Setup|Init|FsMkdir|FsPutFile1|FsPutFile2|FsUpdateFile1|ObjectOpenRange)$'
 	t.Run("TestObjectOpenRange", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		for _, test := range []struct {
 			ro                 fs.RangeOption
 			wantStart, wantEnd int
@@ -921,7 +924,7 @@ func Run(t *testing.T, opt *Opt) {
 			{fs.RangeOption{Start: -1, End: 20}, 80, 100}, // if start is omitted this means get the final bytes
 			// {fs.RangeOption{Start: -1, End: -1}, 0, 100}, - this seems to work but the RFC doesn't define it
 		} {
-			got := readObject(t, obj, -1, &test.ro)
+			got := readObject(ctx, t, obj, -1, &test.ro)
 			foundAt := strings.Index(file1Contents, got)
 			help := fmt.Sprintf("%#v failed want [%d:%d] got [%d:%d]", test.ro, test.wantStart, test.wantEnd, foundAt, foundAt+len(got))
 			assert.Equal(t, file1Contents[test.wantStart:test.wantEnd], got, help)
@@ -931,8 +934,8 @@ func Run(t *testing.T, opt *Opt) {
 	// TestObjectPartialRead tests that reading only part of the object does the correct thing
 	t.Run("TestObjectPartialRead", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
-		assert.Equal(t, file1Contents[:50], readObject(t, obj, 50), "contents of file1 differ after limited read")
+		obj := findObject(ctx, t, remote, file1.Path)
+		assert.Equal(t, file1Contents[:50], readObject(ctx, t, obj, 50), "contents of file1 differ after limited read")
 	})
 
 	// TestObjectUpdate tests that Update works
@@ -944,9 +947,9 @@ func Run(t *testing.T, opt *Opt) {
 		in := io.TeeReader(buf, hash)
 
 		file1.Size = int64(buf.Len())
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		obji := object.NewStaticObjectInfo(file1.Path, file1.ModTime, int64(len(contents)), true, nil, obj.Fs())
-		err := obj.Update(in, obji)
+		err := obj.Update(ctx, in, obji)
 		require.NoError(t, err)
 		file1.Hashes = hash.Sums()
 
@@ -954,18 +957,18 @@ func Run(t *testing.T, opt *Opt) {
 		file1.Check(t, obj, remote.Precision())
 
 		// Re-read the object and check again
-		obj = findObject(t, remote, file1.Path)
+		obj = findObject(ctx, t, remote, file1.Path)
 		file1.Check(t, obj, remote.Precision())
 
 		// check contents correct
-		assert.Equal(t, contents, readObject(t, obj, -1), "contents of updated file1 differ")
+		assert.Equal(t, contents, readObject(ctx, t, obj, -1), "contents of updated file1 differ")
 		file1Contents = contents
 	})
 
 	// TestObjectStorable tests that Storable works
 	t.Run("TestObjectStorable", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
+		obj := findObject(ctx, t, remote, file1.Path)
 		require.NotNil(t, !obj.Storable(), "Expecting object to be storable")
 	})
 
@@ -1040,7 +1043,7 @@ func Run(t *testing.T, opt *Opt) {
 		// ensure sub remote isn't empty
 		buf := bytes.NewBufferString("somecontent")
 		obji := object.NewStaticObjectInfo("somefile", time.Now(), int64(buf.Len()), true, nil, nil)
-		_, err = subRemote.Put(buf, obji)
+		_, err = subRemote.Put(ctx, buf, obji)
 		require.NoError(t, err)
 
 		link4, err := subRemote.Features().PublicLink("")
@@ -1051,8 +1054,8 @@ func Run(t *testing.T, opt *Opt) {
 	// TestObjectRemove tests Remove
 	t.Run("TestObjectRemove", func(t *testing.T) {
 		skipIfNotOk(t)
-		obj := findObject(t, remote, file1.Path)
-		err := obj.Remove()
+		obj := findObject(ctx, t, remote, file1.Path)
+		err := obj.Remove(ctx)
 		require.NoError(t, err)
 		// check listing without modtime as TestPublicLink may change the modtime
 		fstest.CheckListingWithPrecision(t, remote, []fstest.Item{file2}, nil, fs.ModTimeNotSupported)
@@ -1082,7 +1085,7 @@ func Run(t *testing.T, opt *Opt) {
 
 		file.Size = -1
 		obji := object.NewStaticObjectInfo(file.Path, file.ModTime, file.Size, true, nil, nil)
-		obj, err := remote.Features().PutStream(in, obji)
+		obj, err := remote.Features().PutStream(ctx, in, obji)
 		if err != nil {
 			// Retry if err returned a retry error
 			if fserrors.IsRetryError(err) && tries < maxTries {
@@ -1098,7 +1101,7 @@ func Run(t *testing.T, opt *Opt) {
 		file.Size = int64(contentSize) // use correct size when checking
 		file.Check(t, obj, remote.Precision())
 		// Re-read the object and check again
-		obj = findObject(t, remote, file.Path)
+		obj = findObject(ctx, t, remote, file.Path)
 		file.Check(t, obj, remote.Precision())
 	})
 
@@ -1123,11 +1126,11 @@ func Run(t *testing.T, opt *Opt) {
 	t.Run("TestObjectPurge", func(t *testing.T) {
 		skipIfNotOk(t)
 
-		err := operations.Purge(remote, "")
+		err := operations.Purge(ctx, remote, "")
 		require.NoError(t, err)
 		fstest.CheckListing(t, remote, []fstest.Item{})
 
-		err = operations.Purge(remote, "")
+		err = operations.Purge(ctx, remote, "")
 		assert.Error(t, err, "Expecting error after on second purge")
 	})
 
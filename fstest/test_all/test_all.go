@@ -5,6 +5,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"go/build"
 	"log"
@@ -275,11 +276,12 @@ func (t *test) trial() {
 
 // cleanFs runs a single clean fs for left over directories
 func (t *test) cleanFs() error {
+	ctx := context.Background()
 	f, err := fs.NewFs(t.remote)
 	if err != nil {
 		return err
 	}
-	entries, err := list.DirSorted(f, true, "")
+	entries, err := list.DirSorted(ctx, f, true, "")
 	if err != nil {
 		return err
 	}
@@ -291,7 +293,7 @@ func (t *test) cleanFs() error {
 			if err != nil {
 				return err
 			}
-			return operations.Purge(dir, "")
+			return operations.Purge(ctx, dir, "")
 		}
 		return nil
 	})
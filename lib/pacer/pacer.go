@@ -333,6 +333,10 @@ func (p *Pacer) call(fn Paced, retries int) (err error) {
 	for i := 1; i <= retries; i++ {
 		p.beginCall()
 		retry, err = fn()
+		if retry && fserrors.IsContextError(err) {
+			// Don't retry if the context was cancelled
+			retry = false
+		}
 		p.endCall(retry)
 		if !retry {
 			break
@@ -1,6 +1,7 @@
 package pacer
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -435,3 +436,19 @@ func TestCallNoRetry(t *testing.T) {
 		t.Errorf("didn't return a retry error")
 	}
 }
+
+func TestCallContextCancelled(t *testing.T) {
+	p := New().SetMinSleep(time.Millisecond).SetMaxSleep(2 * time.Millisecond).SetRetries(20)
+
+	called := 0
+	err := p.Call(func() (bool, error) {
+		called++
+		return true, context.Canceled
+	})
+	if called != 1 {
+		t.Errorf("called want %d got %d", 1, called)
+	}
+	if err != context.Canceled {
+		t.Errorf("want %v got %v", context.Canceled, err)
+	}
+}
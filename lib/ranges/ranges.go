@@ -0,0 +1,170 @@
+// Package ranges provides the Ranges type for keeping track of byte
+// ranges which may or may not be present in an object.
+package ranges
+
+import (
+	"sort"
+)
+
+// Range describes a single byte range
+type Range struct {
+	Pos  int64 // offset of the start of the range
+	Size int64 // number of bytes in the range
+}
+
+// End returns the end of the Range (one past the last byte)
+func (r Range) End() int64 {
+	return r.Pos + r.Size
+}
+
+// IsEmpty returns true if the Range has no bytes in it
+func (r Range) IsEmpty() bool {
+	return r.Size <= 0
+}
+
+// Clip ensures r.End() <= offset by modifying r.Size if necessary
+//
+// if r.Pos > offset then a Range{Pos:0, Size:0} will be returned.
+func (r *Range) Clip(offset int64) {
+	if r.End() <= offset {
+		return
+	}
+	r.Size -= r.End() - offset
+	if r.Size < 0 {
+		r.Pos = 0
+		r.Size = 0
+	}
+}
+
+// Intersection returns the common Range for two Ranges
+//
+// If there is no intersection then the Range returned will have
+// IsEmpty() true
+func (r Range) Intersection(b Range) (intersection Range) {
+	if (r.Pos >= b.Pos && r.Pos < b.End()) || (b.Pos >= r.Pos && b.Pos < r.End()) {
+		intersection.Pos = r.Pos
+		if b.Pos > intersection.Pos {
+			intersection.Pos = b.Pos
+		}
+		end := r.End()
+		if b.End() < end {
+			end = b.End()
+		}
+		intersection.Size = end - intersection.Pos
+	}
+	return
+}
+
+// Ranges describes a number of Range segments. These should only be
+// added with the Insert function so they are kept sorted and
+// coalesced.
+type Ranges []Range
+
+// merge the Ranges in rs at i and i+1 if they overlap or touch,
+// returning true if they were merged
+func (rs *Ranges) merge(i int) bool {
+	left, right := &(*rs)[i], (*rs)[i+1]
+	if left.End() < right.Pos {
+		return false
+	}
+	if right.End() > left.End() {
+		left.Size = right.End() - left.Pos
+	}
+	*rs = append((*rs)[:i+1], (*rs)[i+2:]...)
+	return true
+}
+
+// Insert the new Range into a sorted and coalesced slice of Ranges.
+// The result will be sorted and coalesced.
+func (rs *Ranges) Insert(r Range) {
+	if r.IsEmpty() {
+		return
+	}
+	// Find the first Range which starts after r and insert r
+	// before it
+	i := sort.Search(len(*rs), func(i int) bool {
+		return (*rs)[i].Pos > r.Pos
+	})
+	*rs = append(*rs, Range{})
+	copy((*rs)[i+1:], (*rs)[i:])
+	(*rs)[i] = r
+	// Merge with the one before if possible
+	if i > 0 && rs.merge(i-1) {
+		i--
+	}
+	// Merge with any following which now overlap
+	for i+1 < len(*rs) && rs.merge(i) {
+	}
+}
+
+// Present returns whether r can be satisfied by rs, ie whether all
+// of r is contained in rs
+func (rs Ranges) Present(r Range) bool {
+	if r.IsEmpty() {
+		return true
+	}
+	for _, cur := range rs {
+		if cur.Pos <= r.Pos && cur.End() >= r.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing returns the parts of r which aren't contained in rs, in
+// order
+func (rs Ranges) Missing(r Range) (missing Ranges) {
+	pos, end := r.Pos, r.End()
+	for _, cur := range rs {
+		if pos >= end {
+			break
+		}
+		if cur.End() <= pos {
+			continue
+		}
+		if cur.Pos >= end {
+			break
+		}
+		if cur.Pos > pos {
+			missing = append(missing, Range{Pos: pos, Size: cur.Pos - pos})
+		}
+		pos = cur.End()
+	}
+	if pos < end {
+		missing = append(missing, Range{Pos: pos, Size: end - pos})
+	}
+	return missing
+}
+
+// Size returns the total number of bytes in rs
+func (rs Ranges) Size() (size int64) {
+	for _, r := range rs {
+		size += r.Size
+	}
+	return size
+}
+
+// Truncate removes any parts of rs at or beyond offset
+func (rs *Ranges) Truncate(offset int64) {
+	for i := range *rs {
+		r := &(*rs)[i]
+		if r.Pos >= offset {
+			*rs = (*rs)[:i]
+			return
+		}
+		r.Clip(offset)
+	}
+}
+
+// Equal returns true if rs == bs
+func (rs Ranges) Equal(bs Ranges) bool {
+	if len(rs) != len(bs) {
+		return false
+	}
+	for i := range rs {
+		if rs[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package ranges
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeEnd(t *testing.T) {
+	assert.Equal(t, int64(3), Range{Pos: 1, Size: 2}.End())
+	assert.Equal(t, int64(1), Range{Pos: 1, Size: 0}.End())
+}
+
+func TestRangeIsEmpty(t *testing.T) {
+	assert.True(t, Range{Pos: 1, Size: 0}.IsEmpty())
+	assert.True(t, Range{Pos: 1, Size: -1}.IsEmpty())
+	assert.False(t, Range{Pos: 1, Size: 1}.IsEmpty())
+}
+
+func TestRangeClip(t *testing.T) {
+	r := Range{Pos: 1, Size: 10}
+	r.Clip(5)
+	assert.Equal(t, Range{Pos: 1, Size: 4}, r)
+	r = Range{Pos: 1, Size: 10}
+	r.Clip(20)
+	assert.Equal(t, Range{Pos: 1, Size: 10}, r)
+	r = Range{Pos: 10, Size: 10}
+	r.Clip(5)
+	assert.Equal(t, Range{Pos: 0, Size: 0}, r)
+}
+
+func TestRangeIntersection(t *testing.T) {
+	for _, test := range []struct {
+		r    Range
+		b    Range
+		want Range
+	}{
+		{Range{1, 1}, Range{3, 1}, Range{}},
+		{Range{1, 1}, Range{1, 1}, Range{1, 1}},
+		{Range{1, 9}, Range{3, 2}, Range{3, 2}},
+		{Range{1, 5}, Range{3, 5}, Range{3, 3}},
+		{Range{3, 5}, Range{1, 5}, Range{3, 3}},
+		{Range{1, 5}, Range{6, 5}, Range{}},
+	} {
+		what := fmt.Sprintf("test r=%v, b=%v", test.r, test.b)
+		assert.Equal(t, test.want, test.r.Intersection(test.b), what)
+	}
+}
+
+func TestRangesInsert(t *testing.T) {
+	for _, test := range []struct {
+		new  Range
+		rs   Ranges
+		want Ranges
+	}{
+		{
+			new:  Range{Pos: 1, Size: 0},
+			rs:   Ranges{},
+			want: Ranges(nil),
+		},
+		{
+			new:  Range{Pos: 1, Size: 1},
+			rs:   Ranges{},
+			want: Ranges{{Pos: 1, Size: 1}},
+		},
+		{
+			new:  Range{Pos: 1, Size: 1},
+			rs:   Ranges{{Pos: 5, Size: 1}},
+			want: Ranges{{Pos: 1, Size: 1}, {Pos: 5, Size: 1}},
+		},
+		{
+			new:  Range{Pos: 5, Size: 1},
+			rs:   Ranges{{Pos: 1, Size: 1}},
+			want: Ranges{{Pos: 1, Size: 1}, {Pos: 5, Size: 1}},
+		},
+		{
+			new:  Range{Pos: 2, Size: 1},
+			rs:   Ranges{{Pos: 1, Size: 1}},
+			want: Ranges{{Pos: 1, Size: 2}},
+		},
+		{
+			new:  Range{Pos: 1, Size: 1},
+			rs:   Ranges{{Pos: 2, Size: 1}},
+			want: Ranges{{Pos: 1, Size: 2}},
+		},
+		{
+			new:  Range{Pos: 2, Size: 3},
+			rs:   Ranges{{Pos: 1, Size: 1}, {Pos: 5, Size: 1}, {Pos: 10, Size: 1}},
+			want: Ranges{{Pos: 1, Size: 5}, {Pos: 10, Size: 1}},
+		},
+		{
+			new:  Range{Pos: 0, Size: 100},
+			rs:   Ranges{{Pos: 1, Size: 1}, {Pos: 5, Size: 1}, {Pos: 10, Size: 1}},
+			want: Ranges{{Pos: 0, Size: 100}},
+		},
+		{
+			new:  Range{Pos: 3, Size: 1},
+			rs:   Ranges{{Pos: 1, Size: 10}},
+			want: Ranges{{Pos: 1, Size: 10}},
+		},
+	} {
+		got := append(Ranges(nil), test.rs...)
+		got.Insert(test.new)
+		what := fmt.Sprintf("test new=%v, rs=%v", test.new, test.rs)
+		assert.Equal(t, test.want, got, what)
+	}
+}
+
+func TestRangesPresent(t *testing.T) {
+	rs := Ranges{{Pos: 1, Size: 5}, {Pos: 10, Size: 5}}
+	assert.True(t, rs.Present(Range{Pos: 1, Size: 5}))
+	assert.True(t, rs.Present(Range{Pos: 2, Size: 2}))
+	assert.True(t, rs.Present(Range{Pos: 100, Size: 0}))
+	assert.False(t, rs.Present(Range{Pos: 0, Size: 2}))
+	assert.False(t, rs.Present(Range{Pos: 5, Size: 6}))
+	assert.False(t, rs.Present(Range{Pos: 14, Size: 2}))
+}
+
+func TestRangesMissing(t *testing.T) {
+	rs := Ranges{{Pos: 1, Size: 5}, {Pos: 10, Size: 5}}
+	for _, test := range []struct {
+		r    Range
+		want Ranges
+	}{
+		{Range{Pos: 1, Size: 5}, Ranges(nil)},
+		{Range{Pos: 0, Size: 1}, Ranges{{Pos: 0, Size: 1}}},
+		{Range{Pos: 0, Size: 3}, Ranges{{Pos: 0, Size: 1}}},
+		{Range{Pos: 4, Size: 8}, Ranges{{Pos: 6, Size: 4}}},
+		{Range{Pos: 0, Size: 20}, Ranges{{Pos: 0, Size: 1}, {Pos: 6, Size: 4}, {Pos: 15, Size: 5}}},
+		{Range{Pos: 30, Size: 5}, Ranges{{Pos: 30, Size: 5}}},
+		{Range{Pos: 3, Size: 0}, Ranges(nil)},
+	} {
+		assert.Equal(t, test.want, rs.Missing(test.r), fmt.Sprintf("r=%v", test.r))
+	}
+	assert.Equal(t, Ranges{{Pos: 2, Size: 3}}, Ranges(nil).Missing(Range{Pos: 2, Size: 3}))
+}
+
+func TestRangesSize(t *testing.T) {
+	assert.Equal(t, int64(0), Ranges(nil).Size())
+	assert.Equal(t, int64(10), Ranges{{Pos: 1, Size: 5}, {Pos: 10, Size: 5}}.Size())
+}
+
+func TestRangesTruncate(t *testing.T) {
+	for _, test := range []struct {
+		offset int64
+		want   Ranges
+	}{
+		{100, Ranges{{Pos: 1, Size: 5}, {Pos: 10, Size: 5}}},
+		{12, Ranges{{Pos: 1, Size: 5}, {Pos: 10, Size: 2}}},
+		{8, Ranges{{Pos: 1, Size: 5}}},
+		{3, Ranges{{Pos: 1, Size: 2}}},
+		{1, Ranges{}},
+		{0, Ranges{}},
+	} {
+		rs := Ranges{{Pos: 1, Size: 5}, {Pos: 10, Size: 5}}
+		rs.Truncate(test.offset)
+		assert.Equal(t, test.want, rs, fmt.Sprintf("offset=%d", test.offset))
+	}
+}
+
+func TestRangesEqual(t *testing.T) {
+	assert.True(t, Ranges(nil).Equal(Ranges{}))
+	assert.True(t, Ranges{{1, 2}}.Equal(Ranges{{1, 2}}))
+	assert.False(t, Ranges{{1, 2}}.Equal(Ranges{{1, 3}}))
+	assert.False(t, Ranges{{1, 2}}.Equal(Ranges{{1, 2}, {4, 1}}))
+}
@@ -0,0 +1,55 @@
+// Package version provides machinery for versioning file names
+// with a timestamp-based version string
+package version
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+const versionFormat = "-v2006-01-02-150405.000"
+
+// Add returns fileName modified to include t as the version
+//
+// eg "potato.txt" becomes "potato-v2001-02-03-040506-123.txt"
+func Add(fileName string, t time.Time) string {
+	ext := path.Ext(fileName)
+	base := fileName[:len(fileName)-len(ext)]
+	s := t.Format(versionFormat)
+	// Replace the '.' with a '-'
+	s = strings.Replace(s, ".", "-", -1)
+	return base + s + ext
+}
+
+// Remove returns a modified fileName without the version string
+// and the time it represented
+//
+// If the fileName did not have a version then time.Time{} is
+// returned along with an unmodified fileName
+func Remove(fileName string) (t time.Time, fileNameWithoutVersion string) {
+	fileNameWithoutVersion = fileName
+	ext := path.Ext(fileName)
+	base := fileName[:len(fileName)-len(ext)]
+	if len(base) < len(versionFormat) {
+		return
+	}
+	versionStart := len(base) - len(versionFormat)
+	// Check it ends in -xxx
+	if base[len(base)-4] != '-' {
+		return
+	}
+	// Replace with .xxx for parsing
+	base = base[:len(base)-4] + "." + base[len(base)-3:]
+	newT, err := time.Parse(versionFormat, base[versionStart:])
+	if err != nil {
+		return
+	}
+	return newT, base[:versionStart] + ext
+}
+
+// Match returns true if the fileName has a version string
+func Match(fileName string) bool {
+	t, _ := Remove(fileName)
+	return !t.IsZero()
+}
@@ -4,7 +4,9 @@ package vfs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -17,6 +19,7 @@ import (
 	"github.com/djherbis/times"
 	"github.com/artpar/rclone/fs"
 	"github.com/artpar/rclone/fs/config"
+	"github.com/artpar/rclone/lib/ranges"
 	"github.com/pkg/errors"
 )
 
@@ -64,18 +67,34 @@ func (l *CacheMode) Type() string {
 
 // cache opened files
 type cache struct {
-	f      fs.Fs                 // fs for the cache directory
-	opt    *Options              // vfs Options
-	root   string                // root of the cache directory
-	itemMu sync.Mutex            // protects the next two maps
-	item   map[string]*cacheItem // files/directories in the cache
+	f         fs.Fs                 // fs for the cache directory
+	fremote   fs.Fs                 // fs the cache is for
+	opt       *Options              // vfs Options
+	root      string                // root of the cache directory
+	metaRoot  string                // root of the cache metadata directory
+	itemMu    sync.Mutex            // protects the next two maps
+	item      map[string]*cacheItem // files/directories in the cache
+	writeback *writeBack            // files waiting to be uploaded
 }
 
 // cacheItem is stored in the item map
 type cacheItem struct {
-	opens  int       // number of times file is open
-	atime  time.Time // last time file was accessed
-	isFile bool      // if this is a file or a directory
+	opens   int           // number of times file is open
+	atime   time.Time     // last time file was accessed
+	isFile  bool          // if this is a file or a directory
+	size    int64         // bytes of the file stored in the cache
+	hasInfo bool          // set if info is valid
+	info    cacheItemInfo // persistent info about the file
+}
+
+// cacheItemInfo is the metadata about a cached file which is
+// persisted in the metadata directory so it survives restarts
+type cacheItemInfo struct {
+	ATime       time.Time     // last time the file was accessed
+	Size        int64         // size of the remote object the cache file is a copy of
+	Fingerprint string        // fingerprint of the remote object
+	Rs          ranges.Ranges // which parts of the file are present in the cache
+	Dirty       bool          // set if the file needs uploading to the remote
 }
 
 // newCacheItem returns an item for the cache
@@ -83,6 +102,25 @@ func newCacheItem(isFile bool) *cacheItem {
 	return &cacheItem{atime: time.Now(), isFile: isFile}
 }
 
+// cachedSize returns the number of bytes of the item stored on disk
+func (item *cacheItem) cachedSize() int64 {
+	if item.hasInfo {
+		return item.info.Rs.Size()
+	}
+	return item.size
+}
+
+// dirty returns whether the item needs uploading to the remote
+func (item *cacheItem) dirty() bool {
+	return item.hasInfo && item.info.Dirty
+}
+
+// objectFingerprint returns a string which changes if the contents
+// of the object change
+func objectFingerprint(o fs.Object) string {
+	return fmt.Sprintf("%d,%s", o.Size(), o.ModTime().UTC().Format(time.RFC3339Nano))
+}
+
 // newCache creates a new cache heirachy for f
 //
 // This starts background goroutines which can be cancelled with the
@@ -97,20 +135,32 @@ func newCache(ctx context.Context, f fs.Fs, opt *Options) (*cache, error) {
 	}
 	root := filepath.Join(config.CacheDir, "vfs", f.Name(), fRoot)
 	fs.Debugf(nil, "vfs cache root is %q", root)
+	metaRoot := filepath.Join(config.CacheDir, "vfsMeta", f.Name(), fRoot)
+	fs.Debugf(nil, "vfs metadata cache root is %q", metaRoot)
 
-	f, err := fs.NewFs(root)
+	fcache, err := fs.NewFs(root)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create cache remote")
 	}
 
 	c := &cache{
-		f:    f,
-		opt:  opt,
-		root: root,
-		item: make(map[string]*cacheItem),
+		f:        fcache,
+		fremote:  f,
+		opt:      opt,
+		root:     root,
+		metaRoot: metaRoot,
+		item:     make(map[string]*cacheItem),
+	}
+	c.writeback = newWriteBack(c)
+
+	// Queue any uploads which didn't finish last time
+	err = c.queueDirty()
+	if err != nil {
+		fs.Errorf(nil, "Failed to find files waiting to upload in cache %q: %v", metaRoot, err)
 	}
 
 	go c.cleaner(ctx)
+	go c.writeback.run(ctx)
 
 	return c, nil
 }
@@ -139,6 +189,12 @@ func (c *cache) toOSPath(name string) string {
 	return filepath.Join(c.root, filepath.FromSlash(name))
 }
 
+// toOSPathMeta turns a remote relative name into an OS path in the
+// cache metadata directory
+func (c *cache) toOSPathMeta(name string) string {
+	return filepath.Join(c.metaRoot, filepath.FromSlash(name))
+}
+
 // mkdir makes the directory for name in the cache and returns an os
 // path for the file
 func (c *cache) mkdir(name string) (string, error) {
@@ -167,10 +223,249 @@ func (c *cache) _get(isFile bool, name string) (item *cacheItem, found bool) {
 	if !found {
 		item = newCacheItem(isFile)
 		c.item[name] = item
+		if isFile {
+			c._loadInfo(name, item)
+		}
 	}
 	return item, found
 }
 
+// _loadInfo reads the persisted info for name into item if it exists
+//
+// must be called with itemMu held
+func (c *cache) _loadInfo(name string, item *cacheItem) {
+	data, err := ioutil.ReadFile(c.toOSPathMeta(name))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fs.Errorf(name, "Failed to read cache metadata: %v", err)
+		}
+		return
+	}
+	var info cacheItemInfo
+	err = json.Unmarshal(data, &info)
+	if err != nil {
+		fs.Errorf(name, "Failed to decode cache metadata - ignoring: %v", err)
+		return
+	}
+	item.info = info
+	item.hasInfo = true
+	if info.ATime.After(item.atime) {
+		item.atime = info.ATime
+	}
+}
+
+// _saveInfo persists the info for the item to disk
+//
+// must be called with itemMu held
+func (c *cache) _saveInfo(name string, item *cacheItem) error {
+	item.info.ATime = item.atime
+	data, err := json.Marshal(&item.info)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode cache metadata")
+	}
+	osPath := c.toOSPathMeta(name)
+	err = os.MkdirAll(filepath.Dir(osPath), 0700)
+	if err != nil {
+		return errors.Wrap(err, "failed to make cache metadata directory")
+	}
+	err = ioutil.WriteFile(osPath, data, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to write cache metadata")
+	}
+	return nil
+}
+
+// saveInfo persists the info for name to disk
+//
+// name should be a remote path not an osPath
+func (c *cache) saveInfo(name string) {
+	name = clean(name)
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	item, _ := c._get(true, name)
+	if !item.hasInfo {
+		return
+	}
+	err := c._saveInfo(name, item)
+	if err != nil {
+		fs.Errorf(name, "%v", err)
+	}
+}
+
+// setObject resets the info about name so it describes a cache file
+// for the object o with nothing downloaded yet.  Pass o == nil for a
+// file which doesn't exist on the remote.
+//
+// name should be a remote path not an osPath
+func (c *cache) setObject(name string, o fs.Object) {
+	name = clean(name)
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	item, _ := c._get(true, name)
+	item.hasInfo = true
+	item.info.Rs = nil
+	item.info.Size = 0
+	item.info.Fingerprint = ""
+	if o != nil {
+		item.info.Size = o.Size()
+		item.info.Fingerprint = objectFingerprint(o)
+	}
+}
+
+// setUploaded marks the whole of name as present in the cache and
+// records that it is now a copy of the object o which has just been
+// uploaded
+//
+// name should be a remote path not an osPath
+func (c *cache) setUploaded(name string, o fs.Object) {
+	name = clean(name)
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	item, _ := c._get(true, name)
+	item.hasInfo = true
+	item.info.Size = o.Size()
+	item.info.Fingerprint = objectFingerprint(o)
+	item.info.Rs = ranges.Ranges{{Pos: 0, Size: o.Size()}}
+	item.info.Dirty = false
+}
+
+// setDirty marks name as needing uploading to the remote
+//
+// name should be a remote path not an osPath
+func (c *cache) setDirty(name string) {
+	name = clean(name)
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	item, _ := c._get(true, name)
+	item.hasInfo = true
+	item.info.Dirty = true
+}
+
+// isDirty returns whether name is waiting to be uploaded to the
+// remote
+//
+// name should be a remote path not an osPath
+func (c *cache) isDirty(name string) bool {
+	name = clean(name)
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	item := c.item[name]
+	return item != nil && item.dirty()
+}
+
+// upload copies name from the cache to the remote replacing dst,
+// which may be nil, and marks it as clean
+//
+// name should be a remote path not an osPath
+func (c *cache) upload(name string, dst fs.Object) (fs.Object, error) {
+	cacheObj, err := c.f.NewObject(context.TODO(), name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find cache file")
+	}
+	o, err := copyObj(c.fremote, dst, name, cacheObj)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to transfer file from cache to remote")
+	}
+	c.setUploaded(name, o)
+	c.saveInfo(name)
+	return o, nil
+}
+
+// queueDirty reads the metadata for the cache and queues any files
+// which were waiting to be uploaded when rclone last stopped
+func (c *cache) queueDirty() error {
+	return filepath.Walk(c.metaRoot, func(osPath string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		name, err := filepath.Rel(c.metaRoot, osPath)
+		if err != nil {
+			return errors.Wrap(err, "filepath.Rel failed in queueDirty")
+		}
+		name = filepath.ToSlash(name)
+		c.itemMu.Lock()
+		item, _ := c._get(true, name)
+		dirty := item.dirty()
+		c.itemMu.Unlock()
+		if !dirty {
+			return nil
+		}
+		if _, err := os.Stat(c.toOSPath(name)); err != nil {
+			fs.Errorf(name, "Can't upload file left in cache: %v", err)
+			return nil
+		}
+		fs.Infof(name, "Queueing upload of file left in cache")
+		c.writeback.add(name, nil, c.opt.WriteBack)
+		return nil
+	})
+}
+
+// isStale returns true if the cached copy of name isn't a copy of
+// the object o
+//
+// name should be a remote path not an osPath
+func (c *cache) isStale(name string, o fs.Object) bool {
+	name = clean(name)
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	item, _ := c._get(true, name)
+	if item.dirty() {
+		// Never throw away changes which haven't been uploaded
+		return false
+	}
+	return !item.hasInfo || item.info.Fingerprint != objectFingerprint(o)
+}
+
+// addRange marks the range r of name as present in the cache
+//
+// name should be a remote path not an osPath
+func (c *cache) addRange(name string, r ranges.Range) {
+	name = clean(name)
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	item, _ := c._get(true, name)
+	item.hasInfo = true
+	item.info.Rs.Insert(r)
+}
+
+// truncate records that name has been truncated to size
+//
+// name should be a remote path not an osPath
+func (c *cache) truncate(name string, size int64) {
+	name = clean(name)
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	item, _ := c._get(true, name)
+	item.hasInfo = true
+	if size < item.info.Size {
+		item.info.Size = size
+	}
+	item.info.Rs.Truncate(size)
+}
+
+// missing returns the parts of the range r of name which need to be
+// fetched from the remote
+//
+// name should be a remote path not an osPath
+func (c *cache) missing(name string, r ranges.Range) ranges.Ranges {
+	name = clean(name)
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	item, _ := c._get(true, name)
+	if !item.hasInfo {
+		return nil
+	}
+	// Nothing beyond the end of the remote object needs fetching
+	r.Clip(item.info.Size)
+	return item.info.Rs.Missing(r)
+}
+
 // opens returns the number of opens that are on the file
 //
 // name should be a remote path not an osPath
@@ -285,7 +580,10 @@ func (c *cache) close(name string) {
 }
 
 // remove should be called if name is deleted
+//
+// This may be called with itemMu held
 func (c *cache) remove(name string) {
+	c.writeback.remove(name)
 	osPath := c.toOSPath(name)
 	err := os.Remove(osPath)
 	if err != nil && !os.IsNotExist(err) {
@@ -293,6 +591,10 @@ func (c *cache) remove(name string) {
 	} else {
 		fs.Debugf(name, "Removed from cache")
 	}
+	err = os.Remove(c.toOSPathMeta(name))
+	if err != nil && !os.IsNotExist(err) {
+		fs.Errorf(name, "Failed to remove metadata from cache: %v", err)
+	}
 }
 
 // removeDir should be called if dir is deleted and returns true if
@@ -304,6 +606,8 @@ func (c *cache) removeDir(dir string) bool {
 		if err == nil {
 			fs.Debugf(dir, "Removed empty directory")
 		}
+		// Remove the metadata directory too if empty
+		_ = os.Remove(c.toOSPathMeta(dir))
 		return true
 	}
 	if !os.IsExist(err) {
@@ -314,7 +618,11 @@ func (c *cache) removeDir(dir string) bool {
 
 // cleanUp empties the cache of everything
 func (c *cache) cleanUp() error {
-	return os.RemoveAll(c.root)
+	err := os.RemoveAll(c.root)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(c.metaRoot)
 }
 
 // walk walks the cache calling the function
@@ -345,6 +653,7 @@ func (c *cache) updateAtimes() error {
 			// Update the atime with that of the file
 			atime := times.Get(fi).AccessTime()
 			c.updateTime(name, atime)
+			c.setSize(name, fi.Size())
 		} else {
 			c.cacheDir(name)
 		}
@@ -352,6 +661,59 @@ func (c *cache) updateAtimes() error {
 	})
 }
 
+// setSize records the size of the file name found in the cache
+//
+// name should be a remote path not an osPath
+func (c *cache) setSize(name string, size int64) {
+	name = clean(name)
+	c.itemMu.Lock()
+	item, _ := c._get(true, name)
+	item.size = size
+	c.itemMu.Unlock()
+}
+
+// purgeOverQuota removes the least recently used files until the
+// cache is under quota
+func (c *cache) purgeOverQuota(quota int64) {
+	c._purgeOverQuota(quota, c.remove)
+}
+
+func (c *cache) _purgeOverQuota(quota int64, remove func(name string)) {
+	if quota <= 0 {
+		return
+	}
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	var names []string
+	var total int64
+	for name, item := range c.item {
+		if !item.isFile {
+			continue
+		}
+		total += item.cachedSize()
+		if item.opens == 0 && !item.dirty() {
+			names = append(names, name)
+		}
+	}
+	if total <= quota {
+		return
+	}
+	// Remove the least recently used first
+	sort.Slice(names, func(i, j int) bool {
+		return c.item[names[i]].atime.Before(c.item[names[j]].atime)
+	})
+	for _, name := range names {
+		if total <= quota {
+			break
+		}
+		fs.Debugf(name, "Removing from cache to get under quota (%v > %v)", fs.SizeSuffix(total), fs.SizeSuffix(quota))
+		total -= c.item[name].cachedSize()
+		remove(name)
+		// Remove the entry
+		delete(c.item, name)
+	}
+}
+
 // purgeOld gets rid of any files that are over age
 func (c *cache) purgeOld(maxAge time.Duration) {
 	c._purgeOld(maxAge, c.remove, c.removeDir)
@@ -362,7 +724,7 @@ func (c *cache) _purgeOld(maxAge time.Duration, remove func(name string), remove
 	defer c.itemMu.Unlock()
 	cutoff := time.Now().Add(-maxAge)
 	for name, item := range c.item {
-		if item.isFile && item.opens == 0 {
+		if item.isFile && item.opens == 0 && !item.dirty() {
 			// If not locked and access time too long ago - delete the file
 			dt := item.atime.Sub(cutoff)
 			// fs.Debugf(name, "atime=%v cutoff=%v, dt=%v", item.atime, cutoff, dt)
@@ -407,6 +769,9 @@ func (c *cache) clean() {
 		fs.Errorf(nil, "Error traversing cache %q: %v", c.root, err)
 	}
 
+	// Remove the least recently used files if over quota
+	c.purgeOverQuota(int64(c.opt.CacheMaxSize))
+
 	// Now remove any files that are over age and any empty
 	// directories
 	c.purgeOld(c.opt.CacheMaxAge)
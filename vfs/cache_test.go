@@ -12,6 +12,7 @@ import (
 
 	"github.com/djherbis/times"
 	"github.com/artpar/rclone/fstest"
+	"github.com/artpar/rclone/lib/ranges"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -460,3 +461,115 @@ func TestCachePurgeOld(t *testing.T) {
 
 	assert.Equal(t, []string(nil), itemAsString(c))
 }
+
+func TestCachePurgeOverQuota(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Disable the cache cleaner as it interferes with these tests
+	opt := DefaultOpt
+	opt.CachePollInterval = 0
+	c, err := newCache(ctx, r.Fremote, &opt)
+	require.NoError(t, err)
+
+	// Test funcs
+	var removed []string
+	removeFile := func(name string) {
+		removed = append(removed, name)
+	}
+
+	// Make some files with increasing atimes
+	now := time.Now()
+	for i, name := range []string{"sub/oldest", "sub/middle", "newest", "open"} {
+		c.addRange(name, ranges.Range{Pos: 0, Size: 100})
+		c.updateTime(name, now.Add(time.Duration(i)*time.Minute))
+	}
+	c.open("open")
+	c.updateTime("open", now.Add(-time.Hour))
+
+	// No quota
+	removed = nil
+	c._purgeOverQuota(0, removeFile)
+	assert.Equal(t, []string(nil), removed)
+
+	// Under quota
+	removed = nil
+	c._purgeOverQuota(400, removeFile)
+	assert.Equal(t, []string(nil), removed)
+
+	// Over quota - removes least recently used first but not open files
+	removed = nil
+	c._purgeOverQuota(250, removeFile)
+	assert.Equal(t, []string{"sub/oldest", "sub/middle"}, removed)
+
+	// Way over quota - can't remove the open file
+	removed = nil
+	c._purgeOverQuota(1, removeFile)
+	assert.Equal(t, []string{"newest"}, removed)
+
+	assert.Equal(t, []string{
+		`name="" isFile=false opens=1`,
+		`name="open" isFile=true opens=1`,
+	}, itemAsString(c))
+}
+
+func TestCacheInfo(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opt := DefaultOpt
+	opt.CachePollInterval = 0
+	c, err := newCache(ctx, r.Fremote, &opt)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.cleanUp())
+	}()
+
+	file1 := r.WriteObject(ctx, "file1", "0123456789abcdef", t1)
+	o, err := r.Fremote.NewObject(ctx, file1.Path)
+	require.NoError(t, err)
+
+	// Nothing known so nothing missing and stale
+	assert.Equal(t, ranges.Ranges(nil), c.missing("file1", ranges.Range{Pos: 0, Size: 100}))
+	assert.True(t, c.isStale("file1", o))
+
+	// A new copy of the object has everything missing
+	c.setObject("file1", o)
+	assert.False(t, c.isStale("file1", o))
+	assert.Equal(t, ranges.Ranges{{Pos: 0, Size: 16}}, c.missing("file1", ranges.Range{Pos: 0, Size: 100}))
+
+	// Add some ranges
+	c.addRange("file1", ranges.Range{Pos: 2, Size: 2})
+	c.addRange("file1", ranges.Range{Pos: 10, Size: 2})
+	assert.Equal(t, ranges.Ranges{{Pos: 0, Size: 2}, {Pos: 4, Size: 6}, {Pos: 12, Size: 4}}, c.missing("file1", ranges.Range{Pos: 0, Size: 100}))
+
+	// Truncating removes the need to fetch beyond the new size
+	c.truncate("file1", 11)
+	assert.Equal(t, ranges.Ranges{{Pos: 0, Size: 2}, {Pos: 4, Size: 6}}, c.missing("file1", ranges.Range{Pos: 0, Size: 100}))
+
+	// Persist and read back in a new cache
+	atime := time.Now().Add(time.Hour)
+	c.updateTime("file1", atime)
+	c.saveInfo("file1")
+	c2, err := newCache(ctx, r.Fremote, &opt)
+	require.NoError(t, err)
+	assert.Equal(t, ranges.Ranges{{Pos: 0, Size: 2}, {Pos: 4, Size: 6}}, c2.missing("file1", ranges.Range{Pos: 0, Size: 100}))
+	assert.False(t, c2.isStale("file1", o))
+	assert.True(t, atime.Equal(c2.get("file1").atime))
+
+	// Uploading makes the whole file present
+	c2.setUploaded("file1", o)
+	assert.Equal(t, ranges.Ranges(nil), c2.missing("file1", ranges.Range{Pos: 0, Size: 100}))
+	assert.Equal(t, int64(16), c2.get("file1").cachedSize())
+
+	// Removing removes the metadata
+	c2.remove("file1")
+	_, err = os.Stat(c2.toOSPathMeta("file1"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"os"
 	"path"
 	"sort"
@@ -180,7 +181,7 @@ func (d *Dir) _readDir() error {
 		}
 		fs.Debugf(d.path, "Re-reading directory (%v old)", age)
 	}
-	entries, err := list.DirSorted(d.f, false, d.path)
+	entries, err := list.DirSorted(context.TODO(), d.f, false, d.path)
 	if err == fs.ErrorDirNotFound {
 		// We treat directory not found as empty because we
 		// create directories on the fly
@@ -267,7 +268,7 @@ func (d *Dir) readDirTree() error {
 	when := time.Now()
 	d.read = time.Time{}
 	fs.Debugf(d.path, "Reading directory tree")
-	dt, err := walk.NewDirTree(d.f, d.path, false, -1)
+	dt, err := walk.NewDirTree(context.TODO(), d.f, d.path, false, -1)
 	if err != nil {
 		return err
 	}
@@ -405,7 +406,7 @@ func (d *Dir) Mkdir(name string) (*Dir, error) {
 	}
 	path := path.Join(d.path, name)
 	// fs.Debugf(path, "Dir.Mkdir")
-	err := d.f.Mkdir(path)
+	err := d.f.Mkdir(context.TODO(), path)
 	if err != nil {
 		fs.Errorf(d, "Dir.Mkdir failed to create directory: %v", err)
 		return nil, err
@@ -433,7 +434,7 @@ func (d *Dir) Remove() error {
 		return ENOTEMPTY
 	}
 	// remove directory
-	err = d.f.Rmdir(d.path)
+	err = d.f.Rmdir(context.TODO(), d.path)
 	if err != nil {
 		fs.Errorf(d, "Dir.Remove failed to remove directory: %v", err)
 		return err
@@ -531,7 +532,7 @@ func (d *Dir) Rename(oldName, newName string, destDir *Dir) error {
 		}
 		srcRemote := x.Remote()
 		dstRemote := newPath
-		err = doDirMove(d.f, srcRemote, dstRemote)
+		err = doDirMove(context.TODO(), d.f, srcRemote, dstRemote)
 		if err != nil {
 			fs.Errorf(oldPath, "Dir.Rename error: %v", err)
 			return err
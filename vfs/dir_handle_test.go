@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"io"
 	"os"
 	"testing"
@@ -11,9 +12,10 @@ import (
 )
 
 func TestDirHandleMethods(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, dir, _ := dirCreate(t, r)
+	_, dir, _ := dirCreate(ctx, t, r)
 
 	h, err := dir.Open(os.O_RDONLY)
 	require.NoError(t, err)
@@ -39,13 +41,14 @@ func TestDirHandleMethods(t *testing.T) {
 }
 
 func TestDirHandleReaddir(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	vfs := New(r.Fremote, nil)
 
-	file1 := r.WriteObject("dir/file1", "file1 contents", t1)
-	file2 := r.WriteObject("dir/file2", "file2- contents", t2)
-	file3 := r.WriteObject("dir/subdir/file3", "file3-- contents", t3)
+	file1 := r.WriteObject(ctx, "dir/file1", "file1 contents", t1)
+	file2 := r.WriteObject(ctx, "dir/file2", "file2- contents", t2)
+	file3 := r.WriteObject(ctx, "dir/subdir/file3", "file3-- contents", t3)
 	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
 
 	node, err := vfs.Stat("dir")
@@ -95,9 +98,10 @@ func TestDirHandleReaddir(t *testing.T) {
 }
 
 func TestDirHandleReaddirnames(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, dir, _ := dirCreate(t, r)
+	_, dir, _ := dirCreate(ctx, t, r)
 
 	fh, err := dir.Open(os.O_RDONLY)
 	require.NoError(t, err)
@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -13,10 +14,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func dirCreate(t *testing.T, r *fstest.Run) (*VFS, *Dir, fstest.Item) {
+func dirCreate(ctx context.Context, t *testing.T, r *fstest.Run) (*VFS, *Dir, fstest.Item) {
 	vfs := New(r.Fremote, nil)
 
-	file1 := r.WriteObject("dir/file1", "file1 contents", t1)
+	file1 := r.WriteObject(ctx, "dir/file1", "file1 contents", t1)
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	node, err := vfs.Stat("dir")
@@ -27,9 +28,10 @@ func dirCreate(t *testing.T, r *fstest.Run) (*VFS, *Dir, fstest.Item) {
 }
 
 func TestDirMethods(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, _ := dirCreate(t, r)
+	vfs, dir, _ := dirCreate(ctx, t, r)
 
 	// String
 	assert.Equal(t, "dir/", dir.String())
@@ -76,9 +78,10 @@ func TestDirMethods(t *testing.T) {
 }
 
 func TestDirForgetAll(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, file1 := dirCreate(t, r)
+	vfs, dir, file1 := dirCreate(ctx, t, r)
 
 	// Make sure / and dir are in cache
 	_, err := vfs.Stat(file1.Path)
@@ -100,9 +103,10 @@ func TestDirForgetAll(t *testing.T) {
 }
 
 func TestDirForgetPath(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, file1 := dirCreate(t, r)
+	vfs, dir, file1 := dirCreate(ctx, t, r)
 
 	// Make sure / and dir are in cache
 	_, err := vfs.Stat(file1.Path)
@@ -124,11 +128,12 @@ func TestDirForgetPath(t *testing.T) {
 }
 
 func TestDirWalk(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, _, file1 := dirCreate(t, r)
+	vfs, _, file1 := dirCreate(ctx, t, r)
 
-	file2 := r.WriteObject("fil/a/b/c", "super long file", t1)
+	file2 := r.WriteObject(ctx, "fil/a/b/c", "super long file", t1)
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
 	root, err := vfs.Root()
@@ -189,9 +194,10 @@ func TestDirWalk(t *testing.T) {
 }
 
 func TestDirSetModTime(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, _ := dirCreate(t, r)
+	vfs, dir, _ := dirCreate(ctx, t, r)
 
 	err := dir.SetModTime(t1)
 	require.NoError(t, err)
@@ -207,9 +213,10 @@ func TestDirSetModTime(t *testing.T) {
 }
 
 func TestDirStat(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, dir, _ := dirCreate(t, r)
+	_, dir, _ := dirCreate(ctx, t, r)
 
 	node, err := dir.Stat("file1")
 	require.NoError(t, err)
@@ -234,13 +241,14 @@ func checkListing(t *testing.T, dir *Dir, want []string) {
 }
 
 func TestDirReadDirAll(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	vfs := New(r.Fremote, nil)
 
-	file1 := r.WriteObject("dir/file1", "file1 contents", t1)
-	file2 := r.WriteObject("dir/file2", "file2- contents", t2)
-	file3 := r.WriteObject("dir/subdir/file3", "file3-- contents", t3)
+	file1 := r.WriteObject(ctx, "dir/file1", "file1 contents", t1)
+	file2 := r.WriteObject(ctx, "dir/file2", "file2- contents", t2)
+	file3 := r.WriteObject(ctx, "dir/subdir/file3", "file3-- contents", t3)
 	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
 
 	node, err := vfs.Stat("dir")
@@ -263,9 +271,10 @@ func TestDirReadDirAll(t *testing.T) {
 }
 
 func TestDirOpen(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, dir, _ := dirCreate(t, r)
+	_, dir, _ := dirCreate(ctx, t, r)
 
 	fd, err := dir.Open(os.O_RDONLY)
 	require.NoError(t, err)
@@ -278,9 +287,10 @@ func TestDirOpen(t *testing.T) {
 }
 
 func TestDirCreate(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, _ := dirCreate(t, r)
+	vfs, dir, _ := dirCreate(ctx, t, r)
 
 	file, err := dir.Create("potato", os.O_WRONLY|os.O_CREATE)
 	require.NoError(t, err)
@@ -312,9 +322,10 @@ func TestDirCreate(t *testing.T) {
 }
 
 func TestDirMkdir(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, file1 := dirCreate(t, r)
+	vfs, dir, file1 := dirCreate(ctx, t, r)
 
 	_, err := dir.Mkdir("file1")
 	assert.Error(t, err)
@@ -335,9 +346,10 @@ func TestDirMkdir(t *testing.T) {
 }
 
 func TestDirRemove(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, _ := dirCreate(t, r)
+	vfs, dir, _ := dirCreate(ctx, t, r)
 
 	// check directory is there
 	node, err := vfs.Stat("dir")
@@ -376,9 +388,10 @@ func TestDirRemove(t *testing.T) {
 }
 
 func TestDirRemoveAll(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, _ := dirCreate(t, r)
+	vfs, dir, _ := dirCreate(ctx, t, r)
 
 	// Remove the directory and contents
 	err := dir.RemoveAll()
@@ -399,9 +412,10 @@ func TestDirRemoveAll(t *testing.T) {
 }
 
 func TestDirRemoveName(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, _ := dirCreate(t, r)
+	vfs, dir, _ := dirCreate(ctx, t, r)
 
 	err := dir.RemoveName("file1")
 	require.NoError(t, err)
@@ -420,9 +434,10 @@ func TestDirRemoveName(t *testing.T) {
 }
 
 func TestDirRename(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, dir, file1 := dirCreate(t, r)
+	vfs, dir, file1 := dirCreate(ctx, t, r)
 
 	root, err := vfs.Root()
 	require.NoError(t, err)
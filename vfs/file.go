@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"os"
 	"path"
 	"sync"
@@ -117,9 +118,17 @@ func (f *File) rename(destDir *Dir, newName string) error {
 		return err
 	}
 
+	// Upload the file first if it is waiting to be written back
+	if f.d.vfs.cache != nil {
+		if err := f.d.vfs.cache.writeback.flush(f.Path()); err != nil {
+			fs.Errorf(f.Path(), "File.Rename failed to upload file from cache: %v", err)
+			return err
+		}
+	}
+
 	renameCall := func() error {
 		newPath := path.Join(destDir.path, newName)
-		newObject, err := doMove(f.o, newPath)
+		newObject, err := doMove(context.TODO(), f.o, newPath)
 		if err != nil {
 			fs.Errorf(f.Path(), "File.Rename error: %v", err)
 			return err
@@ -447,7 +456,7 @@ func (f *File) Remove() error {
 		return EROFS
 	}
 	if f.o != nil {
-		err := f.o.Remove()
+		err := f.o.Remove(context.TODO())
 		if err != nil {
 			fs.Errorf(f, "File.Remove file error: %v", err)
 			return err
@@ -538,7 +547,7 @@ func (f *File) Open(flags int) (fd Handle, err error) {
 
 	// Open the correct sort of handle
 	CacheMode := f.d.vfs.Opt.CacheMode
-	if CacheMode >= CacheModeMinimal && f.d.vfs.cache.opens(f.Path()) > 0 {
+	if CacheMode >= CacheModeMinimal && (f.d.vfs.cache.opens(f.Path()) > 0 || f.d.vfs.cache.isDirty(f.Path())) {
 		fd, err = f.openRW(flags)
 	} else if read && write {
 		if CacheMode >= CacheModeMinimal {
@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -10,10 +11,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func fileCreate(t *testing.T, r *fstest.Run) (*VFS, *File, fstest.Item) {
+func fileCreate(ctx context.Context, t *testing.T, r *fstest.Run) (*VFS, *File, fstest.Item) {
 	vfs := New(r.Fremote, nil)
 
-	file1 := r.WriteObject("dir/file1", "file1 contents", t1)
+	file1 := r.WriteObject(ctx, "dir/file1", "file1 contents", t1)
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	node, err := vfs.Stat("dir/file1")
@@ -24,9 +25,10 @@ func fileCreate(t *testing.T, r *fstest.Run) (*VFS, *File, fstest.Item) {
 }
 
 func TestFileMethods(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, file, _ := fileCreate(t, r)
+	vfs, file, _ := fileCreate(ctx, t, r)
 
 	// String
 	assert.Equal(t, "dir/file1", file.String())
@@ -76,9 +78,10 @@ func TestFileMethods(t *testing.T) {
 }
 
 func TestFileSetModTime(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, file, file1 := fileCreate(t, r)
+	vfs, file, file1 := fileCreate(ctx, t, r)
 
 	err := file.SetModTime(t2)
 	require.NoError(t, err)
@@ -92,9 +95,10 @@ func TestFileSetModTime(t *testing.T) {
 }
 
 func TestFileOpenRead(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, file, _ := fileCreate(t, r)
+	_, file, _ := fileCreate(ctx, t, r)
 
 	fd, err := file.openRead()
 	require.NoError(t, err)
@@ -107,9 +111,10 @@ func TestFileOpenRead(t *testing.T) {
 }
 
 func TestFileOpenWrite(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, file, _ := fileCreate(t, r)
+	vfs, file, _ := fileCreate(ctx, t, r)
 
 	fd, err := file.openWrite(os.O_WRONLY | os.O_TRUNC)
 	require.NoError(t, err)
@@ -128,9 +133,10 @@ func TestFileOpenWrite(t *testing.T) {
 }
 
 func TestFileRemove(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, file, _ := fileCreate(t, r)
+	vfs, file, _ := fileCreate(ctx, t, r)
 
 	err := file.Remove()
 	require.NoError(t, err)
@@ -143,9 +149,10 @@ func TestFileRemove(t *testing.T) {
 }
 
 func TestFileRemoveAll(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	vfs, file, _ := fileCreate(t, r)
+	vfs, file, _ := fileCreate(ctx, t, r)
 
 	err := file.RemoveAll()
 	require.NoError(t, err)
@@ -158,9 +165,10 @@ func TestFileRemoveAll(t *testing.T) {
 }
 
 func TestFileOpen(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, file, _ := fileCreate(t, r)
+	_, file, _ := fileCreate(ctx, t, r)
 
 	fd, err := file.Open(os.O_RDONLY)
 	require.NoError(t, err)
@@ -43,8 +43,10 @@ may find that you need one or the other or both.
 
     --cache-dir string                   Directory rclone will use for caching.
     --vfs-cache-max-age duration         Max age of objects in the cache. (default 1h0m0s)
+    --vfs-cache-max-size int             Max total size of objects in the cache. (default off)
     --vfs-cache-mode string              Cache mode off|minimal|writes|full (default "off")
     --vfs-cache-poll-interval duration   Interval to poll the cache for stale objects. (default 1m0s)
+    --vfs-write-back duration            Time to wait after a file is closed before uploading it from the cache. 0 uploads on close.
 
 If run with ` + "`-vv`" + ` rclone will print the location of the file cache.  The
 files are stored in the user cache file area which is OS dependent but
@@ -60,6 +62,26 @@ closed so if rclone is quit or dies with open files then these won't
 get written back to the remote.  However they will still be in the on
 disk cache.
 
+Files which have been closed but not yet uploaded are recorded in the
+cache metadata.  If rclone is stopped before they are uploaded then
+they will be queued for upload again the next time rclone starts with
+the same remote and ` + "`--cache-dir`" + `.  Until they are uploaded they
+won't be removed from the cache by ` + "`--vfs-cache-max-age`" + ` or
+` + "`--vfs-cache-max-size`" + `.
+
+By default files are uploaded as soon as they are closed and the
+close waits for the upload to finish.  If ` + "`--vfs-write-back`" + ` is
+set then the close returns immediately and the file is uploaded in the
+background that long after it was last closed.  If an upload
+fails it is retried in the background, waiting 1s before the first
+retry and doubling the wait each time up to a maximum of 5m.
+
+If using --vfs-cache-max-size note that the cache may exceed this size
+for two reasons.  Firstly because it is only checked every
+--vfs-cache-poll-interval.  Secondly because open files cannot be
+evicted from the cache.  When the cache is over quota the least
+recently used files are removed first.
+
 #### --vfs-cache-mode off
 
 In this mode the cache will read directly from the remote and write
@@ -101,7 +123,14 @@ If an upload fails it will be retried up to --low-level-retries times.
 #### --vfs-cache-mode full
 
 In this mode all reads and writes are buffered to and from disk.  When
-a file is opened for read it will be downloaded in its entirety first.
+data is read from a file it is downloaded into a sparse file in the
+cache in chunks, so only the parts of the file which are actually read
+are downloaded.  The whole file is only downloaded if it needs to be
+written back to the remote.
+
+A record of which parts of each file have been downloaded is kept
+next to the cache so that they can be reused after rclone is
+restarted, provided the file hasn't changed on the remote.
 
 This may be appropriate for your needs, or you may prefer to look at
 the cache backend which does a much more sophisticated job of caching,
@@ -109,7 +138,8 @@ including caching directory hierarchies and chunks of files.
 
 In this mode, unlike the others, when a file is written to the disk,
 it will be kept on the disk after it is written to the remote.  It
-will be purged on a schedule according to ` + "`--vfs-cache-max-age`" + `.
+will be purged on a schedule according to ` + "`--vfs-cache-max-age`" + `
+and ` + "`--vfs-cache-max-size`" + `.
 
 This mode should support all normal file system operations.
 
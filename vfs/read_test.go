@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"io"
 	"os"
 	"testing"
@@ -11,10 +12,10 @@ import (
 )
 
 // Open a file for write
-func readHandleCreate(t *testing.T, r *fstest.Run) (*VFS, *ReadFileHandle) {
+func readHandleCreate(ctx context.Context, t *testing.T, r *fstest.Run) (*VFS, *ReadFileHandle) {
 	vfs := New(r.Fremote, nil)
 
-	file1 := r.WriteObject("dir/file1", "0123456789abcdef", t1)
+	file1 := r.WriteObject(ctx, "dir/file1", "0123456789abcdef", t1)
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	h, err := vfs.OpenFile("dir/file1", os.O_RDONLY, 0777)
@@ -36,9 +37,10 @@ func readString(t *testing.T, fh *ReadFileHandle, n int) string {
 }
 
 func TestReadFileHandleMethods(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, fh := readHandleCreate(t, r)
+	_, fh := readHandleCreate(ctx, t, r)
 
 	// String
 	assert.Equal(t, "dir/file1 (r)", fh.String())
@@ -80,9 +82,10 @@ func TestReadFileHandleMethods(t *testing.T) {
 }
 
 func TestReadFileHandleSeek(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, fh := readHandleCreate(t, r)
+	_, fh := readHandleCreate(ctx, t, r)
 
 	assert.Equal(t, "0", readString(t, fh, 1))
 
@@ -124,9 +127,10 @@ func TestReadFileHandleSeek(t *testing.T) {
 }
 
 func TestReadFileHandleReadAt(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, fh := readHandleCreate(t, r)
+	_, fh := readHandleCreate(ctx, t, r)
 
 	// read from start
 	buf := make([]byte, 1)
@@ -181,9 +185,10 @@ func TestReadFileHandleReadAt(t *testing.T) {
 }
 
 func TestReadFileHandleFlush(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, fh := readHandleCreate(t, r)
+	_, fh := readHandleCreate(ctx, t, r)
 
 	// Check Flush does nothing if read not called
 	err := fh.Flush()
@@ -211,9 +216,10 @@ func TestReadFileHandleFlush(t *testing.T) {
 }
 
 func TestReadFileHandleRelease(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
-	_, fh := readHandleCreate(t, r)
+	_, fh := readHandleCreate(ctx, t, r)
 
 	// Check Release does nothing if file not read from
 	err := fh.Release()
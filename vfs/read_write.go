@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,13 +13,22 @@ import (
 	"github.com/artpar/rclone/fs/accounting"
 	"github.com/artpar/rclone/fs/log"
 	"github.com/artpar/rclone/fs/operations"
+	"github.com/artpar/rclone/lib/ranges"
 	"github.com/pkg/errors"
 )
 
+// cacheChunkSize is the unit the cache file is downloaded in.  Reads
+// are rounded out to whole chunks to avoid lots of small downloads.
+const cacheChunkSize = 1024 * 1024
+
 // RWFileHandle is a handle that can be open for read and write.
 //
 // It will be open to a temporary file which, when closed, will be
 // transferred to the remote.
+//
+// The temporary file is a sparse copy of the remote object - only the
+// parts which have been read are downloaded into it.  Which parts are
+// present is kept in the cache metadata.
 type RWFileHandle struct {
 	*os.File
 	mu          sync.Mutex
@@ -84,9 +94,9 @@ func newRWFileHandle(d *Dir, f *File, remote string, flags int) (fh *RWFileHandl
 
 // copy an object to or from the remote while accounting for it
 func copyObj(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
-	if operations.NeedTransfer(dst, src) {
+	if operations.NeedTransfer(context.TODO(), dst, src) {
 		accounting.Stats.Transferring(src.Remote())
-		newDst, err = operations.Copy(f, dst, remote, src)
+		newDst, err = operations.Copy(context.TODO(), f, dst, remote, src)
 		accounting.Stats.DoneTransferring(src.Remote(), err == nil)
 	} else {
 		newDst = dst
@@ -108,49 +118,41 @@ func (fh *RWFileHandle) openPending(truncate bool) (err error) {
 	o := fh.file.getObject()
 
 	var fd *os.File
+	cache := fh.d.vfs.cache
 	cacheFileOpenFlags := fh.flags
 	// if not truncating the file, need to read it first
 	if fh.flags&os.O_TRUNC == 0 && !truncate {
-		// If the remote object exists AND its cached file exists locally AND there are no
-		// other RW handles with it open, then attempt to update it.
-		if o != nil && fh.file.rwOpens() == 0 {
-			cacheObj, err := fh.d.vfs.cache.f.NewObject(fh.remote)
-			if err == nil && cacheObj != nil {
-				_, err = copyObj(fh.d.vfs.cache.f, cacheObj, fh.remote, o)
-				if err != nil {
-					return errors.Wrap(err, "open RW handle failed to update cached file")
-				}
+		// If the remote object exists AND there are no other RW
+		// handles with it open, then discard the cached copy if it
+		// isn't a copy of the remote object.
+		if o != nil && fh.file.rwOpens() == 0 && cache.isStale(fh.remote, o) {
+			err = os.Remove(fh.osPath)
+			if err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "open RW handle failed to remove stale cached file")
 			}
 		}
 
 		// try to open a exising cache file
 		fd, err = os.OpenFile(fh.osPath, cacheFileOpenFlags&^os.O_CREATE, 0600)
 		if os.IsNotExist(err) {
-			// cache file does not exist, so need to fetch it if we have an object to fetch
-			// it from
 			if o != nil {
-				_, err = copyObj(fh.d.vfs.cache.f, nil, fh.remote, o)
+				// cache file does not exist, so make a sparse
+				// file the size of the object which will be
+				// filled in from the remote as it is read
+				err = makeSparseFile(fh.osPath, o.Size())
 				if err != nil {
-					cause := errors.Cause(err)
-					if cause != fs.ErrorObjectNotFound && cause != fs.ErrorDirNotFound {
-						// return any non NotFound errors
-						return errors.Wrap(err, "open RW handle failed to cache file")
-					}
-					// continue here with err=fs.Error{Object,Dir}NotFound
-				}
-			}
-			// if err == nil, then we have cached the file successfully, otherwise err is
-			// indicating some kind of non existent file/directory either
-			// os.IsNotExist(err) or fs.Error{Object,Dir}NotFound
-			if err != nil {
-				if fh.flags&os.O_CREATE != 0 {
-					// if the object wasn't found AND O_CREATE is set then
-					// ignore error as we are about to create the file
-					fh.file.setSize(0)
-					fh.changed = true
-				} else {
-					return errors.Wrap(err, "open RW handle failed to cache file")
+					return errors.Wrap(err, "open RW handle failed to create cache file")
 				}
+				cache.setObject(fh.remote, o)
+				fs.Debugf(fh.logPrefix(), "Created new sparse cached copy")
+			} else if fh.flags&os.O_CREATE != 0 {
+				// if the object wasn't found AND O_CREATE is set then
+				// ignore error as we are about to create the file
+				fh.file.setSize(0)
+				fh.changed = true
+				cache.setObject(fh.remote, nil)
+			} else {
+				return errors.Wrap(err, "open RW handle failed to cache file")
 			}
 		} else if err != nil {
 			return errors.Wrap(err, "cache open file failed")
@@ -161,6 +163,7 @@ func (fh *RWFileHandle) openPending(truncate bool) (err error) {
 		// Set the size to 0 since we are truncating and flag we need to write it back
 		fh.file.setSize(0)
 		fh.changed = true
+		cache.setObject(fh.remote, nil)
 		if fh.flags&os.O_CREATE == 0 && fh.file.exists() {
 			// create an empty file if it exists on the source
 			err = ioutil.WriteFile(fh.osPath, []byte{}, 0600)
@@ -197,6 +200,85 @@ func (fh *RWFileHandle) openPending(truncate bool) (err error) {
 	return nil
 }
 
+// makeSparseFile creates a sparse file of size at osPath
+func makeSparseFile(osPath string, size int64) (err error) {
+	fd, err := os.OpenFile(osPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(fd, &err)
+	return fd.Truncate(size)
+}
+
+// fetch makes sure the range r of the cache file is present,
+// downloading any parts which are missing from the remote
+//
+// call with the lock held
+func (fh *RWFileHandle) fetch(r ranges.Range) error {
+	if r.IsEmpty() {
+		return nil
+	}
+	// Round the range out to whole chunks
+	start := r.Pos / cacheChunkSize * cacheChunkSize
+	end := (r.End() + cacheChunkSize - 1) / cacheChunkSize * cacheChunkSize
+	missing := fh.d.vfs.cache.missing(fh.remote, ranges.Range{Pos: start, Size: end - start})
+	if len(missing) == 0 {
+		return nil
+	}
+	o := fh.file.getObject()
+	if o == nil {
+		return errors.New("can't fetch missing data for cache file: object not found")
+	}
+	for _, m := range missing {
+		err := fh.download(o, m)
+		if err != nil {
+			return err
+		}
+		fh.d.vfs.cache.addRange(fh.remote, m)
+	}
+	return nil
+}
+
+// download the range r from o into the cache file
+func (fh *RWFileHandle) download(o fs.Object, r ranges.Range) (err error) {
+	fs.Debugf(fh.logPrefix(), "downloading %d bytes at offset %d into cache", r.Size, r.Pos)
+	// Use a separate file descriptor as fh.File may be read only
+	// or opened with O_APPEND
+	fd, err := os.OpenFile(fh.osPath, os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open cache file for download")
+	}
+	defer fs.CheckClose(fd, &err)
+	_, err = fd.Seek(r.Pos, io.SeekStart)
+	if err != nil {
+		return errors.Wrap(err, "failed to seek cache file for download")
+	}
+	in, err := o.Open(context.TODO(), &fs.RangeOption{Start: r.Pos, End: r.End() - 1})
+	if err != nil {
+		return errors.Wrap(err, "failed to open remote object for download")
+	}
+	accounting.Stats.Transferring(o.Remote())
+	acc := accounting.NewAccountSizeName(in, r.Size, o.Remote())
+	n, err := io.CopyN(fd, acc, r.Size)
+	if err == io.EOF {
+		// A short read is only OK if it stopped at the end of the object
+		if r.Pos+n == o.Size() {
+			err = nil
+		} else {
+			err = errors.Errorf("short read: expecting %d bytes but got %d", r.Size, n)
+		}
+	}
+	closeErr := acc.Close()
+	if err == nil {
+		err = closeErr
+	}
+	accounting.Stats.DoneTransferring(o.Remote(), err == nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to download into cache file")
+	}
+	return nil
+}
+
 // String converts it to printable
 func (fh *RWFileHandle) String() string {
 	if fh == nil {
@@ -251,6 +333,7 @@ func (fh *RWFileHandle) close() (err error) {
 		if fh.opened {
 			fh.file.delRWOpen()
 		}
+		fh.d.vfs.cache.saveInfo(fh.remote)
 		fh.d.vfs.cache.close(fh.remote)
 	}()
 	rdwrMode := fh.flags & accessModeMask
@@ -281,6 +364,16 @@ func (fh *RWFileHandle) close() (err error) {
 			fs.Errorf(fh.logPrefix(), "Failed to stat cache file: %v", err)
 		} else {
 			fh.file.setSize(fi.Size())
+			// Make sure the whole file is present before writing it back
+			if isCopied {
+				err = fh.fetch(ranges.Range{Pos: 0, Size: fi.Size()})
+				if err != nil {
+					err = errors.Wrap(err, "failed to fetch cache file before transfer")
+					fs.Errorf(fh.logPrefix(), "%v", err)
+					_ = fh.File.Close()
+					return err
+				}
+			}
 		}
 	}
 
@@ -294,18 +387,24 @@ func (fh *RWFileHandle) close() (err error) {
 	}
 
 	if isCopied {
-		// Transfer the temp file to the remote
-		cacheObj, err := fh.d.vfs.cache.f.NewObject(fh.remote)
-		if err != nil {
-			err = errors.Wrap(err, "failed to find cache file")
-			fs.Errorf(fh.logPrefix(), "%v", err)
-			return err
+		cache := fh.d.vfs.cache
+		// Record that the file needs uploading first so the
+		// upload is resumed if rclone stops before it is done
+		cache.setDirty(fh.remote)
+		cache.saveInfo(fh.remote)
+
+		if writeBack := fh.d.vfs.Opt.WriteBack; writeBack > 0 {
+			cache.writeback.add(fh.remote, fh.file, writeBack)
+			fs.Debugf(fh.logPrefix(), "queued for upload in %v", writeBack)
+			return nil
 		}
 
-		o, err := copyObj(fh.d.vfs.f, fh.file.getObject(), fh.remote, cacheObj)
+		// Transfer the temp file to the remote
+		o, err := cache.upload(fh.remote, fh.file.getObject())
 		if err != nil {
-			err = errors.Wrap(err, "failed to transfer file from cache to remote")
 			fs.Errorf(fh.logPrefix(), "%v", err)
+			// Keep trying in the background
+			cache.writeback.add(fh.remote, fh.file, cache.writeback.minDelay)
 			return err
 		}
 		fh.file.setObject(o)
@@ -418,6 +517,14 @@ func (fh *RWFileHandle) readFn(read func() (int, error)) (n int, err error) {
 // Read bytes from the file
 func (fh *RWFileHandle) Read(b []byte) (n int, err error) {
 	return fh.readFn(func() (int, error) {
+		off, err := fh.File.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		err = fh.fetch(ranges.Range{Pos: off, Size: int64(len(b))})
+		if err != nil {
+			return 0, err
+		}
 		return fh.File.Read(b)
 	})
 }
@@ -425,6 +532,10 @@ func (fh *RWFileHandle) Read(b []byte) (n int, err error) {
 // ReadAt bytes from the file at off
 func (fh *RWFileHandle) ReadAt(b []byte, off int64) (n int, err error) {
 	return fh.readFn(func() (int, error) {
+		err := fh.fetch(ranges.Range{Pos: off, Size: int64(len(b))})
+		if err != nil {
+			return 0, err
+		}
 		return fh.File.ReadAt(b, off)
 	})
 }
@@ -447,8 +558,9 @@ func (fh *RWFileHandle) Seek(offset int64, whence int) (ret int64, err error) {
 
 // writeFn general purpose write call
 //
-// Pass a closure to do the actual write
-func (fh *RWFileHandle) writeFn(write func() error) (err error) {
+// Pass a closure to do the actual write which should return the
+// range of the file written
+func (fh *RWFileHandle) writeFn(write func() (ranges.Range, error)) (err error) {
 	fh.mu.Lock()
 	defer fh.mu.Unlock()
 	if fh.closed {
@@ -461,7 +573,8 @@ func (fh *RWFileHandle) writeFn(write func() error) (err error) {
 		return err
 	}
 	fh.writeCalled = true
-	err = write()
+	r, err := write()
+	fh.d.vfs.cache.addRange(fh.remote, r)
 	if err != nil {
 		return err
 	}
@@ -473,29 +586,40 @@ func (fh *RWFileHandle) writeFn(write func() error) (err error) {
 	return nil
 }
 
+// writtenRange returns the range just written by a sequential write
+// of n bytes
+func (fh *RWFileHandle) writtenRange(n int) ranges.Range {
+	end, err := fh.File.Seek(0, io.SeekCurrent)
+	if err != nil {
+		fs.Errorf(fh.logPrefix(), "Failed to read file position: %v", err)
+		return ranges.Range{}
+	}
+	return ranges.Range{Pos: end - int64(n), Size: int64(n)}
+}
+
 // Write bytes to the file
 func (fh *RWFileHandle) Write(b []byte) (n int, err error) {
-	err = fh.writeFn(func() error {
+	err = fh.writeFn(func() (ranges.Range, error) {
 		n, err = fh.File.Write(b)
-		return err
+		return fh.writtenRange(n), err
 	})
 	return n, err
 }
 
 // WriteAt bytes to the file at off
 func (fh *RWFileHandle) WriteAt(b []byte, off int64) (n int, err error) {
-	err = fh.writeFn(func() error {
+	err = fh.writeFn(func() (ranges.Range, error) {
 		n, err = fh.File.WriteAt(b, off)
-		return err
+		return ranges.Range{Pos: off, Size: int64(n)}, err
 	})
 	return n, err
 }
 
 // WriteString a string to the file
 func (fh *RWFileHandle) WriteString(s string) (n int, err error) {
-	err = fh.writeFn(func() error {
+	err = fh.writeFn(func() (ranges.Range, error) {
 		n, err = fh.File.WriteString(s)
-		return err
+		return fh.writtenRange(n), err
 	})
 	return n, err
 }
@@ -512,6 +636,7 @@ func (fh *RWFileHandle) Truncate(size int64) (err error) {
 	}
 	fh.changed = true
 	fh.file.setSize(size)
+	fh.d.vfs.cache.truncate(fh.remote, size)
 	return fh.File.Truncate(size)
 }
 
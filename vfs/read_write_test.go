@@ -1,14 +1,18 @@
 package vfs
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/artpar/rclone/fs"
 	"github.com/artpar/rclone/fstest"
+	"github.com/artpar/rclone/fstest/mockobject"
+	"github.com/artpar/rclone/lib/ranges"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -20,12 +24,12 @@ func cleanup(t *testing.T, r *fstest.Run, vfs *VFS) {
 }
 
 // Open a file for write
-func rwHandleCreateReadOnly(t *testing.T, r *fstest.Run) (*VFS, *RWFileHandle) {
+func rwHandleCreateReadOnly(ctx context.Context, t *testing.T, r *fstest.Run) (*VFS, *RWFileHandle) {
 	opt := DefaultOpt
 	opt.CacheMode = CacheModeFull
 	vfs := New(r.Fremote, &opt)
 
-	file1 := r.WriteObject("dir/file1", "0123456789abcdef", t1)
+	file1 := r.WriteObject(ctx, "dir/file1", "0123456789abcdef", t1)
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	h, err := vfs.OpenFile("dir/file1", os.O_RDONLY, 0777)
@@ -61,8 +65,9 @@ func rwReadString(t *testing.T, fh *RWFileHandle, n int) string {
 }
 
 func TestRWFileHandleMethodsRead(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
-	vfs, fh := rwHandleCreateReadOnly(t, r)
+	vfs, fh := rwHandleCreateReadOnly(ctx, t, r)
 	defer cleanup(t, r, vfs)
 
 	// String
@@ -118,8 +123,9 @@ func TestRWFileHandleMethodsRead(t *testing.T) {
 }
 
 func TestRWFileHandleSeek(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
-	vfs, fh := rwHandleCreateReadOnly(t, r)
+	vfs, fh := rwHandleCreateReadOnly(ctx, t, r)
 	defer cleanup(t, r, vfs)
 
 	assert.Equal(t, fh.opened, false)
@@ -169,8 +175,9 @@ func TestRWFileHandleSeek(t *testing.T) {
 }
 
 func TestRWFileHandleReadAt(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
-	vfs, fh := rwHandleCreateReadOnly(t, r)
+	vfs, fh := rwHandleCreateReadOnly(ctx, t, r)
 	defer cleanup(t, r, vfs)
 
 	// read from start
@@ -219,9 +226,107 @@ func TestRWFileHandleReadAt(t *testing.T) {
 	assert.Equal(t, ECLOSED, err)
 }
 
+// Check that only the chunks which are read are downloaded and that
+// the record of what has been downloaded persists
+func TestRWFileHandleReadSparse(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt := DefaultOpt
+	opt.CacheMode = CacheModeFull
+
+	contents := strings.Repeat("0123456789abcdef", 3*cacheChunkSize/16)
+	contents += "end"
+	size := int64(len(contents))
+	file1 := r.WriteObject(ctx, "file1", contents, t1)
+	fstest.CheckItems(t, r.Fremote, file1)
+	whole := ranges.Range{Pos: 0, Size: size}
+
+	vfs := New(r.Fremote, &opt)
+	h, err := vfs.OpenFile("file1", os.O_RDONLY, 0777)
+	require.NoError(t, err)
+	fh := h.(*RWFileHandle)
+
+	// Reading from the middle only fetches that chunk
+	buf := make([]byte, 4)
+	n, err := fh.ReadAt(buf, 2*cacheChunkSize+2)
+	require.NoError(t, err)
+	assert.Equal(t, "2345", string(buf[:n]))
+	assert.Equal(t, ranges.Ranges{
+		{Pos: 0, Size: 2 * cacheChunkSize},
+		{Pos: 3 * cacheChunkSize, Size: 3},
+	}, vfs.cache.missing("file1", whole))
+	require.NoError(t, fh.Close())
+	vfs.Shutdown()
+
+	// A new VFS remembers what was fetched
+	vfs = New(r.Fremote, &opt)
+	defer cleanup(t, r, vfs)
+	h, err = vfs.OpenFile("file1", os.O_RDONLY, 0777)
+	require.NoError(t, err)
+	fh = h.(*RWFileHandle)
+	assert.Equal(t, ranges.Ranges{
+		{Pos: 0, Size: 2 * cacheChunkSize},
+		{Pos: 3 * cacheChunkSize, Size: 3},
+	}, vfs.cache.missing("file1", whole))
+
+	// Reading the end fetches the last chunk
+	n, err = fh.ReadAt(buf, size-3)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "end", string(buf[:n]))
+	assert.Equal(t, ranges.Ranges{
+		{Pos: 0, Size: 2 * cacheChunkSize},
+	}, vfs.cache.missing("file1", whole))
+
+	// Reading the whole file fetches the rest
+	got, err := ioutil.ReadAll(fh)
+	require.NoError(t, err)
+	assert.Equal(t, contents, string(got))
+	assert.Equal(t, ranges.Ranges(nil), vfs.cache.missing("file1", whole))
+	require.NoError(t, fh.Close())
+}
+
+// sizedObject is an fs.Object which claims to be size bytes long
+type sizedObject struct {
+	fs.Object
+	size int64
+}
+
+func (o sizedObject) Size() int64 { return o.size }
+
+// Check that download only writes the range asked for and that short
+// reads are only allowed at the end of the object
+func TestRWFileHandleDownload(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	vfs, fh := rwHandleCreateReadOnly(ctx, t, r)
+	defer cleanup(t, r, vfs)
+	require.NoError(t, fh.openPending(false))
+
+	// This object ignores the end of the range so returns too much
+	o := mockobject.New("dir/file1").WithContent([]byte("0123456789abcdef"), mockobject.SeekModeRegular)
+	require.NoError(t, fh.download(o, ranges.Range{Pos: 2, Size: 4}))
+	got, err := ioutil.ReadFile(fh.osPath)
+	require.NoError(t, err)
+	assert.Equal(t, "\x00\x00"+"2345"+strings.Repeat("\x00", 10), string(got))
+
+	// Stopping short at the end of the object is OK
+	o = mockobject.New("dir/file1").WithContent([]byte("0123456789"), mockobject.SeekModeRegular)
+	require.NoError(t, fh.download(o, ranges.Range{Pos: 8, Size: 8}))
+
+	// Stopping short anywhere else isn't
+	o = mockobject.New("dir/file1").WithContent([]byte("0123456789"), mockobject.SeekModeNone)
+	err = fh.download(sizedObject{Object: o, size: 16}, ranges.Range{Pos: 8, Size: 8})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "short read")
+
+	require.NoError(t, fh.Close())
+}
+
 func TestRWFileHandleFlushRead(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
-	vfs, fh := rwHandleCreateReadOnly(t, r)
+	vfs, fh := rwHandleCreateReadOnly(ctx, t, r)
 	defer cleanup(t, r, vfs)
 
 	// Check Flush does nothing if read not called
@@ -250,8 +355,9 @@ func TestRWFileHandleFlushRead(t *testing.T) {
 }
 
 func TestRWFileHandleReleaseRead(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
-	vfs, fh := rwHandleCreateReadOnly(t, r)
+	vfs, fh := rwHandleCreateReadOnly(ctx, t, r)
 	defer cleanup(t, r, vfs)
 
 	// Read data
@@ -47,7 +47,9 @@ var DefaultOpt = Options{
 	FilePerms:         os.FileMode(0666),
 	CacheMode:         CacheModeOff,
 	CacheMaxAge:       3600 * time.Second,
+	CacheMaxSize:      -1,
 	CachePollInterval: 60 * time.Second,
+	WriteBack:         0,
 	ChunkSize:         128 * fs.MebiByte,
 	ChunkSizeLimit:    -1,
 }
@@ -195,7 +197,9 @@ type Options struct {
 	ChunkSizeLimit    fs.SizeSuffix // if > ChunkSize double the chunk size after each chunk until reached
 	CacheMode         CacheMode
 	CacheMaxAge       time.Duration
+	CacheMaxSize      fs.SizeSuffix
 	CachePollInterval time.Duration
+	WriteBack         time.Duration // time to wait after close before uploading - 0 to upload on close
 }
 
 // New creates a new VFS and root directory.  If opt is nil, then
@@ -319,6 +323,11 @@ func (vfs *VFS) WaitForWriters(timeout time.Duration) {
 	}
 }
 
+// Fs returns the Fs passed into the New call
+func (vfs *VFS) Fs() fs.Fs {
+	return vfs.f
+}
+
 // Root returns the root node
 func (vfs *VFS) Root() (*Dir, error) {
 	// fs.Debugf(vfs.f, "Root()")
@@ -0,0 +1,250 @@
+// This deals with uploading files from the cache to the remote in
+// the background
+
+package vfs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+const (
+	writeBackMinRetryDelay = time.Second     // delay before retrying a failed upload
+	writeBackMaxRetryDelay = 5 * time.Minute // maximum delay between retries
+)
+
+// writeBack keeps a queue of files in the cache which need uploading
+// to the remote and uploads them when they are due, retrying with
+// backoff if the upload fails.
+type writeBack struct {
+	c        *cache
+	uploadMu sync.Mutex                // held while uploading
+	mu       sync.Mutex                // protects items
+	items    map[string]*writeBackItem // files waiting to be uploaded
+	kick     chan struct{}             // poke the uploader to look at the queue
+	minDelay time.Duration             // initial delay before retrying
+	maxDelay time.Duration             // maximum delay before retrying
+}
+
+// writeBackItem is a file waiting to be uploaded
+type writeBackItem struct {
+	name   string        // remote path of the file
+	file   *File         // node to update after the upload - may be nil
+	expiry time.Time     // when the upload is due
+	delay  time.Duration // how long to wait before the next retry
+	tries  int           // number of failed uploads so far
+}
+
+// newWriteBack makes a new write back queue for the cache
+func newWriteBack(c *cache) *writeBack {
+	return &writeBack{
+		c:        c,
+		items:    make(map[string]*writeBackItem),
+		kick:     make(chan struct{}, 1),
+		minDelay: writeBackMinRetryDelay,
+		maxDelay: writeBackMaxRetryDelay,
+	}
+}
+
+// poke wakes the uploader up without blocking
+func (wb *writeBack) poke() {
+	select {
+	case wb.kick <- struct{}{}:
+	default:
+	}
+}
+
+// add queues name to be uploaded after delay replacing any existing
+// entry.  file is the node to update once the upload is complete and
+// may be nil.
+func (wb *writeBack) add(name string, file *File, delay time.Duration) {
+	name = clean(name)
+	wb.mu.Lock()
+	wb.items[name] = &writeBackItem{
+		name:   name,
+		file:   file,
+		expiry: time.Now().Add(delay),
+		delay:  wb.minDelay,
+	}
+	wb.mu.Unlock()
+	wb.poke()
+}
+
+// remove takes name off the queue if it is there
+//
+// This may be called with the cache itemMu held
+func (wb *writeBack) remove(name string) {
+	name = clean(name)
+	wb.mu.Lock()
+	delete(wb.items, name)
+	wb.mu.Unlock()
+}
+
+// queued returns whether name is waiting to be uploaded
+func (wb *writeBack) queued(name string) bool {
+	name = clean(name)
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	_, found := wb.items[name]
+	return found
+}
+
+// retry puts item back on the queue after a failed upload, doubling
+// the delay each time, unless it was queued again in the meantime
+func (wb *writeBack) retry(item *writeBackItem, err error) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	if _, found := wb.items[item.name]; found {
+		return
+	}
+	item.tries++
+	fs.Errorf(item.name, "Failed to upload from cache (try %d) - will retry in %v: %v", item.tries, item.delay, err)
+	item.expiry = time.Now().Add(item.delay)
+	item.delay *= 2
+	if item.delay > wb.maxDelay {
+		item.delay = wb.maxDelay
+	}
+	wb.items[item.name] = item
+}
+
+// popDue removes and returns the item which has been due for upload
+// longest, or nil if none are due
+func (wb *writeBack) popDue(now time.Time) (item *writeBackItem) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	for _, cur := range wb.items {
+		if cur.expiry.After(now) {
+			continue
+		}
+		if item == nil || cur.expiry.Before(item.expiry) {
+			item = cur
+		}
+	}
+	if item != nil {
+		delete(wb.items, item.name)
+	}
+	return item
+}
+
+// nextExpiry returns the time the next upload is due and whether
+// there is one at all
+func (wb *writeBack) nextExpiry() (next time.Time, found bool) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	for _, item := range wb.items {
+		if !found || item.expiry.Before(next) {
+			next = item.expiry
+			found = true
+		}
+	}
+	return next, found
+}
+
+// uploadDue uploads all the items which are due
+func (wb *writeBack) uploadDue() {
+	wb.uploadMu.Lock()
+	defer wb.uploadMu.Unlock()
+	for {
+		item := wb.popDue(time.Now())
+		if item == nil {
+			return
+		}
+		err := wb.upload(item)
+		if err != nil {
+			wb.retry(item, err)
+		}
+	}
+}
+
+// flush uploads name immediately if it is waiting to be uploaded
+func (wb *writeBack) flush(name string) error {
+	name = clean(name)
+	wb.uploadMu.Lock()
+	defer wb.uploadMu.Unlock()
+	wb.mu.Lock()
+	item, found := wb.items[name]
+	delete(wb.items, name)
+	wb.mu.Unlock()
+	if !found {
+		return nil
+	}
+	err := wb.upload(item)
+	if err != nil {
+		wb.retry(item, err)
+	}
+	return err
+}
+
+// upload the item from the cache to the remote
+//
+// call with uploadMu held
+func (wb *writeBack) upload(item *writeBackItem) (err error) {
+	c := wb.c
+	if c.opens(item.name) > 0 {
+		fs.Debugf(item.name, "Delaying upload from cache as file is open")
+		wb.add(item.name, item.file, wb.minDelay)
+		return nil
+	}
+	if !c.isDirty(item.name) {
+		return nil
+	}
+	var dst fs.Object
+	if item.file != nil {
+		item.file.muRW.Lock()
+		dst = item.file.getObject()
+	} else {
+		dst, err = c.fremote.NewObject(context.TODO(), item.name)
+		if err == fs.ErrorObjectNotFound {
+			dst = nil
+		} else if err != nil {
+			return errors.Wrap(err, "failed to find destination object")
+		}
+	}
+	o, err := c.upload(item.name, dst)
+	if item.file != nil {
+		if err == nil {
+			item.file.setObject(o)
+		}
+		item.file.muRW.Unlock()
+	}
+	if err != nil {
+		return err
+	}
+	fs.Infof(o, "Uploaded from cache")
+	if item.file != nil {
+		item.file.applyPendingRename()
+	}
+	return nil
+}
+
+// run uploads the items as they become due
+//
+// doesn't return until context is cancelled
+func (wb *writeBack) run(ctx context.Context) {
+	for {
+		wb.uploadDue()
+		var timer *time.Timer
+		var due <-chan time.Time
+		if next, found := wb.nextExpiry(); found {
+			timer = time.NewTimer(time.Until(next))
+			due = timer.C
+		}
+		select {
+		case <-wb.kick:
+		case <-due:
+		case <-ctx.Done():
+			fs.Debugf(nil, "cache write back exiting")
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package vfs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/artpar/rclone/fs"
+	"github.com/artpar/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes contents to name in the vfs
+func writeFile(t *testing.T, vfs *VFS, name, contents string) {
+	h, err := vfs.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	require.NoError(t, err)
+	_, err = h.WriteString(contents)
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+}
+
+// waitForObject waits for name to appear on the remote with the
+// contents given
+func waitForObject(t *testing.T, f fs.Fs, name, contents string) {
+	ctx := context.Background()
+	var o fs.Object
+	var err error
+	for i := 0; i < 100; i++ {
+		o, err = f.NewObject(ctx, name)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NoError(t, err)
+	in, err := o.Open(ctx)
+	require.NoError(t, err)
+	got, err := ioutil.ReadAll(in)
+	require.NoError(t, err)
+	require.NoError(t, in.Close())
+	assert.Equal(t, contents, string(got))
+}
+
+func TestWriteBackDelay(t *testing.T) {
+	r := fstest.NewRun(t)
+	opt := DefaultOpt
+	opt.CacheMode = CacheModeWrites
+	opt.WriteBack = 500 * time.Millisecond
+	vfs := New(r.Fremote, &opt)
+	defer cleanup(t, r, vfs)
+
+	writeFile(t, vfs, "file1", "hello")
+
+	// Not uploaded yet
+	_, err := r.Fremote.NewObject(context.Background(), "file1")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+	assert.True(t, vfs.cache.isDirty("file1"))
+
+	// But can be read back from the cache
+	h, err := vfs.OpenFile("file1", os.O_RDONLY, 0777)
+	require.NoError(t, err)
+	got, err := ioutil.ReadAll(h)
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+	assert.Equal(t, "hello", string(got))
+
+	// Then it is uploaded
+	waitForObject(t, r.Fremote, "file1", "hello")
+	assert.False(t, vfs.cache.isDirty("file1"))
+}
+
+func TestWriteBackRename(t *testing.T) {
+	r := fstest.NewRun(t)
+	opt := DefaultOpt
+	opt.CacheMode = CacheModeWrites
+	opt.WriteBack = time.Hour
+	vfs := New(r.Fremote, &opt)
+	defer cleanup(t, r, vfs)
+
+	writeFile(t, vfs, "file1", "hello")
+	assert.True(t, vfs.cache.writeback.queued("file1"))
+
+	// Renaming uploads the file first
+	require.NoError(t, vfs.Rename("file1", "file2"))
+	assert.False(t, vfs.cache.writeback.queued("file1"))
+	waitForObject(t, r.Fremote, "file2", "hello")
+	_, err := r.Fremote.NewObject(context.Background(), "file1")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+}
+
+func TestWriteBackResume(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt := DefaultOpt
+	opt.CacheMode = CacheModeWrites
+	opt.CachePollInterval = 0
+	opt.WriteBack = time.Hour
+	vfs := New(r.Fremote, &opt)
+
+	_, err := vfs.root.Mkdir("dir")
+	require.NoError(t, err)
+	writeFile(t, vfs, "dir/file1", "resumed")
+
+	// Stop before the upload happens
+	vfs.Shutdown()
+	_, err = r.Fremote.NewObject(context.Background(), "dir/file1")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+
+	// A new VFS picks up the upload from the cache
+	opt.WriteBack = 0
+	vfs = New(r.Fremote, &opt)
+	defer func() {
+		assert.NoError(t, vfs.CleanUp())
+		vfs.Shutdown()
+	}()
+	waitForObject(t, r.Fremote, "dir/file1", "resumed")
+	assert.False(t, vfs.cache.isDirty("dir/file1"))
+}
+
+func TestWriteBackRetry(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	// Cancel the context so the background uploader doesn't run
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	opt := DefaultOpt
+	opt.CachePollInterval = 0
+	c, err := newCache(ctx, r.Fremote, &opt)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.cleanUp())
+	}()
+	wb := c.writeback
+	wb.minDelay = time.Millisecond
+	wb.maxDelay = 3 * time.Millisecond
+
+	// Mark a file dirty which isn't in the cache so the upload fails
+	c.setDirty("file1")
+	wb.add("file1", nil, 0)
+	for i, wantDelay := range []time.Duration{2, 3, 3} {
+		wb.mu.Lock()
+		item := wb.items["file1"]
+		require.NotNil(t, item)
+		item.expiry = time.Now()
+		wb.mu.Unlock()
+
+		wb.uploadDue()
+
+		wb.mu.Lock()
+		item = wb.items["file1"]
+		require.NotNil(t, item)
+		assert.Equal(t, i+1, item.tries)
+		assert.Equal(t, wantDelay*time.Millisecond, item.delay)
+		wb.mu.Unlock()
+	}
+
+	// Now put the file in the cache and it gets uploaded
+	osPath, err := c.mkdir("file1")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(osPath, []byte("retried"), 0600))
+	require.NoError(t, wb.flush("file1"))
+	assert.False(t, wb.queued("file1"))
+	assert.False(t, c.isDirty("file1"))
+	waitForObject(t, r.Fremote, "file1", "retried")
+
+	// Removing the file takes it off the queue
+	c.setDirty("file1")
+	wb.add("file1", nil, time.Hour)
+	c.remove("file1")
+	assert.False(t, wb.queued("file1"))
+}